@@ -1,7 +1,6 @@
 package accounts
 
 import (
-	"errors"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
@@ -10,39 +9,51 @@ import (
 
 // AccountServiceInMemory emulates an account service by saving data in memory
 type AccountServiceInMemory struct {
-	Amount           float32
+	Amount           domain.Decimal
 	withdraws        int
 	deposits         int
 	assetsRepository domain.AssetsRepository
 	ID               string
+	Trades           []domain.Trade
 }
 
 // NewAccountServiceInMemory returns an instance of AccountServiceInMemory
-func NewAccountServiceInMemory(initialAmount float32, assetsRepository domain.AssetsRepository) *AccountServiceInMemory {
-	return &AccountServiceInMemory{initialAmount, 0, 0, assetsRepository, primitive.NewObjectID().Hex()}
+func NewAccountServiceInMemory(initialAmount domain.Decimal, assetsRepository domain.AssetsRepository) *AccountServiceInMemory {
+	return &AccountServiceInMemory{initialAmount, 0, 0, assetsRepository, primitive.NewObjectID().Hex(), []domain.Trade{}}
 }
 
 // Deposit increases account amount
-func (a *AccountServiceInMemory) Deposit(amount float32) error {
+func (a *AccountServiceInMemory) Deposit(amount domain.Decimal) error {
 	a.deposits++
-	a.Amount += amount
+	a.Amount = a.Amount.Add(amount)
 	return nil
 }
 
 // Withdraw decreases account amount
-func (a *AccountServiceInMemory) Withdraw(amount float32) error {
-	if amount > a.Amount {
-		return errors.New("Insufficient Funds")
+func (a *AccountServiceInMemory) Withdraw(amount domain.Decimal) error {
+	if amount.GreaterThan(a.Amount) {
+		return domain.ErrInsufficientFunds
 	}
 
-	a.Amount -= amount
+	a.Amount = a.Amount.Sub(amount)
 	a.withdraws++
 
 	return nil
 }
 
+// Adjust adds the amount to the account, negative amounts decrease it
+func (a *AccountServiceInMemory) Adjust(amount domain.Decimal, description string) error {
+	a.Amount = a.Amount.Add(amount)
+	return nil
+}
+
+// Fee returns the exchange fee of an order value, the in memory account pays no fees
+func (a *AccountServiceInMemory) Fee(value domain.Decimal) domain.Decimal {
+	return domain.Decimal{}
+}
+
 // GetAmount returns amount value
-func (a *AccountServiceInMemory) GetAmount() (float32, error) {
+func (a *AccountServiceInMemory) GetAmount() (domain.Decimal, error) {
 	return a.Amount, nil
 }
 
@@ -54,7 +65,7 @@ func (a *AccountServiceInMemory) FindAllAssets() (*[]domain.Asset, error) {
 	return a.assetsRepository.FindAll(a.ID)
 }
 
-func (a *AccountServiceInMemory) CreateAsset(amount, price float32, time time.Time) (*domain.Asset, error) {
+func (a *AccountServiceInMemory) CreateAsset(amount, price domain.Decimal, time time.Time) (*domain.Asset, error) {
 	asset := &domain.Asset{ID: primitive.NewObjectID(), Amount: amount, BuyPrice: price, BuyTime: time}
 
 	err := a.assetsRepository.Create(asset)
@@ -62,11 +73,102 @@ func (a *AccountServiceInMemory) CreateAsset(amount, price float32, time time.Ti
 	return asset, err
 }
 
-func (a *AccountServiceInMemory) SellAsset(assetID string, price float32, time time.Time) error {
-	return a.assetsRepository.Sell(assetID, price, time)
+// Buy withdraws the asset value and creates the asset. The withdraw is reverted when the asset is not created.
+// The in memory account has no ledger to link to the order.
+func (a *AccountServiceInMemory) Buy(amount, price domain.Decimal, time time.Time, orderID string) (*domain.Asset, error) {
+	value, err := amount.MulChecked(price)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.Withdraw(value); err != nil {
+		return nil, err
+	}
+
+	asset, err := a.CreateAsset(amount, price, time)
+
+	if err != nil {
+		a.Amount = a.Amount.Add(value)
+		a.withdraws--
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// Sell updates the asset status to sold and deposits its value
+func (a *AccountServiceInMemory) Sell(asset *domain.Asset, price domain.Decimal, time time.Time) error {
+	selection := domain.LotSelection{Method: domain.LotSpecificID, AssetIDs: []primitive.ObjectID{asset.ID}}
+	_, err := a.SellAmount(asset.Amount, price, selection, time, "")
+
+	return err
+}
+
+// SellAmount sells an amount of the pending lots matched by the selection, splitting lots partially sold, and deposits its value
+func (a *AccountServiceInMemory) SellAmount(amount, price domain.Decimal, selection domain.LotSelection, time time.Time, orderID string) (*domain.Trade, error) {
+	lots, err := a.FindPendingAssets()
+
+	if err != nil {
+		return nil, err
+	}
+
+	fills, err := domain.MatchLots(*lots, amount, selection)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, fill := range fills {
+		if fill.Amount.LessThan(fill.Asset.Amount) {
+			lot, err := a.assetsRepository.Split(&fill.Asset, fill.Amount)
+
+			if err != nil {
+				return nil, err
+			}
+
+			fills[i].Asset = *lot
+		}
+
+		if err := a.SellAsset(fills[i].Asset.ID.Hex(), price, time); err != nil {
+			return nil, err
+		}
+	}
+
+	accountOID, _ := primitive.ObjectIDFromHex(a.ID)
+	trade, err := domain.NewTrade(accountOID, fills, price, selection.Method, time)
+
+	if err != nil {
+		return nil, err
+	}
+
+	a.Trades = append(a.Trades, *trade)
+
+	return trade, a.Deposit(trade.Proceeds)
+}
+
+// MergeLots replaces the pending lots with the ids passed by argument by a lot bought at their average price
+func (a *AccountServiceInMemory) MergeLots(ids []primitive.ObjectID) (*domain.Asset, error) {
+	lots, err := a.FindPendingAssets()
+
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectPendingLots(*lots, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.assetsRepository.Merge(selected)
+}
+
+func (a *AccountServiceInMemory) SellAsset(assetID string, price domain.Decimal, time time.Time) error {
+	return a.assetsRepository.Sell(assetID, price, domain.Decimal{}, time)
 }
 
-func (a *AccountServiceInMemory) GetBalance(startDate, endDate time.Time) (float32, error) {
+func (a *AccountServiceInMemory) GetBalance(startDate, endDate time.Time) (domain.Decimal, error) {
 	return a.assetsRepository.GetBalance(a.ID, startDate, endDate)
 }
 
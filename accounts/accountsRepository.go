@@ -49,7 +49,7 @@ func (r *Repository) FindByBroker(broker string) (*domain.Account, error) {
 }
 
 // Create inserts a new account in collection
-func (r *Repository) Create(broker string, amount float32) (*domain.Account, error) {
+func (r *Repository) Create(broker string, amount domain.Decimal) (*domain.Account, error) {
 
 	account := &domain.Account{ID: primitive.NewObjectID(), Amount: amount, Broker: broker}
 	err := r.repo.InsertOne(account)
@@ -57,8 +57,9 @@ func (r *Repository) Create(broker string, amount float32) (*domain.Account, err
 	return account, err
 }
 
-// Withdraw decrements an amount from the account
-func (r *Repository) Withdraw(id string, amount float32) error {
+// Withdraw decrements an amount from the account.
+// It returns domain.ErrInsufficientFunds when the account amount is lower than the amount to withdraw.
+func (r *Repository) Withdraw(id string, amount domain.Decimal) error {
 
 	accountOID, err := primitive.ObjectIDFromHex(id)
 
@@ -66,13 +67,34 @@ func (r *Repository) Withdraw(id string, amount float32) error {
 		return err
 	}
 
+	account, err := r.FindById(id)
+
+	if err != nil {
+		return err
+	}
+
+	if account.Amount.LessThan(amount) {
+		return domain.ErrInsufficientFunds
+	}
+
+	// the amount may be withdrawn concurrently after the account is read
 	filter := bson.M{"_id": accountOID, "amount": bson.M{"$gte": amount}}
-	update := bson.M{"$inc": bson.M{"amount": amount * -1}}
-	return r.repo.UpdateOne(filter, update)
+	update := bson.M{"$inc": bson.M{"amount": amount.Neg()}}
+	matched, err := r.repo.UpdateOneMatched(filter, update)
+
+	if err != nil {
+		return err
+	}
+
+	if !matched {
+		return domain.ErrInsufficientFunds
+	}
+
+	return nil
 }
 
 // Deposit increments an amount to the account
-func (r *Repository) Deposit(id string, amount float32) error {
+func (r *Repository) Deposit(id string, amount domain.Decimal) error {
 	accountOID, err := primitive.ObjectIDFromHex(id)
 
 	if err != nil {
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
 	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/trades"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -13,76 +16,482 @@ type AccountService struct {
 	ID               string
 	repository       *Repository
 	assetsRepository domain.AssetsRepository
+	ledgerRepository domain.LedgerRepository
+	unitOfWork       domain.UnitOfWork
+	// symbol is the asset of the lots handled by the service, every lot of the account is handled when empty
+	symbol string
+	// optionsVersion is the version of the application options the lots are bought with
+	optionsVersion int
+	// feeRate is the ratio of the value of the buys and sells paid to the exchange
+	feeRate float64
 }
 
-// NewAccountService returns an instance of account service
-func NewAccountService(ID string, repository *Repository, assetsRepository domain.AssetsRepository) (*AccountService, error) {
+// NewAccountService returns an instance of account service.
+// Every change of the account amount is recorded in the ledger within the unit of work passed by argument.
+func NewAccountService(ID string, repository *Repository, assetsRepository domain.AssetsRepository, ledgerRepository domain.LedgerRepository, unitOfWork domain.UnitOfWork) (*AccountService, error) {
 	_, err := repository.FindById(ID)
 
 	if err != nil {
 		return nil, fmt.Errorf("Not able to get account with id %v due to %v", ID, err)
 	}
 
-	return &AccountService{ID, repository, assetsRepository}, nil
+	return &AccountService{ID, repository, assetsRepository, ledgerRepository, unitOfWork, "", 0, 0}, nil
 }
 
-// Withdraw decrements an amount from an account
-func (a *AccountService) Withdraw(amount float32) error {
-	return a.repository.Withdraw(a.ID, amount)
+// ForAsset returns a service of the same account that only buys and sells lots of the asset passed by argument.
+// The account amount is shared with the services of the other assets.
+func (a *AccountService) ForAsset(symbol string) *AccountService {
+	service := *a
+	service.symbol = symbol
+
+	return &service
+}
+
+// WithOptionsVersion returns a service of the same account that tags the lots it buys with the application options version
+func (a *AccountService) WithOptionsVersion(version int) *AccountService {
+	service := *a
+	service.optionsVersion = version
+
+	return &service
+}
+
+// WithFeeRate returns a service of the same account that pays the ratio passed by argument of the value of the buys and sells to the exchange
+func (a *AccountService) WithFeeRate(rate float64) *AccountService {
+	service := *a
+	service.feeRate = rate
+
+	return &service
+}
+
+// OpenAccount creates an account with a deposit of the initial amount in the ledger
+func OpenAccount(unitOfWork domain.UnitOfWork, broker string, amount domain.Decimal, date time.Time) (*domain.Account, error) {
+	var account *domain.Account
+
+	err := unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		var err error
+		account, err = NewRepository(repositories(db.ACCOUNTS_COLLECTION)).Create(broker, amount)
+
+		if err != nil {
+			return err
+		}
+
+		return NewLedgerRepository(repositories(db.LEDGER_COLLECTION)).Create(domain.NewLedgerEntry(account.ID, domain.LedgerDeposit, amount, date))
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Withdraw decrements an amount from an account.
+// It returns domain.ErrInsufficientFunds when the account balance is lower than the amount.
+func (a *AccountService) Withdraw(amount domain.Decimal) error {
+	return a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		err := a.withdraw(repositories, amount)
+
+		if err != nil {
+			return err
+		}
+
+		return a.record(repositories, domain.LedgerWithdrawal, amount, time.Now(), primitive.NilObjectID, "")
+	})
 }
 
 // Deposit increments an amount to an account
-func (a *AccountService) Deposit(amount float32) error {
-	return a.repository.Deposit(a.ID, amount)
+func (a *AccountService) Deposit(amount domain.Decimal) error {
+	return a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		err := NewRepository(repositories(db.ACCOUNTS_COLLECTION)).Deposit(a.ID, amount)
+
+		if err != nil {
+			return err
+		}
+
+		return a.record(repositories, domain.LedgerDeposit, amount, time.Now(), primitive.NilObjectID, "")
+	})
+}
+
+// Adjust corrects the account amount with a ledger adjustment, negative amounts decrease it
+func (a *AccountService) Adjust(amount domain.Decimal, description string) error {
+	return a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		err := NewRepository(repositories(db.ACCOUNTS_COLLECTION)).Deposit(a.ID, amount)
+
+		if err != nil {
+			return err
+		}
+
+		accountOID, err := primitive.ObjectIDFromHex(a.ID)
+
+		if err != nil {
+			return err
+		}
+
+		entry := domain.NewLedgerEntry(accountOID, domain.LedgerAdjustment, amount, time.Now())
+		entry.Description = description
+
+		return NewLedgerRepository(repositories(db.LEDGER_COLLECTION)).Create(entry)
+	})
+}
+
+// GetAmount returns the amount hold by the account derived from its ledger
+func (a *AccountService) GetAmount() (domain.Decimal, error) {
+	return a.ledgerRepository.GetBalance(a.ID)
+}
+
+// withdraw checks the amount against the ledger balance and decrements it from the account with the repositories of a unit of work.
+// The account amount is updated in the same unit of work, so concurrent units of work of the account conflict instead of spending the same balance.
+func (a *AccountService) withdraw(repositories domain.RepositoryFactory, amount domain.Decimal) error {
+	balance, err := NewLedgerRepository(repositories(db.LEDGER_COLLECTION)).GetBalance(a.ID)
+
+	if err != nil {
+		return err
+	}
+
+	if balance.LessThan(amount) {
+		return domain.ErrInsufficientFunds
+	}
+
+	return NewRepository(repositories(db.ACCOUNTS_COLLECTION)).Deposit(a.ID, amount.Neg())
+}
+
+// FindLedgerEntries returns the ledger entries of the account between two dates
+func (a *AccountService) FindLedgerEntries(startDate, endDate time.Time) (*[]domain.LedgerEntry, error) {
+	return a.ledgerRepository.FindAll(a.ID, startDate, endDate)
 }
 
-// GetAmount returns the amount hold by the account
-func (a *AccountService) GetAmount() (float32, error) {
-	account, err := a.repository.FindById(a.ID)
+// record creates a ledger entry of the account with the repositories of a unit of work, linked to the asset and to the exchange order when they are set
+func (a *AccountService) record(repositories domain.RepositoryFactory, entryType domain.LedgerEntryType, amount domain.Decimal, date time.Time, assetID primitive.ObjectID, orderID string) error {
+	accountOID, err := primitive.ObjectIDFromHex(a.ID)
 
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	return account.Amount, nil
+	entry := domain.NewLedgerEntry(accountOID, entryType, amount, date)
+	entry.AssetID = assetID
+	entry.OrderID = orderID
+
+	return NewLedgerRepository(repositories(db.LEDGER_COLLECTION)).Create(entry)
+}
+
+// recordFee creates a ledger entry of the exchange fee paid on a buy or a sell, nothing is recorded when there is no fee
+func (a *AccountService) recordFee(repositories domain.RepositoryFactory, fee domain.Decimal, date time.Time, assetID primitive.ObjectID, orderID string) error {
+	if fee.IsZero() {
+		return nil
+	}
+
+	return a.record(repositories, domain.LedgerFee, fee, date, assetID, orderID)
+}
+
+// Fee returns the exchange fee of an order value
+func (a *AccountService) Fee(value domain.Decimal) domain.Decimal {
+	return value.MulFloat(a.feeRate)
 }
 
 // FindPendingAssets returns account assets awaiting to be sold
 func (a *AccountService) FindPendingAssets() (*[]domain.Asset, error) {
-	return a.assetsRepository.FindPendingAssets(a.ID)
+	lots, err := a.assetsRepository.FindPendingAssets(a.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.filterLots(lots), nil
 }
 
 // FindAllAssets returns all assets hold by the account
 func (a *AccountService) FindAllAssets() (*[]domain.Asset, error) {
-	return a.assetsRepository.FindAll(a.ID)
+	lots, err := a.assetsRepository.FindAll(a.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.filterLots(lots), nil
+}
+
+// filterLots returns the lots of the asset of the service
+func (a *AccountService) filterLots(lots *[]domain.Asset) *[]domain.Asset {
+	if a.symbol == "" {
+		return lots
+	}
+
+	filtered := []domain.Asset{}
+
+	for _, lot := range *lots {
+		if lot.Symbol == a.symbol {
+			filtered = append(filtered, lot)
+		}
+	}
+
+	return &filtered
 }
 
 // CreateAsset creates an asset hold by the account
-func (a *AccountService) CreateAsset(amount, price float32, time time.Time) (*domain.Asset, error) {
+func (a *AccountService) CreateAsset(amount, price domain.Decimal, time time.Time) (*domain.Asset, error) {
 	accountOID, err := primitive.ObjectIDFromHex(a.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	asset := &domain.Asset{ID: primitive.NewObjectID(), Amount: amount, BuyPrice: price, BuyTime: time, AccountID: accountOID}
+	asset := &domain.Asset{ID: primitive.NewObjectID(), Symbol: a.symbol, Amount: amount, BuyPrice: price, BuyTime: time, AccountID: accountOID, OptionsVersion: a.optionsVersion}
 
 	err = a.assetsRepository.Create(asset)
 
 	return asset, err
 }
 
+// Buy withdraws the asset value and the exchange fee from the account and creates the asset atomically.
+// The ledger entries are linked to the exchange order passed by argument.
+// It returns domain.ErrInsufficientFunds when the account does not have the asset value and the fee.
+func (a *AccountService) Buy(amount, price domain.Decimal, time time.Time, orderID string) (*domain.Asset, error) {
+	accountOID, err := primitive.ObjectIDFromHex(a.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := amount.MulChecked(price)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fee := a.Fee(value)
+	asset := &domain.Asset{ID: primitive.NewObjectID(), Symbol: a.symbol, Amount: amount, BuyPrice: price, BuyFee: fee, BuyTime: time, AccountID: accountOID, OptionsVersion: a.optionsVersion}
+
+	err = a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		err := a.withdraw(repositories, value.Add(fee))
+
+		if err != nil {
+			return err
+		}
+
+		err = assets.NewRepository(repositories(db.ASSETS_COLLECTION)).Create(asset)
+
+		if err != nil {
+			return err
+		}
+
+		err = a.record(repositories, domain.LedgerBuy, value, time, asset.ID, orderID)
+
+		if err != nil {
+			return err
+		}
+
+		return a.recordFee(repositories, fee, time, asset.ID, orderID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+// BuyDCA withdraws the value and the exchange fee of a dca purchase from the account and records them in its ledger atomically.
+// The ledger entries are linked to the exchange order passed by argument.
+// It returns domain.ErrInsufficientFunds when the account does not have the value and the fee.
+func (a *AccountService) BuyDCA(value, fee domain.Decimal, orderID string, time time.Time) error {
+	return a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		err := a.withdraw(repositories, value.Add(fee))
+
+		if err != nil {
+			return err
+		}
+
+		err = a.record(repositories, domain.LedgerDCAPurchase, value, time, primitive.NilObjectID, orderID)
+
+		if err != nil {
+			return err
+		}
+
+		return a.recordFee(repositories, fee, time, primitive.NilObjectID, orderID)
+	})
+}
+
+// Sell updates the asset status to sold and deposits its value into the account atomically
+func (a *AccountService) Sell(asset *domain.Asset, price domain.Decimal, time time.Time) error {
+	selection := domain.LotSelection{Method: domain.LotSpecificID, AssetIDs: []primitive.ObjectID{asset.ID}}
+	_, err := a.SellAmount(asset.Amount, price, selection, time, "")
+
+	return err
+}
+
+// SellAmount sells an amount of the pending lots matched by the selection and deposits its value minus the exchange fee into the account atomically.
+// Lots partially sold are split and the realized profit or loss is stored in a trade that is returned.
+// The ledger entries are linked to the exchange order passed by argument.
+// It returns domain.ErrInsufficientAssets when the lots do not hold the amount.
+func (a *AccountService) SellAmount(amount, price domain.Decimal, selection domain.LotSelection, time time.Time, orderID string) (*domain.Trade, error) {
+	accountOID, err := primitive.ObjectIDFromHex(a.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var trade *domain.Trade
+
+	err = a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		assetsRepository := assets.NewRepository(repositories(db.ASSETS_COLLECTION))
+		lots, err := assetsRepository.FindPendingAssets(a.ID)
+
+		if err != nil {
+			return err
+		}
+
+		fills, err := domain.MatchLots(*a.filterLots(lots), amount, selection)
+
+		if err != nil {
+			return err
+		}
+
+		for i, fill := range fills {
+			if fill.Amount.LessThan(fill.Asset.Amount) {
+				lot, err := assetsRepository.Split(&fill.Asset, fill.Amount)
+
+				if err != nil {
+					return err
+				}
+
+				fills[i].Asset = *lot
+			}
+
+			value, err := fill.Amount.MulChecked(price)
+
+			if err != nil {
+				return err
+			}
+
+			fee := a.Fee(value)
+			fills[i].Fee = fee
+
+			err = assetsRepository.Sell(fills[i].Asset.ID.Hex(), price, fee, time)
+
+			if err != nil {
+				return err
+			}
+
+			err = a.record(repositories, domain.LedgerSell, value, time, fills[i].Asset.ID, orderID)
+
+			if err != nil {
+				return err
+			}
+
+			err = a.recordFee(repositories, fee, time, fills[i].Asset.ID, orderID)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		trade, err = domain.NewTrade(accountOID, fills, price, selection.Method, time)
+
+		if err != nil {
+			return err
+		}
+
+		err = NewRepository(repositories(db.ACCOUNTS_COLLECTION)).Deposit(a.ID, trade.Proceeds.Sub(trade.Fees))
+
+		if err != nil {
+			return err
+		}
+
+		return trades.NewRepository(repositories(db.TRADES_COLLECTION)).Create(trade)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return trade, nil
+}
+
+// MergeLots replaces the pending lots with the ids passed by argument by a lot with their amount and buy fees bought at their average price atomically.
+// It returns domain.ErrLotsNotMergeable when a lot is not pending or when there are less than two lots.
+func (a *AccountService) MergeLots(ids []primitive.ObjectID) (*domain.Asset, error) {
+	var merged *domain.Asset
+
+	err := a.unitOfWork.Do(func(repositories domain.RepositoryFactory) error {
+		assetsRepository := assets.NewRepository(repositories(db.ASSETS_COLLECTION))
+		lots, err := assetsRepository.FindPendingAssets(a.ID)
+
+		if err != nil {
+			return err
+		}
+
+		selected, err := selectPendingLots(*a.filterLots(lots), ids)
+
+		if err != nil {
+			return err
+		}
+
+		merged, err = assetsRepository.Merge(selected)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// selectPendingLots returns the lots with the ids passed by argument, domain.ErrLotsNotMergeable when one is not pending
+func selectPendingLots(lots []domain.Asset, ids []primitive.ObjectID) ([]domain.Asset, error) {
+	selected := []domain.Asset{}
+
+	for _, id := range ids {
+		found := false
+
+		for _, lot := range lots {
+			if lot.ID == id {
+				selected = append(selected, lot)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, domain.ErrLotsNotMergeable
+		}
+	}
+
+	return selected, nil
+}
+
 // SellAsset updates asset status to sold
-func (a *AccountService) SellAsset(assetID string, price float32, time time.Time) error {
-	return a.assetsRepository.Sell(assetID, price, time)
+func (a *AccountService) SellAsset(assetID string, price domain.Decimal, time time.Time) error {
+	return a.assetsRepository.Sell(assetID, price, domain.Decimal{}, time)
 }
 
 // GetBalance returns the balance between two dates
-func (a *AccountService) GetBalance(startDate, endDate time.Time) (float32, error) {
+func (a *AccountService) GetBalance(startDate, endDate time.Time) (domain.Decimal, error) {
 	return a.assetsRepository.GetBalance(a.ID, startDate, endDate)
 }
 
 // CheckAssetWithCloserPriceExists verifies whether account already has asset with a price close to the one passed by argument
 func (a *AccountService) CheckAssetWithCloserPriceExists(price, limit float32) (bool, error) {
-	return a.assetsRepository.CheckAssetWithCloserPriceExists(a.ID, price, limit)
+	if a.symbol == "" {
+		return a.assetsRepository.CheckAssetWithCloserPriceExists(a.ID, price, limit)
+	}
+
+	lots, err := a.FindPendingAssets()
+
+	if err != nil {
+		return false, err
+	}
+
+	lowerLimit := domain.NewDecimalFromFloat32(price - (price * limit))
+	upperLimit := domain.NewDecimalFromFloat32(price + (price * limit))
+
+	for _, lot := range *lots {
+		if !lot.BuyPrice.LessThan(lowerLimit) && !lot.BuyPrice.GreaterThan(upperLimit) {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
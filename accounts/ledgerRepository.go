@@ -0,0 +1,98 @@
+package accounts
+
+import (
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LedgerRepository stores and gets the ledger entries of accounts
+type LedgerRepository struct {
+	repo domain.Repository
+}
+
+// NewLedgerRepository returns an instance of LedgerRepository
+func NewLedgerRepository(repo domain.Repository) *LedgerRepository {
+	return &LedgerRepository{repo}
+}
+
+// Create inserts a new entry in the ledger
+func (r *LedgerRepository) Create(entry *domain.LedgerEntry) error {
+	return r.repo.InsertOne(entry)
+}
+
+// FindAll returns the entries of an account sorted by date.
+// Zero dates do not limit the entries returned.
+func (r *LedgerRepository) FindAll(accountID string, startDate, endDate time.Time) (*[]domain.LedgerEntry, error) {
+	accountOID, err := primitive.ObjectIDFromHex(accountID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"accountId": accountOID}
+	date := bson.M{}
+
+	if !startDate.IsZero() {
+		date["$gte"] = startDate
+	}
+
+	if !endDate.IsZero() {
+		date["$lte"] = endDate
+	}
+
+	if len(date) > 0 {
+		filter["date"] = date
+	}
+
+	results := []domain.LedgerEntry{}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	err = r.repo.FindAll(&results, filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
+
+// GetBalance returns the account balance derived from its cash debits and credits
+func (r *LedgerRepository) GetBalance(accountID string) (domain.Decimal, error) {
+	var balance domain.Decimal
+
+	accountOID, err := primitive.ObjectIDFromHex(accountID)
+
+	if err != nil {
+		return balance, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"accountId": accountOID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": nil,
+			"debits": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$debit", domain.LedgerCash}}, "$amount", 0,
+			}}},
+			"credits": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$credit", domain.LedgerCash}}, "$amount", 0,
+			}}},
+		}}},
+	}
+
+	var results []struct {
+		Debits  domain.Decimal `bson:"debits"`
+		Credits domain.Decimal `bson:"credits"`
+	}
+
+	err = r.repo.Aggregate(&results, pipeline)
+
+	if err != nil || len(results) == 0 {
+		return balance, err
+	}
+
+	return results[0].Debits.Sub(results[0].Credits), nil
+}
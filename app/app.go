@@ -2,34 +2,54 @@ package app
 
 import (
 	"fmt"
-	"log"
+	"sync"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
 )
 
+// Market is an asset traded by an application with its own decision maker, trader and lots
+type Market struct {
+	// Asset is the asset of the prices handled, every price is handled when empty
+	Asset         string
+	DecisionMaker domain.DecisionMaker
+	Trader        domain.Trader
+	// AccountService buys and sells the lots of the asset on the application account
+	AccountService domain.AccountService
+	// Indicators are fed with the prices of the asset before deciding
+	Indicators []domain.Indicator
+	// MaxAllocation is the maximum cost of the pending lots of the asset, zero means no cap
+	MaxAllocation domain.Decimal
+}
+
 // App holds instances of each application dependency and executes program
 type App struct {
-	decisionMaker       domain.DecisionMaker
+	// trading serializes the decisions of the markets, they share the account amount
+	trading             sync.Mutex
+	markets             []*Market
 	eventLogsRepository domain.EventsLog
-	trader              domain.Trader
 	accountService      domain.AccountService
 	collectors          *[]domain.Collector
+	lotMatching         domain.LotMatching
 	Asset               string
 }
 
-// NewApp returns an instance of App
+// NewApp returns an instance of App that trades every price received
 func NewApp(
 	collectors *[]domain.Collector,
 	decisionMaker domain.DecisionMaker,
 	trader domain.Trader,
 	accountService domain.AccountService,
 ) *App {
+	return NewMultiAssetApp(collectors, accountService, []*Market{{DecisionMaker: decisionMaker, Trader: trader, AccountService: accountService}})
+}
+
+// NewMultiAssetApp returns an instance of App that trades the markets passed by argument against one account
+func NewMultiAssetApp(collectors *[]domain.Collector, accountService domain.AccountService, markets []*Market) *App {
 	app := &App{
 		collectors:     collectors,
-		decisionMaker:  decisionMaker,
-		trader:         trader,
 		accountService: accountService,
+		markets:        markets,
 	}
 
 	app.RegistOnNewAssetPrice(app.OnNewAssetPrice)
@@ -42,6 +62,11 @@ func (a *App) SetEventsLog(eventsLog domain.EventsLog) {
 	a.eventLogsRepository = eventsLog
 }
 
+// SetLotMatching sets how the lots sold are matched, by default the lots with the profit wanted are sold
+func (a *App) SetLotMatching(lotMatching domain.LotMatching) {
+	a.lotMatching = lotMatching
+}
+
 // log writes message to event log dependency
 func (a *App) log(subject, message string) {
 	if a.eventLogsRepository != nil {
@@ -50,10 +75,16 @@ func (a *App) log(subject, message string) {
 }
 
 // Start starts collecting data
-func (a *App) Start() {
+func (a *App) Start() error {
 	for _, collector := range *a.collectors {
-		collector.Start()
+		err := collector.Start()
+
+		if err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // Stop stops collecting data
@@ -70,39 +101,112 @@ func (a *App) RegistOnNewAssetPrice(observable domain.OnNewAssetPrice) {
 	}
 }
 
-// DecideToBuy do operations to check if an asset should be bought
-func (a *App) DecideToBuy(price float32, currentTime time.Time) error {
-	ok, amount, err := a.decisionMaker.ShouldBuy()
+// market returns the market of the asset
+func (a *App) market(asset string) *Market {
+	for _, market := range a.markets {
+		if market.Asset == "" || market.Asset == asset {
+			return market
+		}
+	}
+
+	return nil
+}
+
+// assetName returns the asset of the market used in logs
+func (a *App) assetName(market *Market) string {
+	if market.Asset == "" {
+		return a.Asset
+	}
+
+	return market.Asset
+}
+
+// buyingPower returns the amount the market is able to spend, the account amount limited by the allocation left of the asset.
+// It returns whether the allocation is the limit.
+func (a *App) buyingPower(market *Market) (domain.Decimal, bool, error) {
+	accountAmount, err := a.accountService.GetAmount()
+
+	if err != nil || market.MaxAllocation.IsZero() {
+		return accountAmount, false, err
+	}
+
+	lots, err := market.AccountService.FindPendingAssets()
+
+	if err != nil {
+		return domain.Decimal{}, false, err
+	}
+
+	allocationLeft := market.MaxAllocation
+
+	for _, lot := range *lots {
+		cost, err := lot.Amount.MulChecked(lot.BuyPrice)
+
+		if err != nil {
+			return domain.Decimal{}, false, err
+		}
+
+		allocationLeft = allocationLeft.Sub(cost)
+	}
+
+	if allocationLeft.LessThan(accountAmount) {
+		return allocationLeft, true, nil
+	}
+
+	return accountAmount, false, nil
+}
+
+// DecideToBuy do operations to check if an asset should be bought.
+// The value bought is limited by the account amount shared by every asset and by the allocation cap of the asset.
+func (a *App) DecideToBuy(asset string, price domain.Decimal, currentTime time.Time) error {
+	market := a.market(asset)
+
+	if market == nil {
+		return nil
+	}
+
+	a.trading.Lock()
+	defer a.trading.Unlock()
+
+	ok, amount, err := market.DecisionMaker.ShouldBuy()
 	if ok && err == nil {
-		accountAmount, err := a.accountService.GetAmount()
+		buyingPower, capped, err := a.buyingPower(market)
 
 		if err != nil {
 			return err
 		}
 
-		if accountAmount > amount*price {
-			err := a.trader.Buy(amount, price, currentTime)
+		tradeAmount, tradePrice := domain.NewDecimalFromFloat32(amount), price
+		value, err := tradeAmount.MulChecked(tradePrice)
 
-			if err != nil {
-				return err
-			}
+		if err != nil {
+			return err
+		}
+
+		assetName := a.assetName(market)
+		cost := value.Add(market.AccountService.Fee(value))
 
-			err = a.accountService.Withdraw(amount * price)
+		// the account is checked right before the order, with the exchange fee the account pays on the buy,
+		// so the order is not placed without funds to record it
+		if buyingPower.GreaterThan(cost) {
+			orderID, err := market.Trader.Buy(tradeAmount, tradePrice, currentTime)
 
 			if err != nil {
 				return err
 			}
 
-			_, err = a.accountService.CreateAsset(amount, price, currentTime)
+			_, err = market.AccountService.Buy(tradeAmount, tradePrice, currentTime, orderID)
 
 			if err != nil {
+				a.log("Order Not Recorded", fmt.Sprintf("buy order of %v%v*%v$ placed but not recorded in the account: %v", tradeAmount.StringFixed(4), assetName, tradePrice.StringFixed(2), err))
 				return err
 			}
 
-			message := fmt.Sprintf("Asset bought: {Price: %v Amount: %v Value: %v, Asset: %v}", price, amount, amount*price, a.Asset)
+			message := fmt.Sprintf("Asset bought: {Price: %v Amount: %v Value: %v, Asset: %v}", tradePrice, tradeAmount, value, assetName)
 			a.log("buy", message)
+		} else if capped {
+			a.log("Allocation Cap Reached", fmt.Sprintf("want to spend %v%v*%v$=%v plus %v of fees, %v left of the %v allocated", tradeAmount.StringFixed(4), assetName, tradePrice.StringFixed(2), value, cost.Sub(value), buyingPower.StringFixed(2), market.MaxAllocation.StringFixed(2)))
 		} else {
-			a.log("Insuffucient Funds", fmt.Sprintf("want to spend %.4f%v*%.2f$=%v, have %.2f in account", amount, a.Asset, price, amount*price, accountAmount))
+			a.log("Insuffucient Funds", fmt.Sprintf("want to spend %v%v*%v$=%v plus %v of fees, have %v in account", tradeAmount.StringFixed(4), assetName, tradePrice.StringFixed(2), value, cost.Sub(value), buyingPower.StringFixed(2)))
 		}
 	}
 
@@ -110,67 +214,107 @@ func (a *App) DecideToBuy(price float32, currentTime time.Time) error {
 }
 
 // DecideToSell do operations to check if an asset should be sold
-func (a *App) DecideToSell(price float32, currentTime time.Time) error {
-	assets, err := a.accountService.FindPendingAssets()
+func (a *App) DecideToSell(asset string, tradePrice domain.Decimal, currentTime time.Time) error {
+	market := a.market(asset)
+
+	if market == nil {
+		return nil
+	}
+
+	a.trading.Lock()
+	defer a.trading.Unlock()
+
+	assets, err := market.AccountService.FindPendingAssets()
 
 	if err != nil {
 		return err
 	}
 
-	ok, _, err := a.decisionMaker.ShouldSell()
+	ok, _, err := market.DecisionMaker.ShouldSell()
 
-	if ok {
-		for _, asset := range *assets {
-			if asset.BuyPrice+(asset.BuyPrice*0.01) < price {
-				if err := a.trader.Sell(&asset, price, currentTime); err != nil {
-					return err
-				}
+	if !ok {
+		return nil
+	}
 
-				err := a.accountService.SellAsset(asset.ID.Hex(), price, currentTime)
+	selection := domain.LotSelection{Method: domain.LotSpecificID}
+	var amount domain.Decimal
 
-				if err != nil {
-					return err
-				}
+	for _, asset := range *assets {
+		if asset.BuyPrice.MulFloat(1.01).LessThan(tradePrice) {
+			selection.AssetIDs = append(selection.AssetIDs, asset.ID)
+			amount = amount.Add(asset.Amount)
+		}
+	}
 
-				err = a.accountService.Deposit(asset.Amount * price)
+	if amount.IsZero() {
+		return nil
+	}
 
-				if err != nil {
-					return err
-				}
+	if a.lotMatching != "" {
+		selection = domain.LotSelection{Method: a.lotMatching}
+	}
 
-				message := fmt.Sprintf("Asset sold: {Price: %v Amount: %v Value: %v, Asset: %v}", price, asset.Amount, price*asset.Amount, a.Asset)
-				a.log("sell", message)
-			}
-		}
+	orderID, err := market.Trader.Sell(amount, tradePrice, currentTime)
+
+	if err != nil {
+		return err
+	}
+
+	trade, err := market.AccountService.SellAmount(amount, tradePrice, selection, currentTime, orderID)
+
+	if err != nil {
+		a.log("Order Not Recorded", fmt.Sprintf("sell order of %v%v*%v$ placed but not recorded in the account: %v", amount.StringFixed(4), a.assetName(market), tradePrice.StringFixed(2), err))
+		return err
 	}
 
+	message := fmt.Sprintf("Asset sold: {Price: %v Amount: %v Value: %v, Asset: %v, Lots: %d, PnL: %v}", tradePrice, trade.Amount, trade.Proceeds, a.assetName(market), len(trade.Lots), trade.RealizedPnL)
+	a.log("sell", message)
+
 	return nil
 }
 
-// OnNewAssetPrice do operations based on asset new price
+// OnNewAssetPrice feeds the indicators of the market of the price asset and do operations based on asset new price
 func (a *App) OnNewAssetPrice(ohlc *domain.OHLC) {
-	a.log("Price change", fmt.Sprintf("%v PRICE: %v", a.Asset, ohlc.Close))
+	market := a.market(ohlc.Asset)
 
-	err := a.DecideToBuy(ohlc.Close, ohlc.Time)
+	if market == nil {
+		return
+	}
 
-	if err != nil {
-		log.Fatal(err)
+	for _, indicator := range market.Indicators {
+		indicator.AddValue(ohlc)
 	}
 
-	err = a.DecideToSell(ohlc.Close, ohlc.Time)
+	a.log("Price change", fmt.Sprintf("%v PRICE: %v", a.assetName(market), ohlc.Close))
 
-	if err != nil {
-		log.Fatal(err)
+	// errors of one price, e.g. an exchange unavailable after the retries, do not stop the application or the others of the process
+	if err := a.DecideToBuy(ohlc.Asset, ohlc.Close, ohlc.Time); err != nil {
+		a.logError("buy", market, err)
+	}
+
+	if err := a.DecideToSell(ohlc.Asset, ohlc.Close, ohlc.Time); err != nil {
+		a.logError("sell", market, err)
 	}
 }
 
+// logError registers an error of a decision, event logs are notified with the reports
+func (a *App) logError(decision string, market *Market, err error) {
+	message := fmt.Sprintf("Not able to %v %v: %v", decision, a.assetName(market), err)
+
+	if a.eventLogsRepository == nil {
+		fmt.Println(message)
+	}
+
+	a.log("Error", message)
+}
+
 // FetchAssets returns all assets
 func (a *App) FetchAssets() (*[]domain.Asset, error) {
 	return a.accountService.FindAllAssets()
 }
 
 // GetAccountAmount returns the account service amount
-func (a *App) GetAccountAmount() (float32, error) {
+func (a *App) GetAccountAmount() (domain.Decimal, error) {
 	return a.accountService.GetAmount()
 }
 
@@ -179,8 +323,8 @@ func (a *App) GetState() interface{} {
 	accountAmount, _ := a.GetAccountAmount()
 
 	accountState := struct {
-		AccountAmount float32 `json:"accountAmount"`
-	}{AccountAmount: accountAmount}
+		AccountAmount float32 `bson:"accountAmount" json:"accountAmount"`
+	}{AccountAmount: accountAmount.Float32()}
 
 	return accountState
 }
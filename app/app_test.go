@@ -0,0 +1,162 @@
+package app_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/eventlogs"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/golang/mock/gomock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// indicatorSpy counts the values added
+type indicatorSpy struct {
+	values int
+}
+
+func (i *indicatorSpy) AddValue(ohlc *domain.OHLC) { i.values++ }
+
+func (i *indicatorSpy) GetState() interface{} { return nil }
+
+func newAccountService(t *testing.T, amount int64) *accounts.AccountService {
+	storage := db.NewMemoryStorage()
+	account, _ := accounts.OpenAccount(storage.UnitOfWork, "kraken", domain.NewDecimalFromInt(amount), time.Now())
+
+	service, err := accounts.NewAccountService(
+		account.ID.Hex(),
+		accounts.NewRepository(storage.Repositories(db.ACCOUNTS_COLLECTION)),
+		assets.NewRepository(storage.Repositories(db.ASSETS_COLLECTION)),
+		accounts.NewLedgerRepository(storage.Repositories(db.LEDGER_COLLECTION)),
+		storage.UnitOfWork,
+	)
+
+	if err != nil {
+		t.Fatalf("Not expected NewAccountService to return error: %v", err)
+	}
+
+	return service
+}
+
+func TestMultiAssetApp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountService := newAccountService(t, 1000)
+	btcDecisionMaker, ethDecisionMaker := mocks.NewMockDecisionMaker(ctrl), mocks.NewMockDecisionMaker(ctrl)
+	btcTrader, ethTrader := mocks.NewMockTrader(ctrl), mocks.NewMockTrader(ctrl)
+	btcIndicator, ethIndicator := &indicatorSpy{}, &indicatorSpy{}
+
+	btc := &app.Market{
+		Asset:          "BTC",
+		DecisionMaker:  btcDecisionMaker,
+		Trader:         btcTrader,
+		AccountService: accountService.ForAsset("BTC"),
+		Indicators:     []domain.Indicator{btcIndicator},
+		MaxAllocation:  domain.NewDecimalFromInt(250),
+	}
+	eth := &app.Market{
+		Asset:          "ETH",
+		DecisionMaker:  ethDecisionMaker,
+		Trader:         ethTrader,
+		AccountService: accountService.ForAsset("ETH"),
+		Indicators:     []domain.Indicator{ethIndicator},
+	}
+
+	application := app.NewMultiAssetApp(&[]domain.Collector{}, accountService, []*app.Market{btc, eth})
+	now := time.Now()
+
+	t.Run("should buy within the allocation cap of the asset", func(t *testing.T) {
+		btcDecisionMaker.EXPECT().ShouldBuy().Return(true, float32(2), nil).Times(2)
+		btcTrader.EXPECT().Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(100), now).Return("1", nil).Times(1)
+
+		for i := 0; i < 2; i++ {
+			if err := application.DecideToBuy("BTC", domain.NewDecimalFromInt(100), now); err != nil {
+				t.Fatalf("Not expected DecideToBuy to return error: %v", err)
+			}
+		}
+
+		lots, _ := btc.AccountService.FindPendingAssets()
+
+		if len(*lots) != 1 {
+			t.Errorf("got %d BTC lots want 1", len(*lots))
+		}
+	})
+
+	t.Run("should not buy more than the account amount shared by the assets", func(t *testing.T) {
+		ethDecisionMaker.EXPECT().ShouldBuy().Return(true, float32(10), nil)
+
+		if err := application.DecideToBuy("ETH", domain.NewDecimalFromInt(90), now); err != nil {
+			t.Fatalf("Not expected DecideToBuy to return error: %v", err)
+		}
+
+		ethDecisionMaker.EXPECT().ShouldBuy().Return(true, float32(5), nil)
+		ethTrader.EXPECT().Buy(domain.NewDecimalFromInt(5), domain.NewDecimalFromInt(100), now).Return("1", nil)
+
+		if err := application.DecideToBuy("ETH", domain.NewDecimalFromInt(100), now); err != nil {
+			t.Fatalf("Not expected DecideToBuy to return error: %v", err)
+		}
+
+		if got, _ := application.GetAccountAmount(); got.String() != "300" {
+			t.Errorf("got account amount %v want 300", got)
+		}
+	})
+
+	t.Run("should feed the indicators of the asset of the price", func(t *testing.T) {
+		ethDecisionMaker.EXPECT().ShouldBuy().Return(false, float32(0), nil)
+		ethDecisionMaker.EXPECT().ShouldSell().Return(false, float32(0), nil)
+
+		application.OnNewAssetPrice(&domain.OHLC{Asset: "ETH", Close: domain.NewDecimal(100), Time: now})
+		application.OnNewAssetPrice(&domain.OHLC{Asset: "ADA", Close: domain.NewDecimal(1), Time: now})
+
+		if btcIndicator.values != 0 || ethIndicator.values != 1 {
+			t.Errorf("got %d BTC and %d ETH values want 0 and 1", btcIndicator.values, ethIndicator.values)
+		}
+	})
+
+	t.Run("should not place the order when the account does not have the exchange fee", func(t *testing.T) {
+		accountService := newAccountService(t, 1001).WithFeeRate(0.01)
+		decisionMaker, trader := mocks.NewMockDecisionMaker(ctrl), mocks.NewMockTrader(ctrl)
+		market := &app.Market{Asset: "BTC", DecisionMaker: decisionMaker, Trader: trader, AccountService: accountService.ForAsset("BTC")}
+		application := app.NewMultiAssetApp(&[]domain.Collector{}, accountService, []*app.Market{market})
+
+		decisionMaker.EXPECT().ShouldBuy().Return(true, float32(10), nil)
+		trader.EXPECT().Buy(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		if err := application.DecideToBuy("BTC", domain.NewDecimalFromInt(100), now); err != nil {
+			t.Fatalf("Not expected DecideToBuy to return error: %v", err)
+		}
+
+		if got, _ := application.GetAccountAmount(); got.String() != "1001" {
+			t.Errorf("got account amount %v want 1001", got)
+		}
+	})
+
+	t.Run("should keep running when an order fails", func(t *testing.T) {
+		eventLogs := eventlogs.NewEventLogsRepository(db.NewMemoryRepository(), primitive.NewObjectID())
+		application.SetEventsLog(eventLogs)
+
+		ethDecisionMaker.EXPECT().ShouldBuy().Return(true, float32(1), nil)
+		ethTrader.EXPECT().Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(100), now).Return("", errors.New("EService:Unavailable"))
+		ethDecisionMaker.EXPECT().ShouldSell().Return(false, float32(0), nil)
+
+		application.OnNewAssetPrice(&domain.OHLC{Asset: "ETH", Close: domain.NewDecimal(100), Time: now})
+
+		logs, _ := eventLogs.FindAll(bson.M{"eventname": "Error"})
+
+		if len(*logs) != 1 {
+			t.Errorf("got %d error event logs want 1", len(*logs))
+		}
+
+		if got, _ := application.GetAccountAmount(); got.String() != "300" {
+			t.Errorf("got account amount %v want 300", got)
+		}
+	})
+}
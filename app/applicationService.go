@@ -3,6 +3,7 @@ package app
 import (
 	"time"
 
+	"github.com/fabiodmferreira/crypto-trading/application-execution-states"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/utils"
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,6 +17,8 @@ type Service struct {
 	stateRepo         domain.ApplicationExecutionStateRepository
 	logEventsRepo     domain.EventsLog
 	notificationsRepo domain.NotificationsRepository
+	stateRollupsRepo  domain.ApplicationExecutionStateRepository
+	retentionPolicy   domain.StatesRetentionPolicy
 }
 
 // NewService returns an instance of applications service
@@ -23,8 +26,10 @@ func NewService(
 	repo *Repository,
 	stateRepo domain.ApplicationExecutionStateRepository,
 	eventsLogRepo domain.EventsLog,
-	notificationsRepo domain.NotificationsRepository) *Service {
-	return &Service{repo, stateRepo, eventsLogRepo, notificationsRepo}
+	notificationsRepo domain.NotificationsRepository,
+	stateRollupsRepo domain.ApplicationExecutionStateRepository,
+	retentionPolicy domain.StatesRetentionPolicy) *Service {
+	return &Service{repo, stateRepo, eventsLogRepo, notificationsRepo, stateRollupsRepo, retentionPolicy}
 }
 
 // GetLastState returns the last application state
@@ -65,6 +70,8 @@ func (a *Service) GetLogEvents(appID primitive.ObjectID) (*[]domain.EventLog, er
 	return a.logEventsRepo.FindAll(bson.M{"applicationID": appID})
 }
 
+// GetStateAggregated returns the application states between two dates grouped by date.
+// States are read from hourly or daily rollups when the dates range is large or raw states already expired.
 func (a *Service) GetStateAggregated(appID string, startDate, endDate time.Time) (*[]bson.M, error) {
 	groupByDatesClause := utils.GetGroupByDatesIDClause(startDate, endDate)
 
@@ -74,6 +81,27 @@ func (a *Service) GetStateAggregated(appID string, startDate, endDate time.Time)
 		return nil, err
 	}
 
+	resolution := a.retentionPolicy.Resolution(startDate, endDate, time.Now())
+
+	if resolution != domain.StatesResolutionRaw && a.stateRollupsRepo != nil {
+		match := bson.M{
+			"executionId": oid,
+			"resolution":  resolution,
+			"date":        bson.M{"$gte": startDate, "$lte": endDate},
+		}
+
+		states, err := a.stateRollupsRepo.Aggregate(applicationExecutionStates.AggregateRollupsPipeline(match, groupByDatesClause))
+
+		if err != nil {
+			return nil, err
+		}
+
+		// benchmarks states are not summarized
+		if len(*states) > 0 {
+			return states, nil
+		}
+	}
+
 	pipelineOptions := mongo.Pipeline{
 		{
 			primitive.E{
@@ -45,6 +45,17 @@ func (r *Repository) Create(asset string, options domain.ApplicationOptions, acc
 	return &app, err
 }
 
+// UpdateOptions replaces the options of an application
+func (r *Repository) UpdateOptions(id string, options domain.ApplicationOptions, updatedBy string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+
+	if err != nil {
+		return err
+	}
+
+	return r.repo.UpdateOne(bson.M{"_id": oid}, bson.M{"$set": bson.M{"options": options, "updatedBy": updatedBy}})
+}
+
 // FindAll returns all applications
 func (r *Repository) FindAll() (*[]domain.Application, error) {
 	var applications []domain.Application
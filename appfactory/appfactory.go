@@ -13,75 +13,211 @@ import (
 	"github.com/fabiodmferreira/crypto-trading/app"
 	"github.com/fabiodmferreira/crypto-trading/assets"
 	"github.com/fabiodmferreira/crypto-trading/assetsprices"
+	"github.com/fabiodmferreira/crypto-trading/binance"
 	"github.com/fabiodmferreira/crypto-trading/broker"
+	"github.com/fabiodmferreira/crypto-trading/collectors"
 	"github.com/fabiodmferreira/crypto-trading/db"
 	"github.com/fabiodmferreira/crypto-trading/decisionmaker"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/eventlogs"
 	"github.com/fabiodmferreira/crypto-trading/indicators"
+	"github.com/fabiodmferreira/crypto-trading/marketdata"
+	"github.com/fabiodmferreira/crypto-trading/markets"
 	"github.com/fabiodmferreira/crypto-trading/notifications"
+	"github.com/fabiodmferreira/crypto-trading/reconciliation"
+	"github.com/fabiodmferreira/crypto-trading/shadow"
 	"github.com/fabiodmferreira/crypto-trading/trader"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func SetupApplication(appMetaData *domain.Application, mongoDatabase *mongo.Database, broker domain.Broker, collector domain.Collector) (*app.App, error) {
+// SetupApplication returns an application that trades every asset of the application metadata against its account.
+// Each asset has its own indicators, decision maker and trader, and the collector must publish the prices of every asset.
+// Prices are not stored by the application, the collector is expected to store them.
+// Lots and states are tagged with the options version passed by argument.
+func SetupApplication(appMetaData *domain.Application, optionsVersion int, storage *db.Storage, broker domain.Broker, collector domain.Collector) (*app.App, error) {
+	repositories := storage.Repositories
+
 	// Setup repositories
-	assetsCollection := mongoDatabase.Collection(db.ASSETS_COLLECTION)
-	assetsRepository := assets.NewRepository(db.NewRepository(assetsCollection))
+	assetsRepository := assets.NewRepository(repositories(db.ASSETS_COLLECTION))
+
+	accountsRepository := accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION))
 
-	accountsCollection := mongoDatabase.Collection(db.ACCOUNTS_COLLECTION)
-	accountsRepository := accounts.NewRepository(db.NewRepository(accountsCollection))
+	ledgerRepository := accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION))
 
-	applicationExecutionStateCollection := mongoDatabase.Collection(db.APPLICATION_EXECUTION_STATES_COLLECTION)
-	applicationExecutionStateRepository := db.NewRepository(applicationExecutionStateCollection)
+	applicationExecutionStateRepository := repositories(db.APPLICATION_EXECUTION_STATES_COLLECTION)
 
 	// Setup services
-	accountService, err := accounts.NewAccountService(appMetaData.AccountID.Hex(), accountsRepository, assetsRepository)
+	accountService, err := accounts.NewAccountService(appMetaData.AccountID.Hex(), accountsRepository, assetsRepository, ledgerRepository, storage.UnitOfWork)
 
 	if err != nil {
 		return nil, err
 	}
 
-	eventLogsCollection := mongoDatabase.Collection(db.EVENT_LOGS_COLLECTION)
-	eventLogsRepository := eventlogs.NewEventLogsRepository(db.NewRepository(eventLogsCollection), appMetaData.ID)
-
-	assetsPricesService := setupAssetsPricesService(mongoDatabase)
-	priceIndicator, volumeIndicator, err := setupIndicators(assetsPricesService, appMetaData.Asset, appMetaData.Options.StatisticsOptions)
+	account, err := accountsRepository.FindById(appMetaData.AccountID.Hex())
 
 	if err != nil {
 		return nil, err
 	}
 
-	notificationsService := setupNotificationsService(mongoDatabase, appMetaData.Options.NotificationOptions, appMetaData.ID)
-	decisionMaker := setupDecisionMaker(priceIndicator, volumeIndicator, accountService, appMetaData.Options.DecisionMakerOptions)
-	dbTrader := trader.NewTrader(broker)
+	accountService = accountService.WithOptionsVersion(optionsVersion).WithFeeRate(domain.ExchangeFeeRate(account.Broker))
+
+	eventLogsRepository := eventlogs.NewEventLogsRepository(repositories(db.EVENT_LOGS_COLLECTION), appMetaData.ID)
+
+	assetsPricesService := setupAssetsPricesService(repositories)
+	markets := []*app.Market{}
+
+	for _, allocation := range appMetaData.GetAssets() {
+		priceIndicator, volumeIndicator, err := setupIndicators(assetsPricesService, domain.PriceSymbol(allocation.Asset, appMetaData.GetQuote()), appMetaData.Options.StatisticsOptions)
+
+		if err != nil {
+			return nil, err
+		}
+
+		assetAccountService := accountService.ForAsset(allocation.Asset)
+
+		markets = append(markets, &app.Market{
+			Asset:          allocation.Asset,
+			DecisionMaker:  setupDecisionMaker(priceIndicator, volumeIndicator, assetAccountService, appMetaData.Options.DecisionMakerOptions),
+			Trader:         trader.NewAssetTrader(broker, allocation.Asset),
+			AccountService: assetAccountService,
+			Indicators:     []domain.Indicator{priceIndicator, volumeIndicator},
+			MaxAllocation:  allocation.MaxAllocation,
+		})
+	}
+
+	// misconfigured channels fail here instead of when the first report is sent
+	if err := appMetaData.Options.NotificationOptions.Validate(); err != nil {
+		return nil, fmt.Errorf("Not able to setup notifications of application %v: %v", appMetaData.ID.Hex(), err)
+	}
+
+	notificationsService := setupNotificationsService(repositories, appMetaData.Options.NotificationOptions, appMetaData.ID)
 
 	// Create application
-	application := app.NewApp(&[]domain.Collector{collector}, decisionMaker, dbTrader, accountService)
+	application := app.NewMultiAssetApp(&[]domain.Collector{collector}, accountService, markets)
 	application.Asset = appMetaData.Asset
+	application.SetLotMatching(appMetaData.Options.LotMatching)
 	application.SetEventsLog(eventLogsRepository)
 
 	// Regist events
 	collector.Regist(NotificationJob(notificationsService, eventLogsRepository, accountService))
-	collector.Regist(SaveAssetPrice(appMetaData.Asset, assetsPricesService))
-	collector.Regist(SaveApplicationState(appMetaData.ID, application, applicationExecutionStateRepository))
+	collector.Regist(SaveApplicationState(appMetaData.ID, optionsVersion, application, applicationExecutionStateRepository, domain.DefaultStatesRetentionPolicy.RawRetention))
 
 	return application, nil
 }
 
-func FindOrCreateAppMetaData(env domain.Env, applicationsRepository domain.ApplicationRepository, accountsRepository domain.AccountsRepository) (*domain.Application, error) {
+// SetupReconciliation returns the service that reconciles the exchange account of the application with the accounts of every live application of the exchange.
+// Applications of the same exchange share its API key, so the exchange balances are compared with the sum of their accounts.
+func SetupReconciliation(appMetaData *domain.Application, exchange string, storage *db.Storage, applicationsRepository domain.ApplicationRepository, brokerAccount domain.BrokerAccount, options reconciliation.Options) *reconciliation.Service {
+	eventLogsRepository := eventlogs.NewEventLogsRepository(storage.Repositories(db.EVENT_LOGS_COLLECTION), appMetaData.ID)
+
+	return reconciliation.NewService(brokerAccount, exchangeBooks(exchange, storage, applicationsRepository), eventLogsRepository, appMetaData.GetQuote(), options)
+}
+
+// exchangeBooks returns the books of the accounts of the live applications of the exchange, applications sharing an account share its book
+func exchangeBooks(exchange string, storage *db.Storage, applicationsRepository domain.ApplicationRepository) reconciliation.Books {
+	return func() ([]reconciliation.Book, error) {
+		applications, err := applicationsRepository.FindAll()
+
+		if err != nil {
+			return nil, err
+		}
+
+		repositories := storage.Repositories
+		accountsRepository := accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION))
+		books := []reconciliation.Book{}
+		bookIndexes := map[primitive.ObjectID]int{}
+
+		for _, application := range *applications {
+			if application.IsSimulated() {
+				continue
+			}
+
+			account, err := accountsRepository.FindById(application.AccountID.Hex())
+
+			if err != nil {
+				return nil, err
+			}
+
+			// accounts opened before binance was supported do not have a broker
+			broker := account.Broker
+
+			if broker == "" {
+				broker = domain.ExchangeKraken
+			}
+
+			if broker != exchange {
+				continue
+			}
+
+			if i, ok := bookIndexes[application.AccountID]; ok {
+				books[i].Assets = appendMissing(books[i].Assets, applicationAssets(&application)...)
+				continue
+			}
+
+			accountService, err := accounts.NewAccountService(
+				application.AccountID.Hex(),
+				accountsRepository,
+				assets.NewRepository(repositories(db.ASSETS_COLLECTION)),
+				accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION)),
+				storage.UnitOfWork,
+			)
+
+			if err != nil {
+				return nil, err
+			}
+
+			bookIndexes[application.AccountID] = len(books)
+			books = append(books, reconciliation.Book{AccountService: accountService, Assets: applicationAssets(&application), Currency: application.GetQuote()})
+		}
+
+		return books, nil
+	}
+}
+
+// applicationAssets returns the assets of an application starting with the asset of the lots without symbol,
+// they were bought before applications traded several assets
+func applicationAssets(application *domain.Application) []string {
+	assets := []string{}
+
+	if application.Asset != "" {
+		assets = append(assets, application.Asset)
+	}
+
+	for _, allocation := range application.GetAssets() {
+		assets = appendMissing(assets, allocation.Asset)
+	}
+
+	return assets
+}
+
+func appendMissing(values []string, others ...string) []string {
+	for _, other := range others {
+		found := false
+
+		for _, value := range values {
+			found = found || value == other
+		}
+
+		if !found {
+			values = append(values, other)
+		}
+	}
+
+	return values
+}
+
+func FindOrCreateAppMetaData(env domain.Env, applicationsRepository domain.ApplicationRepository, unitOfWork domain.UnitOfWork) (*domain.Application, error) {
 	var appMetaData *domain.Application
 	var err error
 
 	if env.AppID == "" {
-		notificationOptions := domain.NotificationOptions{
-			Receiver:       env.NotificationsReceiver,
-			Sender:         env.NotificationsSender,
-			SenderPassword: env.NotificationsSenderPassword,
+		notificationOptions, err := env.NotificationOptions()
+
+		if err != nil {
+			return nil, err
 		}
 
-		appMetaData, err = CreateDefaultAppMetadata(notificationOptions, applicationsRepository, accountsRepository)
+		appMetaData, err = CreateDefaultAppMetadata(notificationOptions, applicationsRepository, unitOfWork)
 
 		if err != nil {
 			log.Fatalf("Not able to create a new application due to %v", err)
@@ -101,25 +237,23 @@ func FindOrCreateAppMetaData(env domain.Env, applicationsRepository domain.Appli
 	return appMetaData, nil
 }
 
-func SaveAssetPrice(asset string, assetsPricesService domain.AssetsPricesService) domain.OnNewAssetPrice {
-	return func(ohlc *domain.OHLC) {
-		assetsPricesService.Create(ohlc, asset)
-	}
-}
-
-func SaveApplicationState(ID primitive.ObjectID, application *app.App, applicationExecutionStateRepository domain.Repository) domain.OnNewAssetPrice {
+// SaveApplicationState stores the application state tagged with the options version on each price change. States are deleted after the retention passed by argument.
+func SaveApplicationState(ID primitive.ObjectID, optionsVersion int, application *app.App, applicationExecutionStateRepository domain.Repository, retention time.Duration) domain.OnNewAssetPrice {
 	return func(ohlc *domain.OHLC) {
+		expireAt := time.Now().Add(retention)
 		state := domain.ApplicationExecutionState{
-			ID:          primitive.NewObjectID(),
-			ExecutionID: ID,
-			Date:        ohlc.Time,
-			State:       application.GetState(),
+			ID:             primitive.NewObjectID(),
+			ExecutionID:    ID,
+			Date:           ohlc.Time,
+			State:          application.GetState(),
+			ExpireAt:       &expireAt,
+			OptionsVersion: optionsVersion,
 		}
 		applicationExecutionStateRepository.InsertOne(state)
 	}
 }
 
-func CreateDefaultAppMetadata(notificationOptions domain.NotificationOptions, repository domain.ApplicationRepository, accountsRepository domain.AccountsRepository) (*domain.Application, error) {
+func CreateDefaultAppMetadata(notificationOptions domain.NotificationOptions, repository domain.ApplicationRepository, unitOfWork domain.UnitOfWork) (*domain.Application, error) {
 	options := domain.ApplicationOptions{
 		NotificationOptions: notificationOptions,
 		StatisticsOptions:   domain.StatisticsOptions{NumberOfPointsHold: 5000},
@@ -136,7 +270,7 @@ func CreateDefaultAppMetadata(notificationOptions domain.NotificationOptions, re
 		},
 	}
 
-	account, err := accountsRepository.Create("kraken", 5000)
+	account, err := accounts.OpenAccount(unitOfWork, "kraken", domain.NewDecimalFromInt(5000), time.Now())
 
 	if err != nil {
 		return nil, err
@@ -145,10 +279,8 @@ func CreateDefaultAppMetadata(notificationOptions domain.NotificationOptions, re
 	return repository.Create("BTC", options, account.ID)
 }
 
-func setupNotificationsService(mongoDatabase *mongo.Database, notificationOptions domain.NotificationOptions, appID primitive.ObjectID) domain.NotificationsService {
-	notificationsCollection := mongoDatabase.Collection(db.NOTIFICATIONS_COLLECTION)
-
-	notificationsRepository := notifications.NewRepository(db.NewRepository(notificationsCollection))
+func setupNotificationsService(repositories domain.RepositoryFactory, notificationOptions domain.NotificationOptions, appID primitive.ObjectID) domain.NotificationsService {
+	notificationsRepository := notifications.NewRepository(repositories(db.NOTIFICATIONS_COLLECTION))
 
 	return notifications.NewService(
 		notificationsRepository,
@@ -240,7 +372,7 @@ func NotificationJob(
 func sendReport(notificationsService domain.NotificationsService, message *bytes.Buffer) error {
 	subject := "Crypto-Trading: Report"
 
-	err := notificationsService.CreateEmailNotification(subject, message.String(), "eventlogs")
+	err := notificationsService.Notify(subject, message.String(), "eventlogs")
 
 	if err != nil {
 		fmt.Println(err)
@@ -265,36 +397,44 @@ func markLogsEventsAsNotified(eventLogsRepository domain.EventsLog, eventLogs *[
 
 func getLastAssetsPrices(asset string, numberOfPoints int, assetsPricesService domain.AssetsPricesService) (*[]domain.AssetPrice, error) {
 	fmt.Println("Fetching assets prices from coindesk...")
-	assetsPricesService.FetchAndStoreAssetPrices(asset, time.Now())
-	fmt.Println("Completed")
+	result, err := assetsPricesService.FetchAndStoreAssetPrices(asset, time.Now())
+
+	if err != nil {
+		fmt.Printf("Not able to fetch all assets prices: %v\n", err)
+	}
+
+	fmt.Printf("Completed: %d inserted, %d skipped\n", result.Inserted, result.Skipped)
 
-	return assetsPricesService.GetLastAssetsPrices(asset, numberOfPoints)
+	return assetsPricesService.GetLastAssetsPrices(asset, assetsprices.CoindeskInterval, numberOfPoints)
 }
 
 func appendAssetsPricesToStatistics(priceIndicator *indicators.PriceIndicator, lastAssetsPrices *[]domain.AssetPrice) {
-	points := []float64{}
-	for _, assetPrice := range *lastAssetsPrices {
-		points = append(points, float64(assetPrice.Close))
-	}
-	nPoints := len(points)
-	for i := nPoints - 1; i >= 0; i-- {
+	for i := len(*lastAssetsPrices) - 1; i >= 0; i-- {
+		price := (*lastAssetsPrices)[i].Close
 		priceIndicator.AddValue(
 			&domain.OHLC{
-				Close:  float32(points[i]),
-				Open:   float32(points[i]),
-				High:   float32(points[i]),
-				Low:    float32(points[i]),
-				Volume: 0,
+				Close: price,
+				Open:  price,
+				High:  price,
+				Low:   price,
 			},
 		)
 	}
 }
 
-func setupAssetsPricesService(mongoDatabase *mongo.Database) domain.AssetsPricesService {
-	assetsPricesCollection := mongoDatabase.Collection(db.ASSETS_PRICES_COLLECTION)
-	assetsPricesRepository := assetsprices.NewRepository(db.NewRepository(assetsPricesCollection))
+// SetupMarketDataHub returns the hub of the exchanges prices of the applications, it stores each price received
+func SetupMarketDataHub(storage *db.Storage, exchanges Exchanges) *marketdata.Hub {
+	newCollector := func(exchange, quote, asset string, interval int) (domain.Collector, error) {
+		return NewExchangeCollector(exchange, quote, []string{asset}, domain.CollectorOptions{NewPriceTimeRate: interval}, exchanges)
+	}
 
-	return assetsprices.NewService(assetsPricesRepository, assetsprices.NewCoindeskRemoteSource(http.Get).FetchRemoteAssetsPrices)
+	return marketdata.NewHub(newCollector, setupAssetsPricesService(storage.Repositories), marketdata.DefaultRestartDelay)
+}
+
+func setupAssetsPricesService(repositories domain.RepositoryFactory) domain.AssetsPricesService {
+	assetsPricesRepository := assetsprices.NewRepository(repositories(db.ASSETS_PRICES_COLLECTION))
+
+	return assetsprices.NewService(assetsPricesRepository, assetsprices.NewCoindeskRemoteSource(http.Get).FetchRemoteAssetsPrices, assetsprices.CoindeskInterval)
 }
 
 func setupIndicators(assetsPricesService domain.AssetsPricesService, asset string, statisticsOptions domain.StatisticsOptions) (*indicators.PriceIndicator, *indicators.VolumeIndicator, error) {
@@ -312,13 +452,115 @@ func setupIndicators(assetsPricesService domain.AssetsPricesService, asset strin
 	return priceIndicator, volumeIndicator, nil
 }
 
-func GetBroker(appEnv string, krakenAPI *krakenapi.KrakenAPI) domain.Broker {
-	var brokerService domain.Broker
-	if appEnv == "production" {
-		brokerService = broker.NewKrakenBroker(krakenAPI)
-	} else {
+// Exchanges are the clients of the exchanges supported and the service of their markets
+type Exchanges struct {
+	Kraken  *krakenapi.KrakenAPI
+	Binance *binance.Client
+	Markets domain.MarketsService
+	// Limiters are shared by the brokers of each exchange
+	Limiters map[string]*broker.RateLimiter
+}
+
+// NewExchanges returns the exchanges of the clients passed by argument with a markets service that loads their pairs.
+// Kraken requests are limited by the API counter of the kraken account tier.
+func NewExchanges(krakenAPI *krakenapi.KrakenAPI, krakenLimit broker.RateLimit, binanceClient *binance.Client) Exchanges {
+	loaders := map[string]markets.Loader{
+		domain.ExchangeKraken:  markets.Kraken(krakenAPI),
+		domain.ExchangeBinance: markets.Binance(binanceClient),
+	}
+
+	return Exchanges{
+		Kraken:  krakenAPI,
+		Binance: binanceClient,
+		Markets: markets.NewService(loaders, markets.DefaultCacheDuration),
+		Limiters: map[string]*broker.RateLimiter{
+			domain.ExchangeKraken:  broker.NewRateLimiter(krakenLimit),
+			domain.ExchangeBinance: broker.NewRateLimiter(broker.BinanceRateLimit),
+		},
+	}
+}
+
+// GetBroker returns the broker of the application mode. Live applications use the broker of the exchange in production, otherwise a broker mock.
+func GetBroker(appEnv string, metadata *domain.Application, exchange string, exchanges Exchanges, repositories domain.RepositoryFactory) (domain.Broker, error) {
+	if err := metadata.ValidateMode(); err != nil {
+		return nil, err
+	}
+
+	switch metadata.GetMode() {
+	case domain.ModeShadow:
+		return broker.NewShadowBroker(metadata.ID, shadow.NewOrdersRepository(repositories(db.SHADOW_ORDERS_COLLECTION))), nil
+	case domain.ModePaper:
+		return broker.NewBrokerMock(), nil
+	}
+
+	if appEnv != "production" {
 		fmt.Println("Broker mocked!")
-		brokerService = broker.NewBrokerMock()
+		return broker.NewBrokerMock(), nil
 	}
-	return brokerService
+
+	return NewExchangeBroker(exchange, exchanges)
+}
+
+// NewExchangeBroker returns the broker of the exchange, an empty exchange is kraken.
+// Requests are rate limited and retried when they fail by transient errors.
+func NewExchangeBroker(exchange string, exchanges Exchanges) (domain.Broker, error) {
+	var exchangeBroker broker.ExchangeBroker
+
+	switch exchange {
+	case "", domain.ExchangeKraken:
+		exchange = domain.ExchangeKraken
+		exchangeBroker = broker.NewKrakenBroker(exchanges.Kraken, exchanges.Markets)
+	case domain.ExchangeBinance:
+		exchangeBroker = broker.NewBinanceBroker(exchanges.Binance, exchanges.Markets)
+	default:
+		return nil, fmt.Errorf("exchange %v is not supported", exchange)
+	}
+
+	return broker.NewResilientBroker(exchangeBroker, exchanges.Limiters[exchange], broker.DefaultRetryOptions), nil
+}
+
+// NewExchangeCollector returns a collector of the prices of the assets in the quote currency of the exchange, an empty exchange is kraken.
+// It returns error when the exchange does not trade an asset with the quote currency.
+func NewExchangeCollector(exchange, quote string, assets []string, options domain.CollectorOptions, exchanges Exchanges) (domain.Collector, error) {
+	assetsMarkets, err := FindMarkets(exchanges.Markets, exchange, quote, assets)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch exchange {
+	case "", domain.ExchangeKraken:
+		return collectors.NewKrakenCollector(assetsMarkets, options, exchanges.Kraken, &[]domain.Indicator{}), nil
+	case domain.ExchangeBinance:
+		collector, err := collectors.NewBinanceCollector(assetsMarkets, options, exchanges.Binance, &[]domain.Indicator{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return collector, nil
+	default:
+		return nil, fmt.Errorf("exchange %v is not supported", exchange)
+	}
+}
+
+// FindMarkets returns the markets of the assets in the quote currency of the exchange or an error when one of them is not traded
+func FindMarkets(marketsService domain.MarketsService, exchange, quote string, assets []string) ([]domain.Market, error) {
+	if err := domain.ValidateQuote(quote); err != nil {
+		return nil, err
+	}
+
+	assetsMarkets := []domain.Market{}
+
+	for _, asset := range assets {
+		market, err := marketsService.Market(exchange, asset, quote)
+
+		if err != nil {
+			return nil, err
+		}
+
+		assetsMarkets = append(assetsMarkets, *market)
+	}
+
+	return assetsMarkets, nil
 }
@@ -3,13 +3,17 @@ package appkeeper
 import (
 	"context"
 	"fmt"
+	"time"
 
-	krakenapi "github.com/beldur/kraken-go-api-client"
+	"github.com/fabiodmferreira/crypto-trading/accounts"
 	"github.com/fabiodmferreira/crypto-trading/app"
 	"github.com/fabiodmferreira/crypto-trading/appfactory"
-	"github.com/fabiodmferreira/crypto-trading/collectors"
+	"github.com/fabiodmferreira/crypto-trading/appoptions"
+	"github.com/fabiodmferreira/crypto-trading/broker"
 	"github.com/fabiodmferreira/crypto-trading/db"
 	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/marketdata"
+	"github.com/fabiodmferreira/crypto-trading/reconciliation"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -22,34 +26,83 @@ type mongoQuery struct {
 // AppKeeper manages algorithm applications state by starting and stopping them
 type AppKeeper struct {
 	applications     map[string]*app.App
-	mongoDatabase    *mongo.Database
-	krakenAPI        *krakenapi.KrakenAPI
+	storage          *db.Storage
+	exchanges        appfactory.Exchanges
 	appEnv           string
 	applicationsRepo domain.ApplicationRepository
+	pollInterval     time.Duration
+	reconciliation   reconciliationSettings
+	marketData       *marketdata.Hub
+	optionsVersions  domain.OptionsVersionsService
+}
+
+type reconciliationSettings struct {
+	interval time.Duration
+	options  reconciliation.Options
+	// stops are closed to stop the reconciliation of the applications
+	stops map[string]chan struct{}
 }
 
 // NewAppKeeper returns an instance of AppKeeper
-func NewAppKeeper(db *mongo.Database, krakenAPI *krakenapi.KrakenAPI, applicationRepo domain.ApplicationRepository) *AppKeeper {
+func NewAppKeeper(storage *db.Storage, exchanges appfactory.Exchanges, applicationRepo domain.ApplicationRepository) *AppKeeper {
 	return &AppKeeper{
 		applications:     map[string]*app.App{},
-		mongoDatabase:    db,
-		krakenAPI:        krakenAPI,
+		storage:          storage,
+		exchanges:        exchanges,
 		applicationsRepo: applicationRepo,
+		reconciliation:   reconciliationSettings{stops: map[string]chan struct{}{}},
+		marketData:       appfactory.SetupMarketDataHub(storage, exchanges),
+		optionsVersions:  appoptions.NewService(applicationRepo, appoptions.NewVersionsRepository(storage.Repositories(db.OPTIONS_VERSIONS_COLLECTION))),
 	}
 }
 
+// MarketDataHub returns the hub that collects the prices of every application
+func (ak *AppKeeper) MarketDataHub() *marketdata.Hub {
+	return ak.marketData
+}
+
 // SetAppEnv sets the application environment variable that decides whether the broker API should be mocked
 func (ak *AppKeeper) SetAppEnv(appEnv string) {
 	ak.appEnv = appEnv
 }
 
-// Initialize starts events listener that helps AppKeeper to keep applications state consistent with database
-func (ak *AppKeeper) Initialize() {
-	applicationsCollection := ak.mongoDatabase.Collection(db.APPLICATIONS_COLLECTION)
+// SetPollInterval makes AppKeeper poll applications changes instead of using mongo change streams, which need a replica set
+func (ak *AppKeeper) SetPollInterval(interval time.Duration) {
+	ak.pollInterval = interval
+}
+
+// SetReconciliation makes AppKeeper reconcile the accounts of the applications with the exchange account periodically.
+// It only applies to brokers able to read the exchange account.
+func (ak *AppKeeper) SetReconciliation(interval time.Duration, options reconciliation.Options) {
+	ak.reconciliation.interval = interval
+	ak.reconciliation.options = options
+}
 
+// Initialize starts events listener that helps AppKeeper to keep applications state consistent with database.
+// Applications are polled when there is no mongo database or a poll interval is set.
+func (ak *AppKeeper) Initialize() {
 	feeder := make(chan bson.M)
 
-	go listenMongoCollectionChanges(applicationsCollection, feeder)
+	if ak.storage.MongoDatabase == nil || ak.pollInterval > 0 {
+		interval := ak.pollInterval
+
+		if interval <= 0 {
+			interval = DefaultPollInterval
+		}
+
+		poller := NewApplicationsPoller(ak.applicationsRepo)
+
+		// applications running were already started
+		if _, err := poller.Poll(); err != nil {
+			fmt.Printf("Not able to poll applications changes: %v\n", err)
+		}
+
+		go poller.Watch(interval, feeder)
+	} else {
+		applicationsCollection := ak.storage.MongoDatabase.Collection(db.APPLICATIONS_COLLECTION)
+
+		go listenMongoCollectionChanges(applicationsCollection, feeder)
+	}
 
 	for {
 		change := <-feeder
@@ -70,30 +123,125 @@ func (ak *AppKeeper) StartApplications(applications *[]domain.Application) error
 	return nil
 }
 
-// StartApplication starts application with metadata passed by argument
+// StartApplication starts application with metadata passed by argument.
+// The application trades in the exchange of the broker of its account.
 func (ak *AppKeeper) StartApplication(metadata *domain.Application) error {
-	brokerService := appfactory.GetBroker(ak.appEnv, ak.krakenAPI)
+	account, err := accounts.NewRepository(ak.storage.Repositories(db.ACCOUNTS_COLLECTION)).FindById(metadata.AccountID.Hex())
+
+	if err != nil {
+		return fmt.Errorf("Not able to find account of application %v: %v", metadata.ID.Hex(), err)
+	}
+
+	// accounts opened before binance was supported do not have a broker
+	exchange := account.Broker
+
+	if exchange == "" {
+		exchange = domain.ExchangeKraken
+	}
+
+	if err := ak.validateShadow(metadata); err != nil {
+		return err
+	}
+
+	// options changed while the application was stopped are versioned when it starts
+	optionsVersion, err := ak.optionsVersions.Record(metadata)
+
+	if err != nil {
+		return fmt.Errorf("Not able to record options of application %v: %v", metadata.ID.Hex(), err)
+	}
+
+	brokerService, err := appfactory.GetBroker(ak.appEnv, metadata, exchange, ak.exchanges, ak.storage.Repositories)
+
+	if err != nil {
+		return err
+	}
+
+	quote := metadata.GetQuote()
+	brokerService.SetQuote(quote)
 
-	collector := collectors.NewKrakenCollector(metadata.Asset, domain.CollectorOptions{NewPriceTimeRate: 1}, ak.krakenAPI, &[]domain.Indicator{})
+	assets := []string{}
+
+	for _, allocation := range metadata.GetAssets() {
+		assets = append(assets, allocation.Asset)
+	}
 
-	application, err := appfactory.SetupApplication(metadata, ak.mongoDatabase, brokerService, collector)
+	// unsupported pairs fail here instead of when the collector is started or orders are placed
+	if _, err := appfactory.FindMarkets(ak.exchanges.Markets, exchange, quote, assets); err != nil {
+		return fmt.Errorf("Not able to start application %v: %v", metadata.ID.Hex(), err)
+	}
+
+	collector := ak.marketData.Subscribe(exchange, quote, assets, 1)
+
+	// orders of shadow applications are filled before the application receives the price
+	if shadowBroker, ok := brokerService.(*broker.ShadowBroker); ok {
+		collector.Regist(shadowBroker.OnNewAssetPrice)
+	}
+
+	application, err := appfactory.SetupApplication(metadata, optionsVersion.Version, ak.storage, brokerService, collector)
 
 	if err != nil {
 		return err
 	}
 
-	go application.Start()
+	go func() {
+		if err := application.Start(); err != nil {
+			fmt.Printf("application %v stopped due to next error: %v\n", metadata.ID.Hex(), err)
+		}
+	}()
 
 	ak.applications[metadata.ID.Hex()] = application
 
+	brokerAccount, ok := brokerService.(domain.BrokerAccount)
+
+	if ak.reconciliation.interval > 0 && ok {
+		ak.startReconciliation(metadata, exchange, brokerAccount)
+	}
+
 	return nil
 }
 
+// validateShadow returns error when a shadow application uses the account of the live application it shadows
+func (ak *AppKeeper) validateShadow(metadata *domain.Application) error {
+	if metadata.GetMode() != domain.ModeShadow || metadata.ShadowOf.IsZero() {
+		return nil
+	}
+
+	live, err := ak.applicationsRepo.FindByID(metadata.ShadowOf.Hex())
+
+	if err != nil {
+		return fmt.Errorf("Not able to find application %v shadowed by %v: %v", metadata.ShadowOf.Hex(), metadata.ID.Hex(), err)
+	}
+
+	if live.AccountID == metadata.AccountID {
+		return fmt.Errorf("shadow application %v must not use the account of application %v", metadata.ID.Hex(), live.ID.Hex())
+	}
+
+	return nil
+}
+
+func (ak *AppKeeper) startReconciliation(metadata *domain.Application, exchange string, brokerAccount domain.BrokerAccount) {
+	service := appfactory.SetupReconciliation(metadata, exchange, ak.storage, ak.applicationsRepo, brokerAccount, ak.reconciliation.options)
+
+	stop := make(chan struct{})
+	ak.reconciliation.stops[metadata.ID.Hex()] = stop
+
+	go service.Schedule(ak.reconciliation.interval, stop)
+}
+
+func (ak *AppKeeper) stopReconciliation(ID string) {
+	if stop, ok := ak.reconciliation.stops[ID]; ok {
+		close(stop)
+		delete(ak.reconciliation.stops, ID)
+	}
+}
+
 func (ak *AppKeeper) restartApp(metadata *domain.Application) error {
 	if application, ok := ak.applications[metadata.ID.Hex()]; ok {
 		application.Stop()
 	}
 
+	ak.stopReconciliation(metadata.ID.Hex())
+
 	err := ak.StartApplication(metadata)
 	if err != nil {
 		return fmt.Errorf("Not able to start application with ID %v due to next error: %v", metadata.ID, err)
@@ -110,6 +258,7 @@ func (ak *AppKeeper) applyCollectionChanges(change bson.M) {
 			application.Stop()
 			ak.applications[metadata.ID.Hex()] = nil
 		}
+		ak.stopReconciliation(metadata.ID.Hex())
 	case "update":
 		var query mongoQuery
 		bsonBytes, _ := bson.Marshal(change["documentKey"])
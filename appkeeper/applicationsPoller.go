@@ -0,0 +1,96 @@
+package appkeeper
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultPollInterval is the interval between applications polls when the storage does not support change streams
+const DefaultPollInterval = 10 * time.Second
+
+// ApplicationsPoller finds the applications periodically and returns the changes since the previous poll
+// with the same format of mongo change streams events.
+type ApplicationsPoller struct {
+	repo         domain.ApplicationRepository
+	applications map[string]domain.Application
+	documents    map[string][]byte
+}
+
+// NewApplicationsPoller returns an instance of ApplicationsPoller
+func NewApplicationsPoller(repo domain.ApplicationRepository) *ApplicationsPoller {
+	return &ApplicationsPoller{repo, map[string]domain.Application{}, map[string][]byte{}}
+}
+
+// Poll returns an insert, replace or delete event for each application changed since the previous poll
+func (p *ApplicationsPoller) Poll() ([]bson.M, error) {
+	applications, err := p.repo.FindAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	events := []bson.M{}
+	found := map[string]bool{}
+
+	for _, application := range *applications {
+		id := application.ID.Hex()
+		found[id] = true
+
+		document, err := bson.Marshal(application)
+
+		if err != nil {
+			return nil, err
+		}
+
+		previous, ok := p.documents[id]
+
+		if !ok {
+			events = append(events, bson.M{"operationType": "insert", "documentKey": bson.M{"_id": application.ID}, "fullDocument": application})
+		} else if !bytes.Equal(previous, document) {
+			events = append(events, bson.M{"operationType": "replace", "documentKey": bson.M{"_id": application.ID}, "fullDocument": application})
+		}
+
+		p.applications[id] = application
+		p.documents[id] = document
+	}
+
+	for id, application := range p.applications {
+		if found[id] {
+			continue
+		}
+
+		events = append(events, bson.M{"operationType": "delete", "documentKey": bson.M{"_id": application.ID}, "fullDocument": application})
+
+		delete(p.applications, id)
+		delete(p.documents, id)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i]["fullDocument"].(domain.Application).ID.Hex() < events[j]["fullDocument"].(domain.Application).ID.Hex()
+	})
+
+	return events, nil
+}
+
+// Watch polls the applications on each interval and sends the changes to the channel passed by argument
+func (p *ApplicationsPoller) Watch(interval time.Duration, ch chan bson.M) {
+	for {
+		time.Sleep(interval)
+
+		events, err := p.Poll()
+
+		if err != nil {
+			fmt.Printf("Not able to poll applications changes: %v\n", err)
+			continue
+		}
+
+		for _, event := range events {
+			ch <- event
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package appkeeper_test
+
+import (
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/appkeeper"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestApplicationsPoller(t *testing.T) {
+	collection := db.NewMemoryRepository()
+	repository := app.NewRepository(collection)
+	poller := appkeeper.NewApplicationsPoller(repository)
+
+	btc, _ := repository.Create("BTC", domain.ApplicationOptions{}, primitive.NewObjectID())
+
+	t.Run("should return inserted applications", func(t *testing.T) {
+		events, err := poller.Poll()
+
+		if err != nil {
+			t.Fatalf("Not expected Poll to return error: %v", err)
+		}
+
+		if len(events) != 1 || events[0]["operationType"] != "insert" {
+			t.Errorf("got %v want one insert event", events)
+		}
+	})
+
+	t.Run("should return nothing when applications did not change", func(t *testing.T) {
+		events, _ := poller.Poll()
+
+		if len(events) != 0 {
+			t.Errorf("got %v want no events", events)
+		}
+	})
+
+	t.Run("should return replaced and deleted applications", func(t *testing.T) {
+		eth, _ := repository.Create("ETH", domain.ApplicationOptions{}, primitive.NewObjectID())
+		poller.Poll()
+
+		collection.UpdateOne(bson.M{"_id": btc.ID}, bson.M{"$set": bson.M{"asset": "ADA"}})
+		repository.DeleteByID(eth.ID.Hex())
+
+		events, _ := poller.Poll()
+		got := map[string]string{}
+
+		for _, event := range events {
+			got[event["operationType"].(string)] = event["fullDocument"].(domain.Application).Asset
+		}
+
+		want := map[string]string{"replace": "ADA", "delete": "ETH"}
+
+		if len(events) != 2 || got["replace"] != want["replace"] || got["delete"] != want["delete"] {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+}
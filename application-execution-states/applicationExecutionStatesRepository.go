@@ -73,6 +73,12 @@ func (r *Repository) BulkDeleteByExecutionID(id string) error {
 	return r.repo.BulkDelete(filter)
 }
 
+// DeleteExpired deletes the documents that expire until the date passed by argument.
+// Mongo TTL indexes delete them too, storages without TTL indexes rely on it.
+func (r *Repository) DeleteExpired(date time.Time) error {
+	return r.repo.BulkDelete(bson.M{"expireAt": bson.M{"$lte": date}})
+}
+
 // FindOne retuns one document of application execution state
 func (r *Repository) FindLast(filter interface{}) (*domain.ApplicationExecutionState, error) {
 	var result domain.ApplicationExecutionState
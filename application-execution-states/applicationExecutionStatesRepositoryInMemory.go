@@ -47,3 +47,8 @@ func (r *RepositoryInMemory) BulkCreate(documents *[]bson.M) error {
 func (r *RepositoryInMemory) BulkDeleteByExecutionID(id string) error {
 	return nil
 }
+
+// DeleteExpired deletes the expired documents
+func (r *RepositoryInMemory) DeleteExpired(date time.Time) error {
+	return nil
+}
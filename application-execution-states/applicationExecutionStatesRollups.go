@@ -0,0 +1,194 @@
+package applicationExecutionStates
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// StateFields are the state fields summarized by rollups
+var StateFields = []string{
+	"price", "priceAverage", "priceStandardDeviation", "priceUpperLimit", "priceLowerLimit",
+	"change", "changeAverage", "changeStandardDeviation", "changeUpperLimit", "changeLowerLimit",
+	"acceleration", "accelerationAverage", "accelerationStandardDeviation", "accelerationUpperLimit", "accelerationLowerLimit",
+	"volume", "volumeAverage", "volumeUpperLimit", "volumeLowerLimit",
+	"open", "close", "high", "low",
+	"accountAmount",
+}
+
+// RollupService summarizes raw application execution states into hourly and daily rollups
+type RollupService struct {
+	statesRepo  domain.ApplicationExecutionStateRepository
+	rollupsRepo domain.ApplicationExecutionStateRepository
+	policy      domain.StatesRetentionPolicy
+}
+
+// NewRollupService returns an instance of RollupService
+func NewRollupService(statesRepo, rollupsRepo domain.ApplicationExecutionStateRepository, policy domain.StatesRetentionPolicy) *RollupService {
+	return &RollupService{statesRepo, rollupsRepo, policy}
+}
+
+// Rollup summarizes the states stored since the date passed by argument.
+// Hours and days already summarized are replaced so it is safe to run it multiple times.
+func (s *RollupService) Rollup(since time.Time) error {
+	_, err := s.statesRepo.Aggregate(HourlyRollupPipeline(since, s.policy.HourlyRetention))
+
+	if err != nil {
+		return fmt.Errorf("hourly rollup: %v", err)
+	}
+
+	_, err = s.rollupsRepo.Aggregate(DailyRollupPipeline(since))
+
+	if err != nil {
+		return fmt.Errorf("daily rollup: %v", err)
+	}
+
+	return nil
+}
+
+// Expire deletes the raw states and the rollups that expire until the date passed by argument,
+// so they do not grow without bound on storages without TTL indexes
+func (s *RollupService) Expire(date time.Time) error {
+	if err := s.statesRepo.DeleteExpired(date); err != nil {
+		return fmt.Errorf("expire states: %v", err)
+	}
+
+	if err := s.rollupsRepo.DeleteExpired(date); err != nil {
+		return fmt.Errorf("expire rollups: %v", err)
+	}
+
+	return nil
+}
+
+// Schedule runs the rollups since the retention of raw states and then periodically since the last run.
+// States and rollups expired are deleted after the rollups.
+func (s *RollupService) Schedule(interval time.Duration) {
+	since := time.Now().Add(-s.policy.RawRetention)
+
+	for {
+		startedAt := time.Now()
+
+		err := s.Rollup(since)
+
+		if err != nil {
+			fmt.Printf("Not able to rollup application execution states: %v\n", err)
+		} else {
+			since = startedAt.Add(-time.Hour)
+		}
+
+		if err := s.Expire(startedAt); err != nil {
+			fmt.Printf("Not able to delete expired application execution states: %v\n", err)
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// HourlyRollupPipeline returns the pipeline that summarizes raw states by hour into the rollups collection
+func HourlyRollupPipeline(since time.Time, retention time.Duration) mongo.Pipeline {
+	since = since.UTC().Truncate(time.Hour)
+
+	group := bson.M{"count": bson.M{"$sum": 1}}
+
+	for _, field := range StateFields {
+		group[field+"Sum"] = bson.M{"$sum": "$state." + field}
+		group[field+"Min"] = bson.M{"$min": "$state." + field}
+		group[field+"Max"] = bson.M{"$max": "$state." + field}
+	}
+
+	dateParts := bson.M{
+		"year":  bson.M{"$year": "$date"},
+		"month": bson.M{"$month": "$date"},
+		"day":   bson.M{"$dayOfMonth": "$date"},
+		"hour":  bson.M{"$hour": "$date"},
+	}
+
+	expireAt := bson.M{"$add": bson.A{"$_id.date", retention.Milliseconds()}}
+
+	return rollupPipeline(bson.M{"date": bson.M{"$gte": since}, "expireAt": bson.M{"$exists": true}}, dateParts, group, domain.StatesResolutionHourly, expireAt)
+}
+
+// DailyRollupPipeline returns the pipeline that summarizes hourly rollups by day into the rollups collection
+func DailyRollupPipeline(since time.Time) mongo.Pipeline {
+	since = since.UTC().Truncate(24 * time.Hour)
+
+	group := bson.M{"count": bson.M{"$sum": "$count"}}
+
+	for _, field := range StateFields {
+		group[field+"Sum"] = bson.M{"$sum": bson.M{"$multiply": bson.A{"$state." + field + ".avg", "$count"}}}
+		group[field+"Min"] = bson.M{"$min": "$state." + field + ".min"}
+		group[field+"Max"] = bson.M{"$max": "$state." + field + ".max"}
+	}
+
+	dateParts := bson.M{
+		"year":  bson.M{"$year": "$date"},
+		"month": bson.M{"$month": "$date"},
+		"day":   bson.M{"$dayOfMonth": "$date"},
+	}
+
+	return rollupPipeline(bson.M{"resolution": domain.StatesResolutionHourly, "date": bson.M{"$gte": since}}, dateParts, group, domain.StatesResolutionDaily, nil)
+}
+
+// rollupPipeline groups documents by execution and date and replaces the rollups of the same execution, resolution and date
+func rollupPipeline(match bson.M, dateParts bson.M, group bson.M, resolution string, expireAt interface{}) mongo.Pipeline {
+	group["_id"] = bson.D{
+		{Key: "executionId", Value: "$executionId"},
+		{Key: "date", Value: bson.M{"$dateFromParts": dateParts}},
+	}
+
+	state := bson.M{}
+
+	for _, field := range StateFields {
+		state[field] = bson.M{
+			"avg": bson.M{"$divide": bson.A{"$" + field + "Sum", "$count"}},
+			"min": "$" + field + "Min",
+			"max": "$" + field + "Max",
+		}
+	}
+
+	project := bson.M{
+		// the fields order of _id must be the same on every run to replace previous rollups
+		"_id": bson.D{
+			{Key: "executionId", Value: "$_id.executionId"},
+			{Key: "resolution", Value: bson.M{"$literal": resolution}},
+			{Key: "date", Value: "$_id.date"},
+		},
+		"executionId": "$_id.executionId",
+		"resolution":  bson.M{"$literal": resolution},
+		"date":        "$_id.date",
+		"count":       "$count",
+		"state":       state,
+	}
+
+	if expireAt != nil {
+		project["expireAt"] = expireAt
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: group}},
+		{{Key: "$project", Value: project}},
+		{{Key: "$merge", Value: bson.M{"into": db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION, "on": "_id", "whenMatched": "replace", "whenNotMatched": "insert"}}},
+	}
+}
+
+// AggregateRollupsPipeline returns the pipeline that groups rollups with the average of each state field weighted by the number of states summarized
+func AggregateRollupsPipeline(match bson.M, groupBy bson.M) mongo.Pipeline {
+	group := bson.M{"_id": groupBy, "count": bson.M{"$sum": "$count"}}
+	project := bson.M{"_id": 1}
+
+	for _, field := range StateFields {
+		group[field] = bson.M{"$sum": bson.M{"$multiply": bson.A{"$state." + field + ".avg", "$count"}}}
+		project[field] = bson.M{"$divide": bson.A{"$" + field, "$count"}}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: group}},
+		{{Key: "$project", Value: project}},
+	}
+}
@@ -0,0 +1,129 @@
+package applicationExecutionStates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/application-execution-states"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRollup(t *testing.T) {
+	t.Run("should summarize raw states by hour and hourly rollups by day", func(t *testing.T) {
+		statesRepo := &mocks.ApplicationExecutionStatesRepositorySpy{}
+		rollupsRepo := &mocks.ApplicationExecutionStatesRepositorySpy{}
+		service := applicationExecutionStates.NewRollupService(statesRepo, rollupsRepo, domain.DefaultStatesRetentionPolicy)
+
+		err := service.Rollup(time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC))
+
+		if err != nil {
+			t.Fatalf("Not expected Rollup to return error: %v", err)
+		}
+
+		if len(statesRepo.AggregateCalls) != 1 || len(rollupsRepo.AggregateCalls) != 1 {
+			t.Fatalf("Expected one aggregation on each repository, got %d and %d", len(statesRepo.AggregateCalls), len(rollupsRepo.AggregateCalls))
+		}
+
+		for _, call := range append(statesRepo.AggregateCalls, rollupsRepo.AggregateCalls...) {
+			pipeline := call.(mongo.Pipeline)
+			merge := pipeline[len(pipeline)-1][0]
+
+			if merge.Key != "$merge" || merge.Value.(bson.M)["into"] != db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION {
+				t.Errorf("Expected pipeline to merge into rollups collection, got %v", merge)
+			}
+		}
+	})
+}
+
+func TestHourlyRollupPipeline(t *testing.T) {
+	t.Run("should summarize live states since the start of the hour", func(t *testing.T) {
+		pipeline := applicationExecutionStates.HourlyRollupPipeline(time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC), time.Hour)
+
+		match := pipeline[0][0].Value.(bson.M)
+		want := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+
+		if got := match["date"].(bson.M)["$gte"]; got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+
+		if _, ok := match["expireAt"]; !ok {
+			t.Errorf("Expected benchmarks states without expireAt to be excluded")
+		}
+	})
+}
+
+func TestRollupInMemory(t *testing.T) {
+	t.Run("should summarize states weighting averages by the number of states", func(t *testing.T) {
+		database := db.NewMemoryDatabase()
+		statesRepo := applicationExecutionStates.NewRepository(database.Collection(db.APPLICATION_EXECUTION_STATES_COLLECTION))
+		rollupsRepo := applicationExecutionStates.NewRepository(database.Collection(db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION))
+		service := applicationExecutionStates.NewRollupService(statesRepo, rollupsRepo, domain.DefaultStatesRetentionPolicy)
+
+		executionID := primitive.NewObjectID()
+		startDate := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+		expireAt := startDate.Add(time.Hour)
+
+		for i, price := range []float64{10, 20, 60} {
+			database.Collection(db.APPLICATION_EXECUTION_STATES_COLLECTION).InsertOne(domain.ApplicationExecutionState{
+				ID:          primitive.NewObjectID(),
+				ExecutionID: executionID,
+				Date:        startDate.Add(time.Duration(i*40) * time.Minute),
+				State:       bson.M{"price": price},
+				ExpireAt:    &expireAt,
+			})
+		}
+
+		err := service.Rollup(startDate)
+
+		if err != nil {
+			t.Fatalf("Not expected Rollup to return error: %v", err)
+		}
+
+		match := bson.M{"executionId": executionID, "resolution": domain.StatesResolutionDaily}
+		got, err := rollupsRepo.Aggregate(applicationExecutionStates.AggregateRollupsPipeline(match, bson.M{"day": bson.M{"$dayOfMonth": "$date"}}))
+
+		if err != nil {
+			t.Fatalf("Not expected Aggregate to return error: %v", err)
+		}
+
+		if len(*got) != 1 || (*got)[0]["price"] != float64(30) {
+			t.Errorf("got %v want price 30", *got)
+		}
+	})
+}
+
+func TestExpireInMemory(t *testing.T) {
+	t.Run("should delete the states and rollups expired and keep the states without expiration", func(t *testing.T) {
+		database := db.NewMemoryDatabase()
+		states := database.Collection(db.APPLICATION_EXECUTION_STATES_COLLECTION)
+		rollups := database.Collection(db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION)
+		service := applicationExecutionStates.NewRollupService(applicationExecutionStates.NewRepository(states), applicationExecutionStates.NewRepository(rollups), domain.DefaultStatesRetentionPolicy)
+
+		now := time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC)
+		expired, live := now.Add(-time.Hour), now.Add(time.Hour)
+
+		for _, expireAt := range []*time.Time{&expired, &live, nil} {
+			states.InsertOne(domain.ApplicationExecutionState{ID: primitive.NewObjectID(), Date: now, State: bson.M{}, ExpireAt: expireAt})
+		}
+
+		rollups.InsertOne(bson.M{"_id": primitive.NewObjectID(), "resolution": domain.StatesResolutionHourly, "expireAt": expired})
+		rollups.InsertOne(bson.M{"_id": primitive.NewObjectID(), "resolution": domain.StatesResolutionDaily})
+
+		if err := service.Expire(now); err != nil {
+			t.Fatalf("Not expected Expire to return error: %v", err)
+		}
+
+		var remainingStates, remainingRollups []bson.M
+		states.FindAll(&remainingStates, bson.M{}, nil)
+		rollups.FindAll(&remainingRollups, bson.M{}, nil)
+
+		if len(remainingStates) != 2 || len(remainingRollups) != 1 {
+			t.Errorf("got %d states and %d rollups want 2 and 1", len(remainingStates), len(remainingRollups))
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package appoptions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Service versions the options of applications
+type Service struct {
+	applicationsRepository domain.ApplicationRepository
+	versionsRepository     domain.OptionsVersionsRepository
+}
+
+// NewService returns an instance of Service
+func NewService(applicationsRepository domain.ApplicationRepository, versionsRepository domain.OptionsVersionsRepository) *Service {
+	return &Service{applicationsRepository, versionsRepository}
+}
+
+// maxRecordAttempts is the number of times a version is recorded when other process records the same version number meanwhile
+const maxRecordAttempts = 5
+
+// Record creates a version with the options of the application when they differ from its last version.
+// The first version of an application has every option as a change. Versions are stored without the notification secrets.
+// The version numbers are unique per application, the version is recorded again after the last one when the number was taken meanwhile.
+func (s *Service) Record(application *domain.Application) (*domain.OptionsVersion, error) {
+	for attempt := 1; ; attempt++ {
+		version, err := s.record(application)
+
+		if mongo.IsDuplicateKeyError(err) && attempt < maxRecordAttempts {
+			continue
+		}
+
+		return version, err
+	}
+}
+
+// record creates a version numbered after the last version of the application when the options changed
+func (s *Service) record(application *domain.Application) (*domain.OptionsVersion, error) {
+	last, err := s.versionsRepository.FindLast(application.ID.Hex())
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous := domain.ApplicationOptions{}
+	number := 1
+
+	if last != nil {
+		previous = last.Options
+		number = last.Version + 1
+	}
+
+	changes, err := domain.DiffOptions(previous, application.Options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if last != nil && len(changes) == 0 {
+		redacted := last.Redacted()
+		return &redacted, nil
+	}
+
+	version := &domain.OptionsVersion{
+		ID:            primitive.NewObjectID(),
+		ApplicationID: application.ID,
+		Version:       number,
+		Options:       application.Options.Redacted(),
+		Author:        application.UpdatedBy,
+		Date:          time.Now(),
+		Changes:       changes,
+	}
+
+	if err := s.versionsRepository.Create(version); err != nil {
+		return nil, err
+	}
+
+	return version, nil
+}
+
+// FindAll returns the versions of the options of an application without the notification secrets
+func (s *Service) FindAll(applicationID string) (*[]domain.OptionsVersion, error) {
+	versions, err := s.versionsRepository.FindAll(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	redacted := make([]domain.OptionsVersion, len(*versions))
+
+	for i, version := range *versions {
+		redacted[i] = version.Redacted()
+	}
+
+	return &redacted, nil
+}
+
+// Update sets the options of the application and records them as a new version of the author when they changed.
+// Options changed by other means are recorded when the application starts.
+func (s *Service) Update(applicationID string, options domain.ApplicationOptions, author string) (*domain.OptionsVersion, error) {
+	if err := s.applicationsRepository.UpdateOptions(applicationID, options, author); err != nil {
+		return nil, err
+	}
+
+	application, err := s.applicationsRepository.FindByID(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Record(application)
+}
+
+// Rollback sets the options of a version on the application as a new version, the notification secrets are kept
+func (s *Service) Rollback(applicationID string, version int, author string) (*domain.OptionsVersion, error) {
+	optionsVersion, err := s.versionsRepository.FindByVersion(applicationID, version)
+
+	if err != nil {
+		return nil, fmt.Errorf("Not able to find version %v of application %v: %v", version, applicationID, err)
+	}
+
+	application, err := s.applicationsRepository.FindByID(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	options := optionsVersion.Options
+	options.NotificationOptions = options.NotificationOptions.WithSecrets(application.Options.NotificationOptions)
+
+	return s.Update(applicationID, options, author)
+}
@@ -0,0 +1,177 @@
+package appoptions_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/appoptions"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestService(t *testing.T) {
+	applicationsRepository := app.NewRepository(db.NewMemoryRepository())
+	service := appoptions.NewService(applicationsRepository, appoptions.NewVersionsRepository(db.NewMemoryRepository()))
+
+	options := domain.ApplicationOptions{DecisionMakerOptions: domain.DecisionMakerOptions{MinimumProfitPerSold: 0.01, MaximumFIATBuyAmount: 500}}
+	application, _ := applicationsRepository.Create("BTC", options, primitive.NewObjectID())
+
+	t.Run("should record the first version with every option", func(t *testing.T) {
+		version, err := service.Record(application)
+
+		if err != nil {
+			t.Fatalf("Not expected Record to return error: %v", err)
+		}
+
+		if version.Version != 1 || len(version.Changes) == 0 {
+			t.Errorf("got version %v with %d changes want version 1 with every option", version.Version, len(version.Changes))
+		}
+	})
+
+	t.Run("should not record a version when the options did not change", func(t *testing.T) {
+		version, _ := service.Record(application)
+		versions, _ := service.FindAll(application.ID.Hex())
+
+		if version.Version != 1 || len(*versions) != 1 {
+			t.Errorf("got version %v and %d versions want version 1 and 1 version", version.Version, len(*versions))
+		}
+	})
+
+	t.Run("should record the author and the changes of updated options", func(t *testing.T) {
+		updated := options
+		updated.DecisionMakerOptions.MaximumFIATBuyAmount = 250
+
+		version, err := service.Update(application.ID.Hex(), updated, "alice")
+
+		if err != nil {
+			t.Fatalf("Not expected Update to return error: %v", err)
+		}
+
+		if version.Version != 2 || version.Author != "alice" || len(version.Changes) != 1 || version.Changes[0].Field != "decisionMakerOptions.maximumFIATBuyAmount" {
+			t.Errorf("got version %+v want version 2 of alice changing the maximum buy amount", version)
+		}
+	})
+
+	t.Run("should rollback the options of a version as a new version", func(t *testing.T) {
+		version, err := service.Rollback(application.ID.Hex(), 1, "bob")
+
+		if err != nil {
+			t.Fatalf("Not expected Rollback to return error: %v", err)
+		}
+
+		application, _ = applicationsRepository.FindByID(application.ID.Hex())
+
+		if application.Options.DecisionMakerOptions.MaximumFIATBuyAmount != 500 || version.Version != 3 || version.Author != "bob" {
+			t.Errorf("got options %+v and version %+v want the options of version 1 in version 3 of bob", application.Options, version)
+		}
+
+		versions, _ := service.FindAll(application.ID.Hex())
+
+		for i, version := range *versions {
+			if version.Version != i+1 {
+				t.Errorf("got version %v at %d want versions sorted by number", version.Version, i)
+			}
+		}
+	})
+
+	t.Run("should not record a version when the application restarts with the options recorded", func(t *testing.T) {
+		version, _ := service.Record(application)
+
+		if version.Version != 3 {
+			t.Errorf("got version %v want 3", version.Version)
+		}
+	})
+
+	t.Run("should return error for versions that do not exist", func(t *testing.T) {
+		if _, err := service.Rollback(application.ID.Hex(), 10, "bob"); err == nil {
+			t.Errorf("Expected Rollback to return error")
+		}
+	})
+}
+
+func TestServiceSecrets(t *testing.T) {
+	applicationsRepository := app.NewRepository(db.NewMemoryRepository())
+	service := appoptions.NewService(applicationsRepository, appoptions.NewVersionsRepository(db.NewMemoryRepository()))
+
+	options := domain.ApplicationOptions{NotificationOptions: domain.NotificationOptions{
+		SenderPassword: "smtp-password",
+		Telegram:       &domain.TelegramOptions{BotToken: "123:token", ChatID: "-42"},
+	}}
+	application, _ := applicationsRepository.Create("BTC", options, primitive.NewObjectID())
+	service.Record(application)
+
+	t.Run("should not return the notification secrets", func(t *testing.T) {
+		updated := options
+		updated.DecisionMakerOptions.MaximumFIATBuyAmount = 250
+		service.Update(application.ID.Hex(), updated, "alice")
+
+		versions, _ := service.FindAll(application.ID.Hex())
+		document, _ := json.Marshal(versions)
+
+		if len(*versions) != 2 || strings.Contains(string(document), "123:token") || strings.Contains(string(document), "smtp-password") {
+			t.Errorf("got versions %s want 2 versions without secrets", document)
+		}
+	})
+
+	t.Run("should keep the notification secrets of the application on rollbacks", func(t *testing.T) {
+		if _, err := service.Rollback(application.ID.Hex(), 1, "bob"); err != nil {
+			t.Fatalf("Not expected Rollback to return error: %v", err)
+		}
+
+		application, _ = applicationsRepository.FindByID(application.ID.Hex())
+		notificationOptions := application.Options.NotificationOptions
+
+		if notificationOptions.SenderPassword != "smtp-password" || notificationOptions.Telegram.BotToken != "123:token" {
+			t.Errorf("got notification options %+v want the secrets kept", notificationOptions)
+		}
+	})
+}
+
+// staleVersionsRepository misses the last version on the first FindLast, like a version recorded meanwhile by other process
+type staleVersionsRepository struct {
+	*appoptions.VersionsRepository
+	stale bool
+}
+
+func (r *staleVersionsRepository) FindLast(applicationID string) (*domain.OptionsVersion, error) {
+	if r.stale {
+		r.stale = false
+		return nil, nil
+	}
+
+	return r.VersionsRepository.FindLast(applicationID)
+}
+
+func TestServiceConcurrentVersions(t *testing.T) {
+	repo := db.NewMemoryRepository()
+	repo.CreateIndex(mongo.IndexModel{Keys: bson.D{{Key: "applicationId", Value: 1}, {Key: "version", Value: 1}}, Options: options.Index().SetUnique(true)})
+	versionsRepository := &staleVersionsRepository{VersionsRepository: appoptions.NewVersionsRepository(repo)}
+	applicationsRepository := app.NewRepository(db.NewMemoryRepository())
+	service := appoptions.NewService(applicationsRepository, versionsRepository)
+
+	applicationOptions := domain.ApplicationOptions{DecisionMakerOptions: domain.DecisionMakerOptions{MaximumFIATBuyAmount: 500}}
+	application, _ := applicationsRepository.Create("BTC", applicationOptions, primitive.NewObjectID())
+	service.Record(application)
+
+	t.Run("should record the version after the one recorded meanwhile", func(t *testing.T) {
+		versionsRepository.stale = true
+		updated := applicationOptions
+		updated.DecisionMakerOptions.MaximumFIATBuyAmount = 250
+
+		version, err := service.Update(application.ID.Hex(), updated, "alice")
+
+		if err != nil {
+			t.Fatalf("Not expected Update to return error: %v", err)
+		}
+
+		if version.Version != 2 {
+			t.Errorf("got version %v want 2", version.Version)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package appoptions
+
+import (
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VersionsRepository stores the options versions of applications
+type VersionsRepository struct {
+	repo domain.Repository
+}
+
+// NewVersionsRepository returns an instance of VersionsRepository
+func NewVersionsRepository(repo domain.Repository) *VersionsRepository {
+	return &VersionsRepository{repo}
+}
+
+// Create inserts a new version
+func (r *VersionsRepository) Create(version *domain.OptionsVersion) error {
+	return r.repo.InsertOne(version)
+}
+
+// FindLast returns the version with the highest number of an application or nil when it does not have versions
+func (r *VersionsRepository) FindLast(applicationID string) (*domain.OptionsVersion, error) {
+	applicationOID, err := primitive.ObjectIDFromHex(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := []domain.OptionsVersion{}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}}).SetLimit(1)
+
+	if err := r.repo.FindAll(&results, bson.M{"applicationId": applicationOID}, opts); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	return &results[0], nil
+}
+
+// FindByVersion returns a version of an application
+func (r *VersionsRepository) FindByVersion(applicationID string, version int) (*domain.OptionsVersion, error) {
+	applicationOID, err := primitive.ObjectIDFromHex(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result domain.OptionsVersion
+
+	if err := r.repo.FindOne(&result, bson.M{"applicationId": applicationOID, "version": version}, nil); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// FindAll returns the versions of an application sorted by number
+func (r *VersionsRepository) FindAll(applicationID string) (*[]domain.OptionsVersion, error) {
+	applicationOID, err := primitive.ObjectIDFromHex(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := []domain.OptionsVersion{}
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+
+	if err := r.repo.FindAll(&results, bson.M{"applicationId": applicationOID}, opts); err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
@@ -82,11 +82,11 @@ func (or *Repository) FindAll(accountID string) (*[]Asset, error) {
 }
 
 // FindCheaperAssetPrice returns the asset with the lower buy price
-func (or *Repository) FindCheaperAssetPrice(accountID string) (float32, error) {
+func (or *Repository) FindCheaperAssetPrice(accountID string) (domain.Decimal, error) {
 	accountOID, err := primitive.ObjectIDFromHex(accountID)
 
 	if err != nil {
-		return 0, err
+		return domain.Decimal{}, err
 	}
 
 	opts := options.FindOne().SetSort(bson.M{"buyPrice": 1})
@@ -95,9 +95,9 @@ func (or *Repository) FindCheaperAssetPrice(accountID string) (float32, error) {
 
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return 0, nil
+			return domain.Decimal{}, nil
 		}
-		return 0, err
+		return domain.Decimal{}, err
 	}
 
 	return foundDocument.BuyPrice, nil
@@ -109,7 +109,7 @@ func (or *Repository) Create(asset *Asset) error {
 }
 
 // Sell updates asset sell fields
-func (or *Repository) Sell(assetID string, price float32, sellTime time.Time) error {
+func (or *Repository) Sell(assetID string, price, fee domain.Decimal, sellTime time.Time) error {
 	assetOID, err := primitive.ObjectIDFromHex(assetID)
 
 	if err != nil {
@@ -117,44 +117,104 @@ func (or *Repository) Sell(assetID string, price float32, sellTime time.Time) er
 	}
 
 	filter := bson.M{"_id": assetOID}
-	update := bson.M{"$set": bson.M{"sellPrice": price, "sold": true, "selltime": sellTime}}
+	update := bson.M{"$set": bson.M{"sellPrice": price, "sellFee": fee, "sold": true, "sellTime": sellTime}}
 	err = or.repo.UpdateOne(filter, update)
 
 	return err
 }
 
+// Split decrements the amount of a pending lot and creates a lot with the same price and the amount decremented.
+// It returns domain.ErrInsufficientAssets when the lot does not hold the amount.
+func (or *Repository) Split(asset *Asset, amount domain.Decimal) (*Asset, error) {
+	current, err := or.FindOne(bson.M{"_id": asset.ID})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if current.Sold || !current.Amount.GreaterThan(amount) {
+		return nil, domain.ErrInsufficientAssets
+	}
+
+	filter := bson.M{"_id": asset.ID, "sold": false, "amount": bson.M{"$gt": amount}}
+	lot, err := splitLot(current, amount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$inc": bson.M{"amount": amount.Neg()}, "$set": bson.M{"buyFee": current.BuyFee.Sub(lot.BuyFee)}}
+	err = or.repo.UpdateOne(filter, update)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return lot, or.Create(lot)
+}
+
+// Merge replaces the lots passed by argument by a lot with their amount and buy fees bought at their average price that is returned.
+// It returns domain.ErrLotsNotMergeable when a lot is not pending.
+func (or *Repository) Merge(lots []Asset) (*Asset, error) {
+	current := make([]Asset, 0, len(lots))
+
+	for _, lot := range lots {
+		found, err := or.FindOne(bson.M{"_id": lot.ID})
+
+		if err != nil {
+			return nil, err
+		}
+
+		current = append(current, *found)
+	}
+
+	merged, err := domain.MergeLots(current)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lot := range current {
+		if err := or.repo.DeleteByID(lot.ID.Hex()); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, or.Create(merged)
+}
+
 // GetBalance returns the assets balance based on buys and sells
-func (ar *Repository) GetBalance(accountID string, startDate, endDate time.Time) (float32, error) {
+func (ar *Repository) GetBalance(accountID string, startDate, endDate time.Time) (domain.Decimal, error) {
+	var balance domain.Decimal
+
 	accountOID, err := primitive.ObjectIDFromHex(accountID)
 
 	if err != nil {
-		return 0, err
+		return balance, err
 	}
 
-	filter := bson.M{"sold": false, "accountID": accountOID, "buytime": bson.M{"$gte": startDate, "$lte": endDate}}
+	filter := bson.M{"sold": false, "accountID": accountOID, "buyTime": bson.M{"$gte": startDate, "$lte": endDate}}
 	var assetsBought []Asset
 	err = ar.repo.FindAll(&assetsBought, filter, nil)
 
 	if err != nil {
-		return 0, err
+		return balance, err
 	}
 
-	filter = bson.M{"sold": true, "selltime": bson.M{"$gte": startDate, "$lte": endDate}}
+	filter = bson.M{"sold": true, "sellTime": bson.M{"$gte": startDate, "$lte": endDate}}
 	var assetsSold []Asset
 	err = ar.repo.FindAll(&assetsSold, filter, nil)
 
 	if err != nil {
-		return 0, err
+		return balance, err
 	}
 
-	var balance float32
-
 	for _, asset := range assetsSold {
-		balance += asset.Amount * asset.SellPrice
+		balance = balance.Add(asset.Amount.Mul(asset.SellPrice))
 	}
 
 	for _, asset := range assetsBought {
-		balance -= asset.Amount * asset.BuyPrice
+		balance = balance.Sub(asset.Amount.Mul(asset.BuyPrice))
 	}
 
 	return balance, nil
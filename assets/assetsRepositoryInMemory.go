@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // AssetsRepositoryInMemory stores assets in memory
@@ -35,11 +36,11 @@ func (ar *AssetsRepositoryInMemory) FindAll(accountID string) (*[]domain.Asset,
 }
 
 // FindCheaperAssetPrice returns the lowest price of non sold assets
-func (ar *AssetsRepositoryInMemory) FindCheaperAssetPrice(accountID string) (float32, error) {
-	var minimumPrice float32
+func (ar *AssetsRepositoryInMemory) FindCheaperAssetPrice(accountID string) (domain.Decimal, error) {
+	var minimumPrice domain.Decimal
 
 	for _, asset := range ar.Assets {
-		if asset.Sold == false && minimumPrice > asset.BuyPrice {
+		if asset.Sold == false && minimumPrice.GreaterThan(asset.BuyPrice) {
 			minimumPrice = asset.BuyPrice
 		}
 	}
@@ -48,8 +49,8 @@ func (ar *AssetsRepositoryInMemory) FindCheaperAssetPrice(accountID string) (flo
 }
 
 // GetBalance mocks the returning of balance between two dates
-func (ar *AssetsRepositoryInMemory) GetBalance(accountID string, startDate, endDate time.Time) (float32, error) {
-	return 0, nil
+func (ar *AssetsRepositoryInMemory) GetBalance(accountID string, startDate, endDate time.Time) (domain.Decimal, error) {
+	return domain.Decimal{}, nil
 }
 
 // Create creates an asset and stores it in a data structure
@@ -59,11 +60,12 @@ func (ar *AssetsRepositoryInMemory) Create(asset *domain.Asset) error {
 }
 
 // Sell updates asset state to sold and other related attributes
-func (ar *AssetsRepositoryInMemory) Sell(id string, price float32, sellTime time.Time) error {
+func (ar *AssetsRepositoryInMemory) Sell(id string, price, fee domain.Decimal, sellTime time.Time) error {
 
 	for index, asset := range ar.Assets {
 		if asset.ID.Hex() == id {
 			ar.Assets[index].SellPrice = price
+			ar.Assets[index].SellFee = fee
 			ar.Assets[index].Sold = true
 			ar.Assets[index].SellTime = sellTime
 			break
@@ -73,13 +75,72 @@ func (ar *AssetsRepositoryInMemory) Sell(id string, price float32, sellTime time
 	return nil
 }
 
+// Merge replaces pending lots by a lot with their amount and buy fees bought at their average price
+func (ar *AssetsRepositoryInMemory) Merge(lots []domain.Asset) (*domain.Asset, error) {
+	ids := map[primitive.ObjectID]bool{}
+	current := []domain.Asset{}
+	remaining := []domain.Asset{}
+
+	for _, lot := range lots {
+		ids[lot.ID] = true
+	}
+
+	for _, asset := range ar.Assets {
+		if ids[asset.ID] {
+			current = append(current, asset)
+		} else {
+			remaining = append(remaining, asset)
+		}
+	}
+
+	if len(current) != len(ids) {
+		return nil, domain.ErrLotsNotMergeable
+	}
+
+	merged, err := domain.MergeLots(current)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ar.Assets = remaining
+
+	return merged, ar.Create(merged)
+}
+
+// Split decrements the amount of a pending lot and creates a lot with the amount decremented
+func (ar *AssetsRepositoryInMemory) Split(asset *domain.Asset, amount domain.Decimal) (*domain.Asset, error) {
+	for index, current := range ar.Assets {
+		if current.ID != asset.ID {
+			continue
+		}
+
+		if current.Sold || !current.Amount.GreaterThan(amount) {
+			return nil, domain.ErrInsufficientAssets
+		}
+
+		lot, err := splitLot(&current, amount)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ar.Assets[index].Amount = current.Amount.Sub(amount)
+		ar.Assets[index].BuyFee = current.BuyFee.Sub(lot.BuyFee)
+
+		return lot, ar.Create(lot)
+	}
+
+	return nil, domain.ErrInsufficientAssets
+}
+
 // CheckAssetWithCloserPriceExists checks whether exist an asset that has the same price within limits defined
 func (ar *AssetsRepositoryInMemory) CheckAssetWithCloserPriceExists(accountID string, price, limit float32) (bool, error) {
 	lowerLimit := price - (price * limit)
 	upperLimit := price + (price * limit)
 
 	for _, asset := range ar.Assets {
-		if !asset.Sold && asset.BuyPrice > lowerLimit && asset.BuyPrice < upperLimit {
+		if buyPrice := asset.BuyPrice.Float32(); !asset.Sold && buyPrice > lowerLimit && buyPrice < upperLimit {
 			return true, nil
 		}
 	}
@@ -1,12 +1,15 @@
 package assets
 
-import "github.com/fabiodmferreira/crypto-trading/domain"
+import (
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type BenchmarkAssetsInfo struct {
-	Buys                [][]float32 `json:"buys"`
-	Sells               [][]float32 `json:"sells"`
-	SellsPending        int         `json:"sellsPending"`
-	AssetsAmountPending float32     `json:"assetsAmountPending"`
+	Buys                [][]float32    `json:"buys"`
+	Sells               [][]float32    `json:"sells"`
+	SellsPending        int            `json:"sellsPending"`
+	AssetsAmountPending domain.Decimal `json:"assetsAmountPending"`
 }
 
 // GroupAssetsByState returns assets bought and sold
@@ -15,16 +18,16 @@ func GroupAssetsByState(assets *[]domain.Asset) BenchmarkAssetsInfo {
 
 	Buys := [][]float32{}
 	Sells := [][]float32{}
-	var AssetsAmountPending float32
+	var AssetsAmountPending domain.Decimal
 
 	for _, asset := range *assets {
-		Buys = append(Buys, []float32{float32(asset.BuyTime.Unix()) * 1000, asset.BuyPrice})
+		Buys = append(Buys, []float32{float32(asset.BuyTime.Unix()) * 1000, asset.BuyPrice.Float32()})
 
 		if asset.Sold {
-			Sells = append(Sells, []float32{float32(asset.SellTime.Unix()) * 1000, asset.SellPrice})
+			Sells = append(Sells, []float32{float32(asset.SellTime.Unix()) * 1000, asset.SellPrice.Float32()})
 			sells++
 		} else {
-			AssetsAmountPending += asset.Amount
+			AssetsAmountPending = AssetsAmountPending.Add(asset.Amount)
 		}
 	}
 
@@ -35,3 +38,33 @@ func GroupAssetsByState(assets *[]domain.Asset) BenchmarkAssetsInfo {
 		AssetsAmountPending: AssetsAmountPending,
 	}
 }
+
+// splitLot returns a new pending lot of the asset with the amount passed by argument and the part of the buy fee paid for it
+func splitLot(asset *domain.Asset, amount domain.Decimal) (*domain.Asset, error) {
+	var buyFee domain.Decimal
+
+	if !asset.BuyFee.IsZero() {
+		fee, err := asset.BuyFee.MulChecked(amount)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buyFee, err = fee.DivChecked(asset.Amount)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.Asset{
+		ID:        primitive.NewObjectID(),
+		Symbol:    asset.Symbol,
+		Amount:    amount,
+		BuyTime:   asset.BuyTime,
+		BuyPrice:  asset.BuyPrice,
+		BuyFee:    buyFee,
+		AccountID: asset.AccountID,
+		ParentID:  asset.ID,
+	}, nil
+}
@@ -10,6 +10,15 @@ import (
 
 type AssetPrice = domain.AssetPrice
 
+// UniqueKeys are the fields that identify an asset price
+var UniqueKeys = []string{"asset", "interval", "date"}
+
+// UniqueIndexName is the name of the unique index created on UniqueKeys
+const UniqueIndexName = "asset_interval_date"
+
+// bulkUpsertSize is the maximum number of prices written on each bulk operation
+const bulkUpsertSize = 1000
+
 // Repository stores and gets assets prices
 type Repository struct {
 	repo domain.Repository
@@ -60,54 +69,106 @@ func (r *Repository) FindOne(filter interface{}) (interface{}, error) {
 	return assetPrice, nil
 }
 
-// Create stores an asset price
-func (r *Repository) Create(ohlc *domain.OHLC, asset string) error {
-	filter := bson.M{
-		"date":    ohlc.Time,
-		"endDate": ohlc.EndTime,
-		"o":       ohlc.Open,
-		"c":       ohlc.Close,
-		"h":       ohlc.High,
-		"l":       ohlc.Low,
-		"v":       ohlc.Volume,
-		"asset":   asset,
-	}
+// Create stores an asset price unless a price of the same asset, interval and date is already stored.
+// The interval is the one of the candles of the source of the price, like 1m or 1h.
+func (r *Repository) Create(ohlc *domain.OHLC, asset string, interval string) error {
+	_, err := r.BulkUpsert(&[]bson.M{{
+		"date":     ohlc.Time,
+		"endDate":  ohlc.EndTime,
+		"o":        ohlc.Open,
+		"c":        ohlc.Close,
+		"h":        ohlc.High,
+		"l":        ohlc.Low,
+		"v":        ohlc.Volume,
+		"asset":    asset,
+		"interval": interval,
+	}})
+
+	return err
+}
 
-	assets, err := r.FindAll(filter)
+// GetLastAssetsPrices return the last asset prices stored in DB with the interval passed by argument.
+// An empty interval returns the last prices of every interval.
+func (r *Repository) GetLastAssetsPrices(asset string, interval string, limit int) (*[]domain.AssetPrice, error) {
+	opts := options.Find().SetSort(bson.M{"date": -1}).SetLimit(int64(limit))
+	filter := bson.M{"asset": asset}
 
-	if len(*assets) != 0 {
-		return err
+	if interval != "" {
+		filter["interval"] = interval
 	}
 
-	assetPrice := domain.AssetPrice{
-		ID:      primitive.NewObjectID(),
-		Date:    ohlc.Time,
-		EndDate: ohlc.EndTime,
-		Open:    ohlc.Open,
-		Close:   ohlc.Close,
-		High:    ohlc.High,
-		Low:     ohlc.Low,
-		Volume:  ohlc.Volume,
-		Asset:   asset,
+	var foundDocument []AssetPrice
+	err := r.repo.FindAll(&foundDocument, filter, opts)
+
+	if err != nil {
+		return nil, err
 	}
 
-	return r.repo.InsertOne(assetPrice)
+	return &foundDocument, nil
 }
 
-// GetLastAssetsPrices return the last asset price stored in DB
-func (r *Repository) GetLastAssetsPrices(asset string, limit int) (*[]domain.AssetPrice, error) {
-	opts := options.Find().SetSort(bson.M{"date": -1}).SetLimit(int64(limit))
-	var foundDocument []AssetPrice
-	err := r.repo.FindAll(&foundDocument, bson.M{"asset": asset}, opts)
+// GetLastAssetPrice returns the most recent price of an asset stored with the interval passed by argument.
+// It returns nil when there are no prices stored.
+func (r *Repository) GetLastAssetPrice(asset string, interval string) (*domain.AssetPrice, error) {
+	var assetPrice domain.AssetPrice
+
+	opts := options.FindOne().SetSort(bson.M{"date": -1})
+	err := r.repo.FindOne(&assetPrice, bson.M{"asset": asset, "interval": interval}, opts)
+
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &foundDocument, nil
+	return &assetPrice, nil
 }
 
-// BulkCreate creates multiple assets prices
-func (r *Repository) BulkCreate(documents *[]bson.M) error {
-	return r.repo.BulkCreate(documents)
+// BulkUpsert stores the prices that are not stored yet with the same asset, interval and date
+func (r *Repository) BulkUpsert(documents *[]bson.M) (*domain.IngestionResult, error) {
+	result := &domain.IngestionResult{}
+
+	for start := 0; start < len(*documents); start += bulkUpsertSize {
+		end := start + bulkUpsertSize
+		if end > len(*documents) {
+			end = len(*documents)
+		}
+
+		batch := make([]bson.M, end-start)
+
+		for i, document := range (*documents)[start:end] {
+			batch[i] = bson.M{"_id": primitive.NewObjectID()}
+
+			for key, value := range document {
+				batch[i][key] = value
+			}
+		}
+
+		inserted, err := r.repo.BulkUpsert(batch, UniqueKeys)
+
+		if err != nil {
+			return result, err
+		}
+
+		result.Inserted += inserted
+		result.Skipped += len(batch) - inserted
+	}
+
+	return result, nil
+}
+
+// EnsureIndexes creates the unique index of assets prices
+func (r *Repository) EnsureIndexes() error {
+	keys := bson.D{}
+
+	for _, key := range UniqueKeys {
+		keys = append(keys, bson.E{Key: key, Value: 1})
+	}
+
+	return r.repo.CreateIndex(mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetUnique(true).SetName(UniqueIndexName),
+	})
 }
@@ -23,32 +23,34 @@ func (r *RepositoryInMemory) FindAll(filter interface{}) (*[]domain.AssetPrice,
 	return &r.assetsPrices, nil
 }
 
-// FindOne returns an asset price
-func (r *RepositoryInMemory) FindOne(date time.Time, value float32, asset string) (*domain.AssetPrice, error) {
-	var assetPrice domain.AssetPrice
-
-	for _, assetP := range r.assetsPrices {
-		if assetP.Date == date && assetP.Close == value && assetP.Asset == asset {
-			assetPrice = assetP
+// FindOne returns the asset price with the asset, interval and date passed by argument or nil if it does not exist
+func (r *RepositoryInMemory) FindOne(asset string, interval string, date time.Time) *domain.AssetPrice {
+	for i, assetPrice := range r.assetsPrices {
+		if assetPrice.Asset == asset && assetPrice.Interval == interval && assetPrice.Date.Equal(date) {
+			return &r.assetsPrices[i]
 		}
 	}
 
-	return &assetPrice, nil
+	return nil
 }
 
-// Create stores an asset price
-func (r *RepositoryInMemory) Create(ohlc *domain.OHLC, asset string) error {
-	foundDocument, err := r.FindOne(ohlc.Time, ohlc.Close, asset)
-
-	if err != nil {
-		return err
-	}
-
-	if foundDocument != nil {
+// Create stores an asset price unless a price of the same asset, interval and date is already stored
+func (r *RepositoryInMemory) Create(ohlc *domain.OHLC, asset string, interval string) error {
+	if r.FindOne(asset, interval, ohlc.Time) != nil {
 		return nil
 	}
 
-	assetPrice := domain.AssetPrice{Date: ohlc.Time, Close: ohlc.Close, Asset: asset}
+	assetPrice := domain.AssetPrice{
+		Date:     ohlc.Time,
+		EndDate:  ohlc.EndTime,
+		Open:     ohlc.Open,
+		Close:    ohlc.Close,
+		High:     ohlc.High,
+		Low:      ohlc.Low,
+		Volume:   ohlc.Volume,
+		Asset:    asset,
+		Interval: interval,
+	}
 
 	r.assetsPrices = append(r.assetsPrices, assetPrice)
 
@@ -63,11 +65,55 @@ func (r *RepositoryInMemory) Aggregate(pipeline mongo.Pipeline) (*[]bson.M, erro
 }
 
 // GetLastAssetsPrices stub
-func (r *RepositoryInMemory) GetLastAssetsPrices(asset string, limit int) (*[]domain.AssetPrice, error) {
+func (r *RepositoryInMemory) GetLastAssetsPrices(asset string, interval string, limit int) (*[]domain.AssetPrice, error) {
 	return nil, nil
 }
 
-// BulkCreate stub
-func (r *RepositoryInMemory) BulkCreate(documents *[]bson.M) error {
+// GetLastAssetPrice returns the most recent price of an asset stored with the interval passed by argument
+func (r *RepositoryInMemory) GetLastAssetPrice(asset string, interval string) (*domain.AssetPrice, error) {
+	var last *domain.AssetPrice
+
+	for i, assetPrice := range r.assetsPrices {
+		if assetPrice.Asset == asset && assetPrice.Interval == interval && (last == nil || assetPrice.Date.After(last.Date)) {
+			last = &r.assetsPrices[i]
+		}
+	}
+
+	return last, nil
+}
+
+// BulkUpsert stores the prices that are not stored yet with the same asset, interval and date
+func (r *RepositoryInMemory) BulkUpsert(documents *[]bson.M) (*domain.IngestionResult, error) {
+	result := &domain.IngestionResult{}
+
+	for _, document := range *documents {
+		assetPrice := domain.AssetPrice{}
+
+		data, err := bson.Marshal(document)
+
+		if err != nil {
+			return result, err
+		}
+
+		err = bson.Unmarshal(data, &assetPrice)
+
+		if err != nil {
+			return result, err
+		}
+
+		if r.FindOne(assetPrice.Asset, assetPrice.Interval, assetPrice.Date) != nil {
+			result.Skipped++
+			continue
+		}
+
+		r.assetsPrices = append(r.assetsPrices, assetPrice)
+		result.Inserted++
+	}
+
+	return result, nil
+}
+
+// EnsureIndexes stub
+func (r *RepositoryInMemory) EnsureIndexes() error {
 	return nil
 }
@@ -1,7 +1,9 @@
 package assetsprices_test
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/assetsprices"
 	"github.com/fabiodmferreira/crypto-trading/domain"
@@ -52,39 +54,79 @@ func TestRepositoryFindOne(t *testing.T) {
 func TestRepositoryCreate(t *testing.T) {
 	assetspricesRepository, repository := setupAssetsPricesRepository()
 
-	assetspricesRepository.Create(&domain.OHLC{}, "BTC")
+	date := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	assetspricesRepository.Create(&domain.OHLC{Time: date, EndTime: date.Add(30 * time.Second)}, "BTC", "1m")
 
-	got := len(repository.InsertOneCalls)
-	want := 1
+	if len(repository.BulkUpsertCalls) != 1 {
+		t.Fatalf("Expected BulkUpsert to be called 1 time, got %v", len(repository.BulkUpsertCalls))
+	}
 
-	if got != want {
-		t.Errorf("got %v want %v", got, want)
+	documents := repository.BulkUpsertCalls[0].([]interface{})[0].([]bson.M)
+
+	if got := documents[0]["interval"]; got != "1m" {
+		t.Errorf("interval: got %v want 1m", got)
+	}
+
+	if len(repository.FindAllCalls) != 0 {
+		t.Errorf("Not expected FindAll to be called")
 	}
 }
 
 func TestRepositoryGetLastAssetsPrices(t *testing.T) {
 	assetspricesRepository, repository := setupAssetsPricesRepository()
 
-	assetspricesRepository.GetLastAssetsPrices("BTC", 5)
+	assetspricesRepository.GetLastAssetsPrices("BTC", "1h", 5)
 
 	got := len(repository.FindAllCalls)
 	want := 1
 
 	if got != want {
-		t.Errorf("got %v want %v", got, want)
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	filter := repository.FindAllCalls[0][1].(bson.M)
+
+	if filter["interval"] != "1h" {
+		t.Errorf("interval: got %v want 1h", filter["interval"])
 	}
 }
 
-func TestRepositoryBulkCreate(t *testing.T) {
+func TestRepositoryBulkUpsert(t *testing.T) {
 	assetspricesRepository, repository := setupAssetsPricesRepository()
 
-	assetspricesRepository.BulkCreate(&[]bson.M{})
+	documents := make([]bson.M, 2500)
+	for i := range documents {
+		documents[i] = bson.M{"asset": "BTC", "interval": "1m", "date": time.Unix(int64(i*60), 0)}
+	}
 
-	got := len(repository.BulkCreateCalls)
-	want := 1
+	result, err := assetspricesRepository.BulkUpsert(&documents)
 
-	if got != want {
-		t.Errorf("got %v want %v", got, want)
+	if err != nil {
+		t.Fatalf("Not expected BulkUpsert to return error: %v", err)
+	}
+
+	if got := len(repository.BulkUpsertCalls); got != 3 {
+		t.Errorf("batches: got %v want 3", got)
+	}
+
+	keys := repository.BulkUpsertCalls[0].([]interface{})[1].([]string)
+
+	if !reflect.DeepEqual(keys, assetsprices.UniqueKeys) {
+		t.Errorf("keys: got %v want %v", keys, assetsprices.UniqueKeys)
+	}
+
+	if result.Inserted != 2500 || result.Skipped != 0 {
+		t.Errorf("got %+v want 2500 inserted", result)
+	}
+}
+
+func TestRepositoryEnsureIndexes(t *testing.T) {
+	assetspricesRepository, repository := setupAssetsPricesRepository()
+
+	assetspricesRepository.EnsureIndexes()
+
+	if len(repository.CreateIndexCalls) != 1 || !*repository.CreateIndexCalls[0].Options.Unique {
+		t.Errorf("Expected an unique index to be created")
 	}
 }
 
@@ -6,7 +6,6 @@ import (
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type FetchRemotePricesType = func(startDate, endDate time.Time, asset string) (*[]bson.M, error)
@@ -15,65 +14,72 @@ type FetchRemotePricesType = func(startDate, endDate time.Time, asset string) (*
 type Service struct {
 	repo              domain.AssetPriceRepository
 	FetchRemotePrices FetchRemotePricesType
+	interval          string
 }
 
-// NewService returns an instance of AssetsPricesService
-func NewService(repo domain.AssetPriceRepository, fetchRemotePrices FetchRemotePricesType) *Service {
-	return &Service{repo, fetchRemotePrices}
+// NewService returns an instance of AssetsPricesService.
+// The interval is the duration between prices fetched remotely, like 1m or 1h.
+func NewService(repo domain.AssetPriceRepository, fetchRemotePrices FetchRemotePricesType, interval string) *Service {
+	return &Service{repo, fetchRemotePrices, interval}
 }
 
-// FetchAndStoreAssetPrices fetches asset prices remotely and save it in repository
-func (s *Service) FetchAndStoreAssetPrices(asset string, endDate time.Time) error {
-	lastAssetPrice, err := s.GetLastAssetsPrices(asset, 1)
+// FetchAndStoreAssetPrices fetches asset prices remotely since the last price stored and save it in repository.
+// Prices already stored are skipped so it is safe to run it multiple times.
+func (s *Service) FetchAndStoreAssetPrices(asset string, endDate time.Time) (*domain.IngestionResult, error) {
+	result := &domain.IngestionResult{}
 
-	if err != nil && err != mongo.ErrNoDocuments {
-		return err
+	lastAssetPrice, err := s.repo.GetLastAssetPrice(asset, s.interval)
+
+	if err != nil {
+		return result, err
 	}
 
 	var startDate time.Time
 
-	if len(*lastAssetPrice) == 0 {
+	if lastAssetPrice == nil {
 		startDate = endDate.AddDate(0, 0, -180)
 	} else {
-		startDate = (*lastAssetPrice)[0].Date
+		startDate = lastAssetPrice.Date
 	}
 
-	counter := 0
-
-	TransverseDatesRange(startDate, endDate, func(startDate, endDate time.Time) error {
+	err = TransverseDatesRange(startDate, endDate, func(startDate, endDate time.Time) error {
 		assetsPrices, err := s.FetchRemotePrices(startDate, endDate, asset)
 
 		if err != nil {
 			return err
 		}
 
-		// Tech Debt: Condition to avoid create the same asset price multiple time.
-		// This happens when `FetchAndStoreAssetPrices` is executed multiple times in a short time
-		if len(*assetsPrices) > 1 {
-			err = s.repo.BulkCreate(assetsPrices)
+		for _, assetPrice := range *assetsPrices {
+			assetPrice["interval"] = s.interval
+		}
+
+		batchResult, err := s.repo.BulkUpsert(assetsPrices)
 
-			counter += len(*assetsPrices)
-			// fmt.Printf("\rAsset: %v Created: %d", asset, counter)
+		if err != nil {
+			return err
 		}
 
+		result.Add(batchResult)
+
 		return nil
 	})
 
-	return nil
+	return result, err
 }
 
-// Create creates an asset price in repository
-func (s *Service) Create(ohlc *domain.OHLC, asset string) error {
-	return s.repo.Create(ohlc, asset)
+// Create creates an asset price of the interval passed by argument in repository
+func (s *Service) Create(ohlc *domain.OHLC, asset string, interval string) error {
+	return s.repo.Create(ohlc, asset, interval)
 }
 
-// GetLastAssetsPrices returns the last price of an asset in the repository
-func (s *Service) GetLastAssetsPrices(asset string, limit int) (*[]domain.AssetPrice, error) {
-	return s.repo.GetLastAssetsPrices(asset, limit)
+// GetLastAssetsPrices returns the last prices of an asset with the interval passed by argument in the repository
+func (s *Service) GetLastAssetsPrices(asset string, interval string, limit int) (*[]domain.AssetPrice, error) {
+	return s.repo.GetLastAssetsPrices(asset, interval, limit)
 }
 
-// TransverseDatesRange iterate every day in the dates range passed and call the callback function
-func TransverseDatesRange(startDate, endDate time.Time, handle func(time.Time, time.Time) error) {
+// TransverseDatesRange iterate every day in the dates range passed and call the callback function.
+// It stops on the first error returned by the callback.
+func TransverseDatesRange(startDate, endDate time.Time, handle func(time.Time, time.Time) error) error {
 	startDateCursor := startDate
 	endDateCursor := startDateCursor.Add(
 		23*time.Hour +
@@ -81,9 +87,16 @@ func TransverseDatesRange(startDate, endDate time.Time, handle func(time.Time, t
 			59*time.Second)
 
 	for startDateCursor.Before(endDate) {
-		handle(startDateCursor, endDateCursor)
+		err := handle(startDateCursor, endDateCursor)
+
+		if err != nil {
+			return err
+		}
+
 		startDateCursor, endDateCursor = GetDatesPlusOneDay(startDateCursor, endDateCursor)
 	}
+
+	return nil
 }
 
 // GetDatesPlusOneDay returns the two dates passed by parameter with one more day
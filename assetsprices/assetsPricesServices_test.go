@@ -89,11 +89,11 @@ func TestTransverseDatesRanges(t *testing.T) {
 func TestServiceCreate(t *testing.T) {
 	service, repo, _ := NewAssetsPricesService(t)
 
-	ohlc := domain.OHLC{Close: 30, Time: time.Now()}
+	ohlc := domain.OHLC{Close: domain.NewDecimal(30), Time: time.Now()}
 
-	repo.EXPECT().Create(&ohlc, "BTC").Return(nil).Times(1)
+	repo.EXPECT().Create(&ohlc, "BTC", "1m").Return(nil).Times(1)
 
-	got := service.Create(&ohlc, "BTC")
+	got := service.Create(&ohlc, "BTC", "1m")
 	var want error
 
 	if got != want {
@@ -104,9 +104,9 @@ func TestServiceCreate(t *testing.T) {
 func TestServiceGetLastAssetsPrices(t *testing.T) {
 	service, repo, _ := NewAssetsPricesService(t)
 
-	repo.EXPECT().GetLastAssetsPrices("BTC", 10).Return(&[]domain.AssetPrice{}, nil).Times(1)
+	repo.EXPECT().GetLastAssetsPrices("BTC", "1h", 10).Return(&[]domain.AssetPrice{}, nil).Times(1)
 
-	assetsPrices, err := service.GetLastAssetsPrices("BTC", 10)
+	assetsPrices, err := service.GetLastAssetsPrices("BTC", "1h", 10)
 
 	if err != nil {
 		t.Errorf("Should not return error")
@@ -118,19 +118,53 @@ func TestServiceGetLastAssetsPrices(t *testing.T) {
 }
 
 func TestServiceFetchAndStore(t *testing.T) {
-	service, assetsPriceRepo, fetchRemotePrices := NewAssetsPricesService(t)
+	t.Run("should resume from the last price stored with the service interval", func(t *testing.T) {
+		service, assetsPriceRepo, fetchRemotePrices := NewAssetsPricesService(t)
 
-	assetsPriceRepo.EXPECT().GetLastAssetsPrices("BTC", 1).Return(&[]domain.AssetPrice{{Date: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)}}, nil).Times(1)
-	assetsPriceRepo.EXPECT().BulkCreate(gomock.Any()).Return(nil).Times(2)
+		assetsPriceRepo.EXPECT().GetLastAssetPrice("BTC", "1h").Return(&domain.AssetPrice{Date: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)}, nil).Times(1)
+		assetsPriceRepo.EXPECT().BulkUpsert(gomock.Any()).Return(&domain.IngestionResult{Inserted: 1, Skipped: 1}, nil).Times(2)
 
-	service.FetchAndStoreAssetPrices("BTC", time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC))
+		result, err := service.FetchAndStoreAssetPrices("BTC", time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC))
 
-	got := len(fetchRemotePrices.Calls)
-	want := 2
+		if err != nil {
+			t.Fatalf("Not expected FetchAndStoreAssetPrices to return error: %v", err)
+		}
 
-	if got != want {
-		t.Errorf("got %v want %v", got, want)
-	}
+		got := len(fetchRemotePrices.Calls)
+		want := 2
+
+		if got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+
+		if *result != (domain.IngestionResult{Inserted: 2, Skipped: 2}) {
+			t.Errorf("got %+v want 2 inserted and 2 skipped", *result)
+		}
+	})
+
+	t.Run("should skip prices already stored", func(t *testing.T) {
+		repository := assetsprices.NewRepositoryInMemory()
+		date := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+		fetchRemotePrices := func(startDate, endDate time.Time, asset string) (*[]bson.M, error) {
+			return &[]bson.M{
+				{"asset": asset, "date": date, "c": 10},
+				{"asset": asset, "date": date.Add(time.Hour), "c": 11},
+			}, nil
+		}
+		service := assetsprices.NewService(repository, fetchRemotePrices, "1h")
+		endDate := time.Date(2020, 4, 3, 0, 0, 0, 0, time.UTC)
+
+		service.FetchAndStoreAssetPrices("BTC", endDate)
+		result, err := service.FetchAndStoreAssetPrices("BTC", endDate)
+
+		if err != nil {
+			t.Fatalf("Not expected FetchAndStoreAssetPrices to return error: %v", err)
+		}
+
+		if result.Inserted != 0 || result.Skipped == 0 {
+			t.Errorf("Expected every price to be skipped, got %+v", result)
+		}
+	})
 }
 
 func NewAssetsPricesService(t *testing.T) (*assetsprices.Service, *mocks.MockAssetPriceRepository, *FetchRemotePrice) {
@@ -140,5 +174,5 @@ func NewAssetsPricesService(t *testing.T) (*assetsprices.Service, *mocks.MockAss
 
 	assetsPriceRepo := mocks.NewMockAssetPriceRepository(ctrl)
 	fetchRemotePrices := &FetchRemotePrice{}
-	return assetsprices.NewService(assetsPriceRepo, fetchRemotePrices.fetch), assetsPriceRepo, fetchRemotePrices
+	return assetsprices.NewService(assetsPriceRepo, fetchRemotePrices.fetch, "1h"), assetsPriceRepo, fetchRemotePrices
 }
@@ -13,6 +13,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+// CoindeskInterval is the interval between prices returned by Coindesk when fetching one day of prices
+const CoindeskInterval = "1h"
+
 type UrlDataFetcher func(url string) (resp *http.Response, err error)
 
 type CoindeskRemoteSource struct {
@@ -23,8 +26,22 @@ func NewCoindeskRemoteSource(HTTPFetcher UrlDataFetcher) *CoindeskRemoteSource {
 	return &CoindeskRemoteSource{HTTPFetcher}
 }
 
-// FetchRemoteAssetsPrices uses remote source to get asset prices
-func (c *CoindeskRemoteSource) FetchRemoteAssetsPrices(startDate, endDate time.Time, asset string) (*[]bson.M, error) {
+// coindeskRates are the rates that convert the dollar prices of coindesk to the quote currencies
+var coindeskRates = map[string]float64{
+	domain.QuoteEUR:  utils.DollarEuroRate,
+	domain.QuoteUSD:  1,
+	domain.QuoteUSDT: 1,
+}
+
+// FetchRemoteAssetsPrices uses remote source to get the prices of a price symbol, e.g. BTC for euro prices or BTC/USD
+func (c *CoindeskRemoteSource) FetchRemoteAssetsPrices(startDate, endDate time.Time, symbol string) (*[]bson.M, error) {
+	asset, quote := domain.SplitPriceSymbol(symbol)
+	rate, ok := coindeskRates[quote]
+
+	if !ok {
+		return nil, fmt.Errorf("coindesk does not have %v prices", quote)
+	}
+
 	response := domain.CoindeskHTTPResponse{}
 
 	err := c.fetchCoindeskData(SerializeDate(startDate), SerializeDate(endDate), asset, &response)
@@ -38,15 +55,16 @@ func (c *CoindeskRemoteSource) FetchRemoteAssetsPrices(startDate, endDate time.T
 	var assetsPrices []bson.M
 
 	for _, entry := range response.Data.Entries {
+		price := domain.NewDecimal(entry[1]).MulFloat(rate)
 		assetsPrices = append(assetsPrices,
 			bson.M{
-				"asset": asset,
+				"asset": symbol,
 				"date":  time.Unix(int64(entry[0])/1000, 0),
-				"o":     float32(entry[1]) * utils.DollarEuroRate,
-				"c":     float32(entry[1]) * utils.DollarEuroRate,
-				"h":     float32(entry[1]) * utils.DollarEuroRate,
-				"l":     float32(entry[1]) * utils.DollarEuroRate,
-				"v":     0,
+				"o":     price,
+				"c":     price,
+				"h":     price,
+				"l":     price,
+				"v":     domain.Decimal{},
 			})
 	}
 
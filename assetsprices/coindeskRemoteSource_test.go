@@ -5,10 +5,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/assetsprices"
+	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/utils"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -38,19 +40,19 @@ func TestCoindeskRemoteSource(t *testing.T) {
 			{
 				"asset": "BTC",
 				"date":  time.Unix(1596183599999/1000, 0),
-				"c":     float32(11201.3598739665) * utils.DollarEuroRate,
-				"l":     float32(11201.3598739665) * utils.DollarEuroRate,
-				"o":     float32(11201.3598739665) * utils.DollarEuroRate,
-				"h":     float32(11201.3598739665) * utils.DollarEuroRate,
-				"v":     0,
+				"c":     domain.NewDecimal(11201.3598739665).MulFloat(utils.DollarEuroRate),
+				"l":     domain.NewDecimal(11201.3598739665).MulFloat(utils.DollarEuroRate),
+				"o":     domain.NewDecimal(11201.3598739665).MulFloat(utils.DollarEuroRate),
+				"h":     domain.NewDecimal(11201.3598739665).MulFloat(utils.DollarEuroRate),
+				"v":     domain.Decimal{},
 			}, {
 				"asset": "BTC",
 				"date":  time.Unix(1596183659999/1000, 0),
-				"c":     float32(11209.1649879131) * utils.DollarEuroRate,
-				"l":     float32(11209.1649879131) * utils.DollarEuroRate,
-				"o":     float32(11209.1649879131) * utils.DollarEuroRate,
-				"h":     float32(11209.1649879131) * utils.DollarEuroRate,
-				"v":     0,
+				"c":     domain.NewDecimal(11209.1649879131).MulFloat(utils.DollarEuroRate),
+				"l":     domain.NewDecimal(11209.1649879131).MulFloat(utils.DollarEuroRate),
+				"o":     domain.NewDecimal(11209.1649879131).MulFloat(utils.DollarEuroRate),
+				"h":     domain.NewDecimal(11209.1649879131).MulFloat(utils.DollarEuroRate),
+				"v":     domain.Decimal{},
 			},
 		}
 
@@ -67,6 +69,36 @@ func TestCoindeskRemoteSource(t *testing.T) {
 		}
 
 	})
+
+	t.Run("should return the dollar prices of the coin of a dollar price symbol", func(t *testing.T) {
+		coindeskRemoteSource, httpSpy := setupCoindeskRemoteSource()
+
+		got, err := coindeskRemoteSource.FetchRemoteAssetsPrices(time.Now(), time.Now(), "BTC/USD")
+
+		if err != nil {
+			t.Fatalf("Not expected FetchRemoteAssetsPrices to return error: %v", err)
+		}
+
+		if len(*got) != 2 || (*got)[0]["asset"] != "BTC/USD" || (*got)[0]["c"] != domain.NewDecimal(11201.3598739665) {
+			t.Errorf("got %v want BTC/USD dollar prices", got)
+		}
+
+		if len(httpSpy.GetCalls) != 1 || !strings.Contains(httpSpy.GetCalls[0], "/values/BTC?") {
+			t.Errorf("got calls %v want one call of BTC prices", httpSpy.GetCalls)
+		}
+	})
+
+	t.Run("should return error for quote currencies without coindesk prices", func(t *testing.T) {
+		coindeskRemoteSource, httpSpy := setupCoindeskRemoteSource()
+
+		if _, err := coindeskRemoteSource.FetchRemoteAssetsPrices(time.Now(), time.Now(), "ETH/BTC"); err == nil {
+			t.Errorf("Expected FetchRemoteAssetsPrices to return error")
+		}
+
+		if len(httpSpy.GetCalls) != 0 {
+			t.Errorf("Not expected coindesk to be called")
+		}
+	})
 }
 
 func setupCoindeskRemoteSource() (*assetsprices.CoindeskRemoteSource, *HTTPSpy) {
@@ -1,9 +1,6 @@
 package benchmark
 
 import (
-	"fmt"
-	"path"
-	"runtime"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/accounts"
@@ -11,6 +8,7 @@ import (
 	"github.com/fabiodmferreira/crypto-trading/assets"
 	"github.com/fabiodmferreira/crypto-trading/broker"
 	"github.com/fabiodmferreira/crypto-trading/collectors"
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
 	"github.com/fabiodmferreira/crypto-trading/decisionmaker"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/indicators"
@@ -18,17 +16,6 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-
-	adadatahistory "github.com/fabiodmferreira/crypto-trading/data-history/ada"
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
-	btccashdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc-cash"
-	eosdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/eos"
-	etcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/etc"
-	ethdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/eth"
-	ltcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/ltc"
-	monerodatahistory "github.com/fabiodmferreira/crypto-trading/data-history/monero"
-	stellardatahistory "github.com/fabiodmferreira/crypto-trading/data-history/stellar"
-	xrpdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/xrp"
 )
 
 // Input is an alias for BenchmarkInput
@@ -42,11 +29,12 @@ type Service struct {
 	repository                           domain.BenchmarksRepository
 	assetpriceRepository                 domain.AssetPriceRepository
 	applicationExecutionStatesRepository domain.ApplicationExecutionStateRepository
+	datasets                             domain.DatasetsService
 }
 
 // NewService returns an instance of Service
-func NewService(repo domain.BenchmarksRepository, assetpriceRepository domain.AssetPriceRepository, applicationExecutionStatesRepository domain.ApplicationExecutionStateRepository) *Service {
-	return &Service{repo, assetpriceRepository, applicationExecutionStatesRepository}
+func NewService(repo domain.BenchmarksRepository, assetpriceRepository domain.AssetPriceRepository, applicationExecutionStatesRepository domain.ApplicationExecutionStateRepository, datasets domain.DatasetsService) *Service {
+	return &Service{repo, assetpriceRepository, applicationExecutionStatesRepository, datasets}
 }
 
 // Create inserts one benchmark in database
@@ -89,6 +77,16 @@ func (s *Service) routineRun(input *Input, done chan domain.BenchmarkResult) {
 
 // Run executes benchmark and returns performance results
 func (s *Service) Run(input Input, benchmarkID *primitive.ObjectID) (*Output, error) {
+	historyFile, err := csvschema.Open(s.datasets.GetFilePath(input.DataSourceFilePath), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer historyFile.Close()
+
+	input.CollectorOptions.DataSource = historyFile
+
 	benchmarkApplication, err := s.setupApplication(input)
 
 	if err != nil {
@@ -96,7 +94,7 @@ func (s *Service) Run(input Input, benchmarkID *primitive.ObjectID) (*Output, er
 	}
 
 	var states []bson.M
-	var LastPrice float32
+	var LastPrice domain.Decimal
 
 	benchmarkApplication.RegistOnNewAssetPrice(func(ohlc *domain.OHLC) {
 		if benchmarkID != nil {
@@ -115,20 +113,29 @@ func (s *Service) Run(input Input, benchmarkID *primitive.ObjectID) (*Output, er
 		}
 	})
 
-	benchmarkApplication.Start()
+	err = benchmarkApplication.Start()
+
+	if err != nil {
+		return nil, err
+	}
 
 	assetsDocs, _ := benchmarkApplication.FetchAssets()
 
 	benchmarkAssetsInfo := assets.GroupAssetsByState(assetsDocs)
 
 	amount, _ := benchmarkApplication.GetAccountAmount()
+	assetsValuePending, err := LastPrice.MulChecked(benchmarkAssetsInfo.AssetsAmountPending)
+
+	if err != nil {
+		return nil, err
+	}
 
 	output := domain.BenchmarkOutput{
 		Buys:                benchmarkAssetsInfo.Buys,
 		Sells:               benchmarkAssetsInfo.Sells,
 		SellsPending:        benchmarkAssetsInfo.SellsPending,
 		AssetsAmountPending: benchmarkAssetsInfo.AssetsAmountPending,
-		AssetsValuePending:  LastPrice * benchmarkAssetsInfo.AssetsAmountPending,
+		AssetsValuePending:  assetsValuePending,
 		LastPrice:           LastPrice,
 		FinalAmount:         amount,
 		Assets:              assetsDocs,
@@ -147,23 +154,13 @@ func (s *Service) setupApplication(input Input) (*app.App, error) {
 	volumeIndicator := indicators.NewVolumeIndicator(indicators.NewMetricStatisticsIndicator(statisticsOptions))
 
 	assetsRepository := &assets.AssetsRepositoryInMemory{}
-	accountService := accounts.NewAccountServiceInMemory(float32(input.AccountInitialAmount), assetsRepository)
+	accountService := accounts.NewAccountServiceInMemory(domain.NewDecimal(input.AccountInitialAmount), assetsRepository)
 
 	buyStrategy := decisionmaker.NewBuyStrategy(priceIndicator, volumeIndicator, accountService, input.DecisionMakerOptions)
 	sellStrategy := decisionmaker.NewSellStrategy(priceIndicator, volumeIndicator, accountService, input.DecisionMakerOptions)
 
 	decisionMaker := decisionmaker.NewDecisionMaker(buyStrategy, sellStrategy)
 
-	_, currentFilePath, _, _ := runtime.Caller(0)
-	currentDir := path.Dir(currentFilePath)
-	historyFile, err := collectors.GetCsv(fmt.Sprintf("%v/../data-history/%v", currentDir, input.DataSourceFilePath))
-
-	if err != nil {
-		return nil, err
-	}
-
-	input.CollectorOptions.DataSource = historyFile
-
 	collector := collectors.NewFileTickerCollector(input.CollectorOptions, &[]domain.Indicator{priceIndicator, volumeIndicator})
 
 	broker := broker.NewBrokerMock()
@@ -171,7 +168,7 @@ func (s *Service) setupApplication(input Input) (*app.App, error) {
 
 	application := app.NewApp(&[]domain.Collector{collector}, decisionMaker, trader, accountService)
 
-	return application, err
+	return application, nil
 }
 
 // HandleBenchmark executes benchmark and updates database accordingly
@@ -193,51 +190,9 @@ func (s *Service) HandleBenchmark(benchmark *domain.Benchmark) error {
 	return nil
 }
 
-// GetDataSources returns all available data sources
-func (s *Service) GetDataSources() map[string]map[string]string {
-	return map[string]map[string]string{
-		"btc": {
-			"2019":         btcdatahistory.Twenty19,
-			"2020 H1":      btcdatahistory.TwentyTwentyH1,
-			"2019-2020 H1": btcdatahistory.Twenty1920H1,
-		},
-		"btc-cash": {
-			"2019":    btccashdatahistory.Twenty19,
-			"2020 H1": btccashdatahistory.TwentyTwentyH1,
-		},
-		"eos": {
-			"2019":    eosdatahistory.Twenty19,
-			"2020 H1": eosdatahistory.TwentyTwentyH1,
-		},
-		"etc": {
-			"2019":    etcdatahistory.Twenty19,
-			"2020 H1": etcdatahistory.TwentyTwentyH1,
-		},
-		"ltc": {
-			"2019":    ltcdatahistory.Twenty19,
-			"2020 H1": ltcdatahistory.TwentyTwentyH1,
-		},
-		"monero": {
-			"2019":    monerodatahistory.Twenty19,
-			"2020 H1": monerodatahistory.TwentyTwentyH1,
-		},
-		"stellar": {
-			"2019":    stellardatahistory.Twenty19,
-			"2020 H1": stellardatahistory.TwentyTwentyH1,
-		},
-		"xrp": {
-			"2019":    xrpdatahistory.Twenty19,
-			"2020 H1": xrpdatahistory.TwentyTwentyH1,
-		},
-		"eth": {
-			"2019":    ethdatahistory.Twenty19,
-			"2020 H1": ethdatahistory.TwentyTwentyH1,
-		},
-		"ada": {
-			"2019":    adadatahistory.Twenty19,
-			"2020 H1": adadatahistory.TwentyTwentyH1,
-		},
-	}
+// GetDataSources returns all available data sources registered in datasets
+func (s *Service) GetDataSources() (map[string]map[string]string, error) {
+	return s.datasets.GetDataSources()
 }
 
 // AggregateApplicationState returns an aggregate of application state
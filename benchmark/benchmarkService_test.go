@@ -1,20 +1,12 @@
 package benchmark_test
 
 import (
+	"path"
 	"reflect"
 	"testing"
 
 	"github.com/fabiodmferreira/crypto-trading/benchmark"
-	adadatahistory "github.com/fabiodmferreira/crypto-trading/data-history/ada"
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
-	btccashdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc-cash"
-	eosdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/eos"
-	etcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/etc"
-	ethdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/eth"
-	ltcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/ltc"
-	monerodatahistory "github.com/fabiodmferreira/crypto-trading/data-history/monero"
-	stellardatahistory "github.com/fabiodmferreira/crypto-trading/data-history/stellar"
-	xrpdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/xrp"
+	"github.com/fabiodmferreira/crypto-trading/datasets"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/mocks"
 	"github.com/golang/mock/gomock"
@@ -64,53 +56,39 @@ func TestHandleBenchmark(t *testing.T) {
 }
 
 func TestServiceGetDatSources(t *testing.T) {
-	service, _, _, _ := NewBenchmarkService(t)
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	datasetsRepository := datasets.NewRepositoryInMemory()
+	datasetsRepository.Datasets = []domain.Dataset{
+		{Asset: "btc", Name: "2019", FilePath: "btc/2019.csv"},
+		{Asset: "btc", Name: "2020 H1", FilePath: "btc/2020-h1.csv"},
+		{Asset: "eth", Name: "2019", FilePath: "eth/2019.csv"},
+	}
 
-	got := service.GetDataSources()
+	service := benchmark.NewService(
+		&mocks.BenchmarkRepositorySpy{},
+		mocks.NewMockAssetPriceRepository(ctrl),
+		&mocks.ApplicationExecutionStatesRepositorySpy{},
+		datasets.NewService(datasetsRepository, path.Join("..", datasets.DefaultRootDir)),
+	)
+
+	got, err := service.GetDataSources()
 	want := map[string]map[string]string{
 		"btc": {
-			"2019":         btcdatahistory.Twenty19,
-			"2020 H1":      btcdatahistory.TwentyTwentyH1,
-			"2019-2020 H1": btcdatahistory.Twenty1920H1,
-		},
-		"btc-cash": {
-			"2019":    btccashdatahistory.Twenty19,
-			"2020 H1": btccashdatahistory.TwentyTwentyH1,
-		},
-		"eos": {
-			"2019":    eosdatahistory.Twenty19,
-			"2020 H1": eosdatahistory.TwentyTwentyH1,
-		},
-		"etc": {
-			"2019":    etcdatahistory.Twenty19,
-			"2020 H1": etcdatahistory.TwentyTwentyH1,
-		},
-		"ltc": {
-			"2019":    ltcdatahistory.Twenty19,
-			"2020 H1": ltcdatahistory.TwentyTwentyH1,
-		},
-		"monero": {
-			"2019":    monerodatahistory.Twenty19,
-			"2020 H1": monerodatahistory.TwentyTwentyH1,
-		},
-		"stellar": {
-			"2019":    stellardatahistory.Twenty19,
-			"2020 H1": stellardatahistory.TwentyTwentyH1,
-		},
-		"xrp": {
-			"2019":    xrpdatahistory.Twenty19,
-			"2020 H1": xrpdatahistory.TwentyTwentyH1,
+			"2019":    "btc/2019.csv",
+			"2020 H1": "btc/2020-h1.csv",
 		},
 		"eth": {
-			"2019":    ethdatahistory.Twenty19,
-			"2020 H1": ethdatahistory.TwentyTwentyH1,
-		},
-		"ada": {
-			"2019":    adadatahistory.Twenty19,
-			"2020 H1": adadatahistory.TwentyTwentyH1,
+			"2019": "eth/2019.csv",
 		},
 	}
 
+	if err != nil {
+		t.Errorf("Not expected GetDataSources to return error: %v", err)
+	}
+
 	if reflect.DeepEqual(got, want) != true {
 		t.Errorf("got %v want %v", got, want)
 	}
@@ -169,7 +147,7 @@ func NewBenchmarkInput() *domain.BenchmarkInput {
 		StatisticsOptions:    domain.StatisticsOptions{NumberOfPointsHold: 10},
 		CollectorOptions:     domain.CollectorOptions{PriceVariationDetection: 0.1},
 		AccountInitialAmount: 5000,
-		DataSourceFilePath:   btcdatahistory.March2020,
+		DataSourceFilePath:   "btc/2020-mar.csv",
 	}
 }
 
@@ -182,5 +160,7 @@ func NewBenchmarkService(t *testing.T) (*benchmark.Service, *mocks.BenchmarkRepo
 	assetsPriceRepo := mocks.NewMockAssetPriceRepository(ctrl)
 	applicationExecutionStatesRepository := &mocks.ApplicationExecutionStatesRepositorySpy{}
 
-	return benchmark.NewService(repository, assetsPriceRepo, applicationExecutionStatesRepository), repository, assetsPriceRepo, applicationExecutionStatesRepository
+	datasetsService := datasets.NewService(datasets.NewRepositoryInMemory(), path.Join("..", datasets.DefaultRootDir))
+
+	return benchmark.NewService(repository, assetsPriceRepo, applicationExecutionStatesRepository, datasetsService), repository, assetsPriceRepo, applicationExecutionStatesRepository
 }
@@ -0,0 +1,230 @@
+// Package binancetest provides a stand-in of the binance API for tests
+package binancetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/gorilla/websocket"
+)
+
+// Balance is a balance of the account
+type Balance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+// Order is an open order of the account
+type Order struct {
+	Symbol      string `json:"symbol"`
+	OrderID     int64  `json:"orderId"`
+	Price       string `json:"price"`
+	OrigQty     string `json:"origQty"`
+	ExecutedQty string `json:"executedQty"`
+	Side        string `json:"side"`
+}
+
+// OrderFailure is the error answered to an order request, the order is placed when it is accepted
+type OrderFailure struct {
+	Status  int
+	Code    int
+	Message string
+	Accept  bool
+}
+
+// Server answers the binance endpoints used by this project with the values set on it.
+// Requests of the account must be signed with the secret key of the server.
+type Server struct {
+	*httptest.Server
+	APIKey    string
+	SecretKey string
+
+	mu sync.Mutex
+	// Orders are the parameters of the orders placed
+	Orders []url.Values
+	// OrderFailures are answered to the next order requests
+	OrderFailures []OrderFailure
+	Balances      []Balance
+	OpenOrders    []Order
+	// Prices are the last prices by symbol
+	Prices map[string]string
+	// ExchangeInfo is the response of the exchange information endpoint
+	ExchangeInfo string
+	// Messages are sent to every stream connection, Streams has the streams requested by each connection
+	Messages []string
+	Streams  [][]string
+}
+
+// NewServer starts a stand-in of the binance API
+func NewServer(apiKey, secretKey string) *Server {
+	s := &Server{APIKey: apiKey, SecretKey: secretKey, Prices: map[string]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/order", s.signed(s.orderHandler))
+	mux.HandleFunc("/api/v3/account", s.signed(s.accountHandler))
+	mux.HandleFunc("/api/v3/openOrders", s.signed(s.openOrdersHandler))
+	mux.HandleFunc("/api/v3/ticker/price", s.priceHandler)
+	mux.HandleFunc("/api/v3/exchangeInfo", s.exchangeInfoHandler)
+	mux.HandleFunc("/stream", s.streamHandler)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Client returns a binance client of the server
+func (s *Server) Client() *binance.Client {
+	return binance.NewWithURLs(s.APIKey, s.SecretKey, s.URL, "ws"+strings.TrimPrefix(s.URL, "http"), s.Server.Client())
+}
+
+// PlacedOrders returns the parameters of the orders placed
+func (s *Server) PlacedOrders() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]url.Values{}, s.Orders...)
+}
+
+// signed rejects requests without the api key or a valid signature
+func (s *Server) signed(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.RawQuery
+		i := strings.LastIndex(query, "&signature=")
+
+		if r.Header.Get("X-MBX-APIKEY") != s.APIKey || i < 0 || binance.Sign(s.SecretKey, query[:i]) != query[i+len("&signature="):] {
+			writeError(w, http.StatusUnauthorized, -1022, "Signature for this request is not valid.")
+			return
+		}
+
+		if r.URL.Query().Get("timestamp") == "" {
+			writeError(w, http.StatusBadRequest, -1102, "Mandatory parameter 'timestamp' was not sent.")
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+func (s *Server) orderHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet:
+		for i, order := range s.Orders {
+			if order.Get("symbol") == query.Get("symbol") && order.Get("newClientOrderId") == query.Get("origClientOrderId") {
+				fmt.Fprintf(w, `{"symbol":%q,"orderId":%d,"clientOrderId":%q,"status":"NEW"}`, order.Get("symbol"), i+1, order.Get("newClientOrderId"))
+				return
+			}
+		}
+
+		writeError(w, http.StatusBadRequest, -2013, "Order does not exist.")
+	case http.MethodPost:
+		if len(s.OrderFailures) > 0 {
+			failure := s.OrderFailures[0]
+			s.OrderFailures = s.OrderFailures[1:]
+
+			if failure.Accept {
+				s.Orders = append(s.Orders, query)
+			}
+
+			writeError(w, failure.Status, failure.Code, failure.Message)
+			return
+		}
+
+		s.Orders = append(s.Orders, query)
+
+		fmt.Fprintf(w, `{"symbol":%q,"orderId":%d,"status":"NEW"}`, query.Get("symbol"), len(s.Orders))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) accountHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"balances": s.Balances})
+}
+
+func (s *Server) openOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := s.OpenOrders
+
+	if orders == nil {
+		orders = []Order{}
+	}
+
+	json.NewEncoder(w).Encode(orders)
+}
+
+func (s *Server) priceHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	symbol := r.URL.Query().Get("symbol")
+	price, ok := s.Prices[symbol]
+
+	if !ok {
+		writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+
+	fmt.Fprintf(w, `{"symbol":%q,"price":%q}`, symbol, price)
+}
+
+func (s *Server) exchangeInfoHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ExchangeInfo == "" {
+		fmt.Fprint(w, `{"symbols":[]}`)
+		return
+	}
+
+	fmt.Fprint(w, s.ExchangeInfo)
+}
+
+// streamHandler sends the messages of the server and keeps the connection open until the client closes it
+func (s *Server) streamHandler(w http.ResponseWriter, r *http.Request) {
+	con, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+
+	if err != nil {
+		return
+	}
+
+	defer con.Close()
+
+	s.mu.Lock()
+	s.Streams = append(s.Streams, strings.Split(r.URL.Query().Get("streams"), "/"))
+	messages := append([]string{}, s.Messages...)
+	s.mu.Unlock()
+
+	for _, message := range messages {
+		if err := con.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
+			return
+		}
+	}
+
+	for {
+		if _, _, err := con.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func writeError(w http.ResponseWriter, status, code int, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(binance.Error{Code: code, Message: message})
+}
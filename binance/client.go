@@ -0,0 +1,145 @@
+package binance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Binance endpoints
+const (
+	BaseURL   = "https://api.binance.com"
+	StreamURL = "wss://stream.binance.com:9443"
+)
+
+// Binance error codes
+const (
+	CodeDisconnected    = -1001
+	CodeTooManyRequests = -1003
+	CodeUnknownStatus   = -1006
+	CodeTimeout         = -1007
+	CodeTooManyOrders   = -1015
+	CodeNoSuchOrder     = -2013
+)
+
+// recvWindow is the number of milliseconds a signed request is valid after its timestamp
+const recvWindow = "5000"
+
+// Error is an error returned by the binance API
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"msg"`
+	// Status is the HTTP status of the response
+	Status int `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("binance responded with status %d: %s", e.Status, e.Message)
+	}
+
+	return fmt.Sprintf("binance error %d: %v", e.Code, e.Message)
+}
+
+// Client calls the binance REST API. Requests of the account are signed with HMAC SHA256 of the secret key.
+type Client struct {
+	apiKey     string
+	secretKey  string
+	baseURL    string
+	streamURL  string
+	httpClient *http.Client
+}
+
+// New returns a client of the binance API
+func New(apiKey, secretKey string) *Client {
+	return NewWithURLs(apiKey, secretKey, BaseURL, StreamURL, http.DefaultClient)
+}
+
+// NewWithURLs returns a client of a binance API served by the URLs passed by argument
+func NewWithURLs(apiKey, secretKey, baseURL, streamURL string, httpClient *http.Client) *Client {
+	return &Client{apiKey, secretKey, strings.TrimSuffix(baseURL, "/"), strings.TrimSuffix(streamURL, "/"), httpClient}
+}
+
+// Query sends a public request and decodes the response into result
+func (c *Client) Query(method, path string, params url.Values, result interface{}) error {
+	return c.do(method, path, params.Encode(), result)
+}
+
+// SignedQuery sends a request of the account and decodes the response into result
+func (c *Client) SignedQuery(method, path string, params url.Values, result interface{}) error {
+	signed := url.Values{}
+
+	for key, values := range params {
+		signed[key] = values
+	}
+
+	signed.Set("timestamp", strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10))
+	signed.Set("recvWindow", recvWindow)
+
+	query := signed.Encode()
+
+	return c.do(method, path, query+"&signature="+Sign(c.secretKey, query), result)
+}
+
+// StreamURL returns the URL of the combined streams passed by argument, e.g. btceur@kline_1m
+func (c *Client) StreamURL(streams []string) string {
+	return c.streamURL + "/stream?streams=" + strings.Join(streams, "/")
+}
+
+func (c *Client) do(method, path, query string, result interface{}) error {
+	request, err := http.NewRequest(method, c.baseURL+path+"?"+query, nil)
+
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("X-MBX-APIKEY", c.apiKey)
+
+	response, err := c.httpClient.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		apiError := &Error{}
+
+		if err := json.Unmarshal(body, apiError); err != nil || apiError.Message == "" {
+			return &Error{Message: string(body), Status: response.StatusCode}
+		}
+
+		apiError.Status = response.StatusCode
+
+		return apiError
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(body, result)
+}
+
+// Sign returns the HMAC SHA256 signature of the payload
+func Sign(secretKey, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package binance_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/binance/binancetest"
+)
+
+func TestSign(t *testing.T) {
+	// example of the binance API documentation
+	got := binance.Sign(
+		"NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j",
+		"symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559",
+	)
+	want := "c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71"
+
+	if got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestClient(t *testing.T) {
+	server := binancetest.NewServer("key", "secret")
+	defer server.Close()
+
+	t.Run("should sign requests of the account", func(t *testing.T) {
+		err := server.Client().SignedQuery(http.MethodPost, "/api/v3/order", url.Values{"symbol": {"BTCEUR"}}, nil)
+
+		if err != nil {
+			t.Fatalf("Not expected SignedQuery to return error: %v", err)
+		}
+
+		if orders := server.PlacedOrders(); len(orders) != 1 || orders[0].Get("recvWindow") != "5000" {
+			t.Errorf("got orders %v want one order with the receive window", orders)
+		}
+	})
+
+	t.Run("should return the error of the API", func(t *testing.T) {
+		client := binance.NewWithURLs("key", "wrong secret", server.URL, "", server.Server.Client())
+		err := client.SignedQuery(http.MethodGet, "/api/v3/account", url.Values{}, nil)
+
+		apiError, ok := err.(*binance.Error)
+
+		if !ok || apiError.Code != -1022 {
+			t.Errorf("got %v want binance error -1022", err)
+		}
+	})
+}
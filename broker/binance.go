@@ -0,0 +1,213 @@
+package broker
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/symbols"
+)
+
+// BinanceBroker connects to binance to sell or buy assets
+type BinanceBroker struct {
+	client  *binance.Client
+	markets domain.MarketsService
+	asset   string
+	quote   string
+}
+
+// NewBinanceBroker returns an instance of a binance broker that buys or sells BTC with euros until other ticker or quote is set
+func NewBinanceBroker(client *binance.Client, markets domain.MarketsService) *BinanceBroker {
+	return &BinanceBroker{client, markets, "BTC", domain.DefaultQuote}
+}
+
+// SetTicker changes the asset bought or sold, orders of assets without a binance market return error
+func (bb *BinanceBroker) SetTicker(ticker string) {
+	bb.asset = ticker
+}
+
+// SetQuote changes the currency the assets are bought or sold with
+func (bb *BinanceBroker) SetQuote(quote string) {
+	bb.quote = quote
+}
+
+// AddBuyOrder request binance to place a buy order with details passed by arguments
+func (bb *BinanceBroker) AddBuyOrder(amount, price domain.Decimal) error {
+	return bb.PlaceOrder(domain.NewLimitOrder(domain.OrderBuy, amount, price))
+}
+
+// AddSellOrder request binance to place a sell order with details passed by arguments
+func (bb *BinanceBroker) AddSellOrder(amount, price domain.Decimal) error {
+	return bb.PlaceOrder(domain.NewLimitOrder(domain.OrderSell, amount, price))
+}
+
+// binanceOrderTypes are the binance order types by order type
+var binanceOrderTypes = map[domain.OrderType]string{
+	domain.OrderMarket:     "MARKET",
+	domain.OrderLimit:      "LIMIT",
+	domain.OrderStopLoss:   "STOP_LOSS",
+	domain.OrderStopLimit:  "STOP_LOSS_LIMIT",
+	domain.OrderTakeProfit: "TAKE_PROFIT",
+}
+
+// PlaceOrder request binance to place an order of the ticker, post-only limit orders are binance limit maker orders
+func (bb *BinanceBroker) PlaceOrder(order domain.Order) error {
+	if err := order.Validate(); err != nil {
+		return err
+	}
+
+	// binance spot orders do not have a reduce-only flag
+	if order.ReduceOnly {
+		return errors.New("binance does not support reduce-only orders")
+	}
+
+	market, err := bb.markets.Market(domain.ExchangeBinance, bb.asset, bb.quote)
+
+	if err != nil {
+		return err
+	}
+
+	quantity, limitPrice, err := FormatOrder(market, order.Amount, order.Price)
+
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{
+		"symbol":   {market.Symbol},
+		"side":     {strings.ToUpper(string(order.Side))},
+		"type":     {binanceOrderTypes[order.Type]},
+		"quantity": {quantity},
+	}
+
+	if order.HasLimitPrice() {
+		params.Set("price", limitPrice)
+		params.Set("timeInForce", string(order.GetTimeInForce()))
+	}
+
+	if order.HasStopPrice() {
+		params.Set("stopPrice", FormatPrice(market, order.StopPrice))
+	}
+
+	if order.PostOnly {
+		params.Set("type", "LIMIT_MAKER")
+		params.Del("timeInForce")
+	}
+
+	if order.ClientOrderID != "" {
+		params.Set("newClientOrderId", order.ClientOrderID)
+	}
+
+	return bb.client.SignedQuery(http.MethodPost, "/api/v3/order", params, nil)
+}
+
+// OrderPlaced returns true when binance has an order of the ticker with the client order id.
+// Binance keeps client order ids unique only among open orders so orders of other dates are not expected.
+func (bb *BinanceBroker) OrderPlaced(clientOrderID string, since time.Time) (bool, error) {
+	market, err := bb.markets.Market(domain.ExchangeBinance, bb.asset, bb.quote)
+
+	if err != nil {
+		return false, err
+	}
+
+	params := url.Values{"symbol": {market.Symbol}, "origClientOrderId": {clientOrderID}}
+	err = bb.client.SignedQuery(http.MethodGet, "/api/v3/order", params, nil)
+
+	if apiError, ok := err.(*binance.Error); ok && apiError.Code == binance.CodeNoSuchOrder {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// GetBalances returns the balances of the binance account by asset symbol, including the amounts locked by open orders
+func (bb *BinanceBroker) GetBalances() (map[string]domain.Decimal, error) {
+	var account struct {
+		Balances []struct {
+			Asset  string `json:"asset"`
+			Free   string `json:"free"`
+			Locked string `json:"locked"`
+		} `json:"balances"`
+	}
+
+	if err := bb.client.SignedQuery(http.MethodGet, "/api/v3/account", url.Values{}, &account); err != nil {
+		return nil, err
+	}
+
+	balances := map[string]domain.Decimal{}
+
+	for _, balance := range account.Balances {
+		free, err := domain.ParseDecimal(balance.Free)
+
+		if err != nil {
+			return nil, err
+		}
+
+		locked, err := domain.ParseDecimal(balance.Locked)
+
+		if err != nil {
+			return nil, err
+		}
+
+		symbol := symbols.Binance.Asset(balance.Asset)
+		balances[symbol] = balances[symbol].Add(free).Add(locked)
+	}
+
+	return balances, nil
+}
+
+// GetOpenOrders returns the orders of the binance account that are not filled yet
+func (bb *BinanceBroker) GetOpenOrders() ([]domain.OpenOrder, error) {
+	var response []struct {
+		Symbol      string `json:"symbol"`
+		OrderID     int64  `json:"orderId"`
+		Price       string `json:"price"`
+		OrigQty     string `json:"origQty"`
+		ExecutedQty string `json:"executedQty"`
+		Side        string `json:"side"`
+	}
+
+	if err := bb.client.SignedQuery(http.MethodGet, "/api/v3/openOrders", url.Values{}, &response); err != nil {
+		return nil, err
+	}
+
+	orders := []domain.OpenOrder{}
+
+	for _, order := range response {
+		quantity, err := domain.ParseDecimal(order.OrigQty)
+
+		if err != nil {
+			return nil, err
+		}
+
+		executed, err := domain.ParseDecimal(order.ExecutedQty)
+
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := domain.ParseDecimal(order.Price)
+
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, domain.OpenOrder{
+			ID:     strconv.FormatInt(order.OrderID, 10),
+			Asset:  marketAsset(bb.markets, domain.ExchangeBinance, order.Symbol, bb.quote, symbols.Binance.Asset),
+			Type:   strings.ToLower(order.Side),
+			Volume: quantity.Sub(executed),
+			Price:  price,
+		})
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+
+	return orders, nil
+}
@@ -0,0 +1,158 @@
+package broker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/binance/binancetest"
+	"github.com/fabiodmferreira/crypto-trading/broker"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/markets"
+)
+
+func TestBinanceBroker(t *testing.T) {
+	server := binancetest.NewServer("key", "secret")
+	defer server.Close()
+
+	server.Balances = []binancetest.Balance{{Asset: "EUR", Free: "1000.50", Locked: "250.00"}, {Asset: "BTC", Free: "0.012345678", Locked: "0"}}
+	server.OpenOrders = []binancetest.Order{
+		{Symbol: "ETHEUR", OrderID: 2, Price: "2000.00", OrigQty: "0.5000", ExecutedQty: "0.0000", Side: "SELL"},
+		{Symbol: "BTCEUR", OrderID: 1, Price: "30000.00", OrigQty: "0.01000", ExecutedQty: "0.00400", Side: "BUY"},
+	}
+
+	binanceMarkets := markets.NewService(map[string]markets.Loader{
+		domain.ExchangeBinance: markets.Static([]domain.Market{
+			{Exchange: domain.ExchangeBinance, Base: "ETH", Quote: "EUR", Symbol: "ETHEUR", WebsocketName: "etheur", PriceDecimals: 2, LotDecimals: 4, MinOrderSize: domain.NewDecimal(0.0001)},
+		}),
+	}, time.Hour)
+	binanceBroker := broker.NewBinanceBroker(server.Client(), binanceMarkets)
+
+	t.Run("should place limit orders of the ticker", func(t *testing.T) {
+		binanceBroker.SetTicker("ETH")
+
+		if err := binanceBroker.AddBuyOrder(domain.NewDecimal(0.123456), domain.NewDecimal(2000.555)); err != nil {
+			t.Fatalf("Not expected AddBuyOrder to return error: %v", err)
+		}
+
+		if err := binanceBroker.AddSellOrder(domain.NewDecimal(0.1), domain.NewDecimalFromInt(2100)); err != nil {
+			t.Fatalf("Not expected AddSellOrder to return error: %v", err)
+		}
+
+		orders := server.PlacedOrders()
+
+		if len(orders) != 2 {
+			t.Fatalf("got %d orders want 2", len(orders))
+		}
+
+		want := map[string]string{"symbol": "ETHEUR", "side": "BUY", "type": "LIMIT", "timeInForce": "GTC", "quantity": "0.1234", "price": "2000.55"}
+
+		for key, value := range want {
+			if got := orders[0].Get(key); got != value {
+				t.Errorf("got %v %v want %v", key, got, value)
+			}
+		}
+
+		if orders[1].Get("side") != "SELL" || orders[1].Get("price") != "2100.00" {
+			t.Errorf("got %v want a sell order at 2100.00", orders[1])
+		}
+	})
+
+	t.Run("should not place orders below the market minimum", func(t *testing.T) {
+		if err := binanceBroker.AddBuyOrder(domain.NewDecimal(0.00005), domain.NewDecimalFromInt(2000)); err == nil {
+			t.Errorf("Expected AddBuyOrder to return error")
+		}
+
+		if orders := server.PlacedOrders(); len(orders) != 2 {
+			t.Errorf("got %d orders want 2", len(orders))
+		}
+	})
+
+	t.Run("should place stop-limit orders with their stop price", func(t *testing.T) {
+		order := domain.Order{Side: domain.OrderSell, Type: domain.OrderStopLimit, Amount: domain.NewDecimal(0.1), Price: domain.NewDecimalFromInt(1800), StopPrice: domain.NewDecimal(1850.555)}
+
+		if err := binanceBroker.PlaceOrder(order); err != nil {
+			t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+		}
+
+		orders := server.PlacedOrders()
+		want := map[string]string{"side": "SELL", "type": "STOP_LOSS_LIMIT", "timeInForce": "GTC", "quantity": "0.1000", "price": "1800.00", "stopPrice": "1850.55"}
+
+		for key, value := range want {
+			if got := orders[len(orders)-1].Get(key); got != value {
+				t.Errorf("got %v %v want %v", key, got, value)
+			}
+		}
+	})
+
+	t.Run("should place post-only limit orders as limit maker orders", func(t *testing.T) {
+		order := domain.Order{Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: domain.NewDecimal(0.1), Price: domain.NewDecimalFromInt(1900), PostOnly: true}
+
+		if err := binanceBroker.PlaceOrder(order); err != nil {
+			t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+		}
+
+		orders := server.PlacedOrders()
+
+		if got := orders[len(orders)-1]; got.Get("type") != "LIMIT_MAKER" || got.Get("timeInForce") != "" {
+			t.Errorf("got %v want a limit maker order without time in force", got)
+		}
+	})
+
+	t.Run("should place market orders without price", func(t *testing.T) {
+		if err := binanceBroker.PlaceOrder(domain.Order{Side: domain.OrderSell, Type: domain.OrderMarket, Amount: domain.NewDecimal(0.1)}); err != nil {
+			t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+		}
+
+		orders := server.PlacedOrders()
+
+		if got := orders[len(orders)-1]; got.Get("type") != "MARKET" || got.Get("price") != "" || got.Get("timeInForce") != "" {
+			t.Errorf("got %v want a market order without price", got)
+		}
+	})
+
+	t.Run("should not place reduce-only orders", func(t *testing.T) {
+		placed := len(server.PlacedOrders())
+		order := domain.Order{Side: domain.OrderSell, Type: domain.OrderStopLoss, Amount: domain.NewDecimal(0.1), StopPrice: domain.NewDecimalFromInt(1800), ReduceOnly: true}
+
+		if err := binanceBroker.PlaceOrder(order); err == nil {
+			t.Errorf("Expected PlaceOrder to return error")
+		}
+
+		if orders := server.PlacedOrders(); len(orders) != placed {
+			t.Errorf("got %d orders want %d", len(orders), placed)
+		}
+	})
+
+	t.Run("should return balances by asset symbol", func(t *testing.T) {
+		got, err := binanceBroker.GetBalances()
+
+		if err != nil {
+			t.Fatalf("Not expected GetBalances to return error: %v", err)
+		}
+
+		want := map[string]string{"EUR": "1250.5", "BTC": "0.01234568"}
+
+		for symbol, balance := range want {
+			if got[symbol].String() != balance {
+				t.Errorf("got %v balance %v want %v", symbol, got[symbol], balance)
+			}
+		}
+	})
+
+	t.Run("should return the volume not executed of open orders", func(t *testing.T) {
+		got, err := binanceBroker.GetOpenOrders()
+
+		if err != nil {
+			t.Fatalf("Not expected GetOpenOrders to return error: %v", err)
+		}
+
+		want := []domain.OpenOrder{
+			{ID: "1", Asset: "BTC", Type: "buy", Volume: domain.NewDecimal(0.006), Price: domain.NewDecimalFromInt(30000)},
+			{ID: "2", Asset: "ETH", Type: "sell", Volume: domain.NewDecimal(0.5), Price: domain.NewDecimalFromInt(2000)},
+		}
+
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+}
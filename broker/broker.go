@@ -2,60 +2,256 @@ package broker
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	krakenapi "github.com/beldur/kraken-go-api-client"
-	"github.com/fabiodmferreira/crypto-trading/utils"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/symbols"
 )
 
 // KrakenBroker connects to kraken to sell or buy assets
 type KrakenBroker struct {
-	api    *krakenapi.KrakenAPI
-	ticker string
+	api     *krakenapi.KrakenAPI
+	markets domain.MarketsService
+	asset   string
+	quote   string
 }
 
-// NewKrakenBroker returns an instance of a kraken broker
-func NewKrakenBroker(api *krakenapi.KrakenAPI) *KrakenBroker {
-	return &KrakenBroker{api, krakenapi.XXBTZEUR}
+// NewKrakenBroker returns an instance of a kraken broker that buys or sells BTC with euros until other ticker or quote is set
+func NewKrakenBroker(api *krakenapi.KrakenAPI, markets domain.MarketsService) *KrakenBroker {
+	return &KrakenBroker{api, markets, "BTC", domain.DefaultQuote}
 }
 
-// SetTicker changes the ticker used to buy or sell assets
+// SetTicker changes the asset bought or sold, orders of assets without a kraken market return error
 func (kb *KrakenBroker) SetTicker(ticker string) {
-	switch strings.ToUpper(ticker) {
-	case "BTC":
-		kb.ticker = krakenapi.XXBTZEUR
-	case "ETH":
-		kb.ticker = krakenapi.XETHZEUR
-	case "ADA":
-		kb.ticker = krakenapi.ADAEUR
-	case "DOT":
-		kb.ticker = "DOTEUR"
-	case "ATOM":
-		kb.ticker = "ATOMEUR"
-	default:
-		panic(fmt.Sprintf("invalid ticker set in broker: %s", ticker))
-	}
+	kb.asset = ticker
+}
+
+// SetQuote changes the currency the assets are bought or sold with
+func (kb *KrakenBroker) SetQuote(quote string) {
+	kb.quote = quote
 }
 
 // AddBuyOrder request kraken to place a buy order with details passed by arguments
-func (kb *KrakenBroker) AddBuyOrder(amount, price float32) error {
-	amount = utils.RoundFloorDecimals(amount, 6)
-	return kb.addOrder(amount, price, "buy")
+func (kb *KrakenBroker) AddBuyOrder(amount, price domain.Decimal) error {
+	return kb.PlaceOrder(domain.NewLimitOrder(domain.OrderBuy, amount, price))
 }
 
 // AddSellOrder request kraken to place a sell order with details passed by arguments
-func (kb *KrakenBroker) AddSellOrder(amount, price float32) error {
-	amount = utils.RoundFloorDecimals(amount, 6)
-	return kb.addOrder(amount, price, "sell")
+func (kb *KrakenBroker) AddSellOrder(amount, price domain.Decimal) error {
+	return kb.PlaceOrder(domain.NewLimitOrder(domain.OrderSell, amount, price))
 }
 
-// addOrder is used by other methods to create orders in kraken
-func (kb *KrakenBroker) addOrder(amount, price float32, orderType string) error {
+// krakenOrderTypes are the kraken order types by order type
+var krakenOrderTypes = map[domain.OrderType]string{
+	domain.OrderMarket:     "market",
+	domain.OrderLimit:      "limit",
+	domain.OrderStopLoss:   "stop-loss",
+	domain.OrderStopLimit:  "stop-loss-limit",
+	domain.OrderTakeProfit: "take-profit",
+}
+
+// PlaceOrder request kraken to place an order of the ticker.
+// Kraken price is the stop price of the orders with one and price2 is the limit price of stop-limit orders.
+func (kb *KrakenBroker) PlaceOrder(order domain.Order) error {
+	if err := order.Validate(); err != nil {
+		return err
+	}
+
+	market, err := kb.markets.Market(domain.ExchangeKraken, kb.asset, kb.quote)
+
+	if err != nil {
+		return err
+	}
+
+	volume, limitPrice, err := FormatOrder(market, order.Amount, order.Price)
+
+	if err != nil {
+		return err
+	}
+
+	args := map[string]string{
+		"pair":      market.Symbol,
+		"type":      string(order.Side),
+		"ordertype": krakenOrderTypes[order.Type],
+		"volume":    volume,
+	}
+
+	switch {
+	case order.Type == domain.OrderStopLimit:
+		args["price"] = FormatPrice(market, order.StopPrice)
+		args["price2"] = limitPrice
+	case order.HasStopPrice():
+		args["price"] = FormatPrice(market, order.StopPrice)
+	case order.HasLimitPrice():
+		args["price"] = limitPrice
+	}
+
+	if order.HasLimitPrice() {
+		args["timeinforce"] = string(order.GetTimeInForce())
+	}
+
+	if order.PostOnly {
+		args["oflags"] = "post"
+	}
+
+	if order.ReduceOnly {
+		args["reduce_only"] = "true"
+	}
+
+	if order.ClientOrderID != "" {
+		if _, err := strconv.ParseInt(order.ClientOrderID, 10, 32); err != nil {
+			return fmt.Errorf("kraken client order id %v is not a 32 bits integer", order.ClientOrderID)
+		}
+
+		args["userref"] = order.ClientOrderID
+	}
 
-	_, err := kb.api.AddOrder(kb.ticker, orderType, "limit", fmt.Sprintf("%f", amount), map[string]string{"price": fmt.Sprintf("%.1f", price)})
+	// AddOrder of the kraken client does not send the time in force
+	_, err = kb.api.Query("AddOrder", args)
 	return err
 }
 
+// OrderPlaced returns true when an open or closed order of the account opened since the date has the client order id as user reference
+func (kb *KrakenBroker) OrderPlaced(clientOrderID string, since time.Time) (bool, error) {
+	open, err := kb.api.OpenOrders(map[string]string{"userref": clientOrderID})
+
+	if err != nil {
+		return false, err
+	}
+
+	if krakenOrderOpenedSince(open.Open, since) {
+		return true, nil
+	}
+
+	closed, err := kb.api.ClosedOrders(map[string]string{"userref": clientOrderID, "start": strconv.FormatInt(since.Unix(), 10)})
+
+	if err != nil {
+		return false, err
+	}
+
+	return krakenOrderOpenedSince(closed.Closed, since), nil
+}
+
+// krakenOrderOpenedSince returns true when one of the orders was opened since the date
+func krakenOrderOpenedSince(orders map[string]krakenapi.Order, since time.Time) bool {
+	for _, order := range orders {
+		if order.OpenTime >= float64(since.Unix()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetBalances returns the balances of the kraken account by asset symbol
+func (kb *KrakenBroker) GetBalances() (map[string]domain.Decimal, error) {
+	result, err := kb.api.Query("Balance", map[string]string{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	values, ok := result.(map[string]interface{})
+
+	if !ok {
+		return nil, fmt.Errorf("unexpected kraken balance response %v", result)
+	}
+
+	balances := map[string]domain.Decimal{}
+
+	for code, value := range values {
+		balance, err := domain.ParseDecimal(fmt.Sprint(value))
+
+		if err != nil {
+			return nil, err
+		}
+
+		symbol := KrakenAssetSymbol(code)
+		balances[symbol] = balances[symbol].Add(balance)
+	}
+
+	return balances, nil
+}
+
+// GetOpenOrders returns the orders of the kraken account that are not filled yet
+func (kb *KrakenBroker) GetOpenOrders() ([]domain.OpenOrder, error) {
+	response, err := kb.api.OpenOrders(map[string]string{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []domain.OpenOrder{}
+
+	for id, order := range response.Open {
+		volume, err := domain.ParseDecimal(order.Volume)
+
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := domain.ParseDecimal(order.Description.PrimaryPrice)
+
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, domain.OpenOrder{
+			ID:     id,
+			Asset:  marketAsset(kb.markets, domain.ExchangeKraken, order.Description.AssetPair, kb.quote, KrakenAssetSymbol),
+			Type:   order.Description.Type,
+			Volume: volume.Sub(domain.NewDecimal(order.VolumeExecuted)),
+			Price:  price,
+		})
+	}
+
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID < orders[j].ID })
+
+	return orders, nil
+}
+
+// KrakenAssetSymbol returns the symbol of a kraken asset code, e.g. BTC for XXBT and EUR for ZEUR
+func KrakenAssetSymbol(code string) string {
+	return symbols.Kraken.Asset(code)
+}
+
+// marketAsset returns the base asset of the market of an exchange pair.
+// Pairs without market are expected to be traded with the quote currency passed by argument.
+func marketAsset(marketsService domain.MarketsService, exchange, pair, quote string, assetSymbol func(string) string) string {
+	markets, err := marketsService.Markets(exchange)
+
+	if err == nil {
+		for _, market := range markets {
+			if market.Symbol == pair || strings.Replace(market.WebsocketName, "/", "", 1) == pair {
+				return market.Base
+			}
+		}
+	}
+
+	return assetSymbol(strings.TrimSuffix(pair, quote))
+}
+
+// FormatOrder returns the volume and the price of an order with the decimals accepted by the market.
+// Volume and price are truncated so orders never spend more than the amounts passed by argument.
+func FormatOrder(market *domain.Market, amount, price domain.Decimal) (string, string, error) {
+	volume := amount.Truncate(market.LotDecimals)
+
+	if volume.LessThan(market.MinOrderSize) {
+		return "", "", fmt.Errorf("order volume %v is below the %v minimum of %v", volume, market.Symbol, market.MinOrderSize)
+	}
+
+	return volume.StringFixed(market.LotDecimals), FormatPrice(market, price), nil
+}
+
+// FormatPrice returns the price truncated to the decimals accepted by the market
+func FormatPrice(market *domain.Market, price domain.Decimal) string {
+	return price.Truncate(market.PriceDecimals).StringFixed(market.PriceDecimals)
+}
+
 // BrokerMock is a broker stub to test it locally
 type BrokerMock struct {
 }
@@ -70,14 +266,25 @@ func (bm *BrokerMock) SetTicker(ticker string) {
 	fmt.Printf("Set ticker %s\n", ticker)
 }
 
+// SetQuote stub
+func (bm *BrokerMock) SetQuote(quote string) {
+	fmt.Printf("Set quote %s\n", quote)
+}
+
 // AddBuyOrder stub
-func (bm *BrokerMock) AddBuyOrder(amount, price float32) error {
-	fmt.Printf("Add buy order (amount:%f,price:%f)\n", amount, price)
+func (bm *BrokerMock) AddBuyOrder(amount, price domain.Decimal) error {
+	fmt.Printf("Add buy order (amount:%v,price:%v)\n", amount, price)
 	return nil
 }
 
 // AddSellOrder stub
-func (bm *BrokerMock) AddSellOrder(amount, price float32) error {
-	fmt.Printf("Add sell order (amount:%f,price:%f)\n", amount, price)
+func (bm *BrokerMock) AddSellOrder(amount, price domain.Decimal) error {
+	fmt.Printf("Add sell order (amount:%v,price:%v)\n", amount, price)
 	return nil
 }
+
+// PlaceOrder stub
+func (bm *BrokerMock) PlaceOrder(order domain.Order) error {
+	fmt.Printf("Place %v %v order (amount:%v,price:%v,stopPrice:%v)\n", order.Type, order.Side, order.Amount, order.Price, order.StopPrice)
+	return order.Validate()
+}
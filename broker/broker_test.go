@@ -0,0 +1,253 @@
+package broker_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+	"github.com/fabiodmferreira/crypto-trading/broker"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/markets"
+)
+
+func TestFormatOrder(t *testing.T) {
+	cases := []struct {
+		market     domain.Market
+		amount     float64
+		price      float64
+		wantVolume string
+		wantPrice  string
+	}{
+		{domain.Market{Symbol: "XXBTZEUR", PriceDecimals: 1, LotDecimals: 8, MinOrderSize: domain.NewDecimal(0.0001)}, 0.00123456, 43210.56, "0.00123456", "43210.5"},
+		{domain.Market{Symbol: "ADAEUR", PriceDecimals: 6, LotDecimals: 8}, 1500.5, 0.3456789, "1500.50000000", "0.345678"},
+		{domain.Market{Symbol: "DOTEUR", PriceDecimals: 4, LotDecimals: 8}, 12, 30.12345, "12.00000000", "30.1234"},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.market.Symbol+" should use the market decimals", func(t *testing.T) {
+			volume, price, err := broker.FormatOrder(&c.market, domain.NewDecimal(c.amount), domain.NewDecimal(c.price))
+
+			if err != nil {
+				t.Fatalf("Not expected FormatOrder to return error: %v", err)
+			}
+
+			if volume != c.wantVolume || price != c.wantPrice {
+				t.Errorf("got volume %v and price %v want %v and %v", volume, price, c.wantVolume, c.wantPrice)
+			}
+		})
+	}
+
+	t.Run("should return error for volumes below the market minimum", func(t *testing.T) {
+		market := &domain.Market{Symbol: "XXBTZEUR", PriceDecimals: 1, LotDecimals: 8, MinOrderSize: domain.NewDecimal(0.0001)}
+
+		if _, _, err := broker.FormatOrder(market, domain.NewDecimal(0.00009), domain.NewDecimalFromInt(40000)); err == nil {
+			t.Errorf("Expected FormatOrder to return error")
+		}
+	})
+}
+
+// stubKrakenAPI returns a kraken api client sending requests to a server that answers with the results by method
+func stubKrakenAPI(t *testing.T, results map[string]string) *krakenapi.KrakenAPI {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok := results[r.URL.Path]
+
+		if !ok {
+			t.Errorf("Not expected request to %v", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"error":[],"result":%s}`, result)
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, _ := url.Parse(server.URL)
+	client := &http.Client{Transport: rewriteHost{serverURL}}
+
+	return krakenapi.NewWithClient("key", "c2VjcmV0", client)
+}
+
+type rewriteHost struct {
+	url *url.URL
+}
+
+func (rh rewriteHost) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = rh.url.Scheme, rh.url.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestKrakenBrokerAccount(t *testing.T) {
+	api := stubKrakenAPI(t, map[string]string{
+		"/0/private/Balance": `{"ZEUR":"1250.5000","XXBT":"0.0123456789","XETH":"0.0000000000"}`,
+		"/0/private/OpenOrders": `{"open":{
+			"OB5VMB-B4U2U-DK2WRW":{"status":"open","descr":{"pair":"XBTEUR","type":"buy","price":"30000.0"},"vol":"0.01000000","vol_exec":"0.00400000"},
+			"OAVY7T-MV5VK-KHDF5X":{"status":"open","descr":{"pair":"ETHEUR","type":"sell","price":"2000.00"},"vol":"0.50000000","vol_exec":"0.00000000"}
+		}}`,
+	})
+	kraken := broker.NewKrakenBroker(api, markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: markets.Static(nil)}, time.Hour))
+
+	t.Run("should return balances by asset symbol", func(t *testing.T) {
+		got, err := kraken.GetBalances()
+
+		if err != nil {
+			t.Fatalf("Not expected GetBalances to return error: %v", err)
+		}
+
+		want := map[string]string{"EUR": "1250.5", "BTC": "0.01234568", "ETH": "0"}
+
+		for symbol, balance := range want {
+			if got[symbol].String() != balance {
+				t.Errorf("got %v balance %v want %v", symbol, got[symbol], balance)
+			}
+		}
+	})
+
+	t.Run("should return the volume not executed of open orders", func(t *testing.T) {
+		got, err := kraken.GetOpenOrders()
+
+		if err != nil {
+			t.Fatalf("Not expected GetOpenOrders to return error: %v", err)
+		}
+
+		want := []domain.OpenOrder{
+			{ID: "OAVY7T-MV5VK-KHDF5X", Asset: "ETH", Type: "sell", Volume: domain.NewDecimal(0.5), Price: domain.NewDecimalFromInt(2000)},
+			{ID: "OB5VMB-B4U2U-DK2WRW", Asset: "BTC", Type: "buy", Volume: domain.NewDecimal(0.006), Price: domain.NewDecimalFromInt(30000)},
+		}
+
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+}
+
+func TestKrakenBrokerOrders(t *testing.T) {
+	kraken := broker.NewKrakenBroker(stubKrakenAPI(t, map[string]string{}), markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: markets.Static(nil)}, time.Hour))
+
+	t.Run("should return error for assets without kraken market", func(t *testing.T) {
+		kraken.SetTicker("XYZ")
+
+		if err := kraken.AddBuyOrder(domain.NewDecimal(1), domain.NewDecimal(1)); err == nil {
+			t.Errorf("Expected AddBuyOrder to return error")
+		}
+	})
+}
+
+func TestKrakenBrokerPlaceOrder(t *testing.T) {
+	requests := []url.Values{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// kraken client does not set the form content type
+		body, _ := ioutil.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		requests = append(requests, form)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"error":[],"result":{"descr":{"order":""},"txid":["OUF4EM-FRGI2-MQMWZD"]}}`)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	api := krakenapi.NewWithClient("key", "c2VjcmV0", &http.Client{Transport: rewriteHost{serverURL}})
+	kraken := broker.NewKrakenBroker(api, markets.NewService(map[string]markets.Loader{
+		domain.ExchangeKraken: markets.Static([]domain.Market{
+			{Exchange: domain.ExchangeKraken, Base: "BTC", Quote: "EUR", Symbol: "XXBTZEUR", WebsocketName: "XBT/EUR", PriceDecimals: 1, LotDecimals: 8, MinOrderSize: domain.NewDecimal(0.0001)},
+		}),
+	}, time.Hour))
+
+	cases := []struct {
+		name  string
+		order domain.Order
+		want  map[string]string
+	}{
+		{
+			"should place post-only limit orders",
+			domain.Order{Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: domain.NewDecimal(0.01), Price: domain.NewDecimal(30000.55), PostOnly: true},
+			map[string]string{"pair": "XXBTZEUR", "type": "buy", "ordertype": "limit", "volume": "0.01000000", "price": "30000.5", "timeinforce": "GTC", "oflags": "post"},
+		},
+		{
+			"should place market orders without price",
+			domain.Order{Side: domain.OrderSell, Type: domain.OrderMarket, Amount: domain.NewDecimal(0.01)},
+			map[string]string{"type": "sell", "ordertype": "market", "price": "", "timeinforce": ""},
+		},
+		{
+			"should place stop-limit orders with the stop price as price",
+			domain.Order{Side: domain.OrderSell, Type: domain.OrderStopLimit, Amount: domain.NewDecimal(0.01), Price: domain.NewDecimalFromInt(27000), StopPrice: domain.NewDecimalFromInt(28000), TimeInForce: domain.ImmediateOrCancel},
+			map[string]string{"ordertype": "stop-loss-limit", "price": "28000.0", "price2": "27000.0", "timeinforce": "IOC"},
+		},
+		{
+			"should place take-profit orders",
+			domain.Order{Side: domain.OrderSell, Type: domain.OrderTakeProfit, Amount: domain.NewDecimal(0.01), StopPrice: domain.NewDecimalFromInt(40000), ReduceOnly: true},
+			map[string]string{"ordertype": "take-profit", "price": "40000.0", "reduce_only": "true"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			kraken.SetTicker("BTC")
+
+			if err := kraken.PlaceOrder(c.order); err != nil {
+				t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+			}
+
+			got := requests[len(requests)-1]
+
+			for key, value := range c.want {
+				if got.Get(key) != value {
+					t.Errorf("got %v %q want %q", key, got.Get(key), value)
+				}
+			}
+		})
+	}
+
+	t.Run("should not send invalid orders", func(t *testing.T) {
+		sent := len(requests)
+
+		if err := kraken.PlaceOrder(domain.Order{Side: domain.OrderBuy, Type: domain.OrderStopLoss, Amount: domain.NewDecimal(0.01)}); err == nil {
+			t.Errorf("Expected PlaceOrder to return error")
+		}
+
+		if len(requests) != sent {
+			t.Errorf("got %d requests want %d", len(requests), sent)
+		}
+	})
+}
+
+func TestKrakenBrokerOrderPlaced(t *testing.T) {
+	since := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	api := stubKrakenAPI(t, map[string]string{
+		"/0/private/OpenOrders":   `{"open":{"OB5VMB-B4U2U-DK2WRW":{"userref":7,"status":"open","opentm":1577836800.1}}}`,
+		"/0/private/ClosedOrders": fmt.Sprintf(`{"closed":{"OAVY7T-MV5VK-KHDF5X":{"userref":7,"status":"closed","opentm":%d}},"count":1}`, since.Unix()+60),
+	})
+	kraken := broker.NewKrakenBroker(api, markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: markets.Static(nil)}, time.Hour))
+
+	t.Run("should find orders opened since the date", func(t *testing.T) {
+		placed, err := kraken.OrderPlaced("7", since)
+
+		if err != nil {
+			t.Fatalf("Not expected OrderPlaced to return error: %v", err)
+		}
+
+		if !placed {
+			t.Errorf("Expected the closed order to be found")
+		}
+	})
+
+	t.Run("should not find orders opened before the date", func(t *testing.T) {
+		if placed, _ := kraken.OrderPlaced("7", since.Add(time.Hour)); placed {
+			t.Errorf("Expected orders opened before the date not to be found")
+		}
+	})
+}
+
+func TestKrakenAssetSymbol(t *testing.T) {
+	for code, want := range map[string]string{"XXBT": "BTC", "XBT": "BTC", "ZEUR": "EUR", "XETH": "ETH", "ADA": "ADA", "DOT": "DOT"} {
+		if got := broker.KrakenAssetSymbol(code); got != want {
+			t.Errorf("got %v want %v for %v", got, want, code)
+		}
+	}
+}
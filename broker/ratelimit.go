@@ -0,0 +1,82 @@
+package broker
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimit is the number of requests of an exchange API counter and the time to regain one request
+type RateLimit struct {
+	Capacity int
+	Interval time.Duration
+}
+
+// KrakenRateLimits are the API counters of the kraken verification tiers
+var KrakenRateLimits = map[string]RateLimit{
+	"starter":      {Capacity: 15, Interval: 3 * time.Second},
+	"intermediate": {Capacity: 20, Interval: 2 * time.Second},
+	"pro":          {Capacity: 20, Interval: time.Second},
+}
+
+// BinanceRateLimit is the request weight of binance, 1200 per minute
+var BinanceRateLimit = RateLimit{Capacity: 1200, Interval: 50 * time.Millisecond}
+
+// KrakenRateLimit returns the API counter of a kraken tier, starter when it is empty
+func KrakenRateLimit(tier string) (RateLimit, error) {
+	if tier == "" {
+		tier = "starter"
+	}
+
+	limit, ok := KrakenRateLimits[tier]
+
+	if !ok {
+		return RateLimit{}, fmt.Errorf("kraken tier %v does not exist", tier)
+	}
+
+	return limit, nil
+}
+
+// RateLimiter is a token bucket shared by the brokers of an exchange account
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a rate limiter with every request of the limit available
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{limit: limit, tokens: float64(limit.Capacity), last: time.Now()}
+}
+
+// Wait blocks until the limiter has the requests passed by argument and takes them. A nil limiter does not block.
+func (r *RateLimiter) Wait(cost int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cost > r.limit.Capacity {
+		cost = r.limit.Capacity
+	}
+
+	for {
+		now := time.Now()
+		r.tokens += float64(now.Sub(r.last)) / float64(r.limit.Interval)
+		r.last = now
+
+		if r.tokens > float64(r.limit.Capacity) {
+			r.tokens = float64(r.limit.Capacity)
+		}
+
+		if r.tokens >= float64(cost) {
+			r.tokens -= float64(cost)
+			return
+		}
+
+		time.Sleep(time.Duration((float64(cost) - r.tokens) * float64(r.limit.Interval)))
+	}
+}
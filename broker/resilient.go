@@ -0,0 +1,234 @@
+package broker
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// ExchangeBroker is a broker of an exchange account that finds its orders by client order id
+type ExchangeBroker interface {
+	domain.Broker
+	domain.BrokerAccount
+	domain.OrderLookup
+}
+
+// RetryOptions decide how many times and how long after a request failed by a transient error is retried
+type RetryOptions struct {
+	Retries int
+	// Backoff is the wait before the first retry, it doubles on each retry until MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryOptions retries 3 times during 7 seconds
+var DefaultRetryOptions = RetryOptions{Retries: 3, Backoff: time.Second, MaxBackoff: 10 * time.Second}
+
+// clockSkew is subtracted from the date orders are looked up since, so orders dated by a late exchange clock are found
+const clockSkew = time.Minute
+
+// krakenTransientErrors are the kraken errors of requests that can succeed when retried
+var krakenTransientErrors = []string{
+	"EAPI:Rate limit exceeded",
+	"EAPI:Invalid nonce",
+	"EOrder:Rate limit exceeded",
+	"EService:Unavailable",
+	"EService:Busy",
+	"EGeneral:Temporary lockout",
+	// requests not sent, responses not read or not decoded
+	"Could not execute request! #2",
+	"Could not execute request! #3",
+	"Could not execute request #4!",
+	"Could not execute request #5!",
+	"Could not execute request! #6",
+}
+
+// ResilientBroker limits the requests of a broker to the exchange rate limit and retries the requests failed by transient errors.
+// Orders get a client order id so a retry places them only when the exchange did not accept them before.
+type ResilientBroker struct {
+	mu      sync.Mutex
+	broker  ExchangeBroker
+	limiter *RateLimiter
+	options RetryOptions
+}
+
+// NewResilientBroker returns a broker that calls the broker passed by argument. Brokers of the same exchange account must share the limiter.
+func NewResilientBroker(broker ExchangeBroker, limiter *RateLimiter, options RetryOptions) *ResilientBroker {
+	return &ResilientBroker{broker: broker, limiter: limiter, options: options}
+}
+
+// SetTicker changes the asset bought or sold
+func (rb *ResilientBroker) SetTicker(ticker string) {
+	rb.broker.SetTicker(ticker)
+}
+
+// SetQuote changes the currency the assets are bought or sold with
+func (rb *ResilientBroker) SetQuote(quote string) {
+	rb.broker.SetQuote(quote)
+}
+
+// AddBuyOrder places a good till cancelled limit buy order
+func (rb *ResilientBroker) AddBuyOrder(amount, price domain.Decimal) error {
+	return rb.PlaceOrder(domain.NewLimitOrder(domain.OrderBuy, amount, price))
+}
+
+// AddSellOrder places a good till cancelled limit sell order
+func (rb *ResilientBroker) AddSellOrder(amount, price domain.Decimal) error {
+	return rb.PlaceOrder(domain.NewLimitOrder(domain.OrderSell, amount, price))
+}
+
+// PlaceOrder places the order with a new client order id when it does not have one.
+// Before each retry the order is looked up and it is only placed again when the exchange does not have it.
+func (rb *ResilientBroker) PlaceOrder(order domain.Order) error {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if order.ClientOrderID == "" {
+		id, err := NewClientOrderID()
+
+		if err != nil {
+			return err
+		}
+
+		order.ClientOrderID = id
+	}
+
+	since := time.Now().Add(-clockSkew)
+
+	rb.limiter.Wait(1)
+	err := rb.broker.PlaceOrder(order)
+	backoff := rb.options.Backoff
+
+	for retry := 0; retry < rb.options.Retries && IsTransient(err); retry++ {
+		time.Sleep(backoff)
+		backoff = rb.nextBackoff(backoff)
+
+		// kraken closed orders cost two requests
+		rb.limiter.Wait(3)
+		placed, lookupErr := rb.broker.OrderPlaced(order.ClientOrderID, since)
+
+		if lookupErr != nil {
+			// the order is not placed again until the exchange confirms it does not have it
+			err = fmt.Errorf("order %v may have been placed: %v, lookup failed: %w", order.ClientOrderID, err, lookupErr)
+			continue
+		}
+
+		if placed {
+			return nil
+		}
+
+		rb.limiter.Wait(1)
+		err = rb.broker.PlaceOrder(order)
+	}
+
+	return err
+}
+
+// OrderPlaced returns true when the exchange accepted an order with the client order id since the date
+func (rb *ResilientBroker) OrderPlaced(clientOrderID string, since time.Time) (placed bool, err error) {
+	err = rb.retry(3, func() error {
+		placed, err = rb.broker.OrderPlaced(clientOrderID, since)
+		return err
+	})
+
+	return placed, err
+}
+
+// GetBalances returns the balances of the exchange account by asset symbol
+func (rb *ResilientBroker) GetBalances() (balances map[string]domain.Decimal, err error) {
+	err = rb.retry(1, func() error {
+		balances, err = rb.broker.GetBalances()
+		return err
+	})
+
+	return balances, err
+}
+
+// GetOpenOrders returns the orders of the exchange account that are not filled yet
+func (rb *ResilientBroker) GetOpenOrders() (orders []domain.OpenOrder, err error) {
+	err = rb.retry(1, func() error {
+		orders, err = rb.broker.GetOpenOrders()
+		return err
+	})
+
+	return orders, err
+}
+
+// retry calls a request that reads the exchange account until it does not fail by a transient error
+func (rb *ResilientBroker) retry(cost int, request func() error) error {
+	rb.limiter.Wait(cost)
+	err := request()
+	backoff := rb.options.Backoff
+
+	for retry := 0; retry < rb.options.Retries && IsTransient(err); retry++ {
+		time.Sleep(backoff)
+		backoff = rb.nextBackoff(backoff)
+
+		rb.limiter.Wait(cost)
+		err = request()
+	}
+
+	return err
+}
+
+func (rb *ResilientBroker) nextBackoff(backoff time.Duration) time.Duration {
+	if backoff *= 2; rb.options.MaxBackoff > 0 && backoff > rb.options.MaxBackoff {
+		return rb.options.MaxBackoff
+	}
+
+	return backoff
+}
+
+// IsTransient returns true for the errors of requests that can succeed when they are retried,
+// e.g. network errors, rate limits and exchanges unavailable
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netError net.Error
+
+	if errors.As(err, &netError) {
+		return true
+	}
+
+	var binanceError *binance.Error
+
+	if errors.As(err, &binanceError) {
+		switch binanceError.Code {
+		case binance.CodeDisconnected, binance.CodeTooManyRequests, binance.CodeUnknownStatus, binance.CodeTimeout, binance.CodeTooManyOrders:
+			return true
+		}
+
+		return binanceError.Status == http.StatusTooManyRequests || binanceError.Status >= http.StatusInternalServerError
+	}
+
+	for _, transientError := range krakenTransientErrors {
+		if strings.Contains(err.Error(), transientError) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewClientOrderID returns a random client order id accepted by kraken and binance, a positive 32 bits integer
+func NewClientOrderID() (string, error) {
+	var bytes [4]byte
+
+	if _, err := rand.Read(bytes[:]); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatUint(uint64(binary.BigEndian.Uint32(bytes[:])&0x7fffffff|1), 10), nil
+}
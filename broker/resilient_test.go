@@ -0,0 +1,132 @@
+package broker_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/binance/binancetest"
+	"github.com/fabiodmferreira/crypto-trading/broker"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/markets"
+)
+
+func TestResilientBroker(t *testing.T) {
+	server := binancetest.NewServer("key", "secret")
+	defer server.Close()
+
+	binanceMarkets := markets.NewService(map[string]markets.Loader{
+		domain.ExchangeBinance: markets.Static([]domain.Market{
+			{Exchange: domain.ExchangeBinance, Base: "BTC", Quote: "EUR", Symbol: "BTCEUR", WebsocketName: "btceur", PriceDecimals: 2, LotDecimals: 5, MinOrderSize: domain.NewDecimal(0.0001)},
+		}),
+	}, time.Hour)
+	resilientBroker := broker.NewResilientBroker(
+		broker.NewBinanceBroker(server.Client(), binanceMarkets),
+		broker.NewRateLimiter(broker.BinanceRateLimit),
+		broker.RetryOptions{Retries: 2, Backoff: time.Millisecond},
+	)
+	unavailable := binancetest.OrderFailure{Status: http.StatusServiceUnavailable}
+
+	t.Run("should place orders with a client order id", func(t *testing.T) {
+		if err := resilientBroker.AddBuyOrder(domain.NewDecimal(0.01), domain.NewDecimalFromInt(30000)); err != nil {
+			t.Fatalf("Not expected AddBuyOrder to return error: %v", err)
+		}
+
+		orders := server.PlacedOrders()
+
+		if len(orders) != 1 || orders[0].Get("newClientOrderId") == "" {
+			t.Errorf("got %v want one order with a client order id", orders)
+		}
+	})
+
+	t.Run("should not place again an order accepted before it failed", func(t *testing.T) {
+		server.OrderFailures = []binancetest.OrderFailure{{Status: http.StatusServiceUnavailable, Code: binance.CodeUnknownStatus, Message: "Unknown error, please check your request or try again later.", Accept: true}}
+
+		if err := resilientBroker.AddBuyOrder(domain.NewDecimal(0.01), domain.NewDecimalFromInt(30000)); err != nil {
+			t.Fatalf("Not expected AddBuyOrder to return error: %v", err)
+		}
+
+		if orders := server.PlacedOrders(); len(orders) != 2 {
+			t.Errorf("got %d orders want 2", len(orders))
+		}
+	})
+
+	t.Run("should place again an order not accepted", func(t *testing.T) {
+		server.OrderFailures = []binancetest.OrderFailure{unavailable, unavailable}
+
+		if err := resilientBroker.AddSellOrder(domain.NewDecimal(0.01), domain.NewDecimalFromInt(31000)); err != nil {
+			t.Fatalf("Not expected AddSellOrder to return error: %v", err)
+		}
+
+		orders := server.PlacedOrders()
+
+		if len(orders) != 3 || orders[2].Get("side") != "SELL" {
+			t.Errorf("got %v want the sell order placed", orders)
+		}
+	})
+
+	t.Run("should return the error of the last retry", func(t *testing.T) {
+		server.OrderFailures = []binancetest.OrderFailure{unavailable, unavailable, unavailable}
+
+		if err := resilientBroker.AddSellOrder(domain.NewDecimal(0.01), domain.NewDecimalFromInt(31000)); err == nil {
+			t.Errorf("Expected AddSellOrder to return error")
+		}
+
+		if orders := server.PlacedOrders(); len(orders) != 3 {
+			t.Errorf("got %d orders want 3", len(orders))
+		}
+	})
+
+	t.Run("should not retry errors that are not transient", func(t *testing.T) {
+		server.OrderFailures = []binancetest.OrderFailure{{Status: http.StatusBadRequest, Code: -2010, Message: "Account has insufficient balance for requested action."}, unavailable}
+
+		if err := resilientBroker.AddBuyOrder(domain.NewDecimal(0.01), domain.NewDecimalFromInt(30000)); err == nil {
+			t.Errorf("Expected AddBuyOrder to return error")
+		}
+
+		if failures := len(server.OrderFailures); failures != 1 {
+			t.Errorf("got %d failures left want 1", failures)
+		}
+
+		server.OrderFailures = nil
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := map[error]bool{
+		nil: false,
+		errors.New("Could not execute request! #7 ([EService:Unavailable])"):                  true,
+		errors.New("Could not execute request! #7 ([EAPI:Rate limit exceeded])"):              true,
+		errors.New("Could not execute request! #7 ([EOrder:Insufficient funds])"):             false,
+		&binance.Error{Code: binance.CodeTooManyRequests, Status: http.StatusTooManyRequests}: true,
+		&binance.Error{Status: http.StatusBadGateway}:                                         true,
+		&binance.Error{Code: -1013, Status: http.StatusBadRequest}:                            false,
+		fmt.Errorf("placing order: %w", &binance.Error{Code: binance.CodeTimeout}):            true,
+	}
+
+	for err, want := range cases {
+		if got := broker.IsTransient(err); got != want {
+			t.Errorf("got %v want %v for %v", got, want, err)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := broker.NewRateLimiter(broker.RateLimit{Capacity: 2, Interval: 20 * time.Millisecond})
+	start := time.Now()
+
+	for i := 0; i < 4; i++ {
+		limiter.Wait(1)
+	}
+
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("got 4 requests in %v want the last 2 to wait 40ms", elapsed)
+	}
+
+	if _, err := broker.KrakenRateLimit("diamond"); err == nil {
+		t.Errorf("Expected KrakenRateLimit to return error")
+	}
+}
@@ -0,0 +1,168 @@
+package broker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShadowBroker records the orders of a shadow application and simulates their fills with the live prices.
+// Limit orders that can be filled at the last price are filled at their price, other orders stay open until a price crosses them.
+type ShadowBroker struct {
+	mu            sync.Mutex
+	applicationID primitive.ObjectID
+	orders        domain.ShadowOrdersRepository
+	asset         string
+	quote         string
+	// prices are the last prices by asset
+	prices map[string]domain.Decimal
+	open   []*domain.ShadowOrder
+}
+
+// NewShadowBroker returns a shadow broker of BTC with euros until other ticker or quote is set
+func NewShadowBroker(applicationID primitive.ObjectID, orders domain.ShadowOrdersRepository) *ShadowBroker {
+	return &ShadowBroker{applicationID: applicationID, orders: orders, asset: "BTC", quote: domain.DefaultQuote, prices: map[string]domain.Decimal{}}
+}
+
+// SetTicker changes the asset bought or sold
+func (sb *ShadowBroker) SetTicker(ticker string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	sb.asset = ticker
+}
+
+// SetQuote changes the currency the assets are bought or sold with
+func (sb *ShadowBroker) SetQuote(quote string) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	sb.quote = quote
+}
+
+// AddBuyOrder records a good till cancelled limit buy order
+func (sb *ShadowBroker) AddBuyOrder(amount, price domain.Decimal) error {
+	return sb.PlaceOrder(domain.NewLimitOrder(domain.OrderBuy, amount, price))
+}
+
+// AddSellOrder records a good till cancelled limit sell order
+func (sb *ShadowBroker) AddSellOrder(amount, price domain.Decimal) error {
+	return sb.PlaceOrder(domain.NewLimitOrder(domain.OrderSell, amount, price))
+}
+
+// PlaceOrder records the order and fills it when the last price of the ticker crosses it
+func (sb *ShadowBroker) PlaceOrder(order domain.Order) error {
+	if err := order.Validate(); err != nil {
+		return err
+	}
+
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	shadowOrder := &domain.ShadowOrder{
+		ID:            primitive.NewObjectID(),
+		ApplicationID: sb.applicationID,
+		Asset:         sb.asset,
+		Quote:         sb.quote,
+		Side:          order.Side,
+		Type:          order.Type,
+		Amount:        order.Amount,
+		Price:         order.Price,
+		StopPrice:     order.StopPrice,
+		Status:        domain.ShadowOrderOpen,
+		CreatedAt:     time.Now(),
+	}
+
+	price, ok := sb.prices[sb.asset]
+
+	// strategies place limit orders at the price they receive, so they are filled before the next price
+	if order.Type == domain.OrderLimit && (!ok || reached(shadowOrder.Side, price, order.Price)) {
+		fill(shadowOrder, order.Price, shadowOrder.CreatedAt)
+	} else if order.Type == domain.OrderMarket && ok {
+		fill(shadowOrder, price, shadowOrder.CreatedAt)
+	}
+
+	if err := sb.orders.Create(shadowOrder); err != nil {
+		return err
+	}
+
+	if shadowOrder.Status == domain.ShadowOrderOpen {
+		sb.open = append(sb.open, shadowOrder)
+	}
+
+	return nil
+}
+
+// OnNewAssetPrice fills the open orders of the asset crossed by the close price
+func (sb *ShadowBroker) OnNewAssetPrice(ohlc *domain.OHLC) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	price := ohlc.Close
+	sb.prices[ohlc.Asset] = price
+
+	open := []*domain.ShadowOrder{}
+
+	for _, order := range sb.open {
+		orderPrice, filled := fillPrice(order, price)
+
+		if order.Asset != ohlc.Asset || !filled {
+			open = append(open, order)
+			continue
+		}
+
+		if err := sb.orders.Fill(order.ID, orderPrice, ohlc.Time); err != nil {
+			open = append(open, order)
+			continue
+		}
+
+		fill(order, orderPrice, ohlc.Time)
+	}
+
+	sb.open = open
+}
+
+// fillPrice returns the price an open order is filled at when the price passed by argument reaches it.
+// Stop-limit orders are filled at their price once they are triggered.
+func fillPrice(order *domain.ShadowOrder, price domain.Decimal) (domain.Decimal, bool) {
+	switch order.Type {
+	case domain.OrderMarket:
+		return price, true
+	case domain.OrderLimit:
+		return order.Price, reached(order.Side, price, order.Price)
+	case domain.OrderTakeProfit:
+		return price, reached(order.Side, price, order.StopPrice)
+	case domain.OrderStopLoss:
+		return price, reached(opposite(order.Side), price, order.StopPrice)
+	case domain.OrderStopLimit:
+		return order.Price, reached(opposite(order.Side), price, order.StopPrice)
+	}
+
+	return price, false
+}
+
+// reached returns true when the price is the same or lower than the limit of buys or the same or higher than the limit of sells
+func reached(side domain.OrderSide, price, limit domain.Decimal) bool {
+	if side == domain.OrderBuy {
+		return price.Cmp(limit) <= 0
+	}
+
+	return price.Cmp(limit) >= 0
+}
+
+// opposite returns the other side, stop orders are triggered by prices moving against their side
+func opposite(side domain.OrderSide) domain.OrderSide {
+	if side == domain.OrderBuy {
+		return domain.OrderSell
+	}
+
+	return domain.OrderBuy
+}
+
+func fill(order *domain.ShadowOrder, price domain.Decimal, date time.Time) {
+	order.Status = domain.ShadowOrderFilled
+	order.FillPrice = price
+	order.FilledAt = date
+}
@@ -0,0 +1,91 @@
+package broker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/broker"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/shadow"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestShadowBroker(t *testing.T) {
+	applicationID := primitive.NewObjectID()
+	ordersRepository := shadow.NewOrdersRepository(db.NewMemoryRepository())
+	shadowBroker := broker.NewShadowBroker(applicationID, ordersRepository)
+	shadowBroker.SetTicker("ETH")
+
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	shadowBroker.OnNewAssetPrice(&domain.OHLC{Asset: "ETH", Close: domain.NewDecimal(2000), Time: day})
+
+	findOrders := func(t *testing.T) []domain.ShadowOrder {
+		orders, err := ordersRepository.FindAll(applicationID.Hex())
+
+		if err != nil {
+			t.Fatalf("Not expected FindAll to return error: %v", err)
+		}
+
+		return *orders
+	}
+
+	t.Run("should fill limit orders reached by the last price at their price", func(t *testing.T) {
+		if err := shadowBroker.AddBuyOrder(domain.NewDecimal(0.5), domain.NewDecimalFromInt(2000)); err != nil {
+			t.Fatalf("Not expected AddBuyOrder to return error: %v", err)
+		}
+
+		orders := findOrders(t)
+
+		if len(orders) != 1 || orders[0].Status != domain.ShadowOrderFilled || orders[0].Asset != "ETH" || orders[0].FillPrice.String() != "2000" {
+			t.Errorf("got %+v want one ETH order filled at 2000", orders)
+		}
+	})
+
+	t.Run("should fill stop-loss orders when the price drops to the stop price", func(t *testing.T) {
+		order := domain.Order{Side: domain.OrderSell, Type: domain.OrderStopLoss, Amount: domain.NewDecimal(0.5), StopPrice: domain.NewDecimalFromInt(1900)}
+
+		if err := shadowBroker.PlaceOrder(order); err != nil {
+			t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+		}
+
+		shadowBroker.OnNewAssetPrice(&domain.OHLC{Asset: "ETH", Close: domain.NewDecimal(1950), Time: day.Add(time.Minute)})
+
+		if orders := findOrders(t); orders[1].Status != domain.ShadowOrderOpen {
+			t.Errorf("got %+v want the stop-loss open", orders[1])
+		}
+
+		shadowBroker.OnNewAssetPrice(&domain.OHLC{Asset: "BTC", Close: domain.NewDecimal(1000), Time: day.Add(2 * time.Minute)})
+		shadowBroker.OnNewAssetPrice(&domain.OHLC{Asset: "ETH", Close: domain.NewDecimal(1880), Time: day.Add(3 * time.Minute)})
+
+		orders := findOrders(t)
+
+		if orders[1].Status != domain.ShadowOrderFilled || orders[1].FillPrice.String() != "1880" || !orders[1].FilledAt.Equal(day.Add(3*time.Minute)) {
+			t.Errorf("got %+v want the stop-loss filled at 1880", orders[1])
+		}
+	})
+
+	t.Run("should fill take-profit orders when the price rises to the stop price", func(t *testing.T) {
+		order := domain.Order{Side: domain.OrderSell, Type: domain.OrderTakeProfit, Amount: domain.NewDecimal(0.5), StopPrice: domain.NewDecimalFromInt(2100)}
+
+		if err := shadowBroker.PlaceOrder(order); err != nil {
+			t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+		}
+
+		shadowBroker.OnNewAssetPrice(&domain.OHLC{Asset: "ETH", Close: domain.NewDecimal(2150), Time: day.Add(4 * time.Minute)})
+
+		if orders := findOrders(t); orders[2].Status != domain.ShadowOrderFilled || orders[2].FillPrice.String() != "2150" {
+			t.Errorf("got %+v want the take-profit filled at 2150", orders[2])
+		}
+	})
+
+	t.Run("should fill market orders at the last price", func(t *testing.T) {
+		if err := shadowBroker.PlaceOrder(domain.Order{Side: domain.OrderBuy, Type: domain.OrderMarket, Amount: domain.NewDecimal(0.5)}); err != nil {
+			t.Fatalf("Not expected PlaceOrder to return error: %v", err)
+		}
+
+		if orders := findOrders(t); orders[3].Status != domain.ShadowOrderFilled || orders[3].FillPrice.String() != "2150" {
+			t.Errorf("got %+v want the market order filled at 2150", orders[3])
+		}
+	})
+}
@@ -4,30 +4,30 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"time"
 
 	applicationExecutionStates "github.com/fabiodmferreira/crypto-trading/application-execution-states"
 	"github.com/fabiodmferreira/crypto-trading/assetsprices"
 	"github.com/fabiodmferreira/crypto-trading/benchmark"
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
+	"github.com/fabiodmferreira/crypto-trading/datasets"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 )
 
 // ExecuteBenchmark create params and execute benchmarks
 func ExecuteBenchmark(done chan domain.BenchmarkResult) int {
 	filesPaths := []string{
-		// fmt.Sprintf("ada/%v", adadatahistory.LastYearMinute),
-		fmt.Sprintf("%v", btcdatahistory.TwentyTwentyH1),
-		// fmt.Sprintf("btc-cash/%v", btccashdatahistory.LastYearMinute),
-		// fmt.Sprintf("btc-sv/%v", btcsvdatahistory.LastYearMinute),
-		// fmt.Sprintf("eos/%v", eosdatahistory.LastYearMinute),
-		// fmt.Sprintf("etc/%v", etcdatahistory.LastYearMinute),
-		// fmt.Sprintf("eth/%v", ethdatahistory.LastYearMinute),
-		// fmt.Sprintf("ltc/%v", ltcdatahistory.LastYearMinute),
-		// fmt.Sprintf("monero/%v", monerodatahistory.LastYearMinute),
-		// fmt.Sprintf("stellar/%v", stellardatahistory.LastYearMinute),
-		// fmt.Sprintf("xrp/%v", xrpdatahistory.LastYearMinute),
+		// "ada/2020-h1.csv",
+		"btc/2020-h1.csv",
+		// "btc-cash/2020-h1.csv",
+		// "eos/2020-h1.csv",
+		// "etc/2020-h1.csv",
+		// "eth/2020-h1.csv",
+		// "ltc/2020-h1.csv",
+		// "monero/2020-h1.csv",
+		// "stellar/2020-h1.csv",
+		// "xrp/2020-h1.csv",
 	}
 	initialAmount := []float64{2000}
 	maximumBuyAmount := []float32{0.1}
@@ -61,7 +61,10 @@ func ExecuteBenchmark(done chan domain.BenchmarkResult) int {
 		}
 	}
 
-	benchmark := benchmark.NewService(benchmark.NewRepositoryInMemory(), new(assetsprices.RepositoryInMemory), applicationExecutionStates.NewRepositoryInMemory())
+	datasetsRootDir := datasets.RootDir()
+	datasetsService := datasets.NewService(datasets.NewManifestRepository(path.Join(datasetsRootDir, datasets.ManifestFileName)), datasetsRootDir)
+
+	benchmark := benchmark.NewService(benchmark.NewRepositoryInMemory(), new(assetsprices.RepositoryInMemory), applicationExecutionStates.NewRepositoryInMemory(), datasetsService)
 
 	benchmark.BulkRun(cases, done)
 
@@ -95,15 +98,15 @@ func main() {
 		if br.Err == nil {
 			result := br.Output
 			input := br.Input
-			profit := float32(((float64(result.FinalAmount) - input.AccountInitialAmount) * 100) / input.AccountInitialAmount)
-			f.WriteString(fmt.Sprintf("%+v,%v,%v,%d,%.2f,%.2f,%.2f%%\n", input, result.Buys, result.Sells, result.SellsPending, input.AccountInitialAmount, result.FinalAmount, profit))
+			profit := float32(((result.FinalAmount.Float64() - input.AccountInitialAmount) * 100) / input.AccountInitialAmount)
+			f.WriteString(fmt.Sprintf("%+v,%v,%v,%d,%.2f,%v,%.2f%%\n", input, result.Buys, result.Sells, result.SellsPending, input.AccountInitialAmount, result.FinalAmount.StringFixed(2), profit))
 			fOrders, err := os.Create(fmt.Sprintf("./reports/orders-reports/benchmark-%v-orders-%v.csv", startDate, i))
 
 			fOrders.WriteString(fmt.Sprintf("Buy Date,Sell Date,Amount,Buy Price,Buy Value,Sell Price,Sell Value,Return\n"))
 			for _, asset := range *result.Assets {
-				buyValue := asset.Amount * asset.BuyPrice
-				sellValue := asset.Amount * asset.SellPrice
-				fOrders.WriteString(fmt.Sprintf("%v,%v,%.2f,%.2f,%.2f,%.2f,%.2f,%.2f\n", asset.BuyTime, asset.SellTime, asset.Amount, asset.BuyPrice, buyValue, asset.SellPrice, sellValue, sellValue-buyValue))
+				buyValue := asset.Amount.Mul(asset.BuyPrice)
+				sellValue := asset.Amount.Mul(asset.SellPrice)
+				fOrders.WriteString(fmt.Sprintf("%v,%v,%v,%v,%v,%v,%v,%v\n", asset.BuyTime, asset.SellTime, asset.Amount, asset.BuyPrice.StringFixed(2), buyValue.StringFixed(2), asset.SellPrice.StringFixed(2), sellValue.StringFixed(2), sellValue.Sub(buyValue).StringFixed(2)))
 			}
 
 			if err != nil {
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabiodmferreira/crypto-trading/datasets"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+const usage = `usage:
+  datasets list
+  datasets import <asset> <name> <csv file>
+  datasets validate`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	rootDir := datasets.RootDir()
+	repository := datasets.NewManifestRepository(path.Join(rootDir, datasets.ManifestFileName))
+	service := datasets.NewService(repository, rootDir)
+
+	switch os.Args[1] {
+	case "list":
+		err := list(service)
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "import":
+		if len(os.Args) != 5 {
+			log.Fatal(usage)
+		}
+
+		err := importFile(service, os.Args[2], os.Args[3], os.Args[4])
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "validate":
+		invalid, err := validate(service)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if invalid > 0 {
+			log.Fatalf("%d invalid datasets", invalid)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// list prints every dataset registered
+func list(service *datasets.Service) error {
+	registered, err := service.FindAll()
+
+	if err != nil {
+		return err
+	}
+
+	for _, dataset := range *registered {
+		fmt.Printf("%v\t%v\t%v\t%v\t%v - %v\n", dataset.Asset, dataset.Name, dataset.FilePath, dataset.Interval, dataset.StartDate.Format("2006-01-02"), dataset.EndDate.Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+// importFile copies a csv file into the datasets directory and registers it
+func importFile(service *datasets.Service, asset, name, csvFile string) error {
+	file, err := os.Open(csvFile)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	asset = strings.ToLower(asset)
+
+	dataset := &domain.Dataset{
+		Asset:    asset,
+		Name:     name,
+		FilePath: path.Join(asset, filepath.Base(csvFile)),
+	}
+
+	err = service.Import(dataset, file)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%v imported: %v %v %v - %v\n", dataset.FilePath, dataset.Format, dataset.Interval, dataset.StartDate, dataset.EndDate)
+
+	return nil
+}
+
+// validate checks every dataset registered and returns the number of invalid datasets
+func validate(service *datasets.Service) (int, error) {
+	registered, err := service.FindAll()
+
+	if err != nil {
+		return 0, err
+	}
+
+	invalid := 0
+
+	for _, dataset := range *registered {
+		err := service.Validate(&dataset)
+
+		if err != nil {
+			invalid++
+			fmt.Printf("%v: %v\n", dataset.FilePath, err)
+			continue
+		}
+
+		fmt.Printf("%v: ok\n", dataset.FilePath)
+	}
+
+	return invalid, nil
+}
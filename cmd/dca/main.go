@@ -8,8 +8,11 @@ import (
 	"time"
 
 	krakenapi "github.com/beldur/kraken-go-api-client"
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/appfactory"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/binance"
 	"github.com/fabiodmferreira/crypto-trading/broker"
-	"github.com/fabiodmferreira/crypto-trading/collectors"
 	"github.com/fabiodmferreira/crypto-trading/db"
 	"github.com/fabiodmferreira/crypto-trading/dca"
 	"github.com/fabiodmferreira/crypto-trading/domain"
@@ -36,30 +39,34 @@ func main() {
 		NotificationsReceiver:       os.Getenv("NOTIFICATIONS_RECEIVER"),
 		NotificationsSender:         os.Getenv("NOTIFICATIONS_SENDER"),
 		NotificationsSenderPassword: os.Getenv("NOTIFICATIONS_SENDER_PASSWORD"),
+		NotificationsSMTPHost:       os.Getenv("NOTIFICATIONS_SMTP_HOST"),
+		NotificationsSMTPPort:       os.Getenv("NOTIFICATIONS_SMTP_PORT"),
+		NotificationsSMTPTLS:        os.Getenv("NOTIFICATIONS_SMTP_TLS"),
 		AppEnv:                      os.Getenv("APP_ENV"),
 		AppID:                       os.Getenv("APP_ID"),
+		Storage:                     os.Getenv("STORAGE"),
+		BoltPath:                    os.Getenv("BOLT_PATH"),
 	}
 
-	dbClient, err := db.ConnectDB(env.MongoURL)
+	storage, err := db.OpenStorage(env)
 
 	if err != nil {
 		log.Fatal("connecting db", err)
 	}
 
-	mongoDatabase := dbClient.Database(env.MongoDB)
-	dcaJobsCollection := mongoDatabase.Collection(db.DCA_JOBS_COLLECTION)
-	dcaJobsRepo := dca.NewJobsRepository(db.NewRepository(dcaJobsCollection))
+	repositories := storage.Repositories
 
-	dcaAssetsCollection := mongoDatabase.Collection(db.DCA_ASSETS_COLLECTION)
-	dcaAssetsRepo := dca.NewAssetsRepository(db.NewRepository(dcaAssetsCollection))
+	dcaJobsRepo := dca.NewJobsRepository(repositories(db.DCA_JOBS_COLLECTION))
 
-	notificationOptions := domain.NotificationOptions{
-		Receiver:       env.NotificationsReceiver,
-		Sender:         env.NotificationsSender,
-		SenderPassword: env.NotificationsSenderPassword,
+	dcaAssetsRepo := dca.NewAssetsRepository(repositories(db.DCA_ASSETS_COLLECTION))
+
+	notificationOptions, err := env.NotificationOptions()
+
+	if err != nil {
+		log.Fatal(err)
 	}
-	notificationsCollection := mongoDatabase.Collection(db.NOTIFICATIONS_COLLECTION)
-	notificationsRepository := notifications.NewRepository(db.NewRepository(notificationsCollection))
+
+	notificationsRepository := notifications.NewRepository(repositories(db.NOTIFICATIONS_COLLECTION))
 	notificationsService := notifications.NewService(
 		notificationsRepository,
 		notificationOptions,
@@ -71,14 +78,50 @@ func main() {
 	krakenKey := os.Getenv("KRAKEN_API_KEY")
 	krakenPrivateKey := os.Getenv("KRAKEN_PRIVATE_KEY")
 	krakenAPI := krakenapi.New(krakenKey, krakenPrivateKey)
+	binanceClient := binance.New(os.Getenv("BINANCE_API_KEY"), os.Getenv("BINANCE_SECRET_KEY"))
 
-	collector := collectors.NewKrakenCollector("BTC", domain.CollectorOptions{}, krakenAPI, &[]domain.Indicator{})
-	trader := broker.NewKrakenBroker(krakenAPI)
+	// KRAKEN_API_TIER is the verification tier of the kraken account, starter by default
+	krakenLimit, err := broker.KrakenRateLimit(os.Getenv("KRAKEN_API_TIER"))
 
-	service := dca.NewService(trader, collector, dcaJobsRepo, dcaAssetsRepo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exchanges := appfactory.NewExchanges(krakenAPI, krakenLimit, binanceClient)
+
+	// DCA_EXCHANGE selects the exchange where assets are bought, kraken by default
+	exchange := os.Getenv("DCA_EXCHANGE")
+
+	collector, err := appfactory.NewExchangeCollector(exchange, domain.DefaultQuote, []string{"BTC"}, domain.CollectorOptions{}, exchanges)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	trader, err := appfactory.NewExchangeBroker(exchange, exchanges)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	service := dca.NewService(trader, collector, exchanges.Markets, exchange, dcaJobsRepo, dcaAssetsRepo)
 
 	service.SetNotificationsService(notificationsService)
 
+	// DCA_ACCOUNT_ID is the account that pays the purchases, they are recorded in its ledger
+	if accountID := os.Getenv("DCA_ACCOUNT_ID"); accountID != "" {
+		accountService, err := accounts.NewAccountService(
+			accountID,
+			accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION)),
+			assets.NewRepository(repositories(db.ASSETS_COLLECTION)),
+			accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION)),
+			storage.UnitOfWork,
+		)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		service.SetAccountService(accountService)
+	}
+
 	if len(os.Args) > 1 && os.Args[1] == "create" {
 		dcaJob := &domain.DCAJob{
 			NextExecution: time.Now().Unix(),
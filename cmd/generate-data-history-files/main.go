@@ -1,14 +1,14 @@
 package main
 
 import (
-	"encoding/csv"
 	"fmt"
 	"io"
 	"log"
 	"os"
-	"strconv"
 	"strings"
 	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
 )
 
 func main() {
@@ -32,27 +32,41 @@ func main() {
 	}
 
 	for _, path := range rootPaths {
-		file, _ := os.Open(fmt.Sprintf("data-history/%v/dataset.csv", path))
-		reader := csv.NewReader(file)
-		reader.LazyQuotes = true
+		reader, err := csvschema.Open(fmt.Sprintf("data-history/%v/dataset.csv", path), nil)
 
-		writer, _ := os.Create(fmt.Sprintf("data-history/%v/%v.csv", path, outputName))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		writer, err := os.Create(fmt.Sprintf("data-history/%v/%v", path, outputName))
+
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		fmt.Printf("writing %v/%v...\n", path, outputName)
 
-		FilterFileByDatesInterval(reader, writer, int(startDate.Unix()), int(endDate.Unix()))
+		err = FilterFileByDatesInterval(reader, writer, startDate, endDate)
+
+		reader.Close()
+		writer.Close()
+
+		if err != nil {
+			log.Fatalf("%v: %v", path, err)
+		}
 
 		fmt.Printf("%v done\n", path)
 	}
 }
 
-func FilterFileByDatesInterval(in *csv.Reader, out io.Writer, startDate int, endDate int) {
+// FilterFileByDatesInterval writes the records of the input that were collected between the start and end dates
+func FilterFileByDatesInterval(in *csvschema.Reader, out io.Writer, startDate time.Time, endDate time.Time) error {
 	first := false
 
 	counter := 0
 
 	for {
-		record, err := in.Read()
+		record, ohlc, err := in.ReadRecord()
 
 		counter++
 
@@ -61,27 +75,25 @@ func FilterFileByDatesInterval(in *csv.Reader, out io.Writer, startDate int, end
 		if err == io.EOF {
 			break
 		} else if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
-		if len(record) > 1 {
-			time, _ := strconv.Atoi(record[0])
-
-			if startDate > time {
-				continue
-			}
-
-			if time > endDate {
-				break
-			}
+		if ohlc.Time.Before(startDate) {
+			continue
+		}
 
-			if first {
-				fmt.Fprint(out, "\n")
-			} else {
-				first = true
-			}
+		if ohlc.Time.After(endDate) {
+			break
+		}
 
-			fmt.Fprint(out, strings.Join(record, ","))
+		if first {
+			fmt.Fprint(out, "\n")
+		} else {
+			first = true
 		}
+
+		fmt.Fprint(out, strings.Join(record, string(in.Schema().Delimiter)))
 	}
+
+	return nil
 }
@@ -2,13 +2,15 @@ package main
 
 import (
 	"bytes"
-	"encoding/csv"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
 )
 
 func TestFilterFileByDatesInterval(t *testing.T) {
-	in := csv.NewReader(strings.NewReader(`1593387180,8106.3,8106.3,8105.1,8105.1,0.12004518,9
+	in, err := csvschema.NewDetectedReader(strings.NewReader(`1593387180,8106.3,8106.3,8105.1,8105.1,0.12004518,9
 1593387240,8105.0,8105.1,8104.5,8104.5,0.06199963,4
 1593387300,8105.2,8107.9,8105.2,8107.9,0.29412518,6
 1593387360,8108.3,8108.3,8107.2,8107.2,0.85862196,4
@@ -20,10 +22,18 @@ func TestFilterFileByDatesInterval(t *testing.T) {
 1593387720,8108.9,8112.9,8108.9,8112.9,0.0409239,5
 1593387780,8112.0,8112.0,8109.9,8109.9,0.04299188,3
 1593387840,8107.9,8110.6,8107.9,8110.6,0.07137652,6`))
-	in.LazyQuotes = true
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	out := &bytes.Buffer{}
 
-	FilterFileByDatesInterval(in, out, 1593387360, 1593387600)
+	err = FilterFileByDatesInterval(in, out, time.Unix(1593387360, 0), time.Unix(1593387600, 0))
+
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	got := out.String()
 	want := `1593387360,8108.3,8108.3,8107.2,8107.2,0.85862196,4
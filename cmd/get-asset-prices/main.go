@@ -55,7 +55,7 @@ func main() {
 	}
 
 	assetsPricesRepository := assetsprices.NewRepositoryInMemory()
-	assetsPricesService := assetsprices.NewService(assetsPricesRepository, assetsprices.NewCoindeskRemoteSource(http.Get).FetchRemoteAssetsPrices)
+	assetsPricesService := assetsprices.NewService(assetsPricesRepository, assetsprices.NewCoindeskRemoteSource(http.Get).FetchRemoteAssetsPrices, assetsprices.CoindeskInterval)
 
 	for _, i := range iterations {
 		fmt.Printf("\nfetching %v...\n", i.coin)
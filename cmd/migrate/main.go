@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/migrations"
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage:
+  migrate up
+  migrate status`
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal(usage)
+	}
+
+	// load environment variables
+	err := godotenv.Load()
+	if err != nil {
+		fmt.Println(".env file does not exist")
+	}
+
+	dbClient, err := db.ConnectDB(os.Getenv("MONGO_URL"))
+
+	if err != nil {
+		log.Fatal("connecting db: ", err)
+	}
+
+	runner := migrations.NewMongoRunner(dbClient.Database(os.Getenv("MONGO_DB")))
+
+	switch os.Args[1] {
+	case "up":
+		applied, err := runner.Run()
+
+		for _, migration := range applied {
+			fmt.Printf("%d\t%v\tapplied\n", migration.Version, migration.Description)
+		}
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+	case "status":
+		statuses, err := runner.Status()
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = fmt.Sprintf("applied at %v", status.AppliedAt.Format("2006-01-02 15:04:05"))
+			}
+
+			fmt.Printf("%d\t%v\t%v\n", status.Version, status.Description, state)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/assetsprices"
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/pricesquality"
+	"github.com/joho/godotenv"
+)
+
+const dateLayout = "2006-01-02T15:04:05"
+
+func main() {
+	file := flag.String("file", "", "csv file to check")
+	asset := flag.String("asset", "", "asset whose prices stored in the database are checked")
+	start := flag.String("start", "", "start date of the prices checked in the database ("+dateLayout+")")
+	end := flag.String("end", "", "end date of the prices checked in the database ("+dateLayout+")")
+	interval := flag.String("interval", "", "expected interval between prices like 1m, 1h or 1d, detected from files when empty and required for the prices stored in the database")
+	zScore := flag.Float64("zscore", pricesquality.DefaultZScoreThreshold, "z-score of a price change above which a price is an outlier")
+	repair := flag.String("repair", "", "repair method: "+strings.Join([]string{domain.RepairDedupe, domain.RepairForwardFill, domain.RepairInterpolate}, ", "))
+	output := flag.String("output", "", "csv file where repaired prices are written")
+	flag.Parse()
+
+	if (*file == "") == (*asset == "") {
+		flag.Usage()
+		log.Fatal("either file or asset is required")
+	}
+
+	if *asset != "" && *interval == "" {
+		log.Fatal("interval is required to check the prices stored in the database")
+	}
+
+	if *repair != "" && *output == "" {
+		log.Fatal("output is required to repair prices")
+	}
+
+	options := domain.PricesQualityOptions{ZScoreThreshold: *zScore}
+
+	if *interval != "" {
+		duration, err := pricesquality.ParseInterval(*interval)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		options.Interval = duration
+	}
+
+	var prices []domain.OHLC
+	var err error
+
+	if *file != "" {
+		prices, err = readFile(*file)
+	} else {
+		prices, err = findAssetPrices(strings.ToUpper(*asset), pricesquality.FormatInterval(options.Interval), *start, *end)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report := pricesquality.Check(prices, options)
+
+	printReport(os.Stdout, report)
+
+	if *repair != "" {
+		repaired, err := pricesquality.Repair(prices, options.Interval, *repair)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = writeFile(*output, repaired)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("%d prices written to %v\n", len(repaired), *output)
+	} else if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+// readFile returns the prices of a csv file
+func readFile(file string) ([]domain.OHLC, error) {
+	reader, err := csvschema.Open(file, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	return pricesquality.ReadAll(reader)
+}
+
+// findAssetPrices returns the prices of an asset stored in the database with the interval passed by argument
+func findAssetPrices(asset, interval, start, end string) ([]domain.OHLC, error) {
+	startDate, err := time.Parse(dateLayout, start)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %v", err)
+	}
+
+	endDate, err := time.Parse(dateLayout, end)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %v", err)
+	}
+
+	err = godotenv.Load()
+	if err != nil {
+		fmt.Println(".env file does not exist")
+	}
+
+	dbClient, err := db.ConnectDB(os.Getenv("MONGO_URL"))
+
+	if err != nil {
+		return nil, fmt.Errorf("connecting db: %v", err)
+	}
+
+	collection := dbClient.Database(os.Getenv("MONGO_DB")).Collection(db.ASSETS_PRICES_COLLECTION)
+	repository := assetsprices.NewRepository(db.NewRepository(collection))
+
+	return pricesquality.FindAssetPrices(repository, asset, interval, startDate, endDate)
+}
+
+// printReport writes a summary of the report and every problem found
+func printReport(w io.Writer, report *domain.PricesQualityReport) {
+	fmt.Fprintf(w, "prices: %d\ninterval: %v\ndates: %v - %v\n", report.Count, report.Interval, report.StartDate.Format(dateLayout), report.EndDate.Format(dateLayout))
+
+	fmt.Fprintf(w, "gaps: %d\n", len(report.Gaps))
+	for _, gap := range report.Gaps {
+		fmt.Fprintf(w, "\t%v - %v (%d missing)\n", gap.Start.Format(dateLayout), gap.End.Format(dateLayout), gap.Missing)
+	}
+
+	issues := []struct {
+		name   string
+		issues []domain.PriceIssue
+	}{
+		{"duplicates", report.Duplicates},
+		{"out of order", report.OutOfOrder},
+		{"invalid prices", report.InvalidPrices},
+	}
+
+	for _, group := range issues {
+		fmt.Fprintf(w, "%v: %d\n", group.name, len(group.issues))
+		for _, issue := range group.issues {
+			fmt.Fprintf(w, "\t#%d %v %v\n", issue.Index, issue.Date.Format(dateLayout), issue.Close)
+		}
+	}
+
+	fmt.Fprintf(w, "outliers: %d\n", len(report.Outliers))
+	for _, outlier := range report.Outliers {
+		fmt.Fprintf(w, "\t#%d %v %v (z-score %.2f)\n", outlier.Index, outlier.Date.Format(dateLayout), outlier.Close, outlier.ZScore)
+	}
+}
+
+// writeFile writes prices to a csv file with unix seconds timestamps
+func writeFile(file string, prices []domain.OHLC) error {
+	f, err := os.Create(file)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	fmt.Fprintln(f, "Date,Open,High,Low,Close,Volume")
+
+	for _, price := range prices {
+		_, err := fmt.Fprintf(f, "%d,%v,%v,%v,%v,%v\n", price.Time.Unix(), price.Open, price.High, price.Low, price.Close, price.Volume)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,32 +1,23 @@
 package main
 
 import (
-	"encoding/csv"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path"
-	"runtime"
-	"strconv"
 	"strings"
-	"time"
 
-	"github.com/fabiodmferreira/crypto-trading/collectors"
-	adadatahistory "github.com/fabiodmferreira/crypto-trading/data-history/ada"
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
-	ethdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/eth"
+	"github.com/fabiodmferreira/crypto-trading/assetsprices"
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
+	"github.com/fabiodmferreira/crypto-trading/datasets"
 	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/migrations"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-// bulkUpdateElements is the number of elements that are used on bulk upsert
-const bulkUpdateElements = 1000
-
-// const csvFile = btcdatahistory.LastYearMinute
-// const asset = "BTC"
-
 func main() {
 	// load environment variables
 	err := godotenv.Load()
@@ -47,90 +38,114 @@ func main() {
 
 	collection := mongoDatabase.Collection(db.ASSETS_PRICES_COLLECTION)
 
-	assetsPricesFiles := map[string]string{
-		"ADA": adadatahistory.Twenty1920H1,
-		"BTC": btcdatahistory.Twenty1920H1,
-		// "BTC-CASH": btccashdatahistory.Twenty19,
-		// "EOS":      eosdatahistory.Twenty19,
-		// "ETC":      etcdatahistory.Twenty19,
-		"ETH": ethdatahistory.Twenty1920H1,
-		// "LTC":      ltcdatahistory.Twenty19,
-		// "MONERO":   monerodatahistory.Twenty19,
-		// "STELLAR":  stellardatahistory.Twenty19,
-		// "XRP":      xrpdatahistory.Twenty19,
+	// datasetName is the name of the dataset registered for every asset that is stored
+	datasetName := "2019-2020 H1"
+
+	assets := []string{
+		"ADA",
+		"BTC",
+		// "BTC-CASH",
+		// "EOS",
+		// "ETC",
+		"ETH",
+		// "LTC",
+		// "MONERO",
+		// "STELLAR",
+		// "XRP",
 	}
 
-	repo := db.NewRepository(collection)
+	datasetsRootDir := datasets.RootDir()
+	datasetsService := datasets.NewService(datasets.NewManifestRepository(path.Join(datasetsRootDir, datasets.ManifestFileName)), datasetsRootDir)
 
-	for asset, csvFile := range assetsPricesFiles {
-		err := repo.BulkDelete(bson.M{"asset": asset})
+	registered, err := datasetsService.FindAll()
 
-		if err != nil {
-			log.Fatalf("error on bulk deleting: %v", err)
+	if err != nil {
+		log.Fatalf("error on getting datasets: %v", err)
+	}
+
+	repo := assetsprices.NewRepository(db.NewRepository(collection))
+
+	// the migrations set the interval of the prices stored before and create the unique index of assets prices
+	applied, err := migrations.NewMongoRunner(mongoDatabase).Run()
+
+	if err != nil {
+		log.Fatalf("running migrations: %v", err)
+	}
+
+	for _, migration := range applied {
+		fmt.Printf("Migration %d applied: %v\n", migration.Version, migration.Description)
+	}
+
+	for _, asset := range assets {
+		dataset := findDataset(registered, strings.ToLower(asset), datasetName)
+
+		if dataset == nil {
+			log.Fatalf("dataset %v of %v is not registered", datasetName, asset)
 		}
 
-		historyFile, err := readHistoryFile(csvFile)
+		historyFile, err := csvschema.Open(datasetsService.GetFilePath(dataset.FilePath), nil)
 
 		if err != nil {
-			log.Fatalf("error on getting cv: %v", err)
+			log.Fatalf("error on opening csv: %v", err)
 		}
 
-		// read header
-		_, err = historyFile.Read()
-		if err == io.EOF {
-			log.Fatalf("Error on reading header file: %v", err)
-		}
+		assetsPrices, err := getFileAssetsPrices(asset, dataset.Interval, historyFile)
 
-		assetsPrices, err := getFileAssetsPrices(asset, historyFile)
+		historyFile.Close()
 
-		err = db.BatchBulkCreate(repo.BulkCreate, assetsPrices, bulkUpdateElements)
+		if err != nil {
+			log.Fatalf("error on reading %v: %v", dataset.FilePath, err)
+		}
+
+		result, err := repo.BulkUpsert(assetsPrices)
 
 		if err != nil {
-			log.Fatalf("Error on bulk creating assets prices: %v", err)
+			log.Fatalf("Error on storing assets prices: %v", err)
 		}
+
+		fmt.Printf("%v: %d inserted, %d skipped\n", asset, result.Inserted, result.Skipped)
 	}
 }
 
-func getFileAssetsPrices(asset string, historyFile *csv.Reader) (*[]bson.M, error) {
+// findDataset returns the dataset of the asset with the name passed by argument or nil if it is not registered
+func findDataset(registered *[]domain.Dataset, asset, name string) *domain.Dataset {
+	for i, dataset := range *registered {
+		if dataset.Asset == asset && dataset.Name == name {
+			return &(*registered)[i]
+		}
+	}
+
+	return nil
+}
 
+// getFileAssetsPrices reads every price of the history file and returns them as assets prices documents
+func getFileAssetsPrices(asset string, interval string, historyFile domain.OHLCReader) (*[]bson.M, error) {
 	var documents []bson.M
 
 	for {
-		// Read each record from csv
-		record, err := historyFile.Read()
+		ohlc, err := historyFile.Read()
+
 		if err == io.EOF {
 			break
 		}
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		priceStr := strings.ReplaceAll(record[1], ",", "")
-		price, err := strconv.ParseFloat(priceStr, 32)
-
-		if err != nil {
-			log.Fatalf("Error on converting price from file:\n%v", err)
-		}
 
-		unixTime, err := strconv.ParseInt(record[0], 10, 64)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
 		documents = append(documents,
 			bson.M{
-				"asset": asset,
-				"c":     price,
-				"date":  time.Unix(unixTime, 0),
+				"asset":    asset,
+				"interval": interval,
+				"o":        ohlc.Open,
+				"h":        ohlc.High,
+				"l":        ohlc.Low,
+				"c":        ohlc.Close,
+				"v":        ohlc.Volume,
+				"date":     ohlc.Time,
 			},
 		)
 	}
 
 	return &documents, nil
 }
-
-func readHistoryFile(fileName string) (*csv.Reader, error) {
-	_, currentFilePath, _, _ := runtime.Caller(0)
-	currentDir := path.Dir(currentFilePath)
-	return collectors.GetCsv(fmt.Sprintf("%v/../../data-history/%v", currentDir, fileName))
-}
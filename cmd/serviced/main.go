@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	krakenapi "github.com/beldur/kraken-go-api-client"
-	"github.com/fabiodmferreira/crypto-trading/accounts"
 	"github.com/fabiodmferreira/crypto-trading/app"
 	"github.com/fabiodmferreira/crypto-trading/appfactory"
 	"github.com/fabiodmferreira/crypto-trading/appkeeper"
+	"github.com/fabiodmferreira/crypto-trading/application-execution-states"
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/broker"
 	"github.com/fabiodmferreira/crypto-trading/db"
 	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/marketdata"
+	"github.com/fabiodmferreira/crypto-trading/migrations"
+	"github.com/fabiodmferreira/crypto-trading/reconciliation"
 	"github.com/joho/godotenv"
 )
 
@@ -28,32 +34,87 @@ func main() {
 		NotificationsReceiver:       os.Getenv("NOTIFICATIONS_RECEIVER"),
 		NotificationsSender:         os.Getenv("NOTIFICATIONS_SENDER"),
 		NotificationsSenderPassword: os.Getenv("NOTIFICATIONS_SENDER_PASSWORD"),
+		NotificationsSMTPHost:       os.Getenv("NOTIFICATIONS_SMTP_HOST"),
+		NotificationsSMTPPort:       os.Getenv("NOTIFICATIONS_SMTP_PORT"),
+		NotificationsSMTPTLS:        os.Getenv("NOTIFICATIONS_SMTP_TLS"),
 		AppEnv:                      os.Getenv("APP_ENV"),
 		AppID:                       os.Getenv("APP_ID"),
+		Storage:                     os.Getenv("STORAGE"),
+		BoltPath:                    os.Getenv("BOLT_PATH"),
 	}
 
 	// initialize third party instances
 	krakenKey := os.Getenv("KRAKEN_API_KEY")
 	krakenPrivateKey := os.Getenv("KRAKEN_PRIVATE_KEY")
 	krakenAPI := krakenapi.New(krakenKey, krakenPrivateKey)
+	binanceClient := binance.New(os.Getenv("BINANCE_API_KEY"), os.Getenv("BINANCE_SECRET_KEY"))
 
-	dbClient, err := db.ConnectDB(env.MongoURL)
+	// KRAKEN_API_TIER is the verification tier of the kraken account, starter by default
+	krakenLimit, err := broker.KrakenRateLimit(os.Getenv("KRAKEN_API_TIER"))
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	storage, err := db.OpenStorage(env)
 
 	if err != nil {
 		log.Fatal("connecting db", err)
 	}
 
-	mongoDatabase := dbClient.Database(env.MongoDB)
+	repositories := storage.Repositories
+
+	applied, err := migrations.NewDefaultRunner(repositories).Run()
+
+	if err != nil {
+		log.Fatalf("running migrations: %v", err)
+	}
+
+	for _, migration := range applied {
+		fmt.Printf("Migration %d applied: %v\n", migration.Version, migration.Description)
+	}
+
+	statesRepository := applicationExecutionStates.NewRepository(repositories(db.APPLICATION_EXECUTION_STATES_COLLECTION))
+	statesRollupsRepository := applicationExecutionStates.NewRepository(repositories(db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION))
+	rollupService := applicationExecutionStates.NewRollupService(statesRepository, statesRollupsRepository, domain.DefaultStatesRetentionPolicy)
 
-	applicationsCollection := mongoDatabase.Collection(db.APPLICATIONS_COLLECTION)
-	applicationsRepository := app.NewRepository(db.NewRepository(applicationsCollection))
+	go rollupService.Schedule(10 * time.Minute)
+
+	applicationsRepository := app.NewRepository(repositories(db.APPLICATIONS_COLLECTION))
 
 	applications, err := applicationsRepository.FindAll()
 
-	keeper := appkeeper.NewAppKeeper(mongoDatabase, krakenAPI, applicationsRepository)
+	keeper := appkeeper.NewAppKeeper(storage, appfactory.NewExchanges(krakenAPI, krakenLimit, binanceClient), applicationsRepository)
 
 	keeper.SetAppEnv(env.AppEnv)
 
+	marketDataStatsRepository := marketdata.NewStatsRepository(repositories(db.MARKET_DATA_STATS_COLLECTION))
+
+	go keeper.MarketDataHub().ScheduleStats(marketDataStatsRepository, time.Minute, make(chan struct{}))
+
+	if pollInterval := os.Getenv("APPLICATIONS_POLL_INTERVAL"); pollInterval != "" {
+		interval, err := time.ParseDuration(pollInterval)
+
+		if err != nil {
+			log.Fatalf("parsing applications poll interval: %v", err)
+		}
+
+		keeper.SetPollInterval(interval)
+	}
+
+	if reconciliationInterval := os.Getenv("RECONCILIATION_INTERVAL"); reconciliationInterval != "" {
+		interval, err := time.ParseDuration(reconciliationInterval)
+
+		if err != nil {
+			log.Fatalf("parsing reconciliation interval: %v", err)
+		}
+
+		options := reconciliation.DefaultOptions
+		options.AutoAdjust = os.Getenv("RECONCILIATION_AUTO_ADJUST") == "true"
+
+		keeper.SetReconciliation(interval, options)
+	}
+
 	err = keeper.StartApplications(applications)
 	if err != nil {
 		log.Fatal(err)
@@ -62,15 +123,13 @@ func main() {
 	if len(*applications) == 0 {
 		fmt.Printf("Creating a default application")
 
-		notificationOptions := domain.NotificationOptions{
-			Receiver:       env.NotificationsReceiver,
-			Sender:         env.NotificationsSender,
-			SenderPassword: env.NotificationsSenderPassword,
-		}
+		notificationOptions, err := env.NotificationOptions()
 
-		accountsRepository := accounts.NewRepository(db.NewRepository(mongoDatabase.Collection(db.ACCOUNTS_COLLECTION)))
+		if err != nil {
+			log.Fatal(err)
+		}
 
-		metadata, err := appfactory.CreateDefaultAppMetadata(notificationOptions, applicationsRepository, accountsRepository)
+		metadata, err := appfactory.CreateDefaultAppMetadata(notificationOptions, applicationsRepository, storage.UnitOfWork)
 
 		if err != nil {
 			log.Fatal(err)
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/dca"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/reports"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	year := flag.Int("year", time.Now().Year()-1, "tax year of the disposals")
+	method := flag.String("method", string(domain.CostBasisFIFO), "cost basis method: fifo or average")
+	format := flag.String("format", "csv", "output format: csv or json")
+	output := flag.String("output", "", "file where the report is written (stdout when empty)")
+	quote := flag.String("quote", domain.DefaultQuote, "quote currency of the trades and purchases: EUR, USD, USDT or BTC")
+	flag.Parse()
+
+	if *format != "csv" && *format != "json" {
+		flag.Usage()
+		log.Fatal("format must be csv or json")
+	}
+
+	err := godotenv.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, ".env file does not exist")
+	}
+
+	storage, err := db.OpenStorage(domain.Env{
+		MongoURL: os.Getenv("MONGO_URL"),
+		MongoDB:  os.Getenv("MONGO_DB"),
+		Storage:  os.Getenv("STORAGE"),
+		BoltPath: os.Getenv("BOLT_PATH"),
+	})
+
+	if err != nil {
+		log.Fatal("connecting db", err)
+	}
+
+	repositories := storage.Repositories
+
+	service := reports.NewTaxReportService(
+		app.NewRepository(repositories(db.APPLICATIONS_COLLECTION)),
+		assets.NewRepository(repositories(db.ASSETS_COLLECTION)),
+		dca.NewAssetsRepository(repositories(db.DCA_ASSETS_COLLECTION)),
+	)
+
+	report, err := service.Generate(*year, domain.CostBasisMethod(*method), *quote)
+
+	if err != nil {
+		log.Fatalf("generating tax report: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+
+	if *output != "" {
+		file, err := os.Create(*output)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "json" {
+		err = json.NewEncoder(w).Encode(report)
+	} else {
+		err = reports.WriteTaxReportCSV(w, report)
+	}
+
+	if err != nil {
+		log.Fatalf("writing tax report: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d disposals, proceeds %v, cost basis %v, gain %v\n", len(report.Disposals), report.Proceeds, report.CostBasis, report.Gain)
+}
@@ -5,16 +5,27 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path"
 
 	"github.com/fabiodmferreira/crypto-trading/accounts"
 	"github.com/fabiodmferreira/crypto-trading/app"
 	"github.com/fabiodmferreira/crypto-trading/application-execution-states"
+	"github.com/fabiodmferreira/crypto-trading/appoptions"
 	"github.com/fabiodmferreira/crypto-trading/assets"
 	"github.com/fabiodmferreira/crypto-trading/assetsprices"
 	"github.com/fabiodmferreira/crypto-trading/benchmark"
+	"github.com/fabiodmferreira/crypto-trading/datasets"
 	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/dca"
+	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/eventlogs"
+	"github.com/fabiodmferreira/crypto-trading/marketdata"
+	"github.com/fabiodmferreira/crypto-trading/migrations"
 	"github.com/fabiodmferreira/crypto-trading/notifications"
+	"github.com/fabiodmferreira/crypto-trading/portfolio"
+	"github.com/fabiodmferreira/crypto-trading/reports"
+	"github.com/fabiodmferreira/crypto-trading/shadow"
+	"github.com/fabiodmferreira/crypto-trading/trades"
 	"github.com/fabiodmferreira/crypto-trading/webserver"
 	"github.com/gorilla/handlers"
 	"github.com/joho/godotenv"
@@ -38,7 +49,12 @@ func main() {
 		serverPort = "5000"
 	}
 
-	dbClient, err := db.ConnectDB(mongoURL)
+	storage, err := db.OpenStorage(domain.Env{
+		MongoURL: mongoURL,
+		MongoDB:  mongoDB,
+		Storage:  os.Getenv("STORAGE"),
+		BoltPath: os.Getenv("BOLT_PATH"),
+	})
 
 	if err != nil {
 		log.Fatal("connecting db", err)
@@ -46,33 +62,54 @@ func main() {
 
 	fmt.Printf("Connected to db successfully!\n")
 
-	mongoDatabase := dbClient.Database(mongoDB)
-	benchmarksCollection := mongoDatabase.Collection(db.BENCHMARKS_COLLECTION)
-	assetspricesCollection := mongoDatabase.Collection(db.ASSETS_PRICES_COLLECTION)
-	applicationExecutionStatesCollection := mongoDatabase.Collection(db.APPLICATION_EXECUTION_STATES_COLLECTION)
+	repositories := storage.Repositories
 
-	benchmarkRepository := benchmark.NewRepository(db.NewRepository(benchmarksCollection))
-	assetspricesRepository := assetsprices.NewRepository(db.NewRepository(assetspricesCollection))
-	applicationExecutionStatesRepository := applicationExecutionStates.NewRepository(db.NewRepository(applicationExecutionStatesCollection))
-	benchmarkService := benchmark.NewService(benchmarkRepository, assetspricesRepository, applicationExecutionStatesRepository)
+	applied, err := migrations.NewDefaultRunner(repositories).Run()
 
-	accountsCollection := mongoDatabase.Collection(db.ACCOUNTS_COLLECTION)
-	accountsRepository := accounts.NewRepository(db.NewRepository(accountsCollection))
+	if err != nil {
+		log.Fatalf("running migrations: %v", err)
+	}
+
+	for _, migration := range applied {
+		fmt.Printf("Migration %d applied: %v\n", migration.Version, migration.Description)
+	}
+
+	benchmarkRepository := benchmark.NewRepository(repositories(db.BENCHMARKS_COLLECTION))
+	assetspricesRepository := assetsprices.NewRepository(repositories(db.ASSETS_PRICES_COLLECTION))
+	applicationExecutionStatesRepository := applicationExecutionStates.NewRepository(repositories(db.APPLICATION_EXECUTION_STATES_COLLECTION))
+	datasetsRootDir := datasets.RootDir()
+	datasetsRepository := datasets.NewManifestRepository(path.Join(datasetsRootDir, datasets.ManifestFileName))
+	datasetsService := datasets.NewService(datasetsRepository, datasetsRootDir)
+
+	benchmarkService := benchmark.NewService(benchmarkRepository, assetspricesRepository, applicationExecutionStatesRepository, datasetsService)
+
+	accountsRepository := accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION))
+
+	assetsRepository := assets.NewRepository(repositories(db.ASSETS_COLLECTION))
+
+	ledgerRepository := accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION))
+
+	logEventsRepository := eventlogs.NewEventLogsRepository(repositories(db.EVENT_LOGS_COLLECTION), primitive.NewObjectID())
+
+	notificationsRepository := notifications.NewRepository(repositories(db.NOTIFICATIONS_COLLECTION))
+
+	applicationsRepository := app.NewRepository(repositories(db.APPLICATIONS_COLLECTION))
+	applicationExecutionStatesRollupsRepository := applicationExecutionStates.NewRepository(repositories(db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION))
+	applicationsService := app.NewService(applicationsRepository, applicationExecutionStatesRepository, logEventsRepository, notificationsRepository, applicationExecutionStatesRollupsRepository, domain.DefaultStatesRetentionPolicy)
+
+	dcaAssetsRepository := dca.NewAssetsRepository(repositories(db.DCA_ASSETS_COLLECTION))
+	taxReportService := reports.NewTaxReportService(applicationsRepository, assetsRepository, dcaAssetsRepository)
 
-	assetsCollection := mongoDatabase.Collection(db.ASSETS_COLLECTION)
-	assetsRepository := assets.NewRepository(db.NewRepository(assetsCollection))
+	tradesRepository := trades.NewRepository(repositories(db.TRADES_COLLECTION))
+	portfolioService := portfolio.NewService(applicationsRepository, assetsRepository, ledgerRepository, tradesRepository, assetspricesRepository)
 
-	logEventsCollection := mongoDatabase.Collection(db.EVENT_LOGS_COLLECTION)
-	logEventsRepository := eventlogs.NewEventLogsRepository(db.NewRepository(logEventsCollection), primitive.NewObjectID())
+	marketDataStatsRepository := marketdata.NewStatsRepository(repositories(db.MARKET_DATA_STATS_COLLECTION))
 
-	notificationsCollection := mongoDatabase.Collection(db.NOTIFICATIONS_COLLECTION)
-	notificationsRepository := notifications.NewRepository(db.NewRepository(notificationsCollection))
+	shadowService := shadow.NewService(applicationsRepository, shadow.NewOrdersRepository(repositories(db.SHADOW_ORDERS_COLLECTION)), portfolioService)
 
-	applicationsCollection := mongoDatabase.Collection(db.APPLICATIONS_COLLECTION)
-	applicationsRepository := app.NewRepository(db.NewRepository(applicationsCollection))
-	applicationsService := app.NewService(applicationsRepository, applicationExecutionStatesRepository, logEventsRepository, notificationsRepository)
+	optionsVersionsService := appoptions.NewService(applicationsRepository, appoptions.NewVersionsRepository(repositories(db.OPTIONS_VERSIONS_COLLECTION)))
 
-	server, err := webserver.NewCryptoTradingServer(benchmarkService, assetspricesRepository, accountsRepository, assetsRepository, applicationsService)
+	server, err := webserver.NewCryptoTradingServer(benchmarkService, assetspricesRepository, accountsRepository, assetsRepository, ledgerRepository, applicationsService, datasetsService, taxReportService, portfolioService, marketDataStatsRepository, shadowService, optionsVersionsService)
 
 	if err != nil {
 		log.Fatalf("problem creating server, %v ", err)
@@ -0,0 +1,226 @@
+package collectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/gorilla/websocket"
+)
+
+// binanceIntervals are the kline intervals of binance by number of minutes
+var binanceIntervals = map[int]string{
+	1:    "1m",
+	3:    "3m",
+	5:    "5m",
+	15:   "15m",
+	30:   "30m",
+	60:   "1h",
+	120:  "2h",
+	240:  "4h",
+	360:  "6h",
+	480:  "8h",
+	720:  "12h",
+	1440: "1d",
+}
+
+// binanceKlineMessage is a type used to decode messages of binance combined kline streams
+type binanceKlineMessage struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		Symbol string `json:"s"`
+		Kline  struct {
+			StartTime int64  `json:"t"`
+			EndTime   int64  `json:"T"`
+			Open      string `json:"o"`
+			Close     string `json:"c"`
+			High      string `json:"h"`
+			Low       string `json:"l"`
+			Volume    string `json:"v"`
+			Closed    bool   `json:"x"`
+			// LastTradeID and QuoteVolume are declared so their keys are not matched to low and volume
+			LastTradeID int64  `json:"L"`
+			QuoteVolume string `json:"V"`
+		} `json:"k"`
+	} `json:"data"`
+}
+
+// BinanceCollector collects data from binance exchange
+type BinanceCollector struct {
+	mu          sync.Mutex
+	options     domain.CollectorOptions
+	client      *binance.Client
+	observables []domain.OnNewAssetPrice
+	// pairs are the assets of the binance symbols subscribed
+	pairs map[string]string
+	// streams are the kline streams of the markets
+	streams    []string
+	wscon      *websocket.Conn
+	stopped    bool
+	indicators *[]domain.Indicator
+}
+
+// NewBinanceCollector returns an instance of BinanceCollector with one stream of the klines of each market.
+// The prices published have the base asset of their market.
+func NewBinanceCollector(markets []domain.Market, options domain.CollectorOptions, client *binance.Client, indicators *[]domain.Indicator) (*BinanceCollector, error) {
+	minutes := options.NewPriceTimeRate
+
+	if minutes == 0 {
+		minutes = 1
+	}
+
+	interval, ok := binanceIntervals[minutes]
+
+	if !ok {
+		return nil, fmt.Errorf("binance does not have klines of %d minutes", options.NewPriceTimeRate)
+	}
+
+	pairs := map[string]string{}
+	streams := []string{}
+
+	for _, market := range markets {
+		pairs[market.Symbol] = market.Base
+		streams = append(streams, market.WebsocketName+"@kline_"+interval)
+	}
+
+	sort.Strings(streams)
+
+	return &BinanceCollector{
+		options:    options,
+		client:     client,
+		pairs:      pairs,
+		streams:    streams,
+		indicators: indicators,
+	}, nil
+}
+
+// GetTicker calls binance API to get the last price of the symbol
+func (bc *BinanceCollector) GetTicker(tickerSymbol string) (domain.Decimal, error) {
+	var ticker struct {
+		Price string `json:"price"`
+	}
+
+	if err := bc.client.Query(http.MethodGet, "/api/v3/ticker/price", url.Values{"symbol": {tickerSymbol}}, &ticker); err != nil {
+		return domain.Decimal{}, err
+	}
+
+	return domain.ParseDecimal(ticker.Price)
+}
+
+// SetIndicators set indicators that listen for price changes
+func (bc *BinanceCollector) SetIndicators(indicators *[]domain.Indicator) {
+	bc.indicators = indicators
+}
+
+// Streams returns the kline streams of every market of the collector
+func (bc *BinanceCollector) Streams() []string {
+	return bc.streams
+}
+
+// Start connects to the binance kline streams and publishes every closed kline
+func (bc *BinanceCollector) Start() error {
+	con, _, err := websocket.DefaultDialer.Dial(bc.client.StreamURL(bc.Streams()), nil)
+
+	if err != nil {
+		return err
+	}
+
+	bc.mu.Lock()
+	bc.wscon = con
+	stopped := bc.stopped
+	bc.mu.Unlock()
+
+	defer con.Close()
+
+	if stopped {
+		return nil
+	}
+
+	for {
+		_, message, err := con.ReadMessage()
+
+		if err != nil {
+			return nil
+		}
+
+		var msg binanceKlineMessage
+
+		if err := json.Unmarshal(message, &msg); err != nil || !msg.Data.Kline.Closed {
+			continue
+		}
+
+		ohlc, err := getOHLCFromKline(&msg)
+
+		if err != nil {
+			fmt.Printf("error parsing binance kline: %v\n", err)
+			continue
+		}
+
+		ohlc.Asset = bc.pairs[msg.Data.Symbol]
+
+		bc.PublishAssetPrice(ohlc)
+	}
+}
+
+// Stop closes connection with binance websocket
+func (bc *BinanceCollector) Stop() {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	bc.stopped = true
+
+	if bc.wscon != nil {
+		bc.wscon.Close()
+	}
+}
+
+// PublishAssetPrice feeds the indicators with the price and calls the observable functions
+func (bc *BinanceCollector) PublishAssetPrice(ohlc *domain.OHLC) error {
+	if bc.indicators != nil {
+		for _, indicator := range *bc.indicators {
+			indicator.AddValue(ohlc)
+		}
+	}
+
+	for _, observable := range bc.observables {
+		observable(ohlc)
+	}
+
+	return nil
+}
+
+// Regist add function to be executed when a new price is received
+func (bc *BinanceCollector) Regist(observable domain.OnNewAssetPrice) {
+	bc.observables = append(bc.observables, observable)
+}
+
+func getOHLCFromKline(msg *binanceKlineMessage) (*domain.OHLC, error) {
+	kline := msg.Data.Kline
+	values := []domain.Decimal{}
+
+	for _, value := range []string{kline.Open, kline.High, kline.Low, kline.Close, kline.Volume} {
+		parsed, err := domain.ParseDecimal(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, parsed)
+	}
+
+	return &domain.OHLC{
+		Time:    time.Unix(0, kline.StartTime*int64(time.Millisecond)),
+		EndTime: time.Unix(0, (kline.EndTime+1)*int64(time.Millisecond)),
+		Open:    values[0],
+		High:    values[1],
+		Low:     values[2],
+		Close:   values[3],
+		Volume:  values[4],
+	}, nil
+}
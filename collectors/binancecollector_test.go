@@ -0,0 +1,89 @@
+package collectors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/binance/binancetest"
+	"github.com/fabiodmferreira/crypto-trading/collectors"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestBinanceCollector(t *testing.T) {
+	server := binancetest.NewServer("key", "secret")
+	defer server.Close()
+
+	server.Prices["BTCEUR"] = "43210.50"
+	server.Messages = []string{
+		`{"stream":"btceur@kline_1m","data":{"e":"kline","s":"BTCEUR","k":{"t":1609459200000,"T":1609459259999,"s":"BTCEUR","i":"1m","o":"100.0","c":"101.0","h":"102.0","l":"99.0","v":"1.5","x":false,"L":10,"V":"150.0"}}}`,
+		`{"stream":"etheur@kline_1m","data":{"e":"kline","s":"ETHEUR","k":{"t":1609459200000,"T":1609459259999,"s":"ETHEUR","i":"1m","o":"700.0","c":"710.0","h":"720.0","l":"690.0","v":"3","x":true,"L":20,"V":"2100.0"}}}`,
+	}
+
+	binanceMarkets := []domain.Market{
+		{Exchange: domain.ExchangeBinance, Base: "BTC", Quote: "EUR", Symbol: "BTCEUR", WebsocketName: "btceur"},
+		{Exchange: domain.ExchangeBinance, Base: "ETH", Quote: "EUR", Symbol: "ETHEUR", WebsocketName: "etheur"},
+	}
+
+	collector, err := collectors.NewBinanceCollector(binanceMarkets, domain.CollectorOptions{NewPriceTimeRate: 1}, server.Client(), &[]domain.Indicator{})
+
+	if err != nil {
+		t.Fatalf("Not expected NewBinanceCollector to return error: %v", err)
+	}
+
+	t.Run("should publish closed klines with the asset of their pair", func(t *testing.T) {
+		received := make(chan *domain.OHLC, 2)
+		collector.Regist(func(ohlc *domain.OHLC) { received <- ohlc })
+
+		done := make(chan error)
+		go func() { done <- collector.Start() }()
+
+		select {
+		case ohlc := <-received:
+			want := domain.OHLC{
+				Asset:   "ETH",
+				Time:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				EndTime: time.Date(2021, 1, 1, 0, 1, 0, 0, time.UTC),
+				Open:    domain.NewDecimal(700), High: domain.NewDecimal(720), Low: domain.NewDecimal(690), Close: domain.NewDecimal(710), Volume: domain.NewDecimal(3),
+			}
+
+			if ohlc.Asset != want.Asset || !ohlc.Time.Equal(want.Time) || !ohlc.EndTime.Equal(want.EndTime) ||
+				ohlc.Open != want.Open || ohlc.High != want.High || ohlc.Low != want.Low || ohlc.Close != want.Close || ohlc.Volume != want.Volume {
+				t.Errorf("got %+v want %+v", ohlc, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Expected a price to be published")
+		}
+
+		collector.Stop()
+
+		if err := <-done; err != nil {
+			t.Errorf("Not expected Start to return error: %v", err)
+		}
+
+		if len(received) != 0 {
+			t.Errorf("Expected klines not closed to not be published")
+		}
+
+		if len(server.Streams) != 1 || len(server.Streams[0]) != 2 || server.Streams[0][0] != "btceur@kline_1m" {
+			t.Errorf("got streams %v want btceur and etheur klines of 1 minute", server.Streams)
+		}
+	})
+
+	t.Run("should return the last price of the symbol", func(t *testing.T) {
+		got, err := collector.GetTicker("BTCEUR")
+
+		if err != nil {
+			t.Fatalf("Not expected GetTicker to return error: %v", err)
+		}
+
+		if got.String() != "43210.5" {
+			t.Errorf("got %v want 43210.5", got)
+		}
+	})
+
+	t.Run("should return error for intervals without klines", func(t *testing.T) {
+		if _, err := collectors.NewBinanceCollector(binanceMarkets, domain.CollectorOptions{NewPriceTimeRate: 7}, server.Client(), nil); err == nil {
+			t.Errorf("Expected NewBinanceCollector to return error")
+		}
+	})
+}
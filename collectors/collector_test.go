@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/collectors"
+	"github.com/fabiodmferreira/crypto-trading/domain"
 )
 
 func TestGetPreviousIntervalDates(t *testing.T) {
@@ -21,3 +22,17 @@ func TestGetPreviousIntervalDates(t *testing.T) {
 	}
 
 }
+
+func TestNewKrakenCollector(t *testing.T) {
+	t.Run("should subscribe the pairs of every market in one message", func(t *testing.T) {
+		krakenMarkets := []domain.Market{{Base: "ETH", Quote: "EUR", WebsocketName: "ETH/EUR"}, {Base: "BTC", Quote: "EUR", WebsocketName: "XBT/EUR"}}
+		collector := collectors.NewKrakenCollector(krakenMarkets, domain.CollectorOptions{NewPriceTimeRate: 1}, nil, &[]domain.Indicator{})
+
+		got, _ := collector.SubscribeMessage()
+		want := `{"event":"subscribe","pair":["ETH/EUR","XBT/EUR"],"subscription":{"interval":1,"name":"ohlc"}}`
+
+		if string(got) != want {
+			t.Errorf("got %s want %s", got, want)
+		}
+	})
+}
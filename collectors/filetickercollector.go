@@ -1,12 +1,7 @@
 package collectors
 
 import (
-	"encoding/csv"
 	"io"
-	"log"
-	"os"
-	"path/filepath"
-	"strconv"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
@@ -30,8 +25,8 @@ func NewFileTickerCollector(options domain.CollectorOptions, indicators *[]domai
 }
 
 // GetTicker is a stub
-func (ftc *FileTickerCollector) GetTicker(tickerSymbol string) (float32, error) {
-	return 0, nil
+func (ftc *FileTickerCollector) GetTicker(tickerSymbol string) (domain.Decimal, error) {
+	return domain.Decimal{}, nil
 }
 
 func (ftc *FileTickerCollector) SetIndicators(indicators *[]domain.Indicator) {
@@ -39,46 +34,16 @@ func (ftc *FileTickerCollector) SetIndicators(indicators *[]domain.Indicator) {
 }
 
 // Start starts collecting data from data source
-func (ftc *FileTickerCollector) Start() {
+func (ftc *FileTickerCollector) Start() error {
 	for {
-		// Read each record from csv
-		record, err := ftc.options.DataSource.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		if len(record) < 6 {
-			break
-		}
-
-		open, err := strconv.ParseFloat(record[1], 32)
-		high, err1 := strconv.ParseFloat(record[2], 32)
-		low, err2 := strconv.ParseFloat(record[3], 32)
-		close, err3 := strconv.ParseFloat(record[4], 32)
-		volume, err4 := strconv.ParseFloat(record[5], 32)
+		ohlc, err := ftc.options.DataSource.Read()
 
-		if err != nil || err1 != nil || err2 != nil || err3 != nil || err4 != nil {
-			log.Fatalf("Error on converting price from file:\n%v %v %v %v %v", err, err1, err2, err3, err4)
+		if err == io.EOF {
+			return nil
 		}
 
-		unixTime, err := strconv.ParseInt(record[0], 10, 64)
 		if err != nil {
-			log.Fatal(err)
-		}
-
-		date := time.Unix(unixTime, 0)
-
-		ohlc := &domain.OHLC{
-			Time:    date,
-			EndTime: date,
-			Open:    float32(open),
-			Close:   float32(close),
-			High:    float32(high),
-			Low:     float32(low),
-			Volume:  float32(volume),
+			return err
 		}
 
 		for _, indicator := range *ftc.indicators {
@@ -88,7 +53,6 @@ func (ftc *FileTickerCollector) Start() {
 		for _, observable := range ftc.observables {
 			observable(ohlc)
 		}
-
 	}
 }
 
@@ -99,25 +63,3 @@ func (ftc *FileTickerCollector) Stop() {}
 func (ftc *FileTickerCollector) Regist(observable domain.OnNewAssetPrice) {
 	ftc.observables = append(ftc.observables, observable)
 }
-
-// GetCsv returns the pointer for a csv file from the filepath
-func GetCsv(file string) (*csv.Reader, error) {
-	// Open the file
-	filename, err := filepath.Abs(file)
-
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	csvfile, err := os.Open(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse the file
-	r := csv.NewReader(csvfile)
-	r.LazyQuotes = true
-	//r := csv.NewReader(bufio.NewReader(csvfile))
-
-	return r, nil
-}
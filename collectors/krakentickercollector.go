@@ -2,9 +2,8 @@ package collectors
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
 	"net/url"
+	"sort"
 	"strconv"
 	"time"
 
@@ -25,14 +24,6 @@ type TickerMessage struct {
 	V []interface{}
 }
 
-var Pairs = map[string]string{
-	"BTC":  "XBT/EUR",
-	"ETH":  "ETH/EUR",
-	"ADA":  "ADA/EUR",
-	"ATOM": "ATOM/EUR",
-	"DOT":  "DOT/EUR",
-}
-
 // KrakenCollector collects data from kraken exchange
 type KrakenCollector struct {
 	options              domain.CollectorOptions
@@ -40,22 +31,23 @@ type KrakenCollector struct {
 	lastTickerPrice      float32
 	observables          []domain.OnNewAssetPrice
 	lastPricePublishDate time.Time
-	pair                 string
-	wscon                *websocket.Conn
-	indicators           *[]domain.Indicator
+	// pairs are the assets of the kraken pairs subscribed
+	pairs      map[string]string
+	wscon      *websocket.Conn
+	indicators *[]domain.Indicator
 }
 
-// NewKrakenCollector returns an instance of KrakenCollector
-func NewKrakenCollector(asset string, options domain.CollectorOptions, krakenAPI *krakenapi.KrakenAPI, indicators *[]domain.Indicator) *KrakenCollector {
-
-	pair, ok := Pairs[asset]
+// NewKrakenCollector returns an instance of KrakenCollector with one subscription to the markets passed by argument.
+// The prices published have the base asset of their market.
+func NewKrakenCollector(markets []domain.Market, options domain.CollectorOptions, krakenAPI *krakenapi.KrakenAPI, indicators *[]domain.Indicator) *KrakenCollector {
+	pairs := map[string]string{}
 
-	if !ok {
-		log.Fatalf("%v does not have a valid kraken pair", asset)
+	for _, market := range markets {
+		pairs[market.WebsocketName] = market.Base
 	}
 
 	return &KrakenCollector{
-		pair:       pair,
+		pairs:      pairs,
 		options:    options,
 		krakenAPI:  krakenAPI,
 		indicators: indicators,
@@ -63,12 +55,12 @@ func NewKrakenCollector(asset string, options domain.CollectorOptions, krakenAPI
 }
 
 // GetTicker calls kraken API to get ticker pair price
-func (kc *KrakenCollector) GetTicker(tickerSymbol string) (float32, error) {
+func (kc *KrakenCollector) GetTicker(tickerSymbol string) (domain.Decimal, error) {
 	result, err := kc.krakenAPI.Query("Ticker", map[string]string{
 		"pair": tickerSymbol,
 	})
 	if err != nil {
-		return 0, err
+		return domain.Decimal{}, err
 	}
 
 	resultBytes := result.(map[string]interface{})
@@ -78,15 +70,10 @@ func (kc *KrakenCollector) GetTicker(tickerSymbol string) (float32, error) {
 
 		result := tickerResponse["c"].([]interface{})
 
-		price, err := strconv.ParseFloat(result[0].(string), 32)
-		if err != nil {
-			return 0, err
-		}
-
-		return float32(price), nil
+		return domain.ParseDecimal(result[0].(string))
 	}
 
-	return 0, nil
+	return domain.Decimal{}, nil
 }
 
 // SetIndicators set indicators that listen for price changes
@@ -95,7 +82,7 @@ func (kc *KrakenCollector) SetIndicators(indicators *[]domain.Indicator) {
 }
 
 // Start connects to a kraken websocket that send prices variations
-func (kc *KrakenCollector) Start() {
+func (kc *KrakenCollector) Start() error {
 	u := url.URL{
 		Scheme: "wss",
 		Host:   "ws.kraken.com",
@@ -104,41 +91,37 @@ func (kc *KrakenCollector) Start() {
 
 	con, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
 
-	kc.wscon = con
-
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
+	kc.wscon = con
+
 	defer kc.wscon.Close()
 
-	subscribeEventMessage := fmt.Sprintf(`{
-		"event": "subscribe",
-		"pair": [
-			"%v"
-		],
-		"subscription": {
-			"name": "ohlc",
-			"interval": %d
-		}
-	}`, kc.pair, kc.options.NewPriceTimeRate)
+	subscribeEventMessage, err := kc.SubscribeMessage()
+
+	if err != nil {
+		return err
+	}
 
 	err = kc.wscon.WriteMessage(
 		websocket.TextMessage,
-		[]byte(subscribeEventMessage),
+		subscribeEventMessage,
 	)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	var currentOHLC *domain.OHLC
+	// currentOHLCs are the prices of the current interval by pair
+	currentOHLCs := map[string]*domain.OHLC{}
 
 	// receive message
 	for {
 		_, message, err := kc.wscon.ReadMessage()
 
 		if err != nil {
-			break
+			return nil
 		}
 
 		var e SocketEvent
@@ -151,7 +134,10 @@ func (kc *KrakenCollector) Start() {
 			err = json.Unmarshal(message, &msg)
 
 			if err == nil {
+				pair, _ := msg[3].(string)
 				ohlc := getOHLCFromPayload(payload)
+				ohlc.Asset = kc.pairs[pair]
+				currentOHLC := currentOHLCs[pair]
 
 				if currentOHLC != nil && currentOHLC.Open != ohlc.Open {
 					startDate, endDate := GetPreviousIntervalDates(time.Now(), kc.options.NewPriceTimeRate)
@@ -162,7 +148,7 @@ func (kc *KrakenCollector) Start() {
 					kc.PublishAssetPrice(currentOHLC)
 				}
 
-				currentOHLC = ohlc
+				currentOHLCs[pair] = ohlc
 
 				// askStr := msg[1].(*TickerMessage).A[0].(string)
 				// ask, err := strconv.ParseFloat(askStr, 32)
@@ -206,6 +192,26 @@ func (kc *KrakenCollector) Start() {
 	}
 }
 
+// SubscribeMessage returns the message that subscribes the ohlc of every pair of the collector
+func (kc *KrakenCollector) SubscribeMessage() ([]byte, error) {
+	pairs := []string{}
+
+	for pair := range kc.pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Strings(pairs)
+
+	return json.Marshal(map[string]interface{}{
+		"event": "subscribe",
+		"pair":  pairs,
+		"subscription": map[string]interface{}{
+			"name":     "ohlc",
+			"interval": kc.options.NewPriceTimeRate,
+		},
+	})
+}
+
 // Stop closes connection with kraken websocket
 func (kc *KrakenCollector) Stop() {
 	if kc.wscon != nil {
@@ -263,19 +269,19 @@ func getOHLCFromPayload(msg []interface{}) *domain.OHLC {
 	etime, _ := strconv.ParseFloat(msg[1].(string), 32)
 	endTime := time.Unix(int64(etime), 0)
 
-	open, _ := strconv.ParseFloat(msg[2].(string), 32)
-	high, _ := strconv.ParseFloat(msg[3].(string), 32)
-	low, _ := strconv.ParseFloat(msg[4].(string), 32)
-	close, _ := strconv.ParseFloat(msg[5].(string), 32)
-	volume, _ := strconv.ParseFloat(msg[7].(string), 32)
+	open, _ := domain.ParseDecimal(msg[2].(string))
+	high, _ := domain.ParseDecimal(msg[3].(string))
+	low, _ := domain.ParseDecimal(msg[4].(string))
+	close, _ := domain.ParseDecimal(msg[5].(string))
+	volume, _ := domain.ParseDecimal(msg[7].(string))
 
 	return &domain.OHLC{
 		Time:    startTime,
 		EndTime: endTime,
-		Open:    float32(open),
-		High:    float32(high),
-		Low:     float32(low),
-		Close:   float32(close),
-		Volume:  float32(volume),
+		Open:    open,
+		High:    high,
+		Low:     low,
+		Close:   close,
+		Volume:  volume,
 	}
 }
@@ -0,0 +1,462 @@
+package csvschema
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+const (
+	// UnixSeconds is the time unit of timestamps counted in seconds
+	UnixSeconds = "s"
+	// UnixMilliseconds is the time unit of timestamps counted in milliseconds
+	UnixMilliseconds = "ms"
+
+	// NoColumn marks a column that does not exist in the file
+	NoColumn = -1
+
+	// sampleSize is the number of bytes read from a file to detect its schema
+	sampleSize = 8192
+	// sampleRecords is the maximum number of records used to detect a schema
+	sampleRecords = 10
+)
+
+// timeLayouts are the dates formats recognized when dates are not unix timestamps
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// headerNames maps lower cased header names to the column they represent
+var headerNames = map[string]string{
+	"date": "time", "time": "time", "timestamp": "time", "unix": "time",
+	"open": "open", "o": "open",
+	"high": "high", "h": "high",
+	"low": "low", "l": "low",
+	"close": "close", "c": "close", "price": "close",
+	"volume": "volume", "v": "volume", "vol": "volume",
+}
+
+// Columns maps each price field to the index of its column
+type Columns struct {
+	Time   int `json:"time"`
+	Open   int `json:"open"`
+	High   int `json:"high"`
+	Low    int `json:"low"`
+	Close  int `json:"close"`
+	Volume int `json:"volume"`
+}
+
+// Schema describes how prices are stored in a csv file
+type Schema struct {
+	Delimiter          rune    `json:"delimiter"`
+	HasHeader          bool    `json:"hasHeader"`
+	Columns            Columns `json:"columns"`
+	TimeUnit           string  `json:"timeUnit"`
+	TimeLayout         string  `json:"timeLayout"`
+	ThousandsSeparator string  `json:"thousandsSeparator"`
+}
+
+// IsOHLC returns true when the schema has open, high and low columns
+func (s *Schema) IsOHLC() bool {
+	return s.Columns.Open != NoColumn && s.Columns.High != NoColumn && s.Columns.Low != NoColumn
+}
+
+// ParseTime converts a time column value to time
+func (s *Schema) ParseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if s.TimeLayout != "" {
+		return time.Parse(s.TimeLayout, value)
+	}
+
+	timestamp, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if s.TimeUnit == UnixMilliseconds {
+		return time.Unix(0, timestamp*int64(time.Millisecond)).UTC(), nil
+	}
+
+	return time.Unix(timestamp, 0).UTC(), nil
+}
+
+// ParseNumber converts a price or volume column value to decimal
+func (s *Schema) ParseNumber(value string) (domain.Decimal, error) {
+	value = strings.TrimSpace(value)
+
+	if s.ThousandsSeparator != "" {
+		value = strings.ReplaceAll(value, s.ThousandsSeparator, "")
+	}
+
+	return domain.ParseDecimal(value)
+}
+
+// Detect infers the schema of a csv file from the first lines of its content
+func Detect(sample []byte) (*Schema, error) {
+	delimiter := detectDelimiter(sample)
+
+	reader := csv.NewReader(bytes.NewReader(sample))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	var records [][]string
+
+	for len(records) < sampleRecords {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			// the last line of the sample might be truncated
+			if len(records) > 0 {
+				break
+			}
+
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("csv file is empty")
+	}
+
+	schema := &Schema{Delimiter: delimiter}
+
+	if _, ok := detectTimeFormat(records[0][0]); !ok {
+		schema.HasHeader = true
+		schema.Columns = detectHeaderColumns(records[0])
+		records = records[1:]
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("csv file has no prices")
+	}
+
+	if !schema.HasHeader || schema.Columns.Close == NoColumn {
+		columns, err := detectPositionalColumns(len(records[0]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		schema.Columns = columns
+	}
+
+	// headers might name more columns than the prices have
+	if err := checkColumns(schema.Columns, len(records[0])); err != nil {
+		return nil, err
+	}
+
+	timeFormat, ok := detectTimeFormat(records[0][schema.Columns.Time])
+
+	if !ok {
+		return nil, fmt.Errorf("not able to detect time format of %q", records[0][schema.Columns.Time])
+	}
+
+	if timeFormat == UnixSeconds || timeFormat == UnixMilliseconds {
+		schema.TimeUnit = timeFormat
+	} else {
+		schema.TimeLayout = timeFormat
+	}
+
+	schema.ThousandsSeparator = detectThousandsSeparator(records, schema.Columns.Close)
+
+	return schema, nil
+}
+
+// detectDelimiter returns the candidate delimiter found the most times on the first line
+func detectDelimiter(sample []byte) rune {
+	firstLine := sample
+	if index := bytes.IndexByte(sample, '\n'); index >= 0 {
+		firstLine = sample[:index]
+	}
+
+	delimiter := ','
+	maxCount := 0
+
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		count := bytes.Count(firstLine, []byte(string(candidate)))
+
+		if count > maxCount {
+			delimiter = candidate
+			maxCount = count
+		}
+	}
+
+	return delimiter
+}
+
+// detectTimeFormat returns the unix time unit or the time layout of a value
+func detectTimeFormat(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// unix seconds only reach this value in the year 5138
+		if timestamp > 1e11 {
+			return UnixMilliseconds, true
+		}
+
+		return UnixSeconds, true
+	}
+
+	for _, layout := range timeLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return layout, true
+		}
+	}
+
+	return "", false
+}
+
+// detectHeaderColumns maps header names to columns
+func detectHeaderColumns(header []string) Columns {
+	columns := Columns{Time: NoColumn, Open: NoColumn, High: NoColumn, Low: NoColumn, Close: NoColumn, Volume: NoColumn}
+
+	for index, name := range header {
+		switch headerNames[strings.ToLower(strings.TrimSpace(name))] {
+		case "time":
+			columns.Time = index
+		case "open":
+			columns.Open = index
+		case "high":
+			columns.High = index
+		case "low":
+			columns.Low = index
+		case "close":
+			columns.Close = index
+		case "volume":
+			columns.Volume = index
+		}
+	}
+
+	if columns.Time == NoColumn {
+		columns.Time = 0
+	}
+
+	return columns
+}
+
+// detectPositionalColumns maps columns by their position for files with time,close or time,open,high,low,close,volume columns
+func detectPositionalColumns(nColumns int) (Columns, error) {
+	switch {
+	case nColumns >= 6:
+		return Columns{Time: 0, Open: 1, High: 2, Low: 3, Close: 4, Volume: 5}, nil
+	case nColumns >= 2:
+		return Columns{Time: 0, Open: NoColumn, High: NoColumn, Low: NoColumn, Close: 1, Volume: NoColumn}, nil
+	default:
+		return Columns{}, fmt.Errorf("expected at least 2 columns, got %d", nColumns)
+	}
+}
+
+// checkColumns returns error when a record with the number of columns passed by argument does not have every column
+func checkColumns(columns Columns, nColumns int) error {
+	for _, column := range []int{columns.Time, columns.Open, columns.High, columns.Low, columns.Close, columns.Volume} {
+		if column >= nColumns {
+			return fmt.Errorf("expected at least %d columns, got %d", column+1, nColumns)
+		}
+	}
+
+	return nil
+}
+
+// detectThousandsSeparator returns "," when prices only parse after removing commas
+func detectThousandsSeparator(records [][]string, closeColumn int) string {
+	for _, record := range records {
+		if closeColumn >= len(record) || !strings.Contains(record[closeColumn], ",") {
+			continue
+		}
+
+		_, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(record[closeColumn]), ",", ""), 32)
+
+		if err == nil {
+			return ","
+		}
+	}
+
+	return ""
+}
+
+// Reader reads prices from a csv file described by a schema
+type Reader struct {
+	reader     *csv.Reader
+	schema     *Schema
+	closer     io.Closer
+	line       int
+	headerRead bool
+}
+
+// NewReader returns a Reader that parses content with the schema passed by argument
+func NewReader(r io.Reader, schema *Schema) *Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = schema.Delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+
+	return &Reader{reader: reader, schema: schema}
+}
+
+// NewDetectedReader returns a Reader with the schema detected from the beginning of the content
+func NewDetectedReader(r io.Reader) (*Reader, error) {
+	buffered := bufio.NewReaderSize(r, sampleSize)
+
+	sample, err := buffered.Peek(sampleSize)
+
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	schema, err := Detect(sample)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReader(buffered, schema), nil
+}
+
+// Open returns a Reader of a csv file. The schema is detected when none is passed by argument.
+func Open(file string, schema *Schema) (*Reader, error) {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var reader *Reader
+
+	if schema != nil {
+		reader = NewReader(f, schema)
+	} else {
+		reader, err = NewDetectedReader(f)
+
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%v: %v", file, err)
+		}
+	}
+
+	reader.closer = f
+
+	return reader, nil
+}
+
+// Schema returns the schema used to parse the file
+func (r *Reader) Schema() *Schema {
+	return r.schema
+}
+
+// Read returns the next price of the file or io.EOF when there are no more prices
+func (r *Reader) Read() (*domain.OHLC, error) {
+	_, ohlc, err := r.ReadRecord()
+
+	return ohlc, err
+}
+
+// ReadRecord returns the next csv record and the price parsed from it
+func (r *Reader) ReadRecord() ([]string, *domain.OHLC, error) {
+	if r.schema.HasHeader && !r.headerRead {
+		r.headerRead = true
+		r.line++
+
+		if _, err := r.reader.Read(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	record, err := r.reader.Read()
+	r.line++
+
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, err
+		}
+
+		return nil, nil, fmt.Errorf("line %d: %v", r.line, err)
+	}
+
+	ohlc, err := r.parse(record)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("line %d: %v", r.line, err)
+	}
+
+	return record, ohlc, nil
+}
+
+// Close closes the file opened by Open
+func (r *Reader) Close() error {
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+
+	return nil
+}
+
+// parse converts a csv record to an OHLC
+func (r *Reader) parse(record []string) (*domain.OHLC, error) {
+	columns := r.schema.Columns
+
+	if err := checkColumns(columns, len(record)); err != nil {
+		return nil, err
+	}
+
+	date, err := r.schema.ParseTime(record[columns.Time])
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q: %v", record[columns.Time], err)
+	}
+
+	close, err := r.schema.ParseNumber(record[columns.Close])
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid close price %q: %v", record[columns.Close], err)
+	}
+
+	ohlc := &domain.OHLC{Time: date, EndTime: date, Open: close, High: close, Low: close, Close: close}
+
+	fields := []struct {
+		column int
+		target *domain.Decimal
+	}{
+		{columns.Open, &ohlc.Open},
+		{columns.High, &ohlc.High},
+		{columns.Low, &ohlc.Low},
+		{columns.Volume, &ohlc.Volume},
+	}
+
+	for _, field := range fields {
+		if field.column == NoColumn {
+			continue
+		}
+
+		value, err := r.schema.ParseNumber(record[field.column])
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %v", record[field.column], err)
+		}
+
+		*field.target = value
+	}
+
+	return ohlc, nil
+}
@@ -0,0 +1,143 @@
+package csvschema_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestDetect(t *testing.T) {
+	t.Run("should detect header and milliseconds timestamps", func(t *testing.T) {
+		got, err := csvschema.Detect([]byte("Date,Price\n1583020800000,8531.11\n1583024400000,8548.63"))
+
+		if err != nil {
+			t.Fatalf("Not expected Detect to return error: %v", err)
+		}
+
+		want := &csvschema.Schema{
+			Delimiter: ',',
+			HasHeader: true,
+			Columns:   csvschema.Columns{Time: 0, Open: csvschema.NoColumn, High: csvschema.NoColumn, Low: csvschema.NoColumn, Close: 1, Volume: csvschema.NoColumn},
+			TimeUnit:  csvschema.UnixMilliseconds,
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should detect ohlcv columns by position", func(t *testing.T) {
+		got, err := csvschema.Detect([]byte("1577836800,2.5128,2.5130,2.5120,2.5125,61.78,1\n"))
+
+		if err != nil {
+			t.Fatalf("Not expected Detect to return error: %v", err)
+		}
+
+		want := &csvschema.Schema{
+			Delimiter: ',',
+			Columns:   csvschema.Columns{Time: 0, Open: 1, High: 2, Low: 3, Close: 4, Volume: 5},
+			TimeUnit:  csvschema.UnixSeconds,
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should detect columns by header names, delimiter and thousands separator", func(t *testing.T) {
+		got, err := csvschema.Detect([]byte("volume;close;timestamp\n10;\"8,531.11\";2020-03-01 00:00:00\n"))
+
+		if err != nil {
+			t.Fatalf("Not expected Detect to return error: %v", err)
+		}
+
+		want := &csvschema.Schema{
+			Delimiter:          ';',
+			HasHeader:          true,
+			Columns:            csvschema.Columns{Time: 2, Open: csvschema.NoColumn, High: csvschema.NoColumn, Low: csvschema.NoColumn, Close: 1, Volume: 0},
+			TimeLayout:         "2006-01-02 15:04:05",
+			ThousandsSeparator: ",",
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should return error if the header names columns the prices do not have", func(t *testing.T) {
+		_, err := csvschema.Detect([]byte("Price,Volume,Date\n8531.11,2.5\n"))
+
+		if err == nil {
+			t.Errorf("Expected Detect to return error")
+		}
+	})
+
+	t.Run("should return error if file has no prices", func(t *testing.T) {
+		_, err := csvschema.Detect([]byte("Date,Price\n"))
+
+		if err == nil {
+			t.Errorf("Expected Detect to return error")
+		}
+	})
+}
+
+func TestReader(t *testing.T) {
+	t.Run("should read prices of a detected schema", func(t *testing.T) {
+		reader, err := csvschema.NewDetectedReader(strings.NewReader("Date,Price\n1583020800000,8531.5\n1583024400000,8548.25"))
+
+		if err != nil {
+			t.Fatalf("Not expected NewDetectedReader to return error: %v", err)
+		}
+
+		var got []domain.OHLC
+
+		for {
+			ohlc, err := reader.Read()
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("Not expected Read to return error: %v", err)
+			}
+
+			got = append(got, *ohlc)
+		}
+
+		firstDate := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+		secondDate := time.Date(2020, 3, 1, 1, 0, 0, 0, time.UTC)
+
+		want := []domain.OHLC{
+			{Time: firstDate, EndTime: firstDate, Open: domain.NewDecimal(8531.5), High: domain.NewDecimal(8531.5), Low: domain.NewDecimal(8531.5), Close: domain.NewDecimal(8531.5)},
+			{Time: secondDate, EndTime: secondDate, Open: domain.NewDecimal(8548.25), High: domain.NewDecimal(8548.25), Low: domain.NewDecimal(8548.25), Close: domain.NewDecimal(8548.25)},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should return error with line number of malformed rows", func(t *testing.T) {
+		reader, err := csvschema.NewDetectedReader(strings.NewReader("1577836800,2.5,2.6,2.4,2.5,61,1\n1577836860,2.5\n"))
+
+		if err != nil {
+			t.Fatalf("Not expected NewDetectedReader to return error: %v", err)
+		}
+
+		if _, err := reader.Read(); err != nil {
+			t.Fatalf("Not expected first Read to return error: %v", err)
+		}
+
+		_, err = reader.Read()
+
+		if err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+			t.Errorf("Expected error on line 2, got %v", err)
+		}
+	})
+}
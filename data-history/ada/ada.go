@@ -1,7 +0,0 @@
-package adadatahistory
-
-const (
-	TwentyTwentyH1 = "ada/2020-h1.csv"
-	Twenty19       = "ada/2019.csv"
-	Twenty1920H1   = "ada/2019-2020-h1.csv"
-)
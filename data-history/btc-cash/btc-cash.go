@@ -1,6 +0,0 @@
-package btccashdatahistory
-
-const (
-	TwentyTwentyH1 = "btc-cash/2020-h1.csv"
-	Twenty19       = "btc-cash/2019.csv"
-)
@@ -1,8 +0,0 @@
-package btcdatahistory
-
-const (
-	March2020      = "btc/2020-mar.csv"
-	TwentyTwentyH1 = "btc/2020-h1.csv"
-	Twenty19       = "btc/2019.csv"
-	Twenty1920H1   = "btc/2019-2020-h1.csv"
-)
@@ -1,6 +0,0 @@
-package eosdatahistory
-
-const (
-	TwentyTwentyH1 = "eos/2020-h1.csv"
-	Twenty19       = "eos/2019.csv"
-)
@@ -1,6 +0,0 @@
-package etccashdatahistory
-
-const (
-	TwentyTwentyH1 = "etc/2020-h1.csv"
-	Twenty19       = "etc/2019.csv"
-)
@@ -1,7 +0,0 @@
-package ethdatahistory
-
-const (
-	TwentyTwentyH1 = "eth/2020-h1.csv"
-	Twenty19       = "eth/2019.csv"
-	Twenty1920H1   = "eth/2019-2020-h1.csv"
-)
@@ -1,6 +0,0 @@
-package ltcdatahistory
-
-const (
-	TwentyTwentyH1 = "ltc/2020-h1.csv"
-	Twenty19       = "ltc/2019.csv"
-)
@@ -1,6 +0,0 @@
-package monerodatahistory
-
-const (
-	TwentyTwentyH1 = "monero/2020-h1.csv"
-	Twenty19       = "monero/2019.csv"
-)
@@ -1,6 +0,0 @@
-package stellardatahistory
-
-const (
-	TwentyTwentyH1 = "stellar/2020-h1.csv"
-	Twenty19       = "stellar/2019.csv"
-)
@@ -1,6 +0,0 @@
-package xrpdatahistory
-
-const (
-	TwentyTwentyH1 = "xrp/2020-h1.csv"
-	Twenty19       = "xrp/2019.csv"
-)
@@ -0,0 +1,24 @@
+package datasets
+
+import "github.com/fabiodmferreira/crypto-trading/domain"
+
+// RepositoryInMemory stores datasets descriptions in memory
+type RepositoryInMemory struct {
+	Datasets []domain.Dataset
+}
+
+// NewRepositoryInMemory returns an instance of RepositoryInMemory
+func NewRepositoryInMemory() *RepositoryInMemory {
+	return &RepositoryInMemory{[]domain.Dataset{}}
+}
+
+// FindAll returns all datasets stored
+func (r *RepositoryInMemory) FindAll() (*[]domain.Dataset, error) {
+	return &r.Datasets, nil
+}
+
+// Save adds a dataset or replaces the one with the same asset and name
+func (r *RepositoryInMemory) Save(dataset *domain.Dataset) error {
+	r.Datasets = upsertDataset(r.Datasets, dataset)
+	return nil
+}
@@ -0,0 +1,254 @@
+package datasets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/pricesquality"
+)
+
+// ManifestFileName is the name of the manifest file stored in the datasets root directory
+const ManifestFileName = "manifest.json"
+
+// Service manages the datasets registry and the files stored in the root directory
+type Service struct {
+	repo    domain.DatasetsRepository
+	rootDir string
+}
+
+// NewService returns an instance of datasets Service
+func NewService(repo domain.DatasetsRepository, rootDir string) *Service {
+	return &Service{repo, rootDir}
+}
+
+// DefaultRootDir is the datasets directory, relative to the working directory, used when DATASETS_DIR is not set
+const DefaultRootDir = "data-history"
+
+// RootDir returns the datasets directory set by the DATASETS_DIR environment variable or DefaultRootDir
+func RootDir() string {
+	if dir := os.Getenv("DATASETS_DIR"); dir != "" {
+		return dir
+	}
+
+	return DefaultRootDir
+}
+
+// FindAll returns every dataset registered
+func (s *Service) FindAll() (*[]domain.Dataset, error) {
+	return s.repo.FindAll()
+}
+
+// GetDataSources returns datasets files paths grouped by asset and dataset name
+func (s *Service) GetDataSources() (map[string]map[string]string, error) {
+	datasets, err := s.repo.FindAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataSources := map[string]map[string]string{}
+
+	for _, dataset := range *datasets {
+		if _, ok := dataSources[dataset.Asset]; !ok {
+			dataSources[dataset.Asset] = map[string]string{}
+		}
+
+		dataSources[dataset.Asset][dataset.Name] = dataset.FilePath
+	}
+
+	return dataSources, nil
+}
+
+// GetFilePath returns the location of a dataset file in the root directory
+func (s *Service) GetFilePath(filePath string) string {
+	return filepath.Join(s.rootDir, filepath.FromSlash(filePath))
+}
+
+// Import stores the dataset file in the root directory and registers its description.
+// Files already in the root directory are not replaced and the file is removed when the dataset is not registered.
+// It returns error when the asset already has a dataset with the same name stored in another file.
+func (s *Service) Import(dataset *domain.Dataset, content io.Reader) error {
+	if dataset.Asset == "" || dataset.Name == "" || dataset.FilePath == "" {
+		return errors.New("dataset asset, name and file path are required")
+	}
+
+	if cleanPath := path.Clean(dataset.FilePath); path.IsAbs(cleanPath) || strings.HasPrefix(cleanPath, "..") {
+		return fmt.Errorf("dataset file path %v must be inside the datasets directory", dataset.FilePath)
+	}
+
+	data, err := ioutil.ReadAll(content)
+
+	if err != nil {
+		return err
+	}
+
+	err = Describe(dataset, bytes.NewReader(data))
+
+	if err != nil {
+		return fmt.Errorf("invalid dataset %v: %v", dataset.FilePath, err)
+	}
+
+	dataset.Checksum = Checksum(data)
+
+	registered, err := s.repo.FindAll()
+
+	if err != nil {
+		return err
+	}
+
+	for _, other := range *registered {
+		if other.Asset == dataset.Asset && other.Name == dataset.Name && other.FilePath != dataset.FilePath {
+			return fmt.Errorf("dataset %v of %v is already stored in %v", dataset.Name, dataset.Asset, other.FilePath)
+		}
+	}
+
+	filePath := s.GetFilePath(dataset.FilePath)
+
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		if err != nil {
+			return err
+		}
+
+		return fmt.Errorf("dataset file %v already exists", dataset.FilePath)
+	}
+
+	err = os.MkdirAll(filepath.Dir(filePath), 0755)
+
+	if err != nil {
+		return err
+	}
+
+	tempPath, err := writeTempFile(filepath.Dir(filePath), data)
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tempPath)
+
+	// a link fails instead of replacing a file created meanwhile
+	err = os.Link(tempPath, filePath)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Save(dataset)
+
+	if err != nil {
+		os.Remove(filePath)
+		return err
+	}
+
+	return nil
+}
+
+// writeTempFile writes the data into a new hidden file of the directory and returns its path
+func writeTempFile(dir string, data []byte) (string, error) {
+	file, err := ioutil.TempFile(dir, ".import-*.csv")
+
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Write(data)
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// Validate verifies the dataset file exists and still matches its registered description
+func (s *Service) Validate(dataset *domain.Dataset) error {
+	data, err := ioutil.ReadFile(s.GetFilePath(dataset.FilePath))
+
+	if err != nil {
+		return err
+	}
+
+	if checksum := Checksum(data); checksum != dataset.Checksum {
+		return fmt.Errorf("checksum mismatch: got %v want %v", checksum, dataset.Checksum)
+	}
+
+	described := domain.Dataset{}
+	err = Describe(&described, bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	if described.Format != dataset.Format {
+		return fmt.Errorf("format mismatch: got %v want %v", described.Format, dataset.Format)
+	}
+
+	if !described.StartDate.Equal(dataset.StartDate) || !described.EndDate.Equal(dataset.EndDate) {
+		return fmt.Errorf("dates range mismatch: got %v - %v want %v - %v", described.StartDate, described.EndDate, dataset.StartDate, dataset.EndDate)
+	}
+
+	return nil
+}
+
+// Checksum returns the sha256 hex digest of a dataset file content
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Describe reads a prices history csv and fills the dataset format, interval and dates range
+func Describe(dataset *domain.Dataset, content io.Reader) error {
+	reader, err := csvschema.NewDetectedReader(content)
+
+	if err != nil {
+		return err
+	}
+
+	var dates []time.Time
+
+	for {
+		ohlc, err := reader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		dates = append(dates, ohlc.Time)
+	}
+
+	if len(dates) == 0 {
+		return errors.New("no prices found")
+	}
+
+	dataset.Format = domain.DatasetFormatDatePrice
+	if reader.Schema().IsOHLC() {
+		dataset.Format = domain.DatasetFormatOHLCV
+	}
+
+	dataset.StartDate = dates[0]
+	dataset.EndDate = dates[len(dates)-1]
+	dataset.Interval = pricesquality.FormatInterval(pricesquality.DetectInterval(dates))
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package datasets_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/datasets"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+const datePriceCSV = `Date,Price
+1583020800000,8531.1174815585
+1583024400000,8548.6381688526
+1583027999000,8646.1581133694
+1583031599000,8634.0109422492`
+
+const ohlcvCSV = `1577836800,2.5128,2.5128,2.5128,2.5128,61.78447576,1
+1577836860,2.5128,2.5128,2.5128,2.5128,308.94983969,1
+1577836920,2.5127,2.5127,2.5127,2.5127,830.2379,1`
+
+func TestDescribe(t *testing.T) {
+	t.Run("should describe files with a header and milliseconds timestamps", func(t *testing.T) {
+		dataset := domain.Dataset{}
+
+		err := datasets.Describe(&dataset, strings.NewReader(datePriceCSV))
+
+		if err != nil {
+			t.Fatalf("Not expected Describe to return error: %v", err)
+		}
+
+		want := domain.Dataset{
+			Format:    domain.DatasetFormatDatePrice,
+			Interval:  "1h",
+			StartDate: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2020, 3, 1, 2, 59, 59, 0, time.UTC),
+		}
+
+		if !reflect.DeepEqual(dataset, want) {
+			t.Errorf("got %+v want %+v", dataset, want)
+		}
+	})
+
+	t.Run("should describe ohlcv files", func(t *testing.T) {
+		dataset := domain.Dataset{}
+
+		err := datasets.Describe(&dataset, strings.NewReader(ohlcvCSV))
+
+		if err != nil {
+			t.Fatalf("Not expected Describe to return error: %v", err)
+		}
+
+		if dataset.Format != domain.DatasetFormatOHLCV || dataset.Interval != "1m" {
+			t.Errorf("got format %v interval %v want format %v interval 1m", dataset.Format, dataset.Interval, domain.DatasetFormatOHLCV)
+		}
+	})
+
+	t.Run("should return error if file has no prices", func(t *testing.T) {
+		err := datasets.Describe(&domain.Dataset{}, strings.NewReader("Date,Price\n"))
+
+		if err == nil {
+			t.Errorf("Expected Describe to return error")
+		}
+	})
+}
+
+func TestServiceImport(t *testing.T) {
+	t.Run("should store the file and register the dataset", func(t *testing.T) {
+		service, repository, rootDir := setupDatasetsService(t)
+		defer os.RemoveAll(rootDir)
+
+		dataset := &domain.Dataset{Asset: "btc", Name: "March 2020", FilePath: "btc/2020-mar.csv"}
+
+		err := service.Import(dataset, strings.NewReader(datePriceCSV))
+
+		if err != nil {
+			t.Fatalf("Not expected Import to return error: %v", err)
+		}
+
+		content, err := ioutil.ReadFile(path.Join(rootDir, "btc", "2020-mar.csv"))
+
+		if err != nil || string(content) != datePriceCSV {
+			t.Errorf("Expected file to be stored in root directory: %v", err)
+		}
+
+		if len(repository.Datasets) != 1 || repository.Datasets[0].Checksum != datasets.Checksum([]byte(datePriceCSV)) {
+			t.Errorf("Expected dataset to be registered with checksum, got %+v", repository.Datasets)
+		}
+
+		if err := service.Validate(dataset); err != nil {
+			t.Errorf("Not expected imported dataset to be invalid: %v", err)
+		}
+	})
+
+	t.Run("should not replace files already stored", func(t *testing.T) {
+		service, repository, rootDir := setupDatasetsService(t)
+		defer os.RemoveAll(rootDir)
+
+		err := service.Import(&domain.Dataset{Asset: "btc", Name: "March 2020", FilePath: "btc/2020-mar.csv"}, strings.NewReader(datePriceCSV))
+
+		if err != nil {
+			t.Fatalf("Not expected Import to return error: %v", err)
+		}
+
+		err = service.Import(&domain.Dataset{Asset: "btc", Name: "Other", FilePath: "btc/2020-mar.csv"}, strings.NewReader(ohlcvCSV))
+
+		if err == nil {
+			t.Errorf("Expected Import to return error")
+		}
+
+		content, _ := ioutil.ReadFile(path.Join(rootDir, "btc", "2020-mar.csv"))
+
+		if string(content) != datePriceCSV || len(repository.Datasets) != 1 {
+			t.Errorf("got %q and %d datasets want the file imported first", content, len(repository.Datasets))
+		}
+	})
+
+	t.Run("should not store the file when the dataset is not registered", func(t *testing.T) {
+		rootDir, err := ioutil.TempDir("", "datasets")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(rootDir)
+
+		service := datasets.NewService(failingRepository{datasets.NewRepositoryInMemory()}, rootDir)
+
+		if err := service.Import(&domain.Dataset{Asset: "btc", Name: "March 2020", FilePath: "btc/2020-mar.csv"}, strings.NewReader(datePriceCSV)); err == nil {
+			t.Errorf("Expected Import to return error")
+		}
+
+		files, _ := ioutil.ReadDir(path.Join(rootDir, "btc"))
+
+		if len(files) != 0 {
+			t.Errorf("got %d files want none", len(files))
+		}
+	})
+
+	t.Run("should not store a dataset of the same asset and name in another file", func(t *testing.T) {
+		service, repository, rootDir := setupDatasetsService(t)
+		defer os.RemoveAll(rootDir)
+
+		err := service.Import(&domain.Dataset{Asset: "btc", Name: "March 2020", FilePath: "btc/2020-mar.csv"}, strings.NewReader(datePriceCSV))
+
+		if err != nil {
+			t.Fatalf("Not expected Import to return error: %v", err)
+		}
+
+		err = service.Import(&domain.Dataset{Asset: "btc", Name: "March 2020", FilePath: "btc/2020-mar-v2.csv"}, strings.NewReader(datePriceCSV))
+
+		if err == nil {
+			t.Errorf("Expected Import to return error")
+		}
+
+		if _, err := os.Stat(path.Join(rootDir, "btc", "2020-mar-v2.csv")); !os.IsNotExist(err) {
+			t.Errorf("Not expected the file to be stored: %v", err)
+		}
+
+		if len(repository.Datasets) != 1 || repository.Datasets[0].FilePath != "btc/2020-mar.csv" {
+			t.Errorf("got %+v want the dataset imported first", repository.Datasets)
+		}
+	})
+
+	t.Run("should reject file paths outside root directory", func(t *testing.T) {
+		service, repository, rootDir := setupDatasetsService(t)
+		defer os.RemoveAll(rootDir)
+
+		dataset := &domain.Dataset{Asset: "btc", Name: "March 2020", FilePath: "../2020-mar.csv"}
+
+		err := service.Import(dataset, strings.NewReader(datePriceCSV))
+
+		if err == nil {
+			t.Errorf("Expected Import to return error")
+		}
+
+		if len(repository.Datasets) != 0 {
+			t.Errorf("Not expected dataset to be registered")
+		}
+	})
+}
+
+// failingRepository does not save datasets
+type failingRepository struct {
+	*datasets.RepositoryInMemory
+}
+
+func (r failingRepository) Save(dataset *domain.Dataset) error {
+	return errors.New("manifest is read only")
+}
+
+func TestServiceValidate(t *testing.T) {
+	t.Run("should return error if file changed", func(t *testing.T) {
+		service, _, rootDir := setupDatasetsService(t)
+		defer os.RemoveAll(rootDir)
+
+		dataset := &domain.Dataset{Asset: "eos", Name: "2020 H1", FilePath: "eos/2020-h1.csv"}
+
+		err := service.Import(dataset, strings.NewReader(ohlcvCSV))
+
+		if err != nil {
+			t.Fatalf("Not expected Import to return error: %v", err)
+		}
+
+		err = ioutil.WriteFile(path.Join(rootDir, "eos", "2020-h1.csv"), []byte(ohlcvCSV+"\n"), 0644)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := service.Validate(dataset); err == nil {
+			t.Errorf("Expected Validate to return error")
+		}
+	})
+
+	t.Run("should return error if file does not exist", func(t *testing.T) {
+		service, _, rootDir := setupDatasetsService(t)
+		defer os.RemoveAll(rootDir)
+
+		if err := service.Validate(&domain.Dataset{FilePath: "xrp/2019.csv"}); err == nil {
+			t.Errorf("Expected Validate to return error")
+		}
+	})
+}
+
+func TestManifestRepository(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "datasets")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(rootDir)
+
+	repository := datasets.NewManifestRepository(path.Join(rootDir, datasets.ManifestFileName))
+
+	repository.Save(&domain.Dataset{Asset: "btc", Name: "2019", FilePath: "btc/2019.csv"})
+	repository.Save(&domain.Dataset{Asset: "btc", Name: "2019", FilePath: "btc/2019-v2.csv"})
+	repository.Save(&domain.Dataset{Asset: "eth", Name: "2019", FilePath: "eth/2019.csv"})
+
+	got, err := datasets.NewManifestRepository(path.Join(rootDir, datasets.ManifestFileName)).FindAll()
+
+	if err != nil {
+		t.Fatalf("Not expected FindAll to return error: %v", err)
+	}
+
+	want := []domain.Dataset{
+		{Asset: "btc", Name: "2019", FilePath: "btc/2019-v2.csv"},
+		{Asset: "eth", Name: "2019", FilePath: "eth/2019.csv"},
+	}
+
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("got %+v want %+v", *got, want)
+	}
+}
+
+func TestRootDir(t *testing.T) {
+	defer os.Unsetenv("DATASETS_DIR")
+
+	t.Run("should return the relative default directory", func(t *testing.T) {
+		os.Unsetenv("DATASETS_DIR")
+
+		if got := datasets.RootDir(); got != datasets.DefaultRootDir {
+			t.Errorf("got %v want %v", got, datasets.DefaultRootDir)
+		}
+	})
+
+	t.Run("should return the directory of the environment", func(t *testing.T) {
+		os.Setenv("DATASETS_DIR", "/var/lib/datasets")
+
+		if got := datasets.RootDir(); got != "/var/lib/datasets" {
+			t.Errorf("got %v want /var/lib/datasets", got)
+		}
+	})
+}
+
+func setupDatasetsService(t *testing.T) (*datasets.Service, *datasets.RepositoryInMemory, string) {
+	rootDir, err := ioutil.TempDir("", "datasets")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repository := datasets.NewRepositoryInMemory()
+
+	return datasets.NewService(repository, rootDir), repository, rootDir
+}
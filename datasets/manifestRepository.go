@@ -0,0 +1,86 @@
+package datasets
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// ManifestRepository stores datasets descriptions in a json manifest file
+type ManifestRepository struct {
+	filePath string
+	mu       sync.Mutex
+}
+
+// NewManifestRepository returns an instance of ManifestRepository
+func NewManifestRepository(filePath string) *ManifestRepository {
+	return &ManifestRepository{filePath: filePath}
+}
+
+// FindAll returns every dataset in the manifest
+func (r *ManifestRepository) FindAll() (*[]domain.Dataset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.read()
+}
+
+// Save adds a dataset to the manifest or replaces the one with the same asset and name
+func (r *ManifestRepository) Save(dataset *domain.Dataset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	datasets, err := r.read()
+
+	if err != nil {
+		return err
+	}
+
+	*datasets = upsertDataset(*datasets, dataset)
+
+	content, err := json.MarshalIndent(datasets, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.filePath, append(content, '\n'), 0644)
+}
+
+// read decodes the manifest file. A manifest that does not exist yet has no datasets.
+func (r *ManifestRepository) read() (*[]domain.Dataset, error) {
+	datasets := []domain.Dataset{}
+
+	content, err := ioutil.ReadFile(r.filePath)
+
+	if os.IsNotExist(err) {
+		return &datasets, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &datasets)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &datasets, nil
+}
+
+// upsertDataset replaces the dataset with the same asset and name or appends it
+func upsertDataset(datasets []domain.Dataset, dataset *domain.Dataset) []domain.Dataset {
+	for index, d := range datasets {
+		if d.Asset == dataset.Asset && d.Name == dataset.Name {
+			datasets[index] = *dataset
+			return datasets
+		}
+	}
+
+	return append(datasets, *dataset)
+}
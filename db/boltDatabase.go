@@ -0,0 +1,157 @@
+package db
+
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// boltIndexesBucket is the bucket that keeps the unique indexes of every collection
+const boltIndexesBucket = "_indexes"
+
+// boltIndex is the unique index definition stored in the indexes bucket
+type boltIndex struct {
+	Collection string   `bson:"collection"`
+	Keys       []string `bson:"keys"`
+}
+
+// BoltDatabase is a memory database that persists every document into a bolt file.
+// Documents are loaded on open so queries are served from memory.
+// A bolt file can only be opened by one process at a time.
+type BoltDatabase struct {
+	*MemoryDatabase
+	bolt *bolt.DB
+}
+
+// OpenBoltDatabase opens or creates the bolt file passed by argument and loads its documents
+func OpenBoltDatabase(path string) (*BoltDatabase, error) {
+	boltDB, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+
+	if err != nil {
+		return nil, err
+	}
+
+	database := &BoltDatabase{NewMemoryDatabase(), boltDB}
+
+	if err := database.load(); err != nil {
+		boltDB.Close()
+		return nil, err
+	}
+
+	database.MemoryDatabase.store = &boltStore{boltDB}
+
+	return database, nil
+}
+
+// Close closes the bolt file
+func (d *BoltDatabase) Close() error {
+	return d.bolt.Close()
+}
+
+// load reads the documents and the unique indexes of every collection
+func (d *BoltDatabase) load() error {
+	indexes := []boltIndex{}
+
+	err := d.bolt.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+			if string(name) == boltIndexesBucket {
+				return bucket.ForEach(func(key, _ []byte) error {
+					var index boltIndex
+					err := bson.Unmarshal(key, &index)
+					indexes = append(indexes, index)
+					return err
+				})
+			}
+
+			repo := d.collection(string(name))
+
+			return bucket.ForEach(func(_, value []byte) error {
+				var document bson.D
+
+				if err := bson.Unmarshal(value, &document); err != nil {
+					return err
+				}
+
+				repo.documents = append(repo.documents, document)
+				repo.addUniqueValues(document)
+
+				return nil
+			})
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, index := range indexes {
+		repo := d.collection(index.Collection)
+		repo.uniqueKeys = append(repo.uniqueKeys, index.Keys)
+		repo.uniqueValues = append(repo.uniqueValues, map[string]bool{})
+		repo.resetUniqueValues()
+	}
+
+	return nil
+}
+
+// boltStore writes the changes of a memory database into a bolt file with a bucket per collection
+type boltStore struct {
+	bolt *bolt.DB
+}
+
+// apply writes the changes in a single bolt transaction
+func (s *boltStore) apply(changes []memoryChange) error {
+	return s.bolt.Update(func(tx *bolt.Tx) error {
+		for _, change := range changes {
+			bucket, err := tx.CreateBucketIfNotExists([]byte(change.collection))
+
+			if err != nil {
+				return err
+			}
+
+			for _, document := range change.delete {
+				if err := bucket.Delete(boltKey(document)); err != nil {
+					return err
+				}
+			}
+
+			for _, document := range change.put {
+				value, err := bson.Marshal(document)
+
+				if err != nil {
+					return err
+				}
+
+				if err := bucket.Put(boltKey(document), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *boltStore) putIndex(collection string, keys []string) error {
+	return s.bolt.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(boltIndexesBucket))
+
+		if err != nil {
+			return err
+		}
+
+		key, err := bson.Marshal(boltIndex{collection, keys})
+
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(key, []byte{})
+	})
+}
+
+// boltKey returns the key of a document which is its encoded id
+func boltKey(document bson.D) []byte {
+	return []byte(uniqueKey(document, []string{"_id"}))
+}
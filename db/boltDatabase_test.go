@@ -0,0 +1,75 @@
+package db_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestBoltDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bolt")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "test.db")
+
+	database, err := db.OpenBoltDatabase(file)
+
+	if err != nil {
+		t.Fatalf("Not expected OpenBoltDatabase to return error: %v", err)
+	}
+
+	repo := database.Collection("prices")
+	repo.CreateIndex(mongo.IndexModel{Keys: bson.D{{Key: "asset", Value: 1}}, Options: options.Index().SetUnique(true)})
+
+	btcID := primitive.NewObjectID()
+	repo.InsertOne(price{btcID, "BTC", startDate, 1})
+	repo.InsertOne(price{primitive.NewObjectID(), "ETH", startDate, 2})
+	repo.InsertOne(price{primitive.NewObjectID(), "ADA", startDate, 3})
+	repo.UpdateOne(bson.M{"_id": btcID}, bson.M{"$set": bson.M{"value": 10}})
+	repo.BulkDelete(bson.M{"asset": "ETH"})
+
+	if err := repo.BulkCreate(&[]bson.M{{"asset": "DOT"}, {"asset": "ADA"}}); !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("Expected duplicate key error, got %v", err)
+	}
+
+	database.Close()
+
+	database, err = db.OpenBoltDatabase(file)
+
+	if err != nil {
+		t.Fatalf("Not expected OpenBoltDatabase to return error: %v", err)
+	}
+
+	defer database.Close()
+
+	repo = database.Collection("prices")
+
+	t.Run("should load documents stored", func(t *testing.T) {
+		var got []price
+		repo.FindAll(&got, bson.M{}, options.Find().SetSort(bson.M{"value": 1}))
+
+		if len(got) != 2 || got[0].Asset != "ADA" || got[1].Asset != "BTC" || got[1].Value != 10 {
+			t.Errorf("got %+v want ADA and BTC with value 10", got)
+		}
+	})
+
+	t.Run("should load unique indexes", func(t *testing.T) {
+		err := repo.InsertOne(price{primitive.NewObjectID(), "BTC", startDate, 1})
+
+		if !mongo.IsDuplicateKeyError(err) {
+			t.Errorf("Expected duplicate key error, got %v", err)
+		}
+	})
+}
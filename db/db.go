@@ -2,24 +2,44 @@ package db
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/fabiodmferreira/crypto-trading/domain"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 const (
-	ASSETS_COLLECTION                       = "assets"
-	EVENT_LOGS_COLLECTION                   = "eventlogs"
-	NOTIFICATIONS_COLLECTION                = "notifications"
-	ACCOUNTS_COLLECTION                     = "accounts"
-	BENCHMARKS_COLLECTION                   = "benchmarks"
-	ASSETS_PRICES_COLLECTION                = "assetsprices"
-	APPLICATION_EXECUTION_STATES_COLLECTION = "applicationExecutionStates"
-	APPLICATIONS_COLLECTION                 = "applications"
-	DCA_JOBS_COLLECTION                     = "dcaJobs"
-	DCA_ASSETS_COLLECTION                   = "dcaAssets"
+	ASSETS_COLLECTION                               = "assets"
+	EVENT_LOGS_COLLECTION                           = "eventlogs"
+	NOTIFICATIONS_COLLECTION                        = "notifications"
+	ACCOUNTS_COLLECTION                             = "accounts"
+	BENCHMARKS_COLLECTION                           = "benchmarks"
+	ASSETS_PRICES_COLLECTION                        = "assetsprices"
+	APPLICATION_EXECUTION_STATES_COLLECTION         = "applicationExecutionStates"
+	APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION = "applicationExecutionStatesRollups"
+	APPLICATIONS_COLLECTION                         = "applications"
+	DCA_JOBS_COLLECTION                             = "dcaJobs"
+	DCA_ASSETS_COLLECTION                           = "dcaAssets"
+	MIGRATIONS_COLLECTION                           = "migrations"
+	LEDGER_COLLECTION                               = "ledger"
+	TRADES_COLLECTION                               = "trades"
+	MARKET_DATA_STATS_COLLECTION                    = "marketDataStats"
+	OPTIONS_VERSIONS_COLLECTION                     = "optionsVersions"
+	SHADOW_ORDERS_COLLECTION                        = "shadowOrders"
+)
+
+const (
+	// MongoStorage stores documents on the mongo database set by the environment
+	MongoStorage = "mongo"
+	// MemoryStorage keeps documents in memory, they are lost when the process stops
+	MemoryStorage = "memory"
+	// BoltStorage stores documents on an embedded bolt file
+	BoltStorage = "bolt"
+	// DefaultBoltPath is the bolt file used when the environment does not set one
+	DefaultBoltPath = "crypto-trading.db"
 )
 
 func NewMongoQueryContext() (context.Context, context.CancelFunc) {
@@ -49,3 +69,59 @@ func ConnectDB(mongoUrl string) (*mongo.Client, error) {
 
 	return client, nil
 }
+
+// NewRepositoryFactory returns a domain.RepositoryFactory of the collections of the mongo database passed by argument
+func NewRepositoryFactory(database *mongo.Database) domain.RepositoryFactory {
+	return func(collection string) domain.Repository {
+		return NewRepository(database.Collection(collection))
+	}
+}
+
+// Storage has the repositories and the unit of work of a storage
+type Storage struct {
+	Repositories domain.RepositoryFactory
+	UnitOfWork   domain.UnitOfWork
+	// MongoDatabase is only set on mongo storage
+	MongoDatabase *mongo.Database
+}
+
+// NewMemoryStorage returns a Storage that keeps every collection in memory
+func NewMemoryStorage() *Storage {
+	database := NewMemoryDatabase()
+
+	return &Storage{database.Collection, database, nil}
+}
+
+// OpenStorage returns the storage set by the environment
+func OpenStorage(env domain.Env) (*Storage, error) {
+	switch env.Storage {
+	case MemoryStorage:
+		return NewMemoryStorage(), nil
+	case BoltStorage:
+		path := env.BoltPath
+
+		if path == "" {
+			path = DefaultBoltPath
+		}
+
+		database, err := OpenBoltDatabase(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Storage{database.Collection, database, nil}, nil
+	case MongoStorage, "":
+		client, err := ConnectDB(env.MongoURL)
+
+		if err != nil {
+			return nil, err
+		}
+
+		database := client.Database(env.MongoDB)
+
+		return &Storage{NewRepositoryFactory(database), NewMongoUnitOfWork(client, database), database}, nil
+	default:
+		return nil, fmt.Errorf("storage %v is not supported", env.Storage)
+	}
+}
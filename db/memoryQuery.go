@@ -0,0 +1,1286 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// lookup returns the value of a field, supporting dotted paths to embedded documents and arrays indexes
+func lookup(document bson.D, path string) (interface{}, bool) {
+	var current interface{} = document
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case bson.D:
+			found := false
+
+			for _, e := range v {
+				if e.Key == key {
+					current, found = e.Value, true
+					break
+				}
+			}
+
+			if !found {
+				return nil, false
+			}
+		case bson.A:
+			i, err := strconv.Atoi(key)
+
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+
+			current = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// setValue sets the value of a field, creating the embedded documents of dotted paths when needed
+func setValue(document bson.D, path string, value interface{}) bson.D {
+	keys := strings.SplitN(path, ".", 2)
+
+	for i, e := range document {
+		if e.Key != keys[0] {
+			continue
+		}
+
+		if len(keys) == 1 {
+			document[i].Value = value
+		} else {
+			embedded, _ := e.Value.(bson.D)
+			document[i].Value = setValue(embedded, keys[1], value)
+		}
+
+		return document
+	}
+
+	if len(keys) == 1 {
+		return append(document, bson.E{Key: path, Value: value})
+	}
+
+	return append(document, bson.E{Key: keys[0], Value: setValue(bson.D{}, keys[1], value)})
+}
+
+// unsetValue removes a field, supporting dotted paths to embedded documents
+func unsetValue(document bson.D, path string) bson.D {
+	keys := strings.SplitN(path, ".", 2)
+
+	for i, e := range document {
+		if e.Key != keys[0] {
+			continue
+		}
+
+		if len(keys) == 1 {
+			return append(document[:i:i], document[i+1:]...)
+		}
+
+		if embedded, ok := e.Value.(bson.D); ok {
+			document[i].Value = unsetValue(embedded, keys[1])
+		}
+
+		return document
+	}
+
+	return document
+}
+
+// matchDocument returns whether a document matches a query filter
+func matchDocument(document bson.D, filter bson.D) (bool, error) {
+	for _, e := range filter {
+		var ok bool
+		var err error
+
+		switch e.Key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(document, e.Key, e.Value)
+		default:
+			ok, err = matchField(document, e.Key, e.Value)
+		}
+
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func matchLogical(document bson.D, operator string, value interface{}) (bool, error) {
+	filters, ok := value.(bson.A)
+
+	if !ok {
+		return false, fmt.Errorf("%v must be an array", operator)
+	}
+
+	matches := 0
+
+	for _, filter := range filters {
+		f, ok := filter.(bson.D)
+
+		if !ok {
+			return false, fmt.Errorf("%v entries must be documents", operator)
+		}
+
+		match, err := matchDocument(document, f)
+
+		if err != nil {
+			return false, err
+		}
+
+		if match {
+			matches++
+		}
+	}
+
+	switch operator {
+	case "$and":
+		return matches == len(filters), nil
+	case "$or":
+		return matches > 0, nil
+	default:
+		return matches == 0, nil
+	}
+}
+
+func matchField(document bson.D, key string, condition interface{}) (bool, error) {
+	value, exists := lookup(document, key)
+
+	operators, ok := condition.(bson.D)
+
+	if !ok || len(operators) == 0 || !strings.HasPrefix(operators[0].Key, "$") {
+		return matchEquals(value, exists, condition), nil
+	}
+
+	for _, operator := range operators {
+		var match bool
+
+		switch operator.Key {
+		case "$eq":
+			match = matchEquals(value, exists, operator.Value)
+		case "$ne":
+			match = !matchEquals(value, exists, operator.Value)
+		case "$gt", "$gte", "$lt", "$lte":
+			match = matchComparison(value, exists, operator.Key, operator.Value)
+		case "$in", "$nin":
+			values, ok := operator.Value.(bson.A)
+
+			if !ok {
+				return false, fmt.Errorf("%v needs an array", operator.Key)
+			}
+
+			for _, v := range values {
+				if matchEquals(value, exists, v) {
+					match = true
+					break
+				}
+			}
+
+			if operator.Key == "$nin" {
+				match = !match
+			}
+		case "$exists":
+			match = exists == isTruthy(operator.Value)
+		case "$not":
+			not, err := matchField(document, key, operator.Value)
+
+			if err != nil {
+				return false, err
+			}
+
+			match = !not
+		default:
+			return false, fmt.Errorf("query operator %v is not supported", operator.Key)
+		}
+
+		if !match {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// matchEquals returns whether a value is equal to the condition or is an array that contains it
+func matchEquals(value interface{}, exists bool, condition interface{}) bool {
+	if condition == nil {
+		return !exists || value == nil
+	}
+
+	if !exists {
+		return false
+	}
+
+	if values, ok := value.(bson.A); ok {
+		if _, ok := condition.(bson.A); !ok {
+			for _, v := range values {
+				if equalValues(v, condition) {
+					return true
+				}
+			}
+
+			return false
+		}
+	}
+
+	return equalValues(value, condition)
+}
+
+func matchComparison(value interface{}, exists bool, operator string, condition interface{}) bool {
+	if !exists || typeRank(value) != typeRank(condition) {
+		return false
+	}
+
+	c := compareValues(value, condition)
+
+	switch operator {
+	case "$gt":
+		return c > 0
+	case "$gte":
+		return c >= 0
+	case "$lt":
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+// applyUpdate returns a copy of the document with update operators applied or the replacement document
+func applyUpdate(document bson.D, update bson.D) (bson.D, error) {
+	id, _ := lookup(document, "_id")
+
+	if len(update) == 0 || !strings.HasPrefix(update[0].Key, "$") {
+		return append(bson.D{{Key: "_id", Value: id}}, unsetValue(copyDocument(update), "_id")...), nil
+	}
+
+	result := copyDocument(document)
+
+	for _, operator := range update {
+		fields, ok := operator.Value.(bson.D)
+
+		if !ok {
+			return nil, fmt.Errorf("%v must be a document", operator.Key)
+		}
+
+		for _, field := range fields {
+			switch operator.Key {
+			case "$set":
+				result = setValue(result, field.Key, field.Value)
+			case "$setOnInsert":
+			case "$unset":
+				result = unsetValue(result, field.Key)
+			case "$inc":
+				current, exists := lookup(result, field.Key)
+
+				if !exists {
+					current = int32(0)
+				}
+
+				sum, err := arithmetic("$add", bson.A{current, field.Value})
+
+				if err != nil {
+					return nil, err
+				}
+
+				result = setValue(result, field.Key, sum)
+			case "$rename":
+				to, ok := field.Value.(string)
+
+				if !ok {
+					return nil, fmt.Errorf("$rename target must be a string")
+				}
+
+				if value, exists := lookup(result, field.Key); exists {
+					result = setValue(unsetValue(result, field.Key), to, value)
+				}
+			case "$push":
+				current, _ := lookup(result, field.Key)
+				values, _ := current.(bson.A)
+				result = setValue(result, field.Key, append(values, field.Value))
+			default:
+				return nil, fmt.Errorf("update operator %v is not supported", operator.Key)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// applyStage applies an aggregation stage to documents
+func applyStage(documents []bson.D, stage string, value interface{}) ([]bson.D, error) {
+	switch stage {
+	case "$match":
+		filter, err := asDocument(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results := []bson.D{}
+
+		for _, document := range documents {
+			ok, err := matchDocument(document, filter)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				results = append(results, document)
+			}
+		}
+
+		return results, nil
+	case "$sort":
+		keys, err := asDocument(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sortDocuments(documents, keys)
+
+		return documents, nil
+	case "$skip", "$limit":
+		n, ok := toFloat(value)
+
+		if !ok {
+			return nil, fmt.Errorf("%v must be a number", stage)
+		}
+
+		if stage == "$skip" {
+			return skipDocuments(documents, int(n)), nil
+		}
+
+		return limitDocuments(documents, int(n)), nil
+	case "$project":
+		spec, err := asDocument(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return projectDocuments(documents, spec)
+	case "$addFields", "$set":
+		spec, err := asDocument(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results := []bson.D{}
+
+		for _, document := range documents {
+			result := document
+
+			for _, field := range spec {
+				v, err := evaluate(document, field.Value)
+
+				if err != nil {
+					return nil, err
+				}
+
+				result = setValue(result, field.Key, v)
+			}
+
+			results = append(results, result)
+		}
+
+		return results, nil
+	case "$unset":
+		fields := toStrings(value)
+
+		for i, document := range documents {
+			for _, field := range fields {
+				document = unsetValue(document, field)
+			}
+
+			documents[i] = document
+		}
+
+		return documents, nil
+	case "$unwind":
+		path, ok := value.(string)
+
+		if spec, isDocument := value.(bson.D); isDocument {
+			v, _ := lookup(spec, "path")
+			path, ok = v.(string)
+		}
+
+		if !ok || !strings.HasPrefix(path, "$") {
+			return nil, fmt.Errorf("$unwind needs a field path")
+		}
+
+		results := []bson.D{}
+
+		for _, document := range documents {
+			v, exists := lookup(document, path[1:])
+
+			if values, isArray := v.(bson.A); isArray {
+				for _, value := range values {
+					results = append(results, setValue(copyDocument(document), path[1:], value))
+				}
+			} else if exists && v != nil {
+				results = append(results, document)
+			}
+		}
+
+		return results, nil
+	case "$group":
+		spec, err := asDocument(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return groupDocuments(documents, spec)
+	case "$count":
+		name, ok := value.(string)
+
+		if !ok {
+			return nil, fmt.Errorf("$count must be a string")
+		}
+
+		return []bson.D{{{Key: name, Value: int32(len(documents))}}}, nil
+	default:
+		return nil, fmt.Errorf("pipeline stage %v is not supported", stage)
+	}
+}
+
+func projectDocuments(documents []bson.D, spec bson.D) ([]bson.D, error) {
+	inclusion := false
+	includeID := true
+
+	for _, field := range spec {
+		isExpression := !isNumberOrBool(field.Value)
+
+		if field.Key == "_id" {
+			includeID = isExpression || isTruthy(field.Value)
+			continue
+		}
+
+		if isExpression || isTruthy(field.Value) {
+			inclusion = true
+		}
+	}
+
+	results := []bson.D{}
+
+	for _, document := range documents {
+		if !inclusion {
+			result := copyDocument(document)
+
+			for _, field := range spec {
+				if !isTruthy(field.Value) {
+					result = unsetValue(result, field.Key)
+				}
+			}
+
+			results = append(results, result)
+			continue
+		}
+
+		result := bson.D{}
+
+		if id, ok := lookup(document, "_id"); ok && includeID {
+			result = append(result, bson.E{Key: "_id", Value: id})
+		}
+
+		for _, field := range spec {
+			if isNumberOrBool(field.Value) {
+				if value, ok := lookup(document, field.Key); ok && isTruthy(field.Value) && field.Key != "_id" {
+					result = setValue(result, field.Key, value)
+				}
+
+				continue
+			}
+
+			value, err := evaluate(document, field.Value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result = setValue(result, field.Key, value)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func groupDocuments(documents []bson.D, spec bson.D) ([]bson.D, error) {
+	idExpression, _ := lookup(spec, "_id")
+
+	type group struct {
+		id     interface{}
+		values map[string][]interface{}
+	}
+
+	groups := []*group{}
+	groupsByKey := map[string]*group{}
+
+	for _, document := range documents {
+		id, err := evaluate(document, idExpression)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key := uniqueKey(bson.D{{Key: "_id", Value: id}}, []string{"_id"})
+
+		if _, ok := groupsByKey[key]; !ok {
+			groupsByKey[key] = &group{id: id, values: map[string][]interface{}{}}
+			groups = append(groups, groupsByKey[key])
+		}
+
+		g := groupsByKey[key]
+
+		for _, field := range spec {
+			if field.Key == "_id" {
+				continue
+			}
+
+			accumulator, ok := field.Value.(bson.D)
+
+			if !ok || len(accumulator) != 1 {
+				return nil, fmt.Errorf("group field %v must be an accumulator", field.Key)
+			}
+
+			value, err := evaluate(document, accumulator[0].Value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			g.values[field.Key] = append(g.values[field.Key], value)
+		}
+	}
+
+	results := []bson.D{}
+
+	for _, g := range groups {
+		result := bson.D{{Key: "_id", Value: g.id}}
+
+		for _, field := range spec {
+			if field.Key == "_id" {
+				continue
+			}
+
+			value, err := accumulate(field.Value.(bson.D)[0].Key, g.values[field.Key])
+
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, bson.E{Key: field.Key, Value: value})
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// accumulate returns the value of a group accumulator for the values of the group documents
+func accumulate(accumulator string, values []interface{}) (interface{}, error) {
+	switch accumulator {
+	case "$sum":
+		numbers := bson.A{int32(0)}
+
+		for _, value := range values {
+			if _, ok := toFloat(value); ok {
+				numbers = append(numbers, value)
+			}
+		}
+
+		return arithmetic("$add", numbers)
+	case "$avg":
+		sum, count := 0.0, 0
+
+		for _, value := range values {
+			if n, ok := toFloat(value); ok {
+				sum += n
+				count++
+			}
+		}
+
+		if count == 0 {
+			return nil, nil
+		}
+
+		return sum / float64(count), nil
+	case "$min", "$max":
+		var result interface{}
+
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+
+			c := compareValues(value, result)
+
+			if result == nil || (accumulator == "$min" && c < 0) || (accumulator == "$max" && c > 0) {
+				result = value
+			}
+		}
+
+		return result, nil
+	case "$first", "$last":
+		if len(values) == 0 {
+			return nil, nil
+		}
+
+		if accumulator == "$first" {
+			return values[0], nil
+		}
+
+		return values[len(values)-1], nil
+	case "$push", "$addToSet":
+		result := bson.A{}
+
+		for _, value := range values {
+			if value == nil {
+				continue
+			}
+
+			duplicated := false
+
+			for _, v := range result {
+				if accumulator == "$addToSet" && equalValues(v, value) {
+					duplicated = true
+					break
+				}
+			}
+
+			if !duplicated {
+				result = append(result, value)
+			}
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("group accumulator %v is not supported", accumulator)
+	}
+}
+
+// evaluate returns the value of an aggregation expression for a document
+func evaluate(document bson.D, expression interface{}) (interface{}, error) {
+	switch v := expression.(type) {
+	case string:
+		if v == "$$ROOT" {
+			return document, nil
+		}
+
+		if strings.HasPrefix(v, "$$") {
+			return nil, fmt.Errorf("variable %v is not supported", v)
+		}
+
+		if strings.HasPrefix(v, "$") {
+			value, _ := lookup(document, v[1:])
+			return value, nil
+		}
+
+		return v, nil
+	case bson.A:
+		values := bson.A{}
+
+		for _, e := range v {
+			value, err := evaluate(document, e)
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, value)
+		}
+
+		return values, nil
+	case bson.D:
+		if len(v) == 1 && strings.HasPrefix(v[0].Key, "$") {
+			return evaluateOperator(document, v[0].Key, v[0].Value)
+		}
+
+		result := bson.D{}
+
+		for _, e := range v {
+			value, err := evaluate(document, e.Value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, bson.E{Key: e.Key, Value: value})
+		}
+
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+func evaluateOperator(document bson.D, operator string, argument interface{}) (interface{}, error) {
+	if operator == "$literal" {
+		return argument, nil
+	}
+
+	if operator == "$dateFromParts" {
+		parts, ok := argument.(bson.D)
+
+		if !ok {
+			return nil, fmt.Errorf("$dateFromParts needs a document")
+		}
+
+		values := map[string]int{"year": 1970, "month": 1, "day": 1}
+
+		for _, part := range parts {
+			value, err := evaluate(document, part.Value)
+
+			if err != nil {
+				return nil, err
+			}
+
+			n, ok := toFloat(value)
+
+			if !ok {
+				return nil, nil
+			}
+
+			values[part.Key] = int(n)
+		}
+
+		date := time.Date(values["year"], time.Month(values["month"]), values["day"], values["hour"], values["minute"], values["second"], values["millisecond"]*int(time.Millisecond), time.UTC)
+
+		return primitive.NewDateTimeFromTime(date), nil
+	}
+
+	value, err := evaluate(document, argument)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "$add", "$subtract", "$multiply", "$divide":
+		arguments, ok := value.(bson.A)
+
+		if !ok {
+			return nil, fmt.Errorf("%v needs an array", operator)
+		}
+
+		return arithmetic(operator, arguments)
+	case "$year", "$month", "$dayOfMonth", "$hour", "$minute", "$second", "$dayOfWeek":
+		if spec, ok := value.(bson.D); ok {
+			value, _ = lookup(spec, "date")
+		}
+
+		date, ok := value.(primitive.DateTime)
+
+		if !ok {
+			return nil, nil
+		}
+
+		return datePart(operator, date.Time().UTC()), nil
+	case "$ifNull":
+		arguments, ok := value.(bson.A)
+
+		if !ok || len(arguments) != 2 {
+			return nil, fmt.Errorf("$ifNull needs two arguments")
+		}
+
+		if arguments[0] != nil {
+			return arguments[0], nil
+		}
+
+		return arguments[1], nil
+	case "$cond":
+		arguments, ok := value.(bson.A)
+
+		if spec, isDocument := value.(bson.D); isDocument {
+			condition, _ := lookup(spec, "if")
+			then, _ := lookup(spec, "then")
+			otherwise, _ := lookup(spec, "else")
+			arguments, ok = bson.A{condition, then, otherwise}, true
+		}
+
+		if !ok || len(arguments) != 3 {
+			return nil, fmt.Errorf("$cond needs if, then and else")
+		}
+
+		if isTruthy(arguments[0]) {
+			return arguments[1], nil
+		}
+
+		return arguments[2], nil
+	case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+		arguments, ok := value.(bson.A)
+
+		if !ok || len(arguments) != 2 {
+			return nil, fmt.Errorf("%v needs two arguments", operator)
+		}
+
+		c := compareValues(arguments[0], arguments[1])
+
+		switch operator {
+		case "$eq":
+			return c == 0, nil
+		case "$ne":
+			return c != 0, nil
+		case "$gt":
+			return c > 0, nil
+		case "$gte":
+			return c >= 0, nil
+		case "$lt":
+			return c < 0, nil
+		default:
+			return c <= 0, nil
+		}
+	default:
+		return nil, fmt.Errorf("expression operator %v is not supported", operator)
+	}
+}
+
+// arithmetic applies an arithmetic operator to numbers and dates the same way mongo does
+func arithmetic(operator string, arguments bson.A) (interface{}, error) {
+	if len(arguments) == 0 {
+		return nil, fmt.Errorf("%v needs arguments", operator)
+	}
+
+	for _, argument := range arguments {
+		if argument == nil {
+			return nil, nil
+		}
+	}
+
+	if operator == "$add" {
+		var date *primitive.DateTime
+		numbers := bson.A{}
+
+		for _, argument := range arguments {
+			if d, ok := argument.(primitive.DateTime); ok {
+				date = &d
+				continue
+			}
+
+			numbers = append(numbers, argument)
+		}
+
+		if date != nil {
+			ms, _ := toFloat(sumNumbers(numbers))
+			return primitive.DateTime(int64(*date) + int64(ms)), nil
+		}
+
+		return sumNumbers(numbers), nil
+	}
+
+	if operator == "$subtract" {
+		if len(arguments) != 2 {
+			return nil, fmt.Errorf("$subtract needs two arguments")
+		}
+
+		a, aIsDate := arguments[0].(primitive.DateTime)
+		b, bIsDate := arguments[1].(primitive.DateTime)
+
+		if aIsDate && bIsDate {
+			return int64(a) - int64(b), nil
+		}
+
+		if aIsDate {
+			ms, _ := toFloat(arguments[1])
+			return primitive.DateTime(int64(a) - int64(ms)), nil
+		}
+
+		negative, err := arithmetic("$multiply", bson.A{arguments[1], int32(-1)})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return sumNumbers(bson.A{arguments[0], negative}), nil
+	}
+
+	if hasDecimal(arguments) {
+		return decimalArithmetic(operator, arguments)
+	}
+
+	numbers := []float64{}
+	integers := true
+
+	for _, argument := range arguments {
+		n, ok := toFloat(argument)
+
+		if !ok {
+			return nil, fmt.Errorf("%v only supports numeric types, not %T", operator, argument)
+		}
+
+		if _, ok := argument.(float64); ok {
+			integers = false
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	if operator == "$divide" {
+		if len(numbers) != 2 {
+			return nil, fmt.Errorf("$divide needs two arguments")
+		}
+
+		if numbers[1] == 0 {
+			return nil, fmt.Errorf("can't $divide by zero")
+		}
+
+		return numbers[0] / numbers[1], nil
+	}
+
+	product := 1.0
+
+	for _, n := range numbers {
+		product *= n
+	}
+
+	if integers {
+		return integer(product, arguments), nil
+	}
+
+	return product, nil
+}
+
+// sumNumbers returns the sum keeping integers types when every number is an integer
+func sumNumbers(numbers bson.A) interface{} {
+	if hasDecimal(numbers) {
+		sum, _ := decimalArithmetic("$add", numbers)
+		return sum
+	}
+
+	sum := 0.0
+
+	for _, number := range numbers {
+		n, _ := toFloat(number)
+		sum += n
+	}
+
+	for _, number := range numbers {
+		if _, ok := number.(float64); ok {
+			return sum
+		}
+	}
+
+	return integer(sum, numbers)
+}
+
+// integer returns the number as int32 when every argument is an int32 and the result fits on it, otherwise as int64
+func integer(n float64, arguments bson.A) interface{} {
+	for _, argument := range arguments {
+		if _, ok := argument.(int32); !ok {
+			return int64(n)
+		}
+	}
+
+	if n > math.MaxInt32 || n < math.MinInt32 {
+		return int64(n)
+	}
+
+	return int32(n)
+}
+
+func datePart(operator string, date time.Time) int32 {
+	switch operator {
+	case "$year":
+		return int32(date.Year())
+	case "$month":
+		return int32(date.Month())
+	case "$dayOfMonth":
+		return int32(date.Day())
+	case "$hour":
+		return int32(date.Hour())
+	case "$minute":
+		return int32(date.Minute())
+	case "$second":
+		return int32(date.Second())
+	default:
+		return int32(date.Weekday()) + 1
+	}
+}
+
+// typeRank returns the position of the value type on the mongo comparison order
+func typeRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 1
+	case int32, int64, float64, primitive.Decimal128:
+		return 2
+	case string:
+		return 3
+	case bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	default:
+		return 10
+	}
+}
+
+// compareValues returns -1, 0 or 1 when a is lower, equal or greater than b following the mongo comparison order
+func compareValues(a, b interface{}) int {
+	rankA, rankB := typeRank(a), typeRank(b)
+
+	if rankA != rankB {
+		return compareInts(int64(rankA), int64(rankB))
+	}
+
+	switch v := a.(type) {
+	case int32, int64, float64, primitive.Decimal128:
+		if hasDecimal(bson.A{a, b}) {
+			x, _ := toRat(a)
+			y, _ := toRat(b)
+
+			return x.Cmp(y)
+		}
+
+		x, _ := toFloat(v)
+		y, _ := toFloat(b)
+
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+
+		return 0
+	case string:
+		return strings.Compare(v, b.(string))
+	case primitive.ObjectID:
+		id := b.(primitive.ObjectID)
+		return bytes.Compare(v[:], id[:])
+	case bool:
+		if v == b.(bool) {
+			return 0
+		} else if v {
+			return 1
+		}
+
+		return -1
+	case primitive.DateTime:
+		return compareInts(int64(v), int64(b.(primitive.DateTime)))
+	case bson.A:
+		w := b.(bson.A)
+
+		for i := 0; i < len(v) && i < len(w); i++ {
+			if c := compareValues(v[i], w[i]); c != 0 {
+				return c
+			}
+		}
+
+		return compareInts(int64(len(v)), int64(len(w)))
+	default:
+		if equalValues(a, b) {
+			return 0
+		}
+
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+func compareInts(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
+
+// equalValues returns whether two values are equal, comparing numbers of different types by value
+func equalValues(a, b interface{}) bool {
+	if typeRank(a) == 2 && typeRank(b) == 2 {
+		return compareValues(a, b) == 0
+	}
+
+	return reflect.DeepEqual(normalizeNumber(a), normalizeNumber(b))
+}
+
+// normalizeNumber converts numbers, including the ones of embedded documents and arrays, to float64
+func normalizeNumber(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		result := bson.D{}
+		for _, e := range v {
+			result = append(result, bson.E{Key: e.Key, Value: normalizeNumber(e.Value)})
+		}
+		return result
+	case bson.A:
+		result := bson.A{}
+		for _, e := range v {
+			result = append(result, normalizeNumber(e))
+		}
+		return result
+	default:
+		if n, ok := toFloat(v); ok {
+			return n
+		}
+		return v
+	}
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case primitive.Decimal128:
+		n, err := strconv.ParseFloat(v.String(), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// hasDecimal returns whether any of the values is a decimal128
+func hasDecimal(values bson.A) bool {
+	for _, value := range values {
+		if _, ok := value.(primitive.Decimal128); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toRat returns the exact value of a number
+func toRat(value interface{}) (*big.Rat, bool) {
+	switch v := value.(type) {
+	case primitive.Decimal128:
+		return new(big.Rat).SetString(v.String())
+	case float32:
+		return new(big.Rat).SetString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	default:
+		n, ok := toFloat(v)
+
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, false
+		}
+
+		return new(big.Rat).SetFloat64(n), true
+	}
+}
+
+// decimalArithmetic applies an arithmetic operator without losing precision when a number is a decimal128, like mongo does
+func decimalArithmetic(operator string, arguments bson.A) (interface{}, error) {
+	numbers := []*big.Rat{}
+
+	for _, argument := range arguments {
+		n, ok := toRat(argument)
+
+		if !ok {
+			return nil, fmt.Errorf("%v only supports numeric types, not %T", operator, argument)
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	result := new(big.Rat).Set(numbers[0])
+
+	for _, n := range numbers[1:] {
+		switch operator {
+		case "$add":
+			result.Add(result, n)
+		case "$subtract":
+			result.Sub(result, n)
+		case "$multiply":
+			result.Mul(result, n)
+		case "$divide":
+			if n.Sign() == 0 {
+				return nil, fmt.Errorf("can't $divide by zero")
+			}
+
+			result.Quo(result, n)
+		}
+	}
+
+	s := result.FloatString(16)
+
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+
+	return primitive.ParseDecimal128(s)
+}
+
+func isNumberOrBool(value interface{}) bool {
+	if _, ok := value.(bool); ok {
+		return true
+	}
+
+	_, ok := toFloat(value)
+
+	return ok
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		if n, ok := toFloat(v); ok {
+			return n != 0
+		}
+
+		return true
+	}
+}
+
+func toStrings(value interface{}) []string {
+	if s, ok := value.(string); ok {
+		return []string{s}
+	}
+
+	values, _ := value.(bson.A)
+	result := []string{}
+
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// asDocument returns the value as a bson.D converting maps and structs
+func asDocument(value interface{}) (bson.D, error) {
+	if document, ok := value.(bson.D); ok {
+		return document, nil
+	}
+
+	return toDocument(value)
+}
@@ -0,0 +1,970 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// duplicateKeyErrorCode is the code of the mongo error returned when an unique index is violated
+const duplicateKeyErrorCode = 11000
+
+// memoryChange has the documents written and deleted on a collection
+type memoryChange struct {
+	collection string
+	put        []bson.D
+	delete     []bson.D
+}
+
+// memoryStore persists the changes of a memory database
+type memoryStore interface {
+	apply(changes []memoryChange) error
+	putIndex(collection string, keys []string) error
+}
+
+// memoryTransaction keeps the documents of the collections used by a unit of work before it started
+// and the changes to persist when it finishes
+type memoryTransaction struct {
+	snapshots map[string][]bson.D
+	changes   []memoryChange
+}
+
+// MemoryDatabase keeps in memory repositories by collection name
+type MemoryDatabase struct {
+	mu          sync.Mutex
+	collections map[string]*MemoryRepository
+	store       memoryStore
+	txMu        sync.Mutex
+	tx          *memoryTransaction
+}
+
+// NewMemoryDatabase returns an instance of MemoryDatabase
+func NewMemoryDatabase() *MemoryDatabase {
+	return &MemoryDatabase{collections: map[string]*MemoryRepository{}}
+}
+
+// Collection returns the repository of the collection passed by argument, creating it when it does not exist yet.
+// It can be used as a domain.RepositoryFactory.
+func (d *MemoryDatabase) Collection(name string) domain.Repository {
+	return d.collection(name)
+}
+
+// Do executes the function as a unit of work. Documents of the collections used by the function are restored when it returns error.
+// Units of work are executed one at a time and writes done outside of them wait until the running one finishes,
+// so only the writes of the function are restored.
+func (d *MemoryDatabase) Do(fn func(repositories domain.RepositoryFactory) error) error {
+	d.txMu.Lock()
+	defer d.txMu.Unlock()
+
+	tx := &memoryTransaction{snapshots: map[string][]bson.D{}}
+
+	d.mu.Lock()
+	d.tx = tx
+	d.mu.Unlock()
+
+	err := fn(func(name string) domain.Repository {
+		repo := &MemoryRepository{d.collection(name).memoryCollection, tx}
+
+		if _, ok := tx.snapshots[name]; !ok {
+			repo.mu.RLock()
+			tx.snapshots[name] = append([]bson.D{}, repo.documents...)
+			repo.mu.RUnlock()
+		}
+
+		return repo
+	})
+
+	d.mu.Lock()
+	d.tx = nil
+	d.mu.Unlock()
+
+	if err != nil {
+		for name, snapshot := range tx.snapshots {
+			tx.changes = append(tx.changes, d.collection(name).restore(snapshot))
+		}
+	}
+
+	if d.store != nil && len(tx.changes) > 0 {
+		if storeErr := d.store.apply(tx.changes); storeErr != nil && err == nil {
+			err = storeErr
+		}
+	}
+
+	return err
+}
+
+// persist writes a change into the store or keeps it until the running unit of work finishes
+func (d *MemoryDatabase) persist(change memoryChange) error {
+	d.mu.Lock()
+
+	if d.tx != nil {
+		d.tx.changes = append(d.tx.changes, change)
+		d.mu.Unlock()
+		return nil
+	}
+
+	d.mu.Unlock()
+
+	if d.store == nil {
+		return nil
+	}
+
+	return d.store.apply([]memoryChange{change})
+}
+
+func (d *MemoryDatabase) collection(name string) *MemoryRepository {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.collections[name]; !ok {
+		d.collections[name] = &MemoryRepository{memoryCollection: &memoryCollection{
+			name:         name,
+			database:     d,
+			uniqueKeys:   [][]string{{"_id"}},
+			uniqueValues: []map[string]bool{{}},
+		}}
+	}
+
+	return d.collections[name]
+}
+
+// MemoryRepository is a generic in memory repository that understands the subset of mongo queries,
+// updates and aggregation stages used by this project.
+type MemoryRepository struct {
+	*memoryCollection
+	// tx is the unit of work the repository writes for, writes of repositories without one wait for the running unit of work
+	tx *memoryTransaction
+}
+
+// memoryCollection has the documents of a collection shared by its repositories
+type memoryCollection struct {
+	mu           sync.RWMutex
+	name         string
+	database     *MemoryDatabase
+	documents    []bson.D
+	uniqueKeys   [][]string
+	uniqueValues []map[string]bool
+	// written and previous track the documents changed by a write to persist or restore them
+	written  []int
+	previous map[int]bson.D
+}
+
+// NewMemoryRepository returns an instance of MemoryRepository
+func NewMemoryRepository() *MemoryRepository {
+	return NewMemoryDatabase().collection("memory")
+}
+
+// FindAll returns rows that match criteria
+func (r *MemoryRepository) FindAll(documents interface{}, filter interface{}, opts *options.FindOptions) error {
+	results, err := r.find(filter, opts)
+
+	if err != nil {
+		return err
+	}
+
+	return decodeDocuments(results, documents)
+}
+
+// Aggregate returns rows aggregated
+func (r *MemoryRepository) Aggregate(documents interface{}, pipelineOptions mongo.Pipeline) error {
+	r.mu.RLock()
+	results := copyDocuments(r.documents)
+	r.mu.RUnlock()
+
+	for _, stage := range pipelineOptions {
+		stage, err := toDocument(stage)
+
+		if err != nil {
+			return err
+		}
+
+		if len(stage) != 1 {
+			return fmt.Errorf("pipeline stage must have exactly one field: %v", stage)
+		}
+
+		if stage[0].Key == "$merge" {
+			if err := r.merge(results, stage[0].Value); err != nil {
+				return err
+			}
+
+			results = []bson.D{}
+			continue
+		}
+
+		results, err = applyStage(results, stage[0].Key, stage[0].Value)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return decodeDocuments(results, documents)
+}
+
+// FindOne returns one row that match criteria
+func (r *MemoryRepository) FindOne(document interface{}, filter interface{}, opts *options.FindOneOptions) error {
+	findOptions := options.Find().SetLimit(1)
+
+	if opts != nil {
+		findOptions.Sort = opts.Sort
+		findOptions.Skip = opts.Skip
+	}
+
+	results, err := r.find(filter, findOptions)
+
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return decodeDocument(results[0], document)
+}
+
+// InsertOne creates one document
+func (r *MemoryRepository) InsertOne(document interface{}) error {
+	defer r.lockWrites()()
+
+	d, err := toDocument(document)
+
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.write(func() error {
+		return r.insert(d)
+	})
+}
+
+// UpdateOne updates one document found with match criteria
+func (r *MemoryRepository) UpdateOne(filter interface{}, update interface{}) error {
+	_, err := r.UpdateOneMatched(filter, update)
+
+	return err
+}
+
+// UpdateOneMatched updates one document found with match criteria and returns false when no document matches
+func (r *MemoryRepository) UpdateOneMatched(filter interface{}, update interface{}) (bool, error) {
+	defer r.lockWrites()()
+
+	f, u, err := toFilterAndUpdate(filter, update)
+
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, document := range r.documents {
+		ok, err := matchDocument(document, f)
+
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			return true, r.write(func() error {
+				return r.update(i, u)
+			})
+		}
+	}
+
+	return false, nil
+}
+
+// DeleteByID delete one document by ID
+func (r *MemoryRepository) DeleteByID(id string) error {
+	defer r.lockWrites()()
+
+	idPrimitive, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf(fmt.Sprint("primitive.ObjectIDFromHex ERROR:", err))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, document := range r.documents {
+		if value, _ := lookup(document, "_id"); value == idPrimitive {
+			if err := r.remove([]bson.D{document}); err != nil {
+				return err
+			}
+
+			r.removeUniqueValues(document)
+			r.documents = append(r.documents[:i], r.documents[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// BulkUpsert inserts the documents that do not match any stored document on the keys passed by argument.
+// Documents already stored are left untouched. It returns the number of documents inserted.
+func (r *MemoryRepository) BulkUpsert(documents []bson.M, keys []string) (int, error) {
+	defer r.lockWrites()()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inserted := 0
+	stored := map[string]bool{}
+
+	for _, document := range r.documents {
+		stored[uniqueKey(document, keys)] = true
+	}
+
+	err := r.write(func() error {
+		for _, document := range documents {
+			d, err := toDocument(document)
+
+			if err != nil {
+				return err
+			}
+
+			key := uniqueKey(d, keys)
+
+			if stored[key] {
+				continue
+			}
+
+			if err := r.insert(d); err != nil {
+				return err
+			}
+
+			stored[key] = true
+			inserted++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+// BulkCreate creates multiple documents
+func (r *MemoryRepository) BulkCreate(documents *[]bson.M) error {
+	defer r.lockWrites()()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.write(func() error {
+		for _, document := range *documents {
+			d, err := toDocument(document)
+
+			if err != nil {
+				return err
+			}
+
+			if err := r.insert(d); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// BulkDelete deletes multiple documents
+func (r *MemoryRepository) BulkDelete(filter bson.M) error {
+	defer r.lockWrites()()
+
+	f, err := toDocument(filter)
+
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := []bson.D{}
+	deleted := []bson.D{}
+
+	for _, document := range r.documents {
+		ok, err := matchDocument(document, f)
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			deleted = append(deleted, document)
+		} else {
+			kept = append(kept, document)
+		}
+	}
+
+	if err := r.remove(deleted); err != nil {
+		return err
+	}
+
+	for _, document := range deleted {
+		r.removeUniqueValues(document)
+	}
+
+	r.documents = kept
+
+	return nil
+}
+
+// BulkUpdate updates multiple documents
+func (r *MemoryRepository) BulkUpdate(filter bson.M, update bson.M) error {
+	defer r.lockWrites()()
+
+	f, u, err := toFilterAndUpdate(filter, update)
+
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.write(func() error {
+		for i, document := range r.documents {
+			ok, err := matchDocument(document, f)
+
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+
+			if err := r.update(i, u); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CreateIndex enforces unique indexes. Other indexes, including TTL ones, are ignored,
+// so the documents that expire must be deleted by the services that write them.
+func (r *MemoryRepository) CreateIndex(index mongo.IndexModel) error {
+	if index.Options == nil || index.Options.Unique == nil || !*index.Options.Unique {
+		return nil
+	}
+
+	keys, err := toDocument(index.Keys)
+
+	if err != nil {
+		return err
+	}
+
+	fields := []string{}
+
+	for _, key := range keys {
+		fields = append(fields, key.Key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, keys := range r.uniqueKeys {
+		if reflect.DeepEqual(keys, fields) {
+			return nil
+		}
+	}
+
+	seen := map[string]bool{}
+
+	for _, document := range r.documents {
+		key := uniqueKey(document, fields)
+
+		if seen[key] {
+			return r.duplicateKeyError(fields)
+		}
+
+		seen[key] = true
+	}
+
+	if r.database != nil && r.database.store != nil {
+		if err := r.database.store.putIndex(r.name, fields); err != nil {
+			return err
+		}
+	}
+
+	r.uniqueKeys = append(r.uniqueKeys, fields)
+	r.uniqueValues = append(r.uniqueValues, seen)
+
+	return nil
+}
+
+// find returns the documents that match the filter sorted, skipped and limited by the options passed by argument
+func (r *MemoryRepository) find(filter interface{}, opts *options.FindOptions) ([]bson.D, error) {
+	f, err := toDocument(filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	results := []bson.D{}
+
+	for _, document := range r.documents {
+		ok, err := matchDocument(document, f)
+
+		if err != nil {
+			r.mu.RUnlock()
+			return nil, err
+		}
+
+		if ok {
+			results = append(results, document)
+		}
+	}
+
+	results = copyDocuments(results)
+	r.mu.RUnlock()
+
+	if opts == nil {
+		return results, nil
+	}
+
+	if opts.Sort != nil {
+		results, err = applyStage(results, "$sort", opts.Sort)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Skip != nil {
+		results = skipDocuments(results, int(*opts.Skip))
+	}
+
+	if opts.Limit != nil && *opts.Limit > 0 {
+		results = limitDocuments(results, int(*opts.Limit))
+	}
+
+	return results, nil
+}
+
+// insert stores a document generating its id when it does not have one. It must be called with the lock held.
+func (r *MemoryRepository) insert(document bson.D) error {
+	if _, ok := lookup(document, "_id"); !ok {
+		document = append(bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, document...)
+	}
+
+	if err := r.checkUniqueValues(document); err != nil {
+		return err
+	}
+
+	r.addUniqueValues(document)
+	r.documents = append(r.documents, document)
+	r.written = append(r.written, len(r.documents)-1)
+
+	return nil
+}
+
+// update applies an update to the document on the index passed by argument. It must be called with the lock held.
+func (r *MemoryRepository) update(i int, update bson.D) error {
+	document, err := applyUpdate(r.documents[i], update)
+
+	if err != nil {
+		return err
+	}
+
+	r.removeUniqueValues(r.documents[i])
+
+	if err := r.checkUniqueValues(document); err != nil {
+		r.addUniqueValues(r.documents[i])
+		return err
+	}
+
+	if _, ok := r.previous[i]; !ok && r.previous != nil {
+		r.previous[i] = r.documents[i]
+	}
+
+	r.addUniqueValues(document)
+	r.documents[i] = document
+	r.written = append(r.written, i)
+
+	return nil
+}
+
+// write runs a function that inserts or updates documents and persists the documents written.
+// Documents are restored when the function or the persistence fails. It must be called with the lock held.
+func (r *MemoryRepository) write(fn func() error) error {
+	length := len(r.documents)
+	r.written, r.previous = []int{}, map[int]bson.D{}
+
+	defer func() {
+		r.written, r.previous = nil, nil
+	}()
+
+	err := fn()
+
+	if err == nil && r.database != nil && len(r.written) > 0 {
+		written := []bson.D{}
+		seen := map[int]bool{}
+
+		for _, i := range r.written {
+			if !seen[i] {
+				written = append(written, r.documents[i])
+				seen[i] = true
+			}
+		}
+
+		err = r.database.persist(memoryChange{collection: r.name, put: written})
+	}
+
+	if err != nil {
+		for i, document := range r.previous {
+			r.documents[i] = document
+		}
+
+		r.documents = r.documents[:length]
+		r.resetUniqueValues()
+	}
+
+	return err
+}
+
+// remove deletes documents from the store. It must be called with the lock held.
+func (r *MemoryRepository) remove(documents []bson.D) error {
+	if r.database == nil || len(documents) == 0 {
+		return nil
+	}
+
+	return r.database.persist(memoryChange{collection: r.name, delete: documents})
+}
+
+// restore replaces the documents by the ones passed by argument and returns the change to persist it
+func (r *MemoryRepository) restore(documents []bson.D) memoryChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := map[string]bool{}
+
+	for _, document := range documents {
+		kept[uniqueKey(document, []string{"_id"})] = true
+	}
+
+	change := memoryChange{collection: r.name, put: documents}
+
+	for _, document := range r.documents {
+		if !kept[uniqueKey(document, []string{"_id"})] {
+			change.delete = append(change.delete, document)
+		}
+	}
+
+	r.documents = append([]bson.D{}, documents...)
+	r.resetUniqueValues()
+
+	return change
+}
+
+// indexOf returns the index of the first document matching the filter or -1. It must be called with the lock held.
+func (r *MemoryRepository) indexOf(filter bson.D) int {
+	for i, document := range r.documents {
+		if ok, _ := matchDocument(document, filter); ok {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// checkUniqueValues returns a duplicate key error when a stored document has the same values on an unique index
+func (r *MemoryRepository) checkUniqueValues(document bson.D) error {
+	for i, keys := range r.uniqueKeys {
+		if r.uniqueValues[i][uniqueKey(document, keys)] {
+			return r.duplicateKeyError(keys)
+		}
+	}
+
+	return nil
+}
+
+func (r *MemoryRepository) addUniqueValues(document bson.D) {
+	for i, keys := range r.uniqueKeys {
+		r.uniqueValues[i][uniqueKey(document, keys)] = true
+	}
+}
+
+func (r *MemoryRepository) removeUniqueValues(document bson.D) {
+	for i, keys := range r.uniqueKeys {
+		delete(r.uniqueValues[i], uniqueKey(document, keys))
+	}
+}
+
+func (r *MemoryRepository) resetUniqueValues() {
+	for i := range r.uniqueValues {
+		r.uniqueValues[i] = map[string]bool{}
+	}
+
+	for _, document := range r.documents {
+		r.addUniqueValues(document)
+	}
+}
+
+func (r *MemoryRepository) duplicateKeyError(keys []string) error {
+	return mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{
+			{Code: duplicateKeyErrorCode, Message: fmt.Sprintf("E11000 duplicate key error collection: %v index: %v", r.name, keys)},
+		},
+	}
+}
+
+// merge writes aggregation results into the collection set by a $merge stage
+func (r *MemoryRepository) merge(results []bson.D, value interface{}) error {
+	spec, ok := value.(bson.D)
+
+	if !ok {
+		return fmt.Errorf("$merge must be a document")
+	}
+
+	into, _ := lookup(spec, "into")
+	name, ok := into.(string)
+
+	if !ok || r.database == nil {
+		return fmt.Errorf("$merge is only supported into a collection of the same memory database")
+	}
+
+	on := []string{"_id"}
+
+	if value, ok := lookup(spec, "on"); ok {
+		on = toStrings(value)
+	}
+
+	whenMatched, ok := lookup(spec, "whenMatched")
+	if !ok {
+		whenMatched = "merge"
+	}
+
+	whenNotMatched, ok := lookup(spec, "whenNotMatched")
+	if !ok {
+		whenNotMatched = "insert"
+	}
+
+	target := &MemoryRepository{r.database.collection(name).memoryCollection, r.tx}
+	defer target.lockWrites()()
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+
+	return target.write(func() error {
+		return target.mergeResults(results, on, whenMatched, whenNotMatched)
+	})
+}
+
+// mergeResults inserts or updates the documents matching each result on the fields passed by argument. It must be called with the lock held.
+func (r *MemoryRepository) mergeResults(results []bson.D, on []string, whenMatched, whenNotMatched interface{}) error {
+	for _, result := range results {
+		filter := bson.D{}
+
+		for _, key := range on {
+			value, _ := lookup(result, key)
+			filter = append(filter, bson.E{Key: key, Value: value})
+		}
+
+		i := r.indexOf(filter)
+
+		if i < 0 {
+			if whenNotMatched == "insert" {
+				if err := r.insert(result); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		var err error
+
+		switch whenMatched {
+		case "replace":
+			err = r.update(i, result)
+		case "merge":
+			err = r.update(i, bson.D{{Key: "$set", Value: unsetValue(copyDocument(result), "_id")}})
+		case "keepExisting":
+		default:
+			err = fmt.Errorf("$merge whenMatched %v is not supported", whenMatched)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toDocument converts structs, maps and documents to a bson.D with the same fields and types stored by mongo
+func toDocument(value interface{}) (bson.D, error) {
+	if value == nil {
+		return bson.D{}, nil
+	}
+
+	data, err := bson.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var document bson.D
+	err = bson.Unmarshal(data, &document)
+
+	return document, err
+}
+
+func toFilterAndUpdate(filter interface{}, update interface{}) (bson.D, bson.D, error) {
+	f, err := toDocument(filter)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u, err := toDocument(update)
+
+	return f, u, err
+}
+
+// decodeDocuments decodes documents into the slice pointer passed by argument
+func decodeDocuments(results []bson.D, documents interface{}) error {
+	values := bson.A{}
+
+	for _, result := range results {
+		values = append(values, result)
+	}
+
+	data, err := bson.Marshal(bson.D{{Key: "results", Value: values}})
+
+	if err != nil {
+		return err
+	}
+
+	return bson.Raw(data).Lookup("results").Unmarshal(documents)
+}
+
+// decodeDocument decodes a document into the pointer passed by argument
+func decodeDocument(result bson.D, document interface{}) error {
+	data, err := bson.Marshal(result)
+
+	if err != nil {
+		return err
+	}
+
+	return bson.Unmarshal(data, document)
+}
+
+func copyDocuments(documents []bson.D) []bson.D {
+	copies := make([]bson.D, len(documents))
+
+	for i, document := range documents {
+		copies[i] = copyDocument(document)
+	}
+
+	return copies
+}
+
+func copyDocument(document bson.D) bson.D {
+	return copyValue(document).(bson.D)
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bson.D:
+		c := make(bson.D, len(v))
+		for i, e := range v {
+			c[i] = bson.E{Key: e.Key, Value: copyValue(e.Value)}
+		}
+		return c
+	case bson.A:
+		c := make(bson.A, len(v))
+		for i, e := range v {
+			c[i] = copyValue(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// uniqueKey returns a comparable representation of the document fields passed by argument
+func uniqueKey(document bson.D, keys []string) string {
+	var buffer bytes.Buffer
+
+	for _, key := range keys {
+		value, _ := lookup(document, key)
+		data, _ := bson.Marshal(bson.D{{Key: "v", Value: normalizeNumber(value)}})
+		buffer.Write(data)
+	}
+
+	return buffer.String()
+}
+
+func skipDocuments(documents []bson.D, n int) []bson.D {
+	if n >= len(documents) {
+		return []bson.D{}
+	}
+
+	return documents[n:]
+}
+
+func limitDocuments(documents []bson.D, n int) []bson.D {
+	if n < len(documents) {
+		return documents[:n]
+	}
+
+	return documents
+}
+
+func sortDocuments(documents []bson.D, keys bson.D) {
+	sort.SliceStable(documents, func(i, j int) bool {
+		for _, key := range keys {
+			a, _ := lookup(documents[i], key.Key)
+			b, _ := lookup(documents[j], key.Key)
+
+			c := compareValues(a, b)
+
+			if c == 0 {
+				continue
+			}
+
+			if direction, _ := toFloat(key.Value); direction < 0 {
+				return c > 0
+			}
+
+			return c < 0
+		}
+
+		return false
+	})
+}
+
+// lockWrites waits until the running unit of work finishes when the repository does not take part of it.
+// It returns the function that lets other units of work run.
+func (r *MemoryRepository) lockWrites() func() {
+	if r.database == nil || r.tx != nil {
+		return func() {}
+	}
+
+	r.database.txMu.Lock()
+
+	return r.database.txMu.Unlock
+}
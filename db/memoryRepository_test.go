@@ -0,0 +1,260 @@
+package db_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type price struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Asset string             `bson:"asset"`
+	Date  time.Time          `bson:"date"`
+	Value float32            `bson:"value"`
+}
+
+// racingRepository runs a write of another client after the first document is read
+type racingRepository struct {
+	domain.Repository
+	race func()
+}
+
+func (r *racingRepository) FindOne(document interface{}, query interface{}, opts *options.FindOneOptions) error {
+	err := r.Repository.FindOne(document, query, opts)
+
+	if r.race != nil {
+		r.race()
+		r.race = nil
+	}
+
+	return err
+}
+
+var startDate = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newPricesRepository(t *testing.T) *db.MemoryRepository {
+	repo := db.NewMemoryRepository()
+
+	for i, asset := range []string{"BTC", "ETH", "BTC", "BTC"} {
+		err := repo.InsertOne(price{primitive.NewObjectID(), asset, startDate.Add(time.Duration(i) * time.Hour), float32(i + 1)})
+
+		if err != nil {
+			t.Fatalf("Not expected InsertOne to return error: %v", err)
+		}
+	}
+
+	return repo
+}
+
+func TestMemoryRepositoryFind(t *testing.T) {
+	repo := newPricesRepository(t)
+
+	t.Run("should filter with comparison and $in operators", func(t *testing.T) {
+		var got []price
+		err := repo.FindAll(&got, bson.M{"asset": bson.M{"$in": bson.A{"BTC"}}, "date": bson.M{"$gte": startDate.Add(time.Hour), "$lte": startDate.Add(3 * time.Hour)}}, nil)
+
+		if err != nil {
+			t.Fatalf("Not expected FindAll to return error: %v", err)
+		}
+
+		if len(got) != 2 || got[0].Value != 3 || got[1].Value != 4 {
+			t.Errorf("got %+v want prices 3 and 4", got)
+		}
+	})
+
+	t.Run("should sort and limit", func(t *testing.T) {
+		var got []price
+		err := repo.FindAll(&got, bson.M{}, options.Find().SetSort(bson.D{{Key: "date", Value: -1}}).SetLimit(2))
+
+		if err != nil {
+			t.Fatalf("Not expected FindAll to return error: %v", err)
+		}
+
+		if len(got) != 2 || got[0].Value != 4 || got[1].Value != 3 {
+			t.Errorf("got %+v want prices 4 and 3", got)
+		}
+	})
+
+	t.Run("should return no documents error when nothing matches", func(t *testing.T) {
+		var got price
+		err := repo.FindOne(&got, bson.M{"asset": "ADA"}, nil)
+
+		if err != mongo.ErrNoDocuments {
+			t.Errorf("got %v want %v", err, mongo.ErrNoDocuments)
+		}
+	})
+}
+
+func TestMemoryRepositoryUpdate(t *testing.T) {
+	t.Run("should increment and set fields of documents matched", func(t *testing.T) {
+		repository := accounts.NewRepository(db.NewMemoryRepository())
+
+		account, err := repository.Create("kraken", domain.NewDecimalFromInt(100))
+
+		if err != nil {
+			t.Fatalf("Not expected Create to return error: %v", err)
+		}
+
+		repository.Withdraw(account.ID.Hex(), domain.NewDecimal(30.5))
+		repository.Withdraw(account.ID.Hex(), domain.NewDecimalFromInt(100))
+		repository.Deposit(account.ID.Hex(), domain.NewDecimal(5.25))
+
+		got, err := repository.FindById(account.ID.Hex())
+
+		if err != nil {
+			t.Fatalf("Not expected FindById to return error: %v", err)
+		}
+
+		if want := domain.NewDecimal(74.75); got.Amount != want {
+			t.Errorf("got %v want %v", got.Amount, want)
+		}
+	})
+
+	t.Run("should return insufficient funds when the amount is withdrawn after the account is read", func(t *testing.T) {
+		collection := db.NewMemoryRepository()
+		account, _ := accounts.NewRepository(collection).Create("kraken", domain.NewDecimalFromInt(100))
+
+		repository := accounts.NewRepository(&racingRepository{collection, func() {
+			accounts.NewRepository(collection).Withdraw(account.ID.Hex(), domain.NewDecimalFromInt(80))
+		}})
+
+		if err := repository.Withdraw(account.ID.Hex(), domain.NewDecimalFromInt(50)); err != domain.ErrInsufficientFunds {
+			t.Errorf("got %v want %v", err, domain.ErrInsufficientFunds)
+		}
+
+		got, _ := repository.FindById(account.ID.Hex())
+
+		if want := domain.NewDecimalFromInt(20); got.Amount != want {
+			t.Errorf("got %v want %v", got.Amount, want)
+		}
+	})
+
+	t.Run("should set fields on every document matched", func(t *testing.T) {
+		repo := newPricesRepository(t)
+
+		err := repo.BulkUpdate(bson.M{"asset": "BTC"}, bson.M{"$set": bson.M{"value": 10}})
+
+		if err != nil {
+			t.Fatalf("Not expected BulkUpdate to return error: %v", err)
+		}
+
+		var got []price
+		repo.FindAll(&got, bson.M{"value": 10}, nil)
+
+		if len(got) != 3 {
+			t.Errorf("got %d documents updated want 3", len(got))
+		}
+	})
+}
+
+func TestMemoryRepositoryUniqueIndex(t *testing.T) {
+	repo := newPricesRepository(t)
+
+	err := repo.CreateIndex(mongo.IndexModel{Keys: bson.D{{Key: "asset", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetUnique(true)})
+
+	if err != nil {
+		t.Fatalf("Not expected CreateIndex to return error: %v", err)
+	}
+
+	t.Run("should return duplicate key error", func(t *testing.T) {
+		err := repo.InsertOne(price{primitive.NewObjectID(), "BTC", startDate, 10})
+
+		if !mongo.IsDuplicateKeyError(err) {
+			t.Errorf("Expected duplicate key error, got %v", err)
+		}
+	})
+
+	t.Run("should upsert only documents not stored", func(t *testing.T) {
+		inserted, err := repo.BulkUpsert([]bson.M{
+			{"asset": "BTC", "date": startDate, "value": 10},
+			{"asset": "BTC", "date": startDate.Add(10 * time.Hour), "value": 10},
+		}, []string{"asset", "date"})
+
+		if err != nil {
+			t.Fatalf("Not expected BulkUpsert to return error: %v", err)
+		}
+
+		if inserted != 1 {
+			t.Errorf("got %d want 1", inserted)
+		}
+	})
+}
+
+func TestMemoryRepositoryAggregate(t *testing.T) {
+	t.Run("should match, group and sort documents", func(t *testing.T) {
+		repo := newPricesRepository(t)
+
+		var got []bson.M
+		err := repo.Aggregate(&got, mongo.Pipeline{
+			{{Key: "$match", Value: bson.M{"date": bson.M{"$gte": startDate}}}},
+			{{Key: "$group", Value: bson.M{
+				"_id":   bson.M{"asset": "$asset", "day": bson.M{"$dayOfMonth": "$date"}},
+				"avg":   bson.M{"$avg": "$value"},
+				"count": bson.M{"$sum": 1},
+				"last":  bson.M{"$last": "$value"},
+			}}},
+			{{Key: "$sort", Value: bson.M{"_id.asset": 1}}},
+			{{Key: "$project", Value: bson.M{"_id": 0, "asset": "$_id.asset", "avg": 1, "count": 1, "double": bson.M{"$multiply": bson.A{"$last", 2}}}}},
+		})
+
+		if err != nil {
+			t.Fatalf("Not expected Aggregate to return error: %v", err)
+		}
+
+		want := []bson.M{
+			{"asset": "BTC", "avg": float64(8) / 3, "count": int32(3), "double": float64(8)},
+			{"asset": "ETH", "avg": float64(2), "count": int32(1), "double": float64(4)},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("should merge results into other collection of the same database", func(t *testing.T) {
+		database := db.NewMemoryDatabase()
+		source := database.Collection("source")
+		source.InsertOne(bson.M{"asset": "BTC", "value": 1})
+		source.InsertOne(bson.M{"asset": "BTC", "value": 3})
+
+		pipeline := mongo.Pipeline{
+			{{Key: "$group", Value: bson.M{"_id": "$asset", "total": bson.M{"$sum": "$value"}}}},
+			{{Key: "$merge", Value: bson.M{"into": "totals", "on": "_id", "whenMatched": "replace", "whenNotMatched": "insert"}}},
+		}
+
+		var results []bson.M
+		source.Aggregate(&results, pipeline)
+		source.InsertOne(bson.M{"asset": "BTC", "value": 5})
+		err := source.Aggregate(&results, pipeline)
+
+		if err != nil {
+			t.Fatalf("Not expected Aggregate to return error: %v", err)
+		}
+
+		var got []bson.M
+		database.Collection("totals").FindAll(&got, bson.M{}, nil)
+
+		want := []bson.M{{"_id": "BTC", "total": int32(9)}}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("should return error on unsupported stages", func(t *testing.T) {
+		var got []bson.M
+		err := db.NewMemoryRepository().Aggregate(&got, mongo.Pipeline{{{Key: "$lookup", Value: bson.M{}}}})
+
+		if err == nil {
+			t.Errorf("Expected Aggregate to return error")
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package db
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -12,16 +14,27 @@ import (
 // Repository is a generic mongo service to used by other repositories
 type Repository struct {
 	collection *mongo.Collection
+	ctx        context.Context
 }
 
 // NewRepository returns an instance of Repository
 func NewRepository(c *mongo.Collection) *Repository {
-	return &Repository{c}
+	return &Repository{c, context.Background()}
+}
+
+// NewSessionRepository returns an instance of Repository that executes every query on the session passed by argument
+func NewSessionRepository(sc mongo.SessionContext, c *mongo.Collection) *Repository {
+	return &Repository{c, sc}
+}
+
+// newQueryContext returns the context of a query with a timeout
+func (r *Repository) newQueryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.ctx, 20*time.Second)
 }
 
 // FindAll returns rows that match criteria
 func (r *Repository) FindAll(documents interface{}, filter interface{}, opts *options.FindOptions) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 	cur, err := r.collection.Find(ctx, filter, opts)
 
 	if err != nil {
@@ -41,7 +54,7 @@ func (r *Repository) FindAll(documents interface{}, filter interface{}, opts *op
 
 // Aggregate returns rows aggregated
 func (r *Repository) Aggregate(documents interface{}, pipelineOptions mongo.Pipeline) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 	cur, err := r.collection.Aggregate(ctx, pipelineOptions)
 
 	if err != nil {
@@ -61,7 +74,7 @@ func (r *Repository) Aggregate(documents interface{}, pipelineOptions mongo.Pipe
 
 // FindOne returns one row that match criteria
 func (r *Repository) FindOne(document interface{}, filter interface{}, opts *options.FindOneOptions) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 
 	err := r.collection.FindOne(ctx, filter, opts).Decode(document)
 
@@ -74,7 +87,7 @@ func (r *Repository) FindOne(document interface{}, filter interface{}, opts *opt
 
 // InsertOne creates one document
 func (r *Repository) InsertOne(document interface{}) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 
 	_, err := r.collection.InsertOne(ctx, document)
 
@@ -87,7 +100,7 @@ func (r *Repository) InsertOne(document interface{}) error {
 
 // UpdateOne updates one document found with match criteria
 func (r *Repository) UpdateOne(filter interface{}, update interface{}) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 
 	_, err := r.collection.UpdateOne(ctx, filter, update)
 
@@ -98,9 +111,23 @@ func (r *Repository) UpdateOne(filter interface{}, update interface{}) error {
 	return err
 }
 
+// UpdateOneMatched updates one document found with match criteria and returns false when no document matches
+func (r *Repository) UpdateOneMatched(filter interface{}, update interface{}) (bool, error) {
+	ctx, cancel := r.newQueryContext()
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+
+	if err != nil {
+		cancel()
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
 // DeleteByID delete one document by ID
 func (r *Repository) DeleteByID(id string) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 
 	idPrimitive, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -116,36 +143,46 @@ func (r *Repository) DeleteByID(id string) error {
 	return err
 }
 
-// BulkUpsert creates multiple documents if there are no documents with the same data
-func (r *Repository) BulkUpsert(documents []bson.M) error {
-	ctx, cancel := NewMongoQueryContext()
+// BulkUpsert inserts the documents that do not match any stored document on the keys passed by argument.
+// Documents already stored are left untouched. It returns the number of documents inserted.
+func (r *Repository) BulkUpsert(documents []bson.M, keys []string) (int, error) {
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := r.newQueryContext()
+	defer cancel()
 
 	var operations []mongo.WriteModel
 
 	for _, document := range documents {
+		filter := bson.M{}
+
+		for _, key := range keys {
+			filter[key] = document[key]
+		}
+
 		operation := mongo.NewUpdateOneModel()
 
-		operation.SetFilter(document)
-		operation.SetUpdate(document)
+		operation.SetFilter(filter)
+		operation.SetUpdate(bson.M{"$setOnInsert": document})
 		operation.SetUpsert(true)
 
 		operations = append(operations, operation)
 	}
 
-	bulkOptions := options.BulkWriteOptions{}
-
-	_, err := r.collection.BulkWrite(ctx, operations, &bulkOptions)
+	result, err := r.collection.BulkWrite(ctx, operations, options.BulkWrite().SetOrdered(false))
 
 	if err != nil {
-		cancel()
+		return 0, err
 	}
 
-	return err
+	return int(result.UpsertedCount), nil
 }
 
 // BulkCreate creates multiple documents
 func (r *Repository) BulkCreate(documents *[]bson.M) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 
 	bulkOptions := options.InsertManyOptions{}
 
@@ -166,7 +203,7 @@ func (r *Repository) BulkCreate(documents *[]bson.M) error {
 
 // BulkDelete deletes multiple documents
 func (r *Repository) BulkDelete(filter bson.M) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 	defer cancel()
 
 	_, err := r.collection.DeleteMany(ctx, filter)
@@ -176,10 +213,20 @@ func (r *Repository) BulkDelete(filter bson.M) error {
 
 // BulkUpdate updates multiple documents
 func (r *Repository) BulkUpdate(filter bson.M, update bson.M) error {
-	ctx, cancel := NewMongoQueryContext()
+	ctx, cancel := r.newQueryContext()
 	defer cancel()
 
 	_, err := r.collection.UpdateMany(ctx, filter, update)
 
 	return err
 }
+
+// CreateIndex creates an index if it does not exist yet
+func (r *Repository) CreateIndex(index mongo.IndexModel) error {
+	ctx, cancel := r.newQueryContext()
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, index)
+
+	return err
+}
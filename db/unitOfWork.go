@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoUnitOfWork executes the writes of multiple repositories in a mongo transaction.
+// Transactions need mongo to run as a replica set.
+type MongoUnitOfWork struct {
+	client   *mongo.Client
+	database *mongo.Database
+}
+
+// NewMongoUnitOfWork returns an instance of MongoUnitOfWork
+func NewMongoUnitOfWork(client *mongo.Client, database *mongo.Database) *MongoUnitOfWork {
+	return &MongoUnitOfWork{client, database}
+}
+
+// Do executes the function in a transaction that is committed when the function does not return error.
+// The function may be retried on transient transaction errors.
+func (u *MongoUnitOfWork) Do(fn func(repositories domain.RepositoryFactory) error) error {
+	session, err := u.client.StartSession()
+
+	if err != nil {
+		return err
+	}
+
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(context.Background(), func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(func(collection string) domain.Repository {
+			return NewSessionRepository(sc, u.database.Collection(collection))
+		})
+	})
+
+	return err
+}
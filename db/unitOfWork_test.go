@@ -0,0 +1,362 @@
+package db_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/trades"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newAccountService(t *testing.T, storage *db.Storage, amount int64) *accounts.AccountService {
+	account, err := accounts.OpenAccount(storage.UnitOfWork, "kraken", domain.NewDecimalFromInt(amount), startDate)
+
+	if err != nil {
+		t.Fatalf("Not expected OpenAccount to return error: %v", err)
+	}
+
+	service, err := accounts.NewAccountService(
+		account.ID.Hex(),
+		accounts.NewRepository(storage.Repositories(db.ACCOUNTS_COLLECTION)),
+		assets.NewRepository(storage.Repositories(db.ASSETS_COLLECTION)),
+		accounts.NewLedgerRepository(storage.Repositories(db.LEDGER_COLLECTION)),
+		storage.UnitOfWork,
+	)
+
+	if err != nil {
+		t.Fatalf("Not expected NewAccountService to return error: %v", err)
+	}
+
+	return service
+}
+
+func tradesRepository(storage *db.Storage) *trades.Repository {
+	return trades.NewRepository(storage.Repositories(db.TRADES_COLLECTION))
+}
+
+func TestMemoryDatabaseUnitOfWork(t *testing.T) {
+	t.Run("should buy and sell updating account and assets", func(t *testing.T) {
+		service := newAccountService(t, db.NewMemoryStorage(), 100)
+
+		asset, err := service.Buy(domain.NewDecimal(0.5), domain.NewDecimal(30.1), startDate, "")
+
+		if err != nil {
+			t.Fatalf("Not expected Buy to return error: %v", err)
+		}
+
+		err = service.Sell(asset, domain.NewDecimal(40.3), startDate.Add(time.Hour))
+
+		if err != nil {
+			t.Fatalf("Not expected Sell to return error: %v", err)
+		}
+
+		got, _ := service.GetAmount()
+
+		if want := domain.NewDecimal(105.1); got != want {
+			t.Errorf("got %v want %v", got, want)
+		}
+
+		entries, _ := service.FindLedgerEntries(time.Time{}, time.Time{})
+		types := []domain.LedgerEntryType{}
+
+		for _, entry := range *entries {
+			types = append(types, entry.Type)
+		}
+
+		want := []domain.LedgerEntryType{domain.LedgerDeposit, domain.LedgerBuy, domain.LedgerSell}
+
+		if !reflect.DeepEqual(types, want) {
+			t.Errorf("got ledger entries %v want %v", types, want)
+		}
+	})
+
+	t.Run("should sell part of the lots splitting them and record the trade", func(t *testing.T) {
+		storage := db.NewMemoryStorage()
+		service := newAccountService(t, storage, 1000)
+
+		first, _ := service.Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(100), startDate, "")
+		service.Buy(domain.NewDecimalFromInt(3), domain.NewDecimalFromInt(200), startDate.Add(time.Hour), "")
+
+		selection := domain.LotSelection{Method: domain.LotLIFO}
+		trade, err := service.SellAmount(domain.NewDecimalFromInt(4), domain.NewDecimalFromInt(150), selection, startDate.Add(2*time.Hour), "")
+
+		if err != nil {
+			t.Fatalf("Not expected SellAmount to return error: %v", err)
+		}
+
+		if trade.CostBasis.String() != "700" || trade.RealizedPnL.String() != "-100" {
+			t.Errorf("got cost basis %v and pnl %v want 700 and -100", trade.CostBasis, trade.RealizedPnL)
+		}
+
+		pending, _ := service.FindPendingAssets()
+
+		if len(*pending) != 1 || (*pending)[0].ID != first.ID || (*pending)[0].Amount.String() != "1" {
+			t.Errorf("got pending assets %+v want 1 of the first lot", pending)
+		}
+
+		if got, _ := service.GetAmount(); got.String() != "800" {
+			t.Errorf("got amount %v want 800", got)
+		}
+
+		trades, _ := tradesRepository(storage).FindAll(service.ID, time.Time{}, time.Time{})
+
+		if len(*trades) != 1 || len((*trades)[0].Lots) != 2 || (*trades)[0].Lots[1].AssetID == first.ID {
+			t.Errorf("got trades %+v want one trade with the split lot", trades)
+		}
+	})
+
+	t.Run("should buy and sell the lots of each asset sharing the account amount", func(t *testing.T) {
+		storage := db.NewMemoryStorage()
+		service := newAccountService(t, storage, 1000)
+		btc, eth := service.ForAsset("BTC"), service.ForAsset("ETH")
+
+		btc.Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(100), startDate, "")
+		eth.Buy(domain.NewDecimalFromInt(10), domain.NewDecimalFromInt(20), startDate, "")
+
+		if _, err := btc.SellAmount(domain.NewDecimalFromInt(3), domain.NewDecimalFromInt(150), domain.LotSelection{}, startDate.Add(time.Hour), ""); err != domain.ErrInsufficientAssets {
+			t.Errorf("got error %v want %v", err, domain.ErrInsufficientAssets)
+		}
+
+		trade, err := btc.SellAmount(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(150), domain.LotSelection{}, startDate.Add(time.Hour), "")
+
+		if err != nil {
+			t.Fatalf("Not expected SellAmount to return error: %v", err)
+		}
+
+		if trade.Asset != "BTC" {
+			t.Errorf("got trade asset %v want BTC", trade.Asset)
+		}
+
+		pending, _ := eth.FindPendingAssets()
+
+		if len(*pending) != 1 || (*pending)[0].Symbol != "ETH" || (*pending)[0].Amount.String() != "10" {
+			t.Errorf("got ETH pending assets %+v want the ETH lot", pending)
+		}
+
+		all, _ := service.FindPendingAssets()
+
+		if len(*all) != 2 {
+			t.Errorf("got %d account pending assets want 2", len(*all))
+		}
+
+		if got, _ := eth.GetAmount(); got.String() != "750" {
+			t.Errorf("got amount %v want 750", got)
+		}
+
+		if ok, _ := eth.CheckAssetWithCloserPriceExists(100, 0.01); ok {
+			t.Errorf("Expected ETH lots not to have a price close to the BTC lot")
+		}
+	})
+
+	t.Run("should tag the lots with the options version", func(t *testing.T) {
+		service := newAccountService(t, db.NewMemoryStorage(), 100).WithOptionsVersion(3)
+
+		if _, err := service.Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(60), startDate, ""); err != nil {
+			t.Fatalf("Not expected Buy to return error: %v", err)
+		}
+
+		pending, _ := service.FindPendingAssets()
+
+		if len(*pending) != 1 || (*pending)[0].OptionsVersion != 3 {
+			t.Errorf("got pending assets %+v want one lot of options version 3", pending)
+		}
+	})
+
+	t.Run("should return insufficient funds without creating the asset", func(t *testing.T) {
+		service := newAccountService(t, db.NewMemoryStorage(), 100)
+
+		_, err := service.Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(60), startDate, "")
+
+		if err != domain.ErrInsufficientFunds {
+			t.Fatalf("got %v want %v", err, domain.ErrInsufficientFunds)
+		}
+
+		pending, _ := service.FindPendingAssets()
+		entries, _ := service.FindLedgerEntries(time.Time{}, time.Time{})
+
+		if len(*pending) != 0 || len(*entries) != 1 {
+			t.Errorf("got %d pending assets and %d ledger entries want 0 and 1", len(*pending), len(*entries))
+		}
+	})
+
+	t.Run("should check the funds against the ledger balance", func(t *testing.T) {
+		storage := db.NewMemoryStorage()
+		service := newAccountService(t, storage, 100)
+
+		// an amount changed without a ledger entry is not spent
+		accounts.NewRepository(storage.Repositories(db.ACCOUNTS_COLLECTION)).Deposit(service.ID, domain.NewDecimalFromInt(100))
+
+		if _, err := service.Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(60), startDate, ""); err != domain.ErrInsufficientFunds {
+			t.Errorf("got %v want %v", err, domain.ErrInsufficientFunds)
+		}
+
+		if err := service.Withdraw(domain.NewDecimalFromInt(150)); err != domain.ErrInsufficientFunds {
+			t.Errorf("got %v want %v", err, domain.ErrInsufficientFunds)
+		}
+	})
+
+	t.Run("should link the ledger entries of buys and sells to their orders", func(t *testing.T) {
+		service := newAccountService(t, db.NewMemoryStorage(), 100).WithFeeRate(0.01)
+
+		if _, err := service.Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(50), startDate, "11"); err != nil {
+			t.Fatalf("Not expected Buy to return error: %v", err)
+		}
+
+		selection := domain.LotSelection{Method: domain.LotFIFO}
+
+		if _, err := service.SellAmount(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(60), selection, startDate.Add(time.Hour), "12"); err != nil {
+			t.Fatalf("Not expected SellAmount to return error: %v", err)
+		}
+
+		entries, _ := service.FindLedgerEntries(time.Time{}, time.Time{})
+		orders := []string{}
+
+		for _, entry := range *entries {
+			orders = append(orders, entry.OrderID)
+		}
+
+		if want := []string{"", "11", "11", "12", "12"}; !reflect.DeepEqual(orders, want) {
+			t.Errorf("got ledger entries of orders %v want %v", orders, want)
+		}
+	})
+
+	t.Run("should merge pending lots into one lot at their average price", func(t *testing.T) {
+		service := newAccountService(t, db.NewMemoryStorage(), 1000)
+
+		first, _ := service.Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(100), startDate, "")
+		second, _ := service.Buy(domain.NewDecimalFromInt(3), domain.NewDecimalFromInt(200), startDate.Add(time.Hour), "")
+		sold, _ := service.Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(50), startDate, "")
+		service.Sell(sold, domain.NewDecimalFromInt(60), startDate.Add(time.Hour))
+
+		if _, err := service.MergeLots([]primitive.ObjectID{first.ID, sold.ID}); err != domain.ErrLotsNotMergeable {
+			t.Errorf("got %v want %v", err, domain.ErrLotsNotMergeable)
+		}
+
+		merged, err := service.MergeLots([]primitive.ObjectID{first.ID, second.ID})
+
+		if err != nil {
+			t.Fatalf("Not expected MergeLots to return error: %v", err)
+		}
+
+		pending, _ := service.FindPendingAssets()
+
+		if len(*pending) != 1 || (*pending)[0].ID != merged.ID {
+			t.Fatalf("got pending assets %+v want the merged lot", pending)
+		}
+
+		if got := (*pending)[0]; got.Amount != domain.NewDecimalFromInt(4) || got.BuyPrice != domain.NewDecimalFromInt(175) {
+			t.Errorf("got lot of %v at %v want 4 at 175", got.Amount, got.BuyPrice)
+		}
+	})
+
+	t.Run("should return error when the asset value overflows", func(t *testing.T) {
+		service := newAccountService(t, db.NewMemoryStorage(), 100)
+
+		_, err := service.Buy(domain.NewDecimalFromInt(1000000), domain.NewDecimalFromInt(1000000), startDate, "")
+
+		if !errors.Is(err, domain.ErrDecimalOverflow) {
+			t.Fatalf("got %v want %v", err, domain.ErrDecimalOverflow)
+		}
+
+		if pending, _ := service.FindPendingAssets(); len(*pending) != 0 {
+			t.Errorf("got %d pending assets want 0", len(*pending))
+		}
+	})
+
+	t.Run("should keep the writes done outside of a unit of work that fails", func(t *testing.T) {
+		database := db.NewMemoryDatabase()
+		written := make(chan error)
+
+		failure := errors.New("failure")
+		err := database.Do(func(repositories domain.RepositoryFactory) error {
+			repositories("prices").InsertOne(bson.M{"asset": "BTC"})
+
+			go func() {
+				written <- database.Collection("prices").InsertOne(bson.M{"asset": "ETH"})
+			}()
+
+			select {
+			case <-written:
+				t.Fatalf("Expected the write to wait for the unit of work")
+			case <-time.After(10 * time.Millisecond):
+			}
+
+			return failure
+		})
+
+		if err != failure {
+			t.Fatalf("got %v want %v", err, failure)
+		}
+
+		if err := <-written; err != nil {
+			t.Fatalf("Not expected InsertOne to return error: %v", err)
+		}
+
+		var prices []bson.M
+		database.Collection("prices").FindAll(&prices, bson.M{}, nil)
+
+		if len(prices) != 1 || prices[0]["asset"] != "ETH" {
+			t.Errorf("got %v want only the ETH price", prices)
+		}
+	})
+
+	t.Run("should rollback every write when the unit of work fails", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "bolt")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer os.RemoveAll(dir)
+
+		file := path.Join(dir, "test.db")
+		database, err := db.OpenBoltDatabase(file)
+
+		if err != nil {
+			t.Fatalf("Not expected OpenBoltDatabase to return error: %v", err)
+		}
+
+		database.Collection("prices").InsertOne(bson.M{"asset": "BTC", "value": 1})
+
+		failure := errors.New("failure")
+		err = database.Do(func(repositories domain.RepositoryFactory) error {
+			repositories("prices").BulkUpdate(bson.M{}, bson.M{"$set": bson.M{"value": 2}})
+			repositories("other").InsertOne(bson.M{"asset": "ETH"})
+
+			return failure
+		})
+
+		if err != failure {
+			t.Fatalf("got %v want %v", err, failure)
+		}
+
+		database.Close()
+		database, err = db.OpenBoltDatabase(file)
+
+		if err != nil {
+			t.Fatalf("Not expected OpenBoltDatabase to return error: %v", err)
+		}
+
+		defer database.Close()
+
+		var prices []bson.M
+		database.Collection("prices").FindAll(&prices, bson.M{"value": 1}, nil)
+
+		var other []bson.M
+		database.Collection("other").FindAll(&other, bson.M{}, nil)
+
+		if len(prices) != 1 || len(other) != 0 {
+			t.Errorf("got prices %v and other %v want writes rolled back", prices, other)
+		}
+	})
+}
@@ -4,26 +4,32 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/trader"
 )
 
 // Service executes DCA operations
 type Service struct {
 	broker        domain.Broker
 	collector     domain.Collector
+	markets       domain.MarketsService
+	exchange      string
 	dcaJobsRepo   domain.DCAJobsRepository
 	dcaAssetsRepo domain.DCAAssetsRepository
 	notifications domain.NotificationsService
+	// accountService pays the purchases and records them in the account ledger, they are only saved as dca assets when it is not set
+	accountService domain.DCAAccountService
 }
 
-// NewService returns an instance of the DCAService
-func NewService(broker domain.Broker, collector domain.Collector, dcaJobsRepo domain.DCAJobsRepository, dcaAssetsRepo domain.DCAAssetsRepository) *Service {
+// NewService returns an instance of the DCAService that buys assets on the exchange passed by argument
+func NewService(broker domain.Broker, collector domain.Collector, markets domain.MarketsService, exchange string, dcaJobsRepo domain.DCAJobsRepository, dcaAssetsRepo domain.DCAAssetsRepository) *Service {
 	return &Service{
 		broker:        broker,
 		collector:     collector,
+		markets:       markets,
+		exchange:      exchange,
 		dcaJobsRepo:   dcaJobsRepo,
 		dcaAssetsRepo: dcaAssetsRepo,
 	}
@@ -34,6 +40,11 @@ func (s *Service) SetNotificationsService(service domain.NotificationsService) {
 	s.notifications = service
 }
 
+// SetAccountService initializes service of the account that pays the purchases
+func (s *Service) SetAccountService(service domain.DCAAccountService) {
+	s.accountService = service
+}
+
 // DrainDCA fetches dca jobs and execute dca operations
 func (s *Service) DrainDCA() error {
 	dcaJobs, err := s.dcaJobsRepo.FindAll()
@@ -51,8 +62,18 @@ func (s *Service) DrainDCA() error {
 	return nil
 }
 
-// CreateDCA creates a dca job in repository
+// CreateDCA creates a dca job in repository, it returns error when the exchange does not trade one of the coins with the job quote currency
 func (s *Service) CreateDCA(dcaJob *domain.DCAJob) error {
+	if err := domain.ValidateQuote(dcaJob.GetQuote()); err != nil {
+		return err
+	}
+
+	for coinSymbol := range dcaJob.Options.CoinsProportion {
+		if _, err := s.markets.Market(s.exchange, coinSymbol, dcaJob.GetQuote()); err != nil {
+			return err
+		}
+	}
+
 	return s.dcaJobsRepo.Save(dcaJob)
 }
 
@@ -83,32 +104,74 @@ func (s *Service) executeDCA(dcaJob *domain.DCAJob) error {
 // execute calls operations to buy crypto assets specified
 func (s *Service) execute(dca *domain.DCAJob) error {
 	coinsAmounts := dca.GetFiatCoinsAmount()
+	quote := dca.GetQuote()
 
 	var errorsContainer []error
 
+	s.broker.SetQuote(quote)
+
 	for coinSymbol, amount := range coinsAmounts {
-		ticker := strings.ToUpper(coinSymbol) + "EUR"
+		market, err := s.markets.Market(s.exchange, coinSymbol, quote)
+		if err != nil {
+			errorsContainer = append(errorsContainer, err)
+			continue
+		}
 
-		price, err := s.collector.GetTicker(ticker)
+		coinPrice, err := s.collector.GetTicker(market.Symbol)
 		if err != nil {
-			errorsContainer = append(errorsContainer, fmt.Errorf("failed getting ticker \"%s\" :%s", ticker, err))
+			errorsContainer = append(errorsContainer, fmt.Errorf("failed getting ticker \"%s\" :%s", market.Symbol, err))
 			continue
 		}
 
-		s.broker.SetTicker(coinSymbol)
-		err = s.broker.AddBuyOrder(amount/price, price)
+		fiatAmount := domain.NewDecimalFromFloat32(amount)
+		coinAmount, err := fiatAmount.DivChecked(coinPrice)
+
+		if err != nil {
+			errorsContainer = append(errorsContainer, fmt.Errorf("failed calculating amount of %s to buy: %s", coinSymbol, err))
+			continue
+		}
+
+		fee := fiatAmount.MulFloat(domain.ExchangeFeeRate(s.exchange))
+
+		// the account is checked right before the order, so the order is not placed without funds to record it
+		if s.accountService != nil {
+			balance, err := s.accountService.GetAmount()
+			if err != nil {
+				errorsContainer = append(errorsContainer, fmt.Errorf("failed getting the account amount: %s", err))
+				continue
+			}
+
+			if balance.LessThan(fiatAmount.Add(fee)) {
+				errorsContainer = append(errorsContainer, fmt.Errorf("failed buying %v of %s: %s", coinAmount, coinSymbol, domain.ErrInsufficientFunds))
+				continue
+			}
+		}
+
+		buyTime := time.Now()
+		orderID, err := trader.NewAssetTrader(s.broker, coinSymbol).Buy(coinAmount, coinPrice, buyTime)
 		if err != nil {
-			errorsContainer = append(errorsContainer, fmt.Errorf("failed buiyng %f of %s: %s", amount/price, coinSymbol, err))
+			errorsContainer = append(errorsContainer, fmt.Errorf("failed buiyng %v of %s: %s", coinAmount, coinSymbol, err))
 			continue
 		}
 
 		asset := &domain.DCAAsset{
 			Coin:       coinSymbol,
-			Amount:     amount / price,
-			Price:      price,
-			FiatAmount: amount,
-			CreatedAt:  time.Now(),
+			Amount:     coinAmount,
+			Price:      coinPrice,
+			Fee:        fee,
+			FiatAmount: fiatAmount,
+			CreatedAt:  buyTime,
+			Quote:      quote,
+			OrderID:    orderID,
 		}
+
+		if s.accountService != nil {
+			err = s.accountService.BuyDCA(asset.FiatAmount, asset.Fee, orderID, buyTime)
+			if err != nil {
+				errorsContainer = append(errorsContainer, fmt.Errorf("order %s of %v %s placed but not recorded in the account: %s", orderID, coinAmount, coinSymbol, err))
+			}
+		}
+
 		err = s.dcaAssetsRepo.Save(asset)
 		if err != nil {
 			errorsContainer = append(errorsContainer, fmt.Errorf("failed saving asset: %s\n%+v", err, asset))
@@ -0,0 +1,133 @@
+package dca_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/dca"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestDrainDCA(t *testing.T) {
+	newService := func(t *testing.T, ctrl *gomock.Controller, amount int64) (*dca.Service, *accounts.AccountService, *mocks.MockBroker, *db.Storage) {
+		storage := db.NewMemoryStorage()
+		account, _ := accounts.OpenAccount(storage.UnitOfWork, domain.ExchangeKraken, domain.NewDecimalFromInt(amount), time.Now())
+
+		accountService, err := accounts.NewAccountService(
+			account.ID.Hex(),
+			accounts.NewRepository(storage.Repositories(db.ACCOUNTS_COLLECTION)),
+			assets.NewRepository(storage.Repositories(db.ASSETS_COLLECTION)),
+			accounts.NewLedgerRepository(storage.Repositories(db.LEDGER_COLLECTION)),
+			storage.UnitOfWork,
+		)
+
+		if err != nil {
+			t.Fatalf("Not expected NewAccountService to return error: %v", err)
+		}
+
+		broker, collector, markets := mocks.NewMockBroker(ctrl), mocks.NewMockCollector(ctrl), mocks.NewMockMarketsService(ctrl)
+		markets.EXPECT().Market(domain.ExchangeKraken, "BTC", domain.DefaultQuote).Return(&domain.Market{Base: "BTC", Quote: domain.DefaultQuote, Symbol: "XXBTZEUR"}, nil).AnyTimes()
+		collector.EXPECT().GetTicker("XXBTZEUR").Return(domain.NewDecimalFromInt(20000), nil).AnyTimes()
+		broker.EXPECT().SetQuote(domain.DefaultQuote).AnyTimes()
+		broker.EXPECT().SetTicker("BTC").AnyTimes()
+
+		service := dca.NewService(
+			broker,
+			collector,
+			markets,
+			domain.ExchangeKraken,
+			dca.NewJobsRepository(storage.Repositories(db.DCA_JOBS_COLLECTION)),
+			dca.NewAssetsRepository(storage.Repositories(db.DCA_ASSETS_COLLECTION)),
+		)
+		service.SetAccountService(accountService)
+
+		err = service.CreateDCA(&domain.DCAJob{
+			NextExecution: time.Now().Add(-time.Hour).Unix(),
+			Period:        3600,
+			Options:       domain.DCAJobOptions{TotalFIATAmount: 100, CoinsProportion: map[string]float32{"BTC": 1}},
+		})
+
+		if err != nil {
+			t.Fatalf("Not expected CreateDCA to return error: %v", err)
+		}
+
+		return service, accountService, broker, storage
+	}
+
+	t.Run("should pay the purchase and its fee from the account with ledger entries linked to the order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, accountService, broker, storage := newService(t, ctrl, 1000)
+
+		var placed domain.Order
+		broker.EXPECT().PlaceOrder(gomock.Any()).DoAndReturn(func(order domain.Order) error {
+			placed = order
+			return nil
+		}).Times(1)
+
+		if err := service.DrainDCA(); err != nil {
+			t.Fatalf("Not expected DrainDCA to return error: %v", err)
+		}
+
+		if placed.ClientOrderID == "" {
+			t.Fatalf("Expected the order to have a client order id")
+		}
+
+		entries, err := accountService.FindLedgerEntries(time.Time{}, time.Time{})
+
+		if err != nil {
+			t.Fatalf("Not expected FindLedgerEntries to return error: %v", err)
+		}
+
+		if got, want := len(*entries), 3; got != want {
+			t.Fatalf("got %v want %v", got, want)
+		}
+
+		purchase, fee := (*entries)[1], (*entries)[2]
+
+		if purchase.Type != domain.LedgerDCAPurchase || purchase.Amount != domain.NewDecimalFromInt(100) || purchase.OrderID != placed.ClientOrderID {
+			t.Errorf("got %+v want a dca purchase of 100 of order %v", purchase, placed.ClientOrderID)
+		}
+
+		if fee.Type != domain.LedgerFee || fee.Amount != domain.NewDecimal(0.26) || fee.OrderID != placed.ClientOrderID {
+			t.Errorf("got %+v want a fee of 0.26 of order %v", fee, placed.ClientOrderID)
+		}
+
+		balance, _ := accountService.GetAmount()
+
+		if want := domain.NewDecimal(899.74); balance != want {
+			t.Errorf("got %v want %v", balance, want)
+		}
+
+		dcaAssets, _ := dca.NewAssetsRepository(storage.Repositories(db.DCA_ASSETS_COLLECTION)).FindAll()
+
+		if got := (*dcaAssets)[0].OrderID; got != placed.ClientOrderID {
+			t.Errorf("got %v want %v", got, placed.ClientOrderID)
+		}
+	})
+
+	t.Run("should not place the order when the account does not pay the purchase and its fee", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		service, accountService, broker, _ := newService(t, ctrl, 100)
+
+		broker.EXPECT().PlaceOrder(gomock.Any()).Times(0)
+
+		if err := service.DrainDCA(); err != nil {
+			t.Fatalf("Not expected DrainDCA to return error: %v", err)
+		}
+
+		balance, _ := accountService.GetAmount()
+
+		if want := domain.NewDecimalFromInt(100); balance != want {
+			t.Errorf("got %v want %v", balance, want)
+		}
+	})
+}
@@ -1,15 +1,19 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrInsufficientFunds is returned when an account does not have the amount to withdraw
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
 // Account has details about an exchange account
 type Account struct {
 	ID     primitive.ObjectID `bson:"_id" json:"_id"`
-	Amount float32            `bson:"amount,truncate" json:"amount"`
+	Amount Decimal            `bson:"amount" json:"amount"`
 	Broker string             `json:"broker"`
 }
 
@@ -17,25 +21,32 @@ type Account struct {
 type AccountsRepository interface {
 	FindById(id string) (*Account, error)
 	FindByBroker(broker string) (*Account, error)
-	Create(broker string, amount float32) (*Account, error)
-	Withdraw(id string, amount float32) error
-	Deposit(id string, amount float32) error
+	Create(broker string, amount Decimal) (*Account, error)
+	Withdraw(id string, amount Decimal) error
+	Deposit(id string, amount Decimal) error
 }
 
 // AccountServiceReader reads information about one account
 type AccountServiceReader interface {
-	GetAmount() (float32, error)
+	GetAmount() (Decimal, error)
 	FindPendingAssets() (*[]Asset, error)
 	FindAllAssets() (*[]Asset, error)
-	GetBalance(startDate, endDate time.Time) (float32, error)
+	GetBalance(startDate, endDate time.Time) (Decimal, error)
 	CheckAssetWithCloserPriceExists(price, limit float32) (bool, error)
 }
 
 // AccountService interacts with one account
 type AccountService interface {
 	AccountServiceReader
-	Withdraw(amount float32) error
-	Deposit(amount float32) error
-	CreateAsset(amount, price float32, time time.Time) (*Asset, error)
-	SellAsset(assetID string, price float32, time time.Time) error
+	Withdraw(amount Decimal) error
+	Deposit(amount Decimal) error
+	CreateAsset(amount, price Decimal, time time.Time) (*Asset, error)
+	SellAsset(assetID string, price Decimal, time time.Time) error
+	// Buy and SellAmount link the ledger entries they record to the exchange order passed by argument
+	Buy(amount, price Decimal, time time.Time, orderID string) (*Asset, error)
+	Sell(asset *Asset, price Decimal, time time.Time) error
+	SellAmount(amount, price Decimal, selection LotSelection, time time.Time, orderID string) (*Trade, error)
+	MergeLots(ids []primitive.ObjectID) (*Asset, error)
+	Adjust(amount Decimal, description string) error
+	Fee(value Decimal) Decimal
 }
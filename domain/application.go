@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -13,21 +14,95 @@ type ApplicationOptions struct {
 	StatisticsOptions    `bson:"statisticsOptions" json:"statisticsOptions"`
 	DecisionMakerOptions `bson:"decisionMakerOptions" json:"decisionMakerOptions"`
 	CollectorOptions     `bson:"collectorOptions" json:"collectorOptions"`
+	// LotMatching decides which lots are sold, by default the lots with the profit wanted
+	LotMatching LotMatching `bson:"lotMatching,omitempty" json:"lotMatching,omitempty"`
+}
+
+// ApplicationMode decides whether the orders of an application are placed on the exchange
+type ApplicationMode string
+
+const (
+	// ModeLive places the orders on the exchange of the application account, orders are only printed outside production
+	ModeLive ApplicationMode = "live"
+	// ModePaper only prints the orders
+	ModePaper ApplicationMode = "paper"
+	// ModeShadow records the orders with fills simulated on the live prices, to be compared with a live application
+	ModeShadow ApplicationMode = "shadow"
+)
+
+// AssetAllocation is an asset traded by an application and the maximum cost of the lots held of it
+type AssetAllocation struct {
+	Asset string `bson:"asset" json:"asset"`
+	// MaxAllocation is the maximum cost of the pending lots of the asset, zero means no cap
+	MaxAllocation Decimal `bson:"maxAllocation,omitempty" json:"maxAllocation,omitempty"`
 }
 
 // Application stores all options and required relations ids for a running application
 type Application struct {
-	ID        primitive.ObjectID `bson:"_id" json:"_id"`
-	Asset     string             `json:"asset"`
+	ID    primitive.ObjectID `bson:"_id" json:"_id"`
+	Asset string             `json:"asset"`
+	// Assets are the assets traded against the application account, only Asset is traded when empty
+	Assets []AssetAllocation `bson:"assets,omitempty" json:"assets,omitempty"`
+	// Quote is the currency the assets are traded with, EUR when empty
+	Quote string `bson:"quote,omitempty" json:"quote,omitempty"`
+	// Mode is live when empty
+	Mode ApplicationMode `bson:"mode,omitempty" json:"mode,omitempty"`
+	// ShadowOf is the live application a shadow application is compared with
+	ShadowOf primitive.ObjectID `bson:"shadowOf,omitempty" json:"shadowOf,omitempty"`
+	// UpdatedBy is who changed the options last, it is the author of their version
+	UpdatedBy string             `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"`
 	AccountID primitive.ObjectID `bson:"accountID" json:"accountID"`
 	Options   ApplicationOptions `bson:"options" json:"options"`
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 }
 
+// GetAssets returns the assets traded by the application
+func (a *Application) GetAssets() []AssetAllocation {
+	if len(a.Assets) == 0 {
+		return []AssetAllocation{{Asset: a.Asset}}
+	}
+
+	return a.Assets
+}
+
+// GetQuote returns the currency the assets of the application are traded with
+func (a *Application) GetQuote() string {
+	if a.Quote == "" {
+		return DefaultQuote
+	}
+
+	return a.Quote
+}
+
+// GetMode returns the mode of the application
+func (a *Application) GetMode() ApplicationMode {
+	if a.Mode == "" {
+		return ModeLive
+	}
+
+	return a.Mode
+}
+
+// IsSimulated returns true for the applications that do not place orders on the exchange, their accounts are not real
+func (a *Application) IsSimulated() bool {
+	return a.GetMode() != ModeLive
+}
+
+// ValidateMode returns error when the mode of the application is not supported
+func (a *Application) ValidateMode() error {
+	switch a.GetMode() {
+	case ModeLive, ModePaper, ModeShadow:
+		return nil
+	default:
+		return fmt.Errorf("application mode %v is not live, paper or shadow", a.Mode)
+	}
+}
+
 // ApplicationRepository stores and gets applications from db
 type ApplicationRepository interface {
 	FindByID(id string) (*Application, error)
 	Create(asset string, options ApplicationOptions, acountID primitive.ObjectID) (*Application, error)
+	UpdateOptions(id string, options ApplicationOptions, updatedBy string) error
 	FindAll() (*[]Application, error)
 	DeleteByID(id string) error
 }
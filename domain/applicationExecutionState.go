@@ -24,6 +24,55 @@ type ApplicationExecutionState struct {
 	ExecutionID primitive.ObjectID `bson:"executionId" json:"executionId"`
 	Date        time.Time          `json:"date"`
 	State       interface{}        `json:"state" bson:"state"`
+	// ExpireAt is the date when the state is deleted. States without it are never deleted.
+	ExpireAt *time.Time `json:"expireAt,omitempty" bson:"expireAt,omitempty"`
+	// OptionsVersion is the version of the options of the application
+	OptionsVersion int `json:"optionsVersion,omitempty" bson:"optionsVersion,omitempty"`
+}
+
+const (
+	// StatesResolutionRaw is the resolution of the states stored on each price change
+	StatesResolutionRaw = "raw"
+	// StatesResolutionHourly is the resolution of the states summarized by hour
+	StatesResolutionHourly = "1h"
+	// StatesResolutionDaily is the resolution of the states summarized by day
+	StatesResolutionDaily = "1d"
+)
+
+// StatesRetentionPolicy sets how long application execution states are kept on each resolution.
+// Daily summaries are kept forever.
+type StatesRetentionPolicy struct {
+	RawRetention    time.Duration
+	HourlyRetention time.Duration
+}
+
+// DefaultStatesRetentionPolicy keeps raw states for 7 days and hourly summaries for 90 days
+var DefaultStatesRetentionPolicy = StatesRetentionPolicy{
+	RawRetention:    7 * 24 * time.Hour,
+	HourlyRetention: 90 * 24 * time.Hour,
+}
+
+// Resolution returns the resolution that has the states between the dates with the detail needed to display them
+func (p StatesRetentionPolicy) Resolution(startDate, endDate, now time.Time) string {
+	days := endDate.Sub(startDate).Hours() / 24
+
+	resolution := StatesResolutionRaw
+
+	if days > 30 {
+		resolution = StatesResolutionDaily
+	} else if days > 5 {
+		resolution = StatesResolutionHourly
+	}
+
+	if resolution == StatesResolutionRaw && startDate.Before(now.Add(-p.RawRetention)) {
+		resolution = StatesResolutionHourly
+	}
+
+	if resolution == StatesResolutionHourly && startDate.Before(now.Add(-p.HourlyRetention)) {
+		resolution = StatesResolutionDaily
+	}
+
+	return resolution
 }
 
 // ApplicationExecutionStateRepository stores and gets application executions states
@@ -32,5 +81,6 @@ type ApplicationExecutionStateRepository interface {
 	Aggregate(pipeline mongo.Pipeline) (*[]bson.M, error)
 	BulkCreate(documents *[]bson.M) error
 	BulkDeleteByExecutionID(id string) error
+	DeleteExpired(date time.Time) error
 	FindLast(filter interface{}) (*ApplicationExecutionState, error)
 }
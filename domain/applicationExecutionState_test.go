@@ -0,0 +1,37 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestStatesRetentionPolicyResolution(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	policy := domain.DefaultStatesRetentionPolicy
+
+	tests := []struct {
+		name      string
+		startDate time.Time
+		endDate   time.Time
+		want      string
+	}{
+		{"should use raw states on short recent ranges", now.Add(-2 * day), now, domain.StatesResolutionRaw},
+		{"should use hourly rollups on ranges longer than 5 days", now.Add(-10 * day), now, domain.StatesResolutionHourly},
+		{"should use daily rollups on ranges longer than 30 days", now.Add(-60 * day), now, domain.StatesResolutionDaily},
+		{"should use hourly rollups when raw states expired", now.Add(-20 * day), now.Add(-19 * day), domain.StatesResolutionHourly},
+		{"should use daily rollups when hourly rollups expired", now.Add(-120 * day), now.Add(-110 * day), domain.StatesResolutionDaily},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Resolution(tt.startDate, tt.endDate, now)
+
+			if got != tt.want {
+				t.Errorf("got %v want %v", got, tt.want)
+			}
+		})
+	}
+}
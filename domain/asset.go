@@ -6,30 +6,52 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Asset is a financial instrument
+// Asset is a financial instrument, a lot bought at once.
+// Selling part of a lot splits it into a lot with the amount sold whose ParentID is the lot split.
 type Asset struct {
-	ID        primitive.ObjectID `bson:"_id" json:"_id"`
-	Amount    float32            `bson:"amount,truncate" json:"amount"`
-	BuyTime   time.Time          `json:"buyTime"`
-	SellTime  time.Time          `json:"sellTime"`
-	BuyPrice  float32            `bson:"buyPrice,truncate" json:"buyPrice"`
-	SellPrice float32            `bson:"sellPrice,truncate" json:"sellPrice"`
-	Sold      bool               `json:"sold"`
+	ID primitive.ObjectID `bson:"_id" json:"_id"`
+	// Symbol is the asset bought, e.g. BTC, it is empty on lots bought before accounts held more than one asset
+	Symbol    string             `bson:"symbol,omitempty" json:"symbol,omitempty"`
+	Amount    Decimal            `bson:"amount" json:"amount"`
+	BuyTime   time.Time          `bson:"buyTime" json:"buyTime"`
+	SellTime  time.Time          `bson:"sellTime" json:"sellTime"`
+	BuyPrice  Decimal            `bson:"buyPrice" json:"buyPrice"`
+	SellPrice Decimal            `bson:"sellPrice" json:"sellPrice"`
+	Sold      bool               `bson:"sold" json:"sold"`
+	BuyFee    Decimal            `bson:"buyFee,omitempty" json:"buyFee,omitempty"`
+	SellFee   Decimal            `bson:"sellFee,omitempty" json:"sellFee,omitempty"`
 	AccountID primitive.ObjectID `bson:"accountID" json:"accountID"`
+	ParentID  primitive.ObjectID `bson:"parentId,omitempty" json:"parentId,omitempty"`
+	// OptionsVersion is the version of the options of the application that bought the lot
+	OptionsVersion int `bson:"optionsVersion,omitempty" json:"optionsVersion,omitempty"`
+}
+
+// GetSymbol returns the symbol of the lot or the default symbol when the lot does not have one
+func (a *Asset) GetSymbol(defaultSymbol string) string {
+	if a.Symbol == "" {
+		return defaultSymbol
+	}
+
+	return a.Symbol
 }
 
 // AssetsRepositoryReader fetches assets data
 type AssetsRepositoryReader interface {
 	FindAll(accountID string) (*[]Asset, error)
 	FindPendingAssets(accountID string) (*[]Asset, error)
-	FindCheaperAssetPrice(accountID string) (float32, error)
+	FindCheaperAssetPrice(accountID string) (Decimal, error)
 	CheckAssetWithCloserPriceExists(accountID string, price float32, limit float32) (bool, error)
-	GetBalance(accountID string, startDate, endDate time.Time) (float32, error)
+	GetBalance(accountID string, startDate, endDate time.Time) (Decimal, error)
 }
 
 // AssetsRepository stores and fetches assets
 type AssetsRepository interface {
 	AssetsRepositoryReader
-	Sell(id string, price float32, sellTime time.Time) error
+	// Sell closes a lot at the price passed by argument, fee is paid to the exchange on the sell
+	Sell(id string, price, fee Decimal, sellTime time.Time) error
+	// Split moves an amount of a lot, and the part of its buy fee, into a new lot that is returned
+	Split(asset *Asset, amount Decimal) (*Asset, error)
+	// Merge replaces pending lots by a lot with their amount and buy fees at their average buy price that is returned
+	Merge(lots []Asset) (*Asset, error)
 	Create(asset *Asset) error
 }
@@ -10,24 +10,44 @@ import (
 
 // AssetPrice represents the price of an asset on a moment
 type AssetPrice struct {
-	ID      primitive.ObjectID `bson:"_id" json:"_id"`
-	Date    time.Time          `bson:"date" json:"date"`
-	EndDate time.Time          `bson:"endDate" json:"endDate"`
-	Open    float32            `bson:"o" json:"o"`
-	Close   float32            `bson:"c" json:"c"`
-	High    float32            `bson:"h" json:"h"`
-	Low     float32            `bson:"l" json:"l"`
-	Volume  float32            `bson:"v" json:"v"`
-	Asset   string             `bson:"asset" json:"asset"`
+	ID       primitive.ObjectID `bson:"_id" json:"_id"`
+	Date     time.Time          `bson:"date" json:"date"`
+	EndDate  time.Time          `bson:"endDate" json:"endDate"`
+	Open     Decimal            `bson:"o" json:"o"`
+	Close    Decimal            `bson:"c" json:"c"`
+	High     Decimal            `bson:"h" json:"h"`
+	Low      Decimal            `bson:"l" json:"l"`
+	Volume   Decimal            `bson:"v" json:"v"`
+	Asset    string             `bson:"asset" json:"asset"`
+	Interval string             `bson:"interval" json:"interval"`
+}
+
+// OHLC returns the asset price as an OHLC
+func (a *AssetPrice) OHLC() OHLC {
+	return OHLC{Time: a.Date, EndTime: a.EndDate, Open: a.Open, Close: a.Close, High: a.High, Low: a.Low, Volume: a.Volume}
+}
+
+// IngestionResult counts the prices inserted and the prices skipped because they were already stored
+type IngestionResult struct {
+	Inserted int `json:"inserted"`
+	Skipped  int `json:"skipped"`
+}
+
+// Add sums the counts of another result
+func (r *IngestionResult) Add(result *IngestionResult) {
+	r.Inserted += result.Inserted
+	r.Skipped += result.Skipped
 }
 
 // AssetPriceRepository stores and gets assets prices
 type AssetPriceRepository interface {
-	Create(ohlc *OHLC, asset string) error
+	Create(ohlc *OHLC, asset string, interval string) error
 	FindAll(filter interface{}) (*[]AssetPrice, error)
 	Aggregate(pipeline mongo.Pipeline) (*[]bson.M, error)
-	GetLastAssetsPrices(asset string, limit int) (*[]AssetPrice, error)
-	BulkCreate(documents *[]bson.M) error
+	GetLastAssetsPrices(asset string, interval string, limit int) (*[]AssetPrice, error)
+	GetLastAssetPrice(asset string, interval string) (*AssetPrice, error)
+	BulkUpsert(documents *[]bson.M) (*IngestionResult, error)
+	EnsureIndexes() error
 }
 
 // AssetPriceGroupByDate is a group id struct
@@ -40,14 +60,14 @@ type AssetPriceGroupByDate struct {
 // AssetPriceAggregatedByDate is the output of the aggregate query that groups prices per date
 type AssetPriceAggregatedByDate struct {
 	ID    AssetPriceGroupByDate `json:"_id"`
-	Price float32               `json:"price"`
+	Price Decimal               `json:"price"`
 }
 
 // AssetsPricesService provides assets prices related methods
 type AssetsPricesService interface {
-	GetLastAssetsPrices(asset string, limit int) (*[]AssetPrice, error)
-	Create(ohlc *OHLC, asset string) error
-	FetchAndStoreAssetPrices(asset string, endDate time.Time) error
+	GetLastAssetsPrices(asset string, interval string, limit int) (*[]AssetPrice, error)
+	Create(ohlc *OHLC, asset string, interval string) error
+	FetchAndStoreAssetPrices(asset string, endDate time.Time) (*IngestionResult, error)
 }
 
 // CoindeskResponse is the body of Coindesk HTTP Response
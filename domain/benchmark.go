@@ -24,11 +24,11 @@ type BenchmarkOutput struct {
 	Buys                [][]float32 `json:"buys"`
 	Sells               [][]float32 `json:"sells"`
 	SellsPending        int         `json:"sellsPending"`
-	FinalAmount         float32     `json:"finalAmount"`
+	FinalAmount         Decimal     `json:"finalAmount"`
 	Assets              *[]Asset    `json:"assets"`
-	AssetsAmountPending float32     `json:"assetsAmountPending"`
-	AssetsValuePending  float32     `json:"assetsValuePending"`
-	LastPrice           float32     `json:"lastPrice"`
+	AssetsAmountPending Decimal     `json:"assetsAmountPending"`
+	AssetsValuePending  Decimal     `json:"assetsValuePending"`
+	LastPrice           Decimal     `json:"lastPrice"`
 }
 
 // String displays Output formatted
@@ -73,6 +73,6 @@ type BenchmarkService interface {
 	BulkRun(inputs []BenchmarkInput, c chan BenchmarkResult)
 	Run(input BenchmarkInput, benchmarkID *primitive.ObjectID) (*BenchmarkOutput, error)
 	HandleBenchmark(benchmark *Benchmark) error
-	GetDataSources() map[string]map[string]string
+	GetDataSources() (map[string]map[string]string, error)
 	AggregateApplicationState(pipeline mongo.Pipeline) (*[]bson.M, error)
 }
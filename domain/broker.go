@@ -2,7 +2,12 @@ package domain
 
 // Broker add order to buy and sell assets in real brokers
 type Broker interface {
-	AddBuyOrder(amount, price float32) error
-	AddSellOrder(amount, price float32) error
+	// AddBuyOrder and AddSellOrder place good till cancelled limit orders
+	AddBuyOrder(amount, price Decimal) error
+	AddSellOrder(amount, price Decimal) error
+	// PlaceOrder places an order of any type of the ticker, e.g. a stop-loss that protects the holdings while the application is down
+	PlaceOrder(order Order) error
 	SetTicker(ticker string)
+	// SetQuote changes the currency the assets are bought or sold with
+	SetQuote(quote string)
 }
@@ -1,35 +1,41 @@
 package domain
 
 import (
-	"encoding/csv"
 	"time"
 )
 
 // CollectorOptions are used to change collectors behaviour
 type CollectorOptions struct {
-	PriceVariationDetection float32 `bson:"priceVariationDetection,truncate" json:"priceVariationDetection"`
-	DataSource              *csv.Reader
-	NewPriceTimeRate        int `bson:"newPriceTimeRate,truncate" json:"newPriceTimeRate"`
+	PriceVariationDetection float32    `bson:"priceVariationDetection,truncate" json:"priceVariationDetection"`
+	DataSource              OHLCReader `bson:"-" json:"-"`
+	NewPriceTimeRate        int        `bson:"newPriceTimeRate,truncate" json:"newPriceTimeRate"`
 }
 
 // Collector notifies when price asset changes
 type Collector interface {
-	Start()
+	Start() error
 	Stop()
 	Regist(observable OnNewAssetPrice)
 	SetIndicators(indicators *[]Indicator)
-	GetTicker(tickerSymbol string) (float32, error)
+	GetTicker(tickerSymbol string) (Decimal, error)
 }
 
 // OHLC is a type with interval asset prices
 type OHLC struct {
+	// Asset is set by collectors of more than one asset
+	Asset   string    `json:"asset,omitempty"`
 	Time    time.Time `json:"time"`
 	EndTime time.Time `json:"etime"`
-	Open    float32   `json:"open"`
-	Close   float32   `json:"close"`
-	High    float32   `json:"high"`
-	Low     float32   `json:"low"`
-	Volume  float32   `json:"volume"`
+	Open    Decimal   `json:"open"`
+	Close   Decimal   `json:"close"`
+	High    Decimal   `json:"high"`
+	Low     Decimal   `json:"low"`
+	Volume  Decimal   `json:"volume"`
+}
+
+// OHLCReader reads prices one at a time and returns io.EOF when there are no more prices
+type OHLCReader interface {
+	Read() (*OHLC, error)
 }
 
 // OnNewAssetPrice
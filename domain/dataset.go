@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	// DatasetFormatOHLCV identifies files with unix seconds, open, high, low, close and volume columns
+	DatasetFormatOHLCV = "ohlcv"
+	// DatasetFormatDatePrice identifies files with a header and unix milliseconds and price columns
+	DatasetFormatDatePrice = "date-price"
+)
+
+// Dataset describes a prices history file that can be used as data source
+type Dataset struct {
+	Asset     string    `json:"asset"`
+	Name      string    `json:"name"`
+	Interval  string    `json:"interval"`
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+	Format    string    `json:"format"`
+	FilePath  string    `json:"filePath"`
+	Checksum  string    `json:"checksum"`
+}
+
+// DatasetsRepository stores and gets datasets descriptions
+type DatasetsRepository interface {
+	FindAll() (*[]Dataset, error)
+	Save(dataset *Dataset) error
+}
+
+// DatasetsService manages the datasets registry and the files it describes
+type DatasetsService interface {
+	FindAll() (*[]Dataset, error)
+	GetDataSources() (map[string]map[string]string, error)
+	GetFilePath(filePath string) string
+	Import(dataset *Dataset, content io.Reader) error
+	Validate(dataset *Dataset) error
+}
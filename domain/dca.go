@@ -13,6 +13,17 @@ type DCAJob struct {
 	Period        int64
 	NextExecution int64
 	Options       DCAJobOptions
+	// Quote is the currency the coins are bought with, EUR when empty
+	Quote string `bson:"quote,omitempty" json:"quote,omitempty"`
+}
+
+// GetQuote returns the currency the coins of the job are bought with
+func (d *DCAJob) GetQuote() string {
+	if d.Quote == "" {
+		return DefaultQuote
+	}
+
+	return d.Quote
 }
 
 // SetNextExecution updates dca job with the next timestamp to execute the job
@@ -52,10 +63,31 @@ type DCAJobsRepository interface {
 // DCAAsset represents an asset bought on a dca operation
 type DCAAsset struct {
 	Coin       string
-	Amount     float32
-	Price      float32
-	FiatAmount float32
+	Amount     Decimal
+	Price      Decimal
+	FiatAmount Decimal
+	Fee        Decimal   `bson:"fee,omitempty" json:"fee,omitempty"`
 	CreatedAt  time.Time `bson:"createdAt" json:"createdAt"`
+	// Quote is the currency of the price and of the fiat amount, EUR when empty
+	Quote string `bson:"quote,omitempty" json:"quote,omitempty"`
+	// OrderID is the client order id of the buy order placed on the exchange
+	OrderID string `bson:"orderId,omitempty" json:"orderId,omitempty"`
+}
+
+// GetQuote returns the currency of the price and of the fiat amount of the asset
+func (d *DCAAsset) GetQuote() string {
+	if d.Quote == "" {
+		return DefaultQuote
+	}
+
+	return d.Quote
+}
+
+// DCAAccountService pays the dca purchases with the money of an account
+type DCAAccountService interface {
+	GetAmount() (Decimal, error)
+	// BuyDCA withdraws the value and the fee of a dca purchase from the account and records them in its ledger
+	BuyDCA(value, fee Decimal, orderID string, time time.Time) error
 }
 
 // DCAAssetsRepository stores and gets dca assets
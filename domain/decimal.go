@@ -0,0 +1,391 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// DecimalPlaces is the number of decimal places kept by Decimal, enough for satoshis
+const DecimalPlaces = 8
+
+// decimalUnit is the number of units of one
+const decimalUnit = 100000000
+
+// ErrDecimalOverflow is returned when the result of an operation does not fit in a decimal
+var ErrDecimalOverflow = errors.New("decimal overflow")
+
+// ErrDecimalDivisionByZero is returned when a decimal is divided by zero
+var ErrDecimalDivisionByZero = errors.New("decimal division by zero")
+
+// Decimal is a fixed-point number used for money, prices and quantities.
+// It is stored as a decimal128 on mongo and encoded as a number on JSON.
+type Decimal struct {
+	units int64
+}
+
+// NewDecimal returns the decimal closest to the float passed by argument
+func NewDecimal(value float64) Decimal {
+	return Decimal{int64(math.Round(value * decimalUnit))}
+}
+
+// NewDecimalFromFloat32 returns the decimal with the shortest representation of the float passed by argument,
+// so 0.1 is 0.1 instead of 0.100000001.
+func NewDecimalFromFloat32(value float32) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(float64(value), 'f', -1, 32))
+	return d
+}
+
+// NewDecimalFromInt returns the decimal of an integer
+func NewDecimalFromInt(value int64) Decimal {
+	return Decimal{value * decimalUnit}
+}
+
+// ParseDecimal parses a number like 12.345 or 1.2E-5. Digits beyond DecimalPlaces are rounded.
+func ParseDecimal(s string) (Decimal, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+
+	if !ok {
+		return Decimal{}, fmt.Errorf("%q is not a decimal", s)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt64(decimalUnit))
+
+	quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+
+	if remainder.Mul(remainder.Abs(remainder), big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return Decimal{}, fmt.Errorf("%q is out of the decimal range", s)
+	}
+
+	return Decimal{quotient.Int64()}, nil
+}
+
+// Add returns d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{d.units + o.units}
+}
+
+// Sub returns d - o
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{d.units - o.units}
+}
+
+// Neg returns -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{-d.units}
+}
+
+// Mul returns d * o rounded to DecimalPlaces. It panics on overflow, money paths use MulChecked.
+func (d Decimal) Mul(o Decimal) Decimal {
+	result, err := d.MulChecked(o)
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return result
+}
+
+// MulChecked returns d * o rounded to DecimalPlaces or ErrDecimalOverflow when the result does not fit in a decimal
+func (d Decimal) MulChecked(o Decimal) (Decimal, error) {
+	a, b := d.units, o.units
+	negative := (a < 0) != (b < 0)
+
+	hi, lo := bits.Mul64(abs(a), abs(b))
+
+	if hi >= decimalUnit {
+		return Decimal{}, fmt.Errorf("%w multiplying %v by %v", ErrDecimalOverflow, d, o)
+	}
+
+	quotient, remainder := bits.Div64(hi, lo, decimalUnit)
+
+	if remainder*2 >= decimalUnit {
+		quotient++
+	}
+
+	if quotient > math.MaxInt64 {
+		return Decimal{}, fmt.Errorf("%w multiplying %v by %v", ErrDecimalOverflow, d, o)
+	}
+
+	if negative {
+		return Decimal{-int64(quotient)}, nil
+	}
+
+	return Decimal{int64(quotient)}, nil
+}
+
+// Div returns d / o rounded to DecimalPlaces. It panics when o is zero or on overflow, money paths use DivChecked.
+func (d Decimal) Div(o Decimal) Decimal {
+	result, err := d.DivChecked(o)
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return result
+}
+
+// DivChecked returns d / o rounded to DecimalPlaces, ErrDecimalDivisionByZero when o is zero
+// or ErrDecimalOverflow when the result does not fit in a decimal
+func (d Decimal) DivChecked(o Decimal) (Decimal, error) {
+	if o.units == 0 {
+		return Decimal{}, ErrDecimalDivisionByZero
+	}
+
+	negative := (d.units < 0) != (o.units < 0)
+	divisor := abs(o.units)
+
+	hi, lo := bits.Mul64(abs(d.units), decimalUnit)
+
+	if hi >= divisor {
+		return Decimal{}, fmt.Errorf("%w dividing %v by %v", ErrDecimalOverflow, d, o)
+	}
+
+	quotient, remainder := bits.Div64(hi, lo, divisor)
+
+	if remainder >= divisor-remainder {
+		quotient++
+	}
+
+	if quotient > math.MaxInt64 {
+		return Decimal{}, fmt.Errorf("%w dividing %v by %v", ErrDecimalOverflow, d, o)
+	}
+
+	if negative {
+		return Decimal{-int64(quotient)}, nil
+	}
+
+	return Decimal{int64(quotient)}, nil
+}
+
+// MulDivChecked returns d * n / o rounded to DecimalPlaces without rounding or overflowing the intermediate product,
+// ErrDecimalDivisionByZero when o is zero or ErrDecimalOverflow when the result does not fit in a decimal
+func (d Decimal) MulDivChecked(n, o Decimal) (Decimal, error) {
+	if o.units == 0 {
+		return Decimal{}, ErrDecimalDivisionByZero
+	}
+
+	negative := (d.units < 0) != (n.units < 0) != (o.units < 0)
+	divisor := abs(o.units)
+
+	hi, lo := bits.Mul64(abs(d.units), abs(n.units))
+
+	if hi >= divisor {
+		return Decimal{}, fmt.Errorf("%w multiplying %v by %v and dividing by %v", ErrDecimalOverflow, d, n, o)
+	}
+
+	quotient, remainder := bits.Div64(hi, lo, divisor)
+
+	if remainder >= divisor-remainder {
+		quotient++
+	}
+
+	if quotient > math.MaxInt64 {
+		return Decimal{}, fmt.Errorf("%w multiplying %v by %v and dividing by %v", ErrDecimalOverflow, d, n, o)
+	}
+
+	if negative {
+		return Decimal{-int64(quotient)}, nil
+	}
+
+	return Decimal{int64(quotient)}, nil
+}
+
+// MulFloat returns d multiplied by a ratio like a percentage or a fee rate
+func (d Decimal) MulFloat(ratio float64) Decimal {
+	return Decimal{int64(math.Round(float64(d.units) * ratio))}
+}
+
+// Cmp returns -1, 0 or 1 when d is lower, equal or greater than o
+func (d Decimal) Cmp(o Decimal) int {
+	if d.units < o.units {
+		return -1
+	} else if d.units > o.units {
+		return 1
+	}
+
+	return 0
+}
+
+// LessThan returns whether d is lower than o
+func (d Decimal) LessThan(o Decimal) bool {
+	return d.units < o.units
+}
+
+// GreaterThan returns whether d is greater than o
+func (d Decimal) GreaterThan(o Decimal) bool {
+	return d.units > o.units
+}
+
+// IsZero returns whether d is zero
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+// IsNegative returns whether d is lower than zero
+func (d Decimal) IsNegative() bool {
+	return d.units < 0
+}
+
+// Truncate drops the digits after the decimal places passed by argument
+func (d Decimal) Truncate(places int) Decimal {
+	if places >= DecimalPlaces {
+		return d
+	}
+
+	step := int64(math.Pow10(DecimalPlaces - places))
+
+	return Decimal{d.units / step * step}
+}
+
+// Float64 returns the closest float64 of d
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / decimalUnit
+}
+
+// Float32 returns the closest float32 of d
+func (d Decimal) Float32() float32 {
+	return float32(d.Float64())
+}
+
+// String returns d without trailing zeros, e.g. 0.0012
+func (d Decimal) String() string {
+	s := d.StringFixed(DecimalPlaces)
+
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+
+	return s
+}
+
+// StringFixed returns d rounded to the decimal places passed by argument with every place written, e.g. 0.10
+func (d Decimal) StringFixed(places int) string {
+	if places > DecimalPlaces {
+		places = DecimalPlaces
+	}
+
+	if places < 0 {
+		places = 0
+	}
+
+	step := uint64(math.Pow10(DecimalPlaces - places))
+	units := abs(d.units)
+	units = (units + step/2) / step
+
+	sign := ""
+
+	if d.units < 0 && units > 0 {
+		sign = "-"
+	}
+
+	integer := strconv.FormatUint(units/uint64(math.Pow10(places)), 10)
+
+	if places == 0 {
+		return sign + integer
+	}
+
+	fraction := strconv.FormatUint(units%uint64(math.Pow10(places)), 10)
+
+	return sign + integer + "." + strings.Repeat("0", places-len(fraction)) + fraction
+}
+
+// MarshalJSON encodes d as a JSON number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or string
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+
+	if s == "null" || s == "" {
+		*d = Decimal{}
+		return nil
+	}
+
+	value, err := ParseDecimal(s)
+
+	if err != nil {
+		return err
+	}
+
+	*d = value
+
+	return nil
+}
+
+// MarshalBSONValue encodes d as a decimal128
+func (d Decimal) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	value, err := primitive.ParseDecimal128(d.String())
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return bsontype.Decimal128, bsoncore.AppendDecimal128(nil, value), nil
+}
+
+// UnmarshalBSONValue decodes a decimal128 or the doubles and integers stored before decimals were used
+func (d *Decimal) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bsoncore.Value{Type: t, Data: data}
+
+	switch t {
+	case bsontype.Decimal128:
+		d128, ok := value.Decimal128OK()
+
+		if !ok {
+			return fmt.Errorf("invalid decimal128")
+		}
+
+		parsed, err := ParseDecimal(d128.String())
+
+		if err != nil {
+			return err
+		}
+
+		*d = parsed
+	case bsontype.Double:
+		*d = DecimalFromDouble(value.Double())
+	case bsontype.Int32:
+		*d = NewDecimalFromInt(int64(value.Int32()))
+	case bsontype.Int64:
+		*d = NewDecimalFromInt(value.Int64())
+	case bsontype.Null, bsontype.Undefined:
+		*d = Decimal{}
+	default:
+		return fmt.Errorf("can't decode %v into a decimal", t)
+	}
+
+	return nil
+}
+
+// DecimalFromDouble returns the decimal of a double stored on the database.
+// Doubles written from float32 fields keep their shortest float32 representation.
+func DecimalFromDouble(value float64) Decimal {
+	if float64(float32(value)) == value {
+		return NewDecimalFromFloat32(float32(value))
+	}
+
+	return NewDecimal(value)
+}
+
+func abs(n int64) uint64 {
+	if n < 0 {
+		return uint64(-n)
+	}
+
+	return uint64(n)
+}
@@ -0,0 +1,164 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDecimal(t *testing.T) {
+	t.Run("should parse and format without losing precision", func(t *testing.T) {
+		for _, s := range []string{"0.00000001", "0.3", "-12.5", "43210.12345678", "5000"} {
+			d, err := domain.ParseDecimal(s)
+
+			if err != nil {
+				t.Fatalf("Not expected ParseDecimal to return error: %v", err)
+			}
+
+			if got := d.String(); got != s {
+				t.Errorf("got %v want %v", got, s)
+			}
+		}
+	})
+
+	t.Run("should parse exponents and round extra digits", func(t *testing.T) {
+		d, _ := domain.ParseDecimal("1.5E-7")
+		rounded, _ := domain.ParseDecimal("0.123456785")
+
+		if d.String() != "0.00000015" || rounded.String() != "0.12345679" {
+			t.Errorf("got %v and %v want 0.00000015 and 0.12345679", d, rounded)
+		}
+	})
+
+	t.Run("should keep the shortest representation of float32", func(t *testing.T) {
+		if got := domain.NewDecimalFromFloat32(0.1).String(); got != "0.1" {
+			t.Errorf("got %v want 0.1", got)
+		}
+	})
+
+	t.Run("should add, multiply and divide", func(t *testing.T) {
+		a, b := domain.NewDecimal(0.1), domain.NewDecimal(0.2)
+
+		if got := a.Add(b); got != domain.NewDecimal(0.3) {
+			t.Errorf("got %v want 0.3", got)
+		}
+
+		if got := domain.NewDecimal(0.00012345).Mul(domain.NewDecimal(43210.5)); got.String() != "5.33433623" {
+			t.Errorf("got %v want 5.33433623", got)
+		}
+
+		if got := domain.NewDecimalFromInt(100).Div(domain.NewDecimal(-3)); got.String() != "-33.33333333" {
+			t.Errorf("got %v want -33.33333333", got)
+		}
+	})
+
+	t.Run("should return error when the result overflows", func(t *testing.T) {
+		amount, price := domain.NewDecimalFromInt(1000000), domain.NewDecimalFromInt(1000000)
+
+		if _, err := amount.MulChecked(price); !errors.Is(err, domain.ErrDecimalOverflow) {
+			t.Errorf("got %v want %v", err, domain.ErrDecimalOverflow)
+		}
+
+		if _, err := domain.NewDecimalFromInt(-90000000000).MulChecked(domain.NewDecimal(1.5)); !errors.Is(err, domain.ErrDecimalOverflow) {
+			t.Errorf("got %v want %v", err, domain.ErrDecimalOverflow)
+		}
+
+		if _, err := domain.NewDecimalFromInt(90000000000).DivChecked(domain.NewDecimal(0.5)); !errors.Is(err, domain.ErrDecimalOverflow) {
+			t.Errorf("got %v want %v", err, domain.ErrDecimalOverflow)
+		}
+
+		if _, err := amount.DivChecked(domain.Decimal{}); err != domain.ErrDecimalDivisionByZero {
+			t.Errorf("got %v want %v", err, domain.ErrDecimalDivisionByZero)
+		}
+
+		if got, err := domain.NewDecimalFromInt(90000).MulChecked(domain.NewDecimalFromInt(1000000)); err != nil || got.String() != "90000000000" {
+			t.Errorf("got %v and %v want 90000000000", got, err)
+		}
+	})
+
+	t.Run("should multiply and divide without overflowing the intermediate product", func(t *testing.T) {
+		cost, amount, held := domain.NewDecimalFromInt(100000), domain.NewDecimalFromInt(1000000), domain.NewDecimalFromInt(3000000)
+
+		if got, err := cost.MulDivChecked(amount, held); err != nil || got.String() != "33333.33333333" {
+			t.Errorf("got %v and %v want 33333.33333333", got, err)
+		}
+
+		if got, err := cost.MulDivChecked(amount.Neg(), held); err != nil || got.String() != "-33333.33333333" {
+			t.Errorf("got %v and %v want -33333.33333333", got, err)
+		}
+
+		if _, err := cost.MulDivChecked(amount, domain.NewDecimal(0.001)); !errors.Is(err, domain.ErrDecimalOverflow) {
+			t.Errorf("got %v want %v", err, domain.ErrDecimalOverflow)
+		}
+
+		if _, err := cost.MulDivChecked(amount, domain.Decimal{}); err != domain.ErrDecimalDivisionByZero {
+			t.Errorf("got %v want %v", err, domain.ErrDecimalDivisionByZero)
+		}
+	})
+
+	t.Run("should panic when the result of Mul overflows", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected Mul to panic")
+			}
+		}()
+
+		domain.NewDecimalFromInt(1000000).Mul(domain.NewDecimalFromInt(1000000))
+	})
+
+	t.Run("should truncate and format with fixed places", func(t *testing.T) {
+		d := domain.NewDecimal(9123.4567)
+
+		if got := d.Truncate(1).StringFixed(1); got != "9123.4" {
+			t.Errorf("got %v want 9123.4", got)
+		}
+
+		if got := d.StringFixed(2); got != "9123.46" {
+			t.Errorf("got %v want 9123.46", got)
+		}
+
+		if got := domain.NewDecimal(0.5).StringFixed(8); got != "0.50000000" {
+			t.Errorf("got %v want 0.50000000", got)
+		}
+	})
+
+	t.Run("should encode json numbers and decode numbers and strings", func(t *testing.T) {
+		data, _ := json.Marshal(struct{ Amount domain.Decimal }{domain.NewDecimal(0.001)})
+
+		if string(data) != `{"Amount":0.001}` {
+			t.Errorf("got %s want {\"Amount\":0.001}", data)
+		}
+
+		var got struct{ A, B domain.Decimal }
+		json.Unmarshal([]byte(`{"A":1.25,"B":"0.00000002"}`), &got)
+
+		if got.A != domain.NewDecimal(1.25) || got.B != domain.NewDecimal(0.00000002) {
+			t.Errorf("got %+v want 1.25 and 0.00000002", got)
+		}
+	})
+
+	t.Run("should encode bson decimals and decode the doubles stored before", func(t *testing.T) {
+		data, _ := bson.Marshal(bson.M{"amount": domain.NewDecimal(0.3)})
+
+		var got struct{ Amount domain.Decimal }
+		bson.Unmarshal(data, &got)
+
+		if got.Amount != domain.NewDecimal(0.3) {
+			t.Errorf("got %v want 0.3", got.Amount)
+		}
+
+		if bson.Raw(data).Lookup("amount").Type != bson.TypeDecimal128 {
+			t.Errorf("Expected amount to be stored as decimal128")
+		}
+
+		data, _ = bson.Marshal(bson.M{"amount": float64(float32(0.3))})
+		bson.Unmarshal(data, &got)
+
+		if got.Amount != domain.NewDecimal(0.3) {
+			t.Errorf("got %v want 0.3", got.Amount)
+		}
+	})
+}
@@ -1,11 +1,43 @@
 package domain
 
+import (
+	"fmt"
+	"strconv"
+)
+
 type Env struct {
 	MongoURL                    string
 	MongoDB                     string
 	NotificationsReceiver       string
 	NotificationsSender         string
 	NotificationsSenderPassword string
+	NotificationsSMTPHost       string
+	NotificationsSMTPPort       string
+	NotificationsSMTPTLS        string
 	AppEnv                      string
 	AppID                       string
+	Storage                     string
+	BoltPath                    string
+}
+
+// NotificationOptions returns the email notification options of the environment
+func (e Env) NotificationOptions() (NotificationOptions, error) {
+	options := NotificationOptions{
+		Receiver:       e.NotificationsReceiver,
+		Sender:         e.NotificationsSender,
+		SenderPassword: e.NotificationsSenderPassword,
+		SMTP:           SMTPOptions{Host: e.NotificationsSMTPHost, TLS: e.NotificationsSMTPTLS},
+	}
+
+	if e.NotificationsSMTPPort != "" {
+		port, err := strconv.Atoi(e.NotificationsSMTPPort)
+
+		if err != nil {
+			return options, fmt.Errorf("smtp port %v is not a number", e.NotificationsSMTPPort)
+		}
+
+		options.SMTP.Port = port
+	}
+
+	return options, options.Validate()
 }
@@ -0,0 +1,26 @@
+package domain
+
+// Exchanges supported, they are the brokers of the accounts
+const (
+	ExchangeKraken  = "kraken"
+	ExchangeBinance = "binance"
+)
+
+// SymbolMapper converts the asset codes of an exchange, e.g. XXBT, to asset symbols, e.g. BTC.
+// Pairs are returned by the markets service.
+type SymbolMapper interface {
+	// Asset returns the symbol of an exchange asset code
+	Asset(code string) string
+}
+
+// exchangeFeeRates are the taker fees of the lowest volume tier of the exchanges, an empty exchange is kraken
+var exchangeFeeRates = map[string]float64{
+	"":              0.0026,
+	ExchangeKraken:  0.0026,
+	ExchangeBinance: 0.001,
+}
+
+// ExchangeFeeRate returns the ratio of the value of an order paid to the exchange, it is zero for unknown exchanges
+func ExchangeFeeRate(exchange string) float64 {
+	return exchangeFeeRates[exchange]
+}
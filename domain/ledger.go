@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LedgerEntryType is the reason of a ledger entry
+type LedgerEntryType string
+
+const (
+	// LedgerDeposit is money added to the account
+	LedgerDeposit LedgerEntryType = "deposit"
+	// LedgerWithdrawal is money taken from the account
+	LedgerWithdrawal LedgerEntryType = "withdrawal"
+	// LedgerBuy is money spent buying an asset
+	LedgerBuy LedgerEntryType = "buy"
+	// LedgerSell is money received selling an asset
+	LedgerSell LedgerEntryType = "sell"
+	// LedgerFee is money paid to the exchange
+	LedgerFee LedgerEntryType = "fee"
+	// LedgerDCAPurchase is money spent buying an asset on a dca job
+	LedgerDCAPurchase LedgerEntryType = "dca-purchase"
+	// LedgerAdjustment is a manual correction of the balance, negative amounts decrease it
+	LedgerAdjustment LedgerEntryType = "adjustment"
+)
+
+// Ledger accounts debited and credited by the entries
+const (
+	LedgerCash     = "cash"
+	LedgerAssets   = "assets"
+	LedgerFees     = "fees"
+	LedgerExternal = "external"
+)
+
+// LedgerEntry is an immutable movement of money between two ledger accounts of an exchange account.
+// The account balance is the sum of the cash debits minus the cash credits.
+type LedgerEntry struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	AccountID   primitive.ObjectID `bson:"accountId" json:"accountId"`
+	Type        LedgerEntryType    `bson:"type" json:"type"`
+	Debit       string             `bson:"debit" json:"debit"`
+	Credit      string             `bson:"credit" json:"credit"`
+	Amount      Decimal            `bson:"amount" json:"amount"`
+	AssetID     primitive.ObjectID `bson:"assetId,omitempty" json:"assetId,omitempty"`
+	OrderID     string             `bson:"orderId,omitempty" json:"orderId,omitempty"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Date        time.Time          `bson:"date" json:"date"`
+}
+
+// NewLedgerEntry returns an entry of the type passed by argument debiting and crediting the ledger accounts of that type
+func NewLedgerEntry(accountID primitive.ObjectID, entryType LedgerEntryType, amount Decimal, date time.Time) *LedgerEntry {
+	debit, credit := LedgerCash, LedgerExternal
+
+	switch entryType {
+	case LedgerWithdrawal:
+		debit, credit = LedgerExternal, LedgerCash
+	case LedgerBuy, LedgerDCAPurchase:
+		debit, credit = LedgerAssets, LedgerCash
+	case LedgerSell:
+		debit, credit = LedgerCash, LedgerAssets
+	case LedgerFee:
+		debit, credit = LedgerFees, LedgerCash
+	}
+
+	if amount.IsNegative() {
+		debit, credit, amount = credit, debit, amount.Neg()
+	}
+
+	return &LedgerEntry{
+		ID:        primitive.NewObjectID(),
+		AccountID: accountID,
+		Type:      entryType,
+		Debit:     debit,
+		Credit:    credit,
+		Amount:    amount,
+		Date:      date,
+	}
+}
+
+// CashAmount returns how much the entry changes the account balance
+func (e *LedgerEntry) CashAmount() Decimal {
+	var amount Decimal
+
+	if e.Debit == LedgerCash {
+		amount = amount.Add(e.Amount)
+	}
+
+	if e.Credit == LedgerCash {
+		amount = amount.Sub(e.Amount)
+	}
+
+	return amount
+}
+
+// LedgerRepository stores and fetches ledger entries. Entries are never updated or deleted.
+type LedgerRepository interface {
+	Create(entry *LedgerEntry) error
+	FindAll(accountID string, startDate, endDate time.Time) (*[]LedgerEntry, error)
+	GetBalance(accountID string) (Decimal, error)
+}
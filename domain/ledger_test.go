@@ -0,0 +1,40 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLedgerEntry(t *testing.T) {
+	cases := []struct {
+		entryType domain.LedgerEntryType
+		amount    float32
+		want      float32
+	}{
+		{domain.LedgerDeposit, 100, 100},
+		{domain.LedgerWithdrawal, 100, -100},
+		{domain.LedgerBuy, 100, -100},
+		{domain.LedgerSell, 100, 100},
+		{domain.LedgerFee, 1, -1},
+		{domain.LedgerDCAPurchase, 50, -50},
+		{domain.LedgerAdjustment, 20, 20},
+		{domain.LedgerAdjustment, -20, -20},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.entryType)+" should change the cash balance", func(t *testing.T) {
+			entry := domain.NewLedgerEntry(primitive.NewObjectID(), c.entryType, domain.NewDecimalFromFloat32(c.amount), time.Now())
+
+			if got, want := entry.CashAmount(), domain.NewDecimalFromFloat32(c.want); got != want {
+				t.Errorf("got %v want %v", got, want)
+			}
+
+			if entry.Amount.IsNegative() {
+				t.Errorf("Expected entry amount to be positive, got %v", entry.Amount)
+			}
+		})
+	}
+}
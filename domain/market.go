@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Market is a pair traded in an exchange
+type Market struct {
+	Exchange string `json:"exchange"`
+	// Base is the symbol of the asset traded, e.g. BTC, and Quote is the currency it is priced in, e.g. EUR
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	// Symbol is the pair used to place orders, e.g. XXBTZEUR, and WebsocketName is the pair of the prices stream, e.g. XBT/EUR
+	Symbol        string `json:"symbol"`
+	WebsocketName string `json:"websocketName"`
+	// PriceDecimals and LotDecimals are the decimal places accepted on the price and on the volume of the orders
+	PriceDecimals int     `json:"priceDecimals"`
+	LotDecimals   int     `json:"lotDecimals"`
+	MinOrderSize  Decimal `json:"minOrderSize"`
+}
+
+// MarketsService returns the markets of the exchanges
+type MarketsService interface {
+	// Market returns the market of the base asset and quote currency, or an error when the exchange does not trade it
+	Market(exchange, base, quote string) (*Market, error)
+	Markets(exchange string) ([]Market, error)
+}
+
+// Quote currencies supported
+const (
+	QuoteEUR  = "EUR"
+	QuoteUSD  = "USD"
+	QuoteUSDT = "USDT"
+	QuoteBTC  = "BTC"
+)
+
+// DefaultQuote is the quote currency of the applications and dca jobs that do not set one
+const DefaultQuote = QuoteEUR
+
+// ValidateQuote returns an error when the quote currency is not supported
+func ValidateQuote(quote string) error {
+	switch quote {
+	case QuoteEUR, QuoteUSD, QuoteUSDT, QuoteBTC:
+		return nil
+	default:
+		return fmt.Errorf("quote currency %v is not supported", quote)
+	}
+}
+
+// PriceSymbol returns the symbol the prices of an asset in a quote currency are stored with.
+// Euro prices use the asset symbol, as they did before quote currencies were configurable, and other prices use asset/quote, e.g. BTC/USD.
+func PriceSymbol(asset, quote string) string {
+	if quote == "" || quote == QuoteEUR {
+		return asset
+	}
+
+	return asset + "/" + quote
+}
+
+// SplitPriceSymbol returns the asset and the quote currency of a price symbol
+func SplitPriceSymbol(symbol string) (string, string) {
+	if i := strings.Index(symbol, "/"); i >= 0 {
+		return symbol[:i], symbol[i+1:]
+	}
+
+	return symbol, QuoteEUR
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// MarketDataStats are the statistics of the subscription of the market data hub to the prices of an asset in a quote currency of an exchange
+type MarketDataStats struct {
+	Exchange string `bson:"exchange" json:"exchange"`
+	Quote    string `bson:"quote" json:"quote"`
+	Asset    string `bson:"asset" json:"asset"`
+	// Interval is the duration of the candles in minutes
+	Interval    int `bson:"interval" json:"interval"`
+	Subscribers int `bson:"subscribers" json:"subscribers"`
+	// Candles is the number of candles received and stored
+	Candles    int       `bson:"candles" json:"candles"`
+	LastCandle time.Time `bson:"lastCandle" json:"lastCandle"`
+	StartedAt  time.Time `bson:"startedAt" json:"startedAt"`
+	// Running is false when the collector stopped, e.g. the websocket connection was closed, until it is restarted
+	Running bool `bson:"running" json:"running"`
+	// Restarts is the number of times the collector was restarted
+	Restarts  int       `bson:"restarts" json:"restarts"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MarketDataStatsRepository stores the statistics of the market data hub subscriptions
+type MarketDataStatsRepository interface {
+	// Save replaces the statistics stored
+	Save(stats []MarketDataStats) error
+	FindAll() (*[]MarketDataStats, error)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Migration is a versioned change to the database that is applied once
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(repositories RepositoryFactory) error
+}
+
+// MigrationRecord is the record of a migration applied to the database
+type MigrationRecord struct {
+	ID          primitive.ObjectID `bson:"_id" json:"_id"`
+	Version     int                `bson:"version" json:"version"`
+	Description string             `bson:"description" json:"description"`
+	AppliedAt   time.Time          `bson:"appliedAt" json:"appliedAt"`
+}
+
+// MigrationStatus tells whether a migration was applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
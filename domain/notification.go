@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -20,17 +21,204 @@ type Notification struct {
 	ApplicationID       primitive.ObjectID `bson:"applicationID" json:"applicationID"`
 }
 
+// Names of the notification channels
+const (
+	NotificationEmail    = "email"
+	NotificationWebhook  = "webhook"
+	NotificationSlack    = "slack"
+	NotificationTelegram = "telegram"
+)
+
+// TLS modes of the SMTP servers
+const (
+	// SMTPStartTLS upgrades the connection to TLS
+	SMTPStartTLS = "starttls"
+	// SMTPImplicitTLS connects with TLS, usually on port 465
+	SMTPImplicitTLS = "tls"
+)
+
 // NotificationOptions has notifications service options
 type NotificationOptions struct {
 	Receiver       string
 	Sender         string
 	SenderPassword string
+	SMTP           SMTPOptions      `bson:"smtp,omitempty" json:"smtp,omitempty"`
+	Webhook        *WebhookOptions  `bson:"webhook,omitempty" json:"webhook,omitempty"`
+	Slack          *SlackOptions    `bson:"slack,omitempty" json:"slack,omitempty"`
+	Telegram       *TelegramOptions `bson:"telegram,omitempty" json:"telegram,omitempty"`
+	// Channels are the channels of every notification, email when empty
+	Channels []string `bson:"channels,omitempty" json:"channels,omitempty"`
+	// Routes are the channels by notification type, types without a route are sent by the channels
+	Routes map[string][]string `bson:"routes,omitempty" json:"routes,omitempty"`
+}
+
+// SMTPOptions is the server of the email channel, gmail with starttls when empty
+type SMTPOptions struct {
+	Host string `bson:"host,omitempty" json:"host,omitempty"`
+	Port int    `bson:"port,omitempty" json:"port,omitempty"`
+	TLS  string `bson:"tls,omitempty" json:"tls,omitempty"`
+}
+
+// WebhookOptions is the URL notifications are posted to, signed with the secret when it is set
+type WebhookOptions struct {
+	URL    string `bson:"url" json:"url"`
+	Secret string `bson:"secret,omitempty" json:"secret,omitempty"`
+}
+
+// SlackOptions is the incoming webhook of a Slack channel
+type SlackOptions struct {
+	WebhookURL string `bson:"webhookURL" json:"webhookURL"`
+}
+
+// TelegramOptions is the bot sending notifications to a chat. APIURL is the bot API, https://api.telegram.org when empty.
+type TelegramOptions struct {
+	BotToken string `bson:"botToken" json:"botToken"`
+	ChatID   string `bson:"chatID" json:"chatID"`
+	APIURL   string `bson:"apiURL,omitempty" json:"apiURL,omitempty"`
+}
+
+// RedactedSecret replaces the secrets of notification options that are stored or returned without them
+const RedactedSecret = "[redacted]"
+
+// Redacted returns a copy of the options with the sender password, webhook secret, Slack webhook and Telegram bot token redacted
+func (o NotificationOptions) Redacted() NotificationOptions {
+	o.SenderPassword = redact(o.SenderPassword)
+
+	if o.Webhook != nil {
+		webhook := *o.Webhook
+		webhook.Secret = redact(webhook.Secret)
+		o.Webhook = &webhook
+	}
+
+	if o.Slack != nil {
+		slack := *o.Slack
+		slack.WebhookURL = redact(slack.WebhookURL)
+		o.Slack = &slack
+	}
+
+	if o.Telegram != nil {
+		telegram := *o.Telegram
+		telegram.BotToken = redact(telegram.BotToken)
+		o.Telegram = &telegram
+	}
+
+	return o
+}
+
+// WithSecrets returns a copy of the options with the redacted secrets replaced by the secrets of the options passed by argument
+func (o NotificationOptions) WithSecrets(secrets NotificationOptions) NotificationOptions {
+	if o.SenderPassword == RedactedSecret {
+		o.SenderPassword = secrets.SenderPassword
+	}
+
+	if o.Webhook != nil && o.Webhook.Secret == RedactedSecret {
+		webhook := *o.Webhook
+		webhook.Secret = ""
+
+		if secrets.Webhook != nil {
+			webhook.Secret = secrets.Webhook.Secret
+		}
+
+		o.Webhook = &webhook
+	}
+
+	if o.Slack != nil && o.Slack.WebhookURL == RedactedSecret {
+		slack := *o.Slack
+		slack.WebhookURL = ""
+
+		if secrets.Slack != nil {
+			slack.WebhookURL = secrets.Slack.WebhookURL
+		}
+
+		o.Slack = &slack
+	}
+
+	if o.Telegram != nil && o.Telegram.BotToken == RedactedSecret {
+		telegram := *o.Telegram
+		telegram.BotToken = ""
+
+		if secrets.Telegram != nil {
+			telegram.BotToken = secrets.Telegram.BotToken
+		}
+
+		o.Telegram = &telegram
+	}
+
+	return o
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return RedactedSecret
+}
+
+// GetChannels returns the channels of a notification type
+func (o NotificationOptions) GetChannels(notificationType string) []string {
+	if channels, ok := o.Routes[notificationType]; ok {
+		return channels
+	}
+
+	if len(o.Channels) == 0 {
+		return []string{NotificationEmail}
+	}
+
+	return o.Channels
+}
+
+// Validate returns error when a channel of the options is not configured or the SMTP TLS mode is not supported
+func (o NotificationOptions) Validate() error {
+	switch o.SMTP.TLS {
+	case "", SMTPStartTLS, SMTPImplicitTLS:
+	default:
+		return fmt.Errorf("smtp tls %v is not starttls or tls", o.SMTP.TLS)
+	}
+
+	channels := append([]string{}, o.Channels...)
+
+	for _, route := range o.Routes {
+		channels = append(channels, route...)
+	}
+
+	for _, channel := range channels {
+		configured := false
+
+		switch channel {
+		case NotificationEmail:
+			configured = true
+		case NotificationWebhook:
+			configured = o.Webhook != nil && o.Webhook.URL != ""
+		case NotificationSlack:
+			configured = o.Slack != nil && o.Slack.WebhookURL != ""
+		case NotificationTelegram:
+			configured = o.Telegram != nil && o.Telegram.BotToken != "" && o.Telegram.ChatID != ""
+		default:
+			return fmt.Errorf("notification channel %v does not exist", channel)
+		}
+
+		if !configured {
+			return fmt.Errorf("notification channel %v is not configured", channel)
+		}
+	}
+
+	return nil
+}
+
+// NotificationChannel sends notifications
+type NotificationChannel interface {
+	// Receiver is who the channel sends the notifications to
+	Receiver() string
+	Send(notification *Notification) error
 }
 
 // NotificationsService interacts with notifications
 type NotificationsService interface {
 	FindLastEventLogsNotificationDate() (time.Time, error)
 	CreateEmailNotification(subject, message, notificationType string) error
+	// Notify stores a notification of each channel of the notification type and sends it
+	Notify(subject, message, notificationType string) error
 	ShouldSendNotification() bool
 	BulkDeleteByApplicationID(id string) error
 	SendEmail(subject, body string) error
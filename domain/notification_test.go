@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestNotificationOptions(t *testing.T) {
+	t.Run("should route the notification types to their channels", func(t *testing.T) {
+		options := domain.NotificationOptions{
+			Channels: []string{domain.NotificationTelegram},
+			Routes:   map[string][]string{"eventlogs": {domain.NotificationSlack}},
+		}
+
+		if got := options.GetChannels("eventlogs"); len(got) != 1 || got[0] != domain.NotificationSlack {
+			t.Errorf("got %v want slack", got)
+		}
+
+		if got := options.GetChannels("dca"); len(got) != 1 || got[0] != domain.NotificationTelegram {
+			t.Errorf("got %v want telegram", got)
+		}
+
+		if got := (domain.NotificationOptions{}).GetChannels("dca"); len(got) != 1 || got[0] != domain.NotificationEmail {
+			t.Errorf("got %v want email", got)
+		}
+	})
+
+	t.Run("should validate the channels are configured", func(t *testing.T) {
+		cases := map[string]struct {
+			options domain.NotificationOptions
+			valid   bool
+		}{
+			"email by default":            {domain.NotificationOptions{}, true},
+			"configured slack":            {domain.NotificationOptions{Channels: []string{domain.NotificationSlack}, Slack: &domain.SlackOptions{WebhookURL: "https://hooks.slack.com/services/T0/B0/X"}}, true},
+			"telegram without chat":       {domain.NotificationOptions{Routes: map[string][]string{"eventlogs": {domain.NotificationTelegram}}, Telegram: &domain.TelegramOptions{BotToken: "123:token"}}, false},
+			"webhook not configured":      {domain.NotificationOptions{Channels: []string{domain.NotificationWebhook}}, false},
+			"channel that does not exist": {domain.NotificationOptions{Channels: []string{"sms"}}, false},
+			"smtp tls not supported":      {domain.NotificationOptions{SMTP: domain.SMTPOptions{TLS: "ssl"}}, false},
+			"smtp implicit tls":           {domain.NotificationOptions{SMTP: domain.SMTPOptions{TLS: domain.SMTPImplicitTLS}}, true},
+		}
+
+		for name, c := range cases {
+			if err := c.options.Validate(); (err == nil) != c.valid {
+				t.Errorf("%v: got error %v want valid %v", name, err, c.valid)
+			}
+		}
+	})
+}
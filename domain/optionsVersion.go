@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OptionsChange is an option changed by a version, fields are the JSON paths of the options, e.g. decisionMakerOptions.minimumProfitPerSold
+type OptionsChange struct {
+	Field string      `bson:"field" json:"field"`
+	From  interface{} `bson:"from" json:"from"`
+	To    interface{} `bson:"to" json:"to"`
+}
+
+// OptionsVersion is the options of an application since a date and what changed from the previous version.
+// The secrets of the notification options are redacted.
+type OptionsVersion struct {
+	ID            primitive.ObjectID `bson:"_id" json:"_id"`
+	ApplicationID primitive.ObjectID `bson:"applicationId" json:"applicationId"`
+	Version       int                `bson:"version" json:"version"`
+	Options       ApplicationOptions `bson:"options" json:"options"`
+	// Author is the updatedBy of the application when the version was created
+	Author  string          `bson:"author,omitempty" json:"author,omitempty"`
+	Date    time.Time       `bson:"date" json:"date"`
+	Changes []OptionsChange `bson:"changes" json:"changes"`
+}
+
+// Redacted returns a copy of the options with the secrets of the notification options redacted
+func (o ApplicationOptions) Redacted() ApplicationOptions {
+	o.NotificationOptions = o.NotificationOptions.Redacted()
+
+	return o
+}
+
+// secretFields are the fields of the changes with secrets of the notification options
+var secretFields = map[string]bool{
+	"notificationOptions.SenderPassword":    true,
+	"notificationOptions.webhook.secret":    true,
+	"notificationOptions.slack.webhookURL":  true,
+	"notificationOptions.telegram.botToken": true,
+}
+
+// Redacted returns a copy of the version without secrets, versions recorded before secrets were redacted store them
+func (v OptionsVersion) Redacted() OptionsVersion {
+	v.Options = v.Options.Redacted()
+	changes := make([]OptionsChange, len(v.Changes))
+
+	for i, change := range v.Changes {
+		if secretFields[change.Field] {
+			change.From, change.To = redactValue(change.From), redactValue(change.To)
+		}
+
+		changes[i] = change
+	}
+
+	v.Changes = changes
+
+	return v
+}
+
+func redactValue(value interface{}) interface{} {
+	if value == nil || value == "" {
+		return value
+	}
+
+	return RedactedSecret
+}
+
+// OptionsVersionsRepository stores the options versions of applications
+type OptionsVersionsRepository interface {
+	Create(version *OptionsVersion) error
+	// FindLast returns nil when the application does not have versions
+	FindLast(applicationID string) (*OptionsVersion, error)
+	FindByVersion(applicationID string, version int) (*OptionsVersion, error)
+	FindAll(applicationID string) (*[]OptionsVersion, error)
+}
+
+// OptionsVersionsService versions the options of applications
+type OptionsVersionsService interface {
+	// Record returns the last version of the application options, a new one when they changed
+	Record(application *Application) (*OptionsVersion, error)
+	FindAll(applicationID string) (*[]OptionsVersion, error)
+	// Update sets the options of the application and records them as a new version of the author
+	Update(applicationID string, options ApplicationOptions, author string) (*OptionsVersion, error)
+	// Rollback sets the options of a version on the application like Update
+	Rollback(applicationID string, version int, author string) (*OptionsVersion, error)
+}
+
+// DiffOptions returns the options changed between two options sorted by field.
+// Secrets are compared redacted so they are never in the changes, changing only a secret is not a change.
+func DiffOptions(from, to ApplicationOptions) ([]OptionsChange, error) {
+	fromFields, err := optionsFields(from.Redacted())
+
+	if err != nil {
+		return nil, err
+	}
+
+	toFields, err := optionsFields(to.Redacted())
+
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []OptionsChange{}
+
+	for field, value := range toFields {
+		if previous, ok := fromFields[field]; !ok || !reflect.DeepEqual(previous, value) {
+			changes = append(changes, OptionsChange{Field: field, From: fromFields[field], To: value})
+		}
+	}
+
+	for field, value := range fromFields {
+		if _, ok := toFields[field]; !ok {
+			changes = append(changes, OptionsChange{Field: field, From: value})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+
+	return changes, nil
+}
+
+// optionsFields returns the JSON values of the options by path
+func optionsFields(options ApplicationOptions) (map[string]interface{}, error) {
+	document, err := json.Marshal(options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+
+	if err := json.Unmarshal(document, &values); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	flattenFields("", values, fields)
+
+	return fields, nil
+}
+
+func flattenFields(prefix string, values map[string]interface{}, fields map[string]interface{}) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenFields(key, nested, fields)
+			continue
+		}
+
+		fields[key] = value
+	}
+}
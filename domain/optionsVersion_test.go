@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestDiffOptions(t *testing.T) {
+	from := domain.ApplicationOptions{
+		DecisionMakerOptions: domain.DecisionMakerOptions{MinimumProfitPerSold: 0.01, MaximumFIATBuyAmount: 500},
+		StatisticsOptions:    domain.StatisticsOptions{NumberOfPointsHold: 5000},
+	}
+
+	t.Run("should return the fields changed sorted by field", func(t *testing.T) {
+		to := from
+		to.DecisionMakerOptions.MaximumFIATBuyAmount = 250
+		to.StatisticsOptions.NumberOfPointsHold = 1000
+
+		got, err := domain.DiffOptions(from, to)
+
+		if err != nil {
+			t.Fatalf("Not expected DiffOptions to return error: %v", err)
+		}
+
+		want := []domain.OptionsChange{
+			{Field: "decisionMakerOptions.maximumFIATBuyAmount", From: float64(500), To: float64(250)},
+			{Field: "statisticsOptions.numberOfPointsHold", From: float64(5000), To: float64(1000)},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("should return no changes for the same options", func(t *testing.T) {
+		got, _ := domain.DiffOptions(from, from)
+
+		if len(got) != 0 {
+			t.Errorf("got %v want no changes", got)
+		}
+	})
+}
+
+func TestOptionsVersionRedacted(t *testing.T) {
+	options := domain.ApplicationOptions{NotificationOptions: domain.NotificationOptions{
+		Webhook: &domain.WebhookOptions{URL: "https://example.com/hooks", Secret: "hmac-secret"},
+	}}
+	version := domain.OptionsVersion{
+		Options: options,
+		Changes: []domain.OptionsChange{{Field: "notificationOptions.webhook.secret", To: "hmac-secret"}},
+	}
+
+	got := version.Redacted()
+
+	if got.Options.Webhook.Secret != domain.RedactedSecret || got.Changes[0].To != domain.RedactedSecret {
+		t.Errorf("got %+v want the webhook secret redacted", got)
+	}
+
+	if options.Webhook.Secret != "hmac-secret" || version.Changes[0].To != "hmac-secret" {
+		t.Errorf("Not expected Redacted to change the version")
+	}
+
+	if changes, _ := domain.DiffOptions(domain.ApplicationOptions{}, options); len(changes) == 0 || changes[0].Field != "notificationOptions.webhook.secret" || changes[0].To != domain.RedactedSecret {
+		t.Errorf("got changes %v want the webhook secret redacted", changes)
+	}
+}
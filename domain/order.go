@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OrderSide is the direction of an order
+type OrderSide string
+
+const (
+	OrderBuy  OrderSide = "buy"
+	OrderSell OrderSide = "sell"
+)
+
+// OrderType decides when and at which price an order is filled
+type OrderType string
+
+const (
+	// OrderMarket is filled at once at the best price of the exchange
+	OrderMarket OrderType = "market"
+	// OrderLimit is filled at the price or better
+	OrderLimit OrderType = "limit"
+	// OrderStopLoss places a market order when the price crosses the stop price against the position
+	OrderStopLoss OrderType = "stop-loss"
+	// OrderStopLimit places a limit order at the price when the price crosses the stop price against the position
+	OrderStopLimit OrderType = "stop-limit"
+	// OrderTakeProfit places a market order when the price crosses the stop price in favour of the position
+	OrderTakeProfit OrderType = "take-profit"
+)
+
+// TimeInForce is how long a limit order stays open
+type TimeInForce string
+
+const (
+	// GoodTillCancelled stays open until it is filled or cancelled, the default
+	GoodTillCancelled TimeInForce = "GTC"
+	// ImmediateOrCancel fills what it can at once and cancels the rest
+	ImmediateOrCancel TimeInForce = "IOC"
+)
+
+// Order is an order placed by a broker for the ticker and quote set on it
+type Order struct {
+	Side   OrderSide
+	Type   OrderType
+	Amount Decimal
+	// Price is the limit price of limit and stop-limit orders
+	Price Decimal
+	// StopPrice is the price that triggers stop-loss, stop-limit and take-profit orders
+	StopPrice   Decimal
+	TimeInForce TimeInForce
+	// PostOnly limit orders are cancelled instead of being filled at once, so they only add liquidity
+	PostOnly bool
+	// ReduceOnly orders only reduce the holdings, spot markets accept them only for sells.
+	// Kraken places them with its reduce_only flag and binance does not support them.
+	ReduceOnly bool
+	// ClientOrderID identifies the order on the exchange so it is not placed twice when it is retried.
+	// Kraken accepts only positive 32 bits integers.
+	ClientOrderID string
+}
+
+// NewLimitOrder returns a good till cancelled limit order
+func NewLimitOrder(side OrderSide, amount, price Decimal) Order {
+	return Order{Side: side, Type: OrderLimit, Amount: amount, Price: price}
+}
+
+// GetTimeInForce returns the time in force of the order, good till cancelled when it is empty
+func (o *Order) GetTimeInForce() TimeInForce {
+	if o.TimeInForce == "" {
+		return GoodTillCancelled
+	}
+
+	return o.TimeInForce
+}
+
+// HasLimitPrice returns true for the orders filled at their price
+func (o *Order) HasLimitPrice() bool {
+	return o.Type == OrderLimit || o.Type == OrderStopLimit
+}
+
+// HasStopPrice returns true for the orders triggered by their stop price
+func (o *Order) HasStopPrice() bool {
+	return o.Type == OrderStopLoss || o.Type == OrderStopLimit || o.Type == OrderTakeProfit
+}
+
+// Validate returns error when the order misses the prices of its type or has options its type does not accept
+func (o *Order) Validate() error {
+	if o.Side != OrderBuy && o.Side != OrderSell {
+		return fmt.Errorf("order side %q is not buy or sell", o.Side)
+	}
+
+	switch o.Type {
+	case OrderMarket, OrderLimit, OrderStopLoss, OrderStopLimit, OrderTakeProfit:
+	default:
+		return fmt.Errorf("order type %q is not supported", o.Type)
+	}
+
+	if !o.Amount.GreaterThan(Decimal{}) {
+		return errors.New("order amount must be positive")
+	}
+
+	if o.HasLimitPrice() && !o.Price.GreaterThan(Decimal{}) {
+		return fmt.Errorf("%v orders must have a price", o.Type)
+	}
+
+	if o.HasStopPrice() && !o.StopPrice.GreaterThan(Decimal{}) {
+		return fmt.Errorf("%v orders must have a stop price", o.Type)
+	}
+
+	switch o.GetTimeInForce() {
+	case GoodTillCancelled, ImmediateOrCancel:
+	default:
+		return fmt.Errorf("time in force %q is not supported", o.TimeInForce)
+	}
+
+	if o.TimeInForce != "" && !o.HasLimitPrice() {
+		return fmt.Errorf("%v orders do not have time in force", o.Type)
+	}
+
+	if o.PostOnly && (o.Type != OrderLimit || o.GetTimeInForce() != GoodTillCancelled) {
+		return errors.New("only good till cancelled limit orders can be post-only")
+	}
+
+	if o.ReduceOnly && o.Side != OrderSell {
+		return errors.New("only sell orders can be reduce-only")
+	}
+
+	return nil
+}
+
+// OrderLookup finds the orders placed by a broker by their client order id
+type OrderLookup interface {
+	// OrderPlaced returns true when the exchange accepted an order of the ticker with the client order id since the date passed by argument
+	OrderPlaced(clientOrderID string, since time.Time) (bool, error)
+}
@@ -0,0 +1,51 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestOrderValidate(t *testing.T) {
+	one := domain.NewDecimalFromInt(1)
+
+	valid := map[string]domain.Order{
+		"market":               {Side: domain.OrderBuy, Type: domain.OrderMarket, Amount: one},
+		"post-only limit":      {Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: one, Price: one, PostOnly: true},
+		"immediate limit":      {Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: one, Price: one, TimeInForce: domain.ImmediateOrCancel},
+		"reduce-only stop":     {Side: domain.OrderSell, Type: domain.OrderStopLoss, Amount: one, StopPrice: one, ReduceOnly: true},
+		"stop-limit":           {Side: domain.OrderSell, Type: domain.OrderStopLimit, Amount: one, Price: one, StopPrice: one},
+		"take-profit of sells": {Side: domain.OrderSell, Type: domain.OrderTakeProfit, Amount: one, StopPrice: one},
+	}
+
+	for name, order := range valid {
+		order := order
+		t.Run(name+" should be valid", func(t *testing.T) {
+			if err := order.Validate(); err != nil {
+				t.Errorf("Not expected Validate to return error: %v", err)
+			}
+		})
+	}
+
+	invalid := map[string]domain.Order{
+		"without side":            {Type: domain.OrderMarket, Amount: one},
+		"without amount":          {Side: domain.OrderBuy, Type: domain.OrderMarket},
+		"limit without price":     {Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: one},
+		"stop-limit without stop": {Side: domain.OrderSell, Type: domain.OrderStopLimit, Amount: one, Price: one},
+		"market with time":        {Side: domain.OrderBuy, Type: domain.OrderMarket, Amount: one, TimeInForce: domain.ImmediateOrCancel},
+		"post-only market":        {Side: domain.OrderBuy, Type: domain.OrderMarket, Amount: one, PostOnly: true},
+		"post-only immediate":     {Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: one, Price: one, PostOnly: true, TimeInForce: domain.ImmediateOrCancel},
+		"reduce-only buy":         {Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: one, Price: one, ReduceOnly: true},
+		"unknown type":            {Side: domain.OrderBuy, Type: "trailing-stop", Amount: one},
+		"unknown time in force":   {Side: domain.OrderBuy, Type: domain.OrderLimit, Amount: one, Price: one, TimeInForce: "GTD"},
+	}
+
+	for name, order := range invalid {
+		order := order
+		t.Run(name+" should return error", func(t *testing.T) {
+			if err := order.Validate(); err == nil {
+				t.Errorf("Expected Validate to return error")
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// Holding is the amount held of an asset valued at its last price
+type Holding struct {
+	Asset         string  `json:"asset"`
+	Amount        Decimal `json:"amount"`
+	AverageCost   Decimal `json:"averageCost"`
+	CostBasis     Decimal `json:"costBasis"`
+	Price         Decimal `json:"price"`
+	MarketValue   Decimal `json:"marketValue"`
+	UnrealizedPnL Decimal `json:"unrealizedPnL"`
+	RealizedPnL   Decimal `json:"realizedPnL"`
+}
+
+// NewHolding returns the holding of the lots of an asset valued at the price passed by argument
+func NewHolding(asset string, lots []Asset, price, realizedPnL Decimal) Holding {
+	holding := Holding{Asset: asset, Price: price, RealizedPnL: realizedPnL}
+
+	for _, lot := range lots {
+		holding.Amount = holding.Amount.Add(lot.Amount)
+		holding.CostBasis = holding.CostBasis.Add(lot.Amount.Mul(lot.BuyPrice))
+	}
+
+	if !holding.Amount.IsZero() {
+		holding.AverageCost = holding.CostBasis.Div(holding.Amount)
+	}
+
+	holding.MarketValue = holding.Amount.Mul(price)
+	holding.UnrealizedPnL = holding.MarketValue.Sub(holding.CostBasis)
+
+	return holding
+}
+
+// Portfolio is the valuation of the cash and the holdings of one or more accounts
+type Portfolio struct {
+	Date time.Time `json:"date"`
+	// Quote is the currency of the cash and of the prices
+	Quote         string    `json:"quote"`
+	Cash          Decimal   `json:"cash"`
+	Holdings      []Holding `json:"holdings"`
+	MarketValue   Decimal   `json:"marketValue"`
+	UnrealizedPnL Decimal   `json:"unrealizedPnL"`
+	RealizedPnL   Decimal   `json:"realizedPnL"`
+	Equity        Decimal   `json:"equity"`
+}
+
+// AddHolding adds the holding to the portfolio totals
+func (p *Portfolio) AddHolding(holding Holding) {
+	p.Holdings = append(p.Holdings, holding)
+	p.MarketValue = p.MarketValue.Add(holding.MarketValue)
+	p.UnrealizedPnL = p.UnrealizedPnL.Add(holding.UnrealizedPnL)
+	p.RealizedPnL = p.RealizedPnL.Add(holding.RealizedPnL)
+	p.Equity = p.Cash.Add(p.MarketValue)
+}
+
+// EquityPoint is the value of one or more accounts at the end of a day
+type EquityPoint struct {
+	Date        time.Time `json:"date"`
+	Cash        Decimal   `json:"cash"`
+	MarketValue Decimal   `json:"marketValue"`
+	Equity      Decimal   `json:"equity"`
+}
+
+// PortfolioService values the accounts used by applications.
+// An empty account id values every account of the live applications of the quote currency, the quote is ignored otherwise.
+type PortfolioService interface {
+	GetPortfolio(accountID, quote string) (*Portfolio, error)
+	GetEquity(accountID, quote string, startDate, endDate time.Time) ([]EquityPoint, error)
+}
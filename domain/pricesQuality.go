@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+const (
+	// RepairDedupe sorts prices by date and removes duplicated dates
+	RepairDedupe = "dedupe"
+	// RepairForwardFill dedupes prices and fills gaps with the last known price
+	RepairForwardFill = "forward-fill"
+	// RepairInterpolate dedupes prices and fills gaps with prices linearly interpolated
+	RepairInterpolate = "interpolate"
+)
+
+// PricesQualityOptions configures the checks made on a prices series
+type PricesQualityOptions struct {
+	// Interval is the expected duration between prices. It is detected from the series when zero.
+	Interval time.Duration
+	// ZScoreThreshold is the absolute z-score of a price change above which the price is an outlier
+	ZScoreThreshold float64
+}
+
+// PriceGap is a dates range without prices
+type PriceGap struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Missing int       `json:"missing"`
+}
+
+// PriceIssue is a price that failed a quality check
+type PriceIssue struct {
+	Index int       `json:"index"`
+	Date  time.Time `json:"date"`
+	Close Decimal   `json:"close"`
+}
+
+// PriceOutlier is a price whose change from the previous price has an unusual z-score
+type PriceOutlier struct {
+	PriceIssue
+	ZScore float64 `json:"zScore"`
+}
+
+// PricesQualityReport lists the problems found in a prices series
+type PricesQualityReport struct {
+	Count      int          `json:"count"`
+	Interval   string       `json:"interval"`
+	StartDate  time.Time    `json:"startDate"`
+	EndDate    time.Time    `json:"endDate"`
+	Gaps       []PriceGap   `json:"gaps"`
+	Duplicates []PriceIssue `json:"duplicates"`
+	// OutOfOrder are the prices dated before the previous one, series read from the database are sorted by date
+	OutOfOrder    []PriceIssue   `json:"outOfOrder"`
+	InvalidPrices []PriceIssue   `json:"invalidPrices"`
+	Outliers      []PriceOutlier `json:"outliers"`
+}
+
+// OK returns true when no problem was found
+func (r *PricesQualityReport) OK() bool {
+	return len(r.Gaps) == 0 && len(r.Duplicates) == 0 && len(r.OutOfOrder) == 0 && len(r.InvalidPrices) == 0 && len(r.Outliers) == 0
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OpenOrder is an order placed on the exchange that is not completely filled
+type OpenOrder struct {
+	ID    string
+	Asset string
+	// Type is buy or sell
+	Type string
+	// Volume is the volume not executed yet
+	Volume Decimal
+	Price  Decimal
+}
+
+// BrokerAccount reads the state of the exchange account used by a broker
+type BrokerAccount interface {
+	// GetBalances returns the balances by asset symbol, e.g. BTC or EUR, including the amounts reserved by open orders
+	GetBalances() (map[string]Decimal, error)
+	GetOpenOrders() ([]OpenOrder, error)
+}
+
+// Reconciliation compares the exchange balances of an account with its bookkeeping.
+// Open orders are already recorded on the bookkeeping so they are considered filled.
+type Reconciliation struct {
+	AccountID      primitive.ObjectID `bson:"accountId" json:"accountId"`
+	Date           time.Time          `bson:"date" json:"date"`
+	Currency       string             `bson:"currency" json:"currency"`
+	ExchangeCash   Decimal            `bson:"exchangeCash" json:"exchangeCash"`
+	BookCash       Decimal            `bson:"bookCash" json:"bookCash"`
+	CashDifference Decimal            `bson:"cashDifference" json:"cashDifference"`
+	// Assets are the reconciliations of each asset traded with the exchange account
+	Assets   []AssetReconciliation `bson:"assets" json:"assets"`
+	Adjusted bool                  `bson:"adjusted" json:"adjusted"`
+}
+
+// AssetReconciliation compares the exchange balance of an asset with the lots of the bookkeeping
+type AssetReconciliation struct {
+	Asset      string  `bson:"asset" json:"asset"`
+	Exchange   Decimal `bson:"exchange" json:"exchange"`
+	Book       Decimal `bson:"book" json:"book"`
+	Difference Decimal `bson:"difference" json:"difference"`
+}
+
+// NewReconciliation returns the reconciliation of the exchange balances and open orders of the assets with the book amounts.
+// The cash reserved by the open orders of every asset passed by argument is considered spent.
+func NewReconciliation(assets []string, currency string, balances map[string]Decimal, orders []OpenOrder, bookCash Decimal, bookAssets map[string]Decimal) *Reconciliation {
+	exchangeCash := balances[currency]
+	exchangeAssets := map[string]Decimal{}
+
+	for _, asset := range assets {
+		exchangeAssets[asset] = balances[asset]
+	}
+
+	for _, order := range orders {
+		exchangeAsset, ok := exchangeAssets[order.Asset]
+
+		if !ok {
+			continue
+		}
+
+		value := order.Volume.Mul(order.Price)
+
+		switch order.Type {
+		case "buy":
+			exchangeCash = exchangeCash.Sub(value)
+			exchangeAssets[order.Asset] = exchangeAsset.Add(order.Volume)
+		case "sell":
+			exchangeCash = exchangeCash.Add(value)
+			exchangeAssets[order.Asset] = exchangeAsset.Sub(order.Volume)
+		}
+	}
+
+	reconciliation := &Reconciliation{
+		Currency:       currency,
+		ExchangeCash:   exchangeCash,
+		BookCash:       bookCash,
+		CashDifference: exchangeCash.Sub(bookCash),
+		Assets:         []AssetReconciliation{},
+	}
+
+	for _, asset := range assets {
+		reconciliation.Assets = append(reconciliation.Assets, AssetReconciliation{
+			Asset:      asset,
+			Exchange:   exchangeAssets[asset],
+			Book:       bookAssets[asset],
+			Difference: exchangeAssets[asset].Sub(bookAssets[asset]),
+		})
+	}
+
+	return reconciliation
+}
+
+// HasCashDiscrepancy returns whether the cash difference is beyond the tolerance
+func (r *Reconciliation) HasCashDiscrepancy(tolerance Decimal) bool {
+	return r.CashDifference.GreaterThan(tolerance) || r.CashDifference.Neg().GreaterThan(tolerance)
+}
+
+// AssetDiscrepancies returns the assets with a difference beyond the tolerance
+func (r *Reconciliation) AssetDiscrepancies(tolerance Decimal) []AssetReconciliation {
+	discrepancies := []AssetReconciliation{}
+
+	for _, asset := range r.Assets {
+		if asset.Difference.GreaterThan(tolerance) || asset.Difference.Neg().GreaterThan(tolerance) {
+			discrepancies = append(discrepancies, asset)
+		}
+	}
+
+	return discrepancies
+}
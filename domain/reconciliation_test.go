@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestNewReconciliation(t *testing.T) {
+	balances := map[string]domain.Decimal{
+		"EUR": domain.NewDecimalFromInt(1000),
+		"BTC": domain.NewDecimal(0.5),
+	}
+	orders := []domain.OpenOrder{
+		{Asset: "BTC", Type: "buy", Volume: domain.NewDecimal(0.01), Price: domain.NewDecimalFromInt(30000)},
+		{Asset: "BTC", Type: "sell", Volume: domain.NewDecimal(0.1), Price: domain.NewDecimalFromInt(40000)},
+		{Asset: "ETH", Type: "buy", Volume: domain.NewDecimalFromInt(1), Price: domain.NewDecimalFromInt(2000)},
+	}
+
+	t.Run("should consider open orders filled", func(t *testing.T) {
+		got := domain.NewReconciliation([]string{"BTC"}, "EUR", balances, orders, domain.NewDecimalFromInt(4700), map[string]domain.Decimal{"BTC": domain.NewDecimal(0.41)})
+
+		if got.ExchangeCash.String() != "4700" || got.Assets[0].Exchange.String() != "0.41" {
+			t.Errorf("got cash %v and asset %v want 4700 and 0.41", got.ExchangeCash, got.Assets[0].Exchange)
+		}
+
+		if got.HasCashDiscrepancy(domain.NewDecimal(0.01)) || len(got.AssetDiscrepancies(domain.Decimal{})) != 0 {
+			t.Errorf("Not expected discrepancies %+v", got)
+		}
+	})
+
+	t.Run("should return discrepancies beyond tolerance", func(t *testing.T) {
+		got := domain.NewReconciliation([]string{"BTC"}, "EUR", balances, orders, domain.NewDecimalFromInt(4710), map[string]domain.Decimal{"BTC": domain.NewDecimal(0.4)})
+
+		if got.CashDifference.String() != "-10" || got.Assets[0].Difference.String() != "0.01" {
+			t.Errorf("got differences %v and %v want -10 and 0.01", got.CashDifference, got.Assets[0].Difference)
+		}
+
+		if !got.HasCashDiscrepancy(domain.NewDecimal(0.01)) || len(got.AssetDiscrepancies(domain.NewDecimal(0.001))) != 1 {
+			t.Errorf("Expected discrepancies %+v", got)
+		}
+
+		if got.HasCashDiscrepancy(domain.NewDecimalFromInt(10)) {
+			t.Errorf("Not expected cash discrepancy within tolerance")
+		}
+	})
+
+	t.Run("should reserve the cash of the open orders of every asset", func(t *testing.T) {
+		balances := map[string]domain.Decimal{"EUR": domain.NewDecimalFromInt(1000), "BTC": domain.NewDecimal(0.5), "ETH": domain.NewDecimalFromInt(2)}
+		got := domain.NewReconciliation([]string{"BTC", "ETH"}, "EUR", balances, orders, domain.NewDecimalFromInt(2700), map[string]domain.Decimal{"BTC": domain.NewDecimal(0.41), "ETH": domain.NewDecimalFromInt(3)})
+
+		if got.HasCashDiscrepancy(domain.NewDecimal(0.01)) || len(got.AssetDiscrepancies(domain.Decimal{})) != 0 {
+			t.Errorf("Not expected discrepancies %+v", got)
+		}
+	})
+}
@@ -13,9 +13,21 @@ type Repository interface {
 	FindOne(document interface{}, query interface{}, opts *options.FindOneOptions) error
 	InsertOne(document interface{}) error
 	UpdateOne(query interface{}, update interface{}) error
+	// UpdateOneMatched updates one document found with match criteria, it returns false when no document matches
+	UpdateOneMatched(query interface{}, update interface{}) (bool, error)
 	DeleteByID(id string) error
-	BulkUpsert(documents []bson.M) error
+	BulkUpsert(documents []bson.M, keys []string) (int, error)
 	BulkCreate(documents *[]bson.M) error
 	BulkDelete(filter bson.M) error
 	BulkUpdate(filter bson.M, update bson.M) error
+	CreateIndex(index mongo.IndexModel) error
+}
+
+// RepositoryFactory returns the repository of the collection passed by argument
+type RepositoryFactory func(collection string) Repository
+
+// UnitOfWork executes the writes of multiple repositories atomically.
+// Only the repositories returned by the factory passed to the function take part of the unit of work.
+type UnitOfWork interface {
+	Do(fn func(repositories RepositoryFactory) error) error
 }
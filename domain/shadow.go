@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Statuses of shadow orders
+const (
+	ShadowOrderOpen   = "open"
+	ShadowOrderFilled = "filled"
+)
+
+// ShadowOrder is an order a shadow application would have placed and its simulated fill
+type ShadowOrder struct {
+	ID            primitive.ObjectID `bson:"_id" json:"_id"`
+	ApplicationID primitive.ObjectID `bson:"applicationId" json:"applicationId"`
+	Asset         string             `bson:"asset" json:"asset"`
+	Quote         string             `bson:"quote" json:"quote"`
+	Side          OrderSide          `bson:"side" json:"side"`
+	Type          OrderType          `bson:"type" json:"type"`
+	Amount        Decimal            `bson:"amount" json:"amount"`
+	Price         Decimal            `bson:"price" json:"price"`
+	StopPrice     Decimal            `bson:"stopPrice" json:"stopPrice"`
+	Status        string             `bson:"status" json:"status"`
+	// FillPrice is the price of the live market the order would have been filled at
+	FillPrice Decimal   `bson:"fillPrice" json:"fillPrice"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	FilledAt  time.Time `bson:"filledAt,omitempty" json:"filledAt,omitempty"`
+}
+
+// ShadowOrdersRepository stores the orders of shadow applications
+type ShadowOrdersRepository interface {
+	Create(order *ShadowOrder) error
+	Fill(id primitive.ObjectID, price Decimal, date time.Time) error
+	FindAll(applicationID string) (*[]ShadowOrder, error)
+}
+
+// ApplicationPerformance is the portfolio of the account of an application
+type ApplicationPerformance struct {
+	ApplicationID primitive.ObjectID `json:"applicationId"`
+	Mode          ApplicationMode    `json:"mode"`
+	Portfolio     *Portfolio         `json:"portfolio"`
+}
+
+// ShadowComparison compares a shadow application with the live application it shadows
+type ShadowComparison struct {
+	Shadow ApplicationPerformance `json:"shadow"`
+	Live   ApplicationPerformance `json:"live"`
+	// Orders are the orders of the shadow application
+	Orders []ShadowOrder `json:"orders"`
+}
+
+// ShadowService compares shadow applications with live applications
+type ShadowService interface {
+	Compare(applicationID string) (*ShadowComparison, error)
+}
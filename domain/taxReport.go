@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CostBasisMethod decides the cost of the amounts disposed
+type CostBasisMethod string
+
+const (
+	// CostBasisFIFO uses the cost of the amounts acquired first
+	CostBasisFIFO CostBasisMethod = "fifo"
+	// CostBasisAverage uses the average cost of the amounts held
+	CostBasisAverage CostBasisMethod = "average"
+)
+
+// Sources of tax events
+const (
+	TaxSourceTrading = "trading"
+	TaxSourceDCA     = "dca"
+)
+
+// TaxEvent is an acquisition or a disposal of an asset
+type TaxEvent struct {
+	Asset  string
+	Source string
+	Date   time.Time
+	Amount Decimal
+	Price  Decimal
+	Fee    Decimal
+}
+
+// TaxDisposal is the realized gain of a disposal, fees are added to the cost basis
+type TaxDisposal struct {
+	Asset     string    `json:"asset"`
+	Source    string    `json:"source"`
+	Date      time.Time `json:"date"`
+	Amount    Decimal   `json:"amount"`
+	Proceeds  Decimal   `json:"proceeds"`
+	Fees      Decimal   `json:"fees"`
+	CostBasis Decimal   `json:"costBasis"`
+	Gain      Decimal   `json:"gain"`
+}
+
+// TaxReport has the realized gains of the disposals of a year
+type TaxReport struct {
+	Year   int             `json:"year"`
+	Method CostBasisMethod `json:"method"`
+	// Quote is the currency of the proceeds and of the costs
+	Quote     string        `json:"quote"`
+	Disposals []TaxDisposal `json:"disposals"`
+	Proceeds  Decimal       `json:"proceeds"`
+	CostBasis Decimal       `json:"costBasis"`
+	Gain      Decimal       `json:"gain"`
+}
+
+// TaxReportService generates tax reports
+type TaxReportService interface {
+	// Generate returns the report of the trades and purchases made with the quote currency
+	Generate(year int, method CostBasisMethod, quote string) (*TaxReport, error)
+}
+
+// taxPosition is an amount held of an asset and its cost including fees
+type taxPosition struct {
+	amount Decimal
+	cost   Decimal
+}
+
+// NewTaxReport returns the gains of the disposals of the year, matching them with the acquisitions of the same asset made before.
+// Every event of previous years is needed to know the amounts held at the beginning of the year.
+func NewTaxReport(year int, method CostBasisMethod, acquisitions, disposals []TaxEvent) (*TaxReport, error) {
+	if method != CostBasisFIFO && method != CostBasisAverage {
+		return nil, fmt.Errorf("unknown cost basis method %v", method)
+	}
+
+	events := make([]TaxEvent, 0, len(acquisitions)+len(disposals))
+	events = append(events, acquisitions...)
+	events = append(events, disposals...)
+	isDisposal := func(i int) bool { return i >= len(acquisitions) }
+
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+
+	// acquisitions made at the same time of a disposal are available to it
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := events[order[i]], events[order[j]]
+
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+
+		return !isDisposal(order[i]) && isDisposal(order[j])
+	})
+
+	report := &TaxReport{Year: year, Method: method, Disposals: []TaxDisposal{}}
+	positions := map[string][]taxPosition{}
+
+	for _, i := range order {
+		event := events[i]
+
+		if !isDisposal(i) {
+			value, err := event.Amount.MulChecked(event.Price)
+
+			if err != nil {
+				return nil, err
+			}
+
+			position := taxPosition{event.Amount, value.Add(event.Fee)}
+			positions[event.Asset] = addTaxPosition(positions[event.Asset], position, method)
+			continue
+		}
+
+		held, costBasis, err := disposeTaxPositions(positions[event.Asset], event.Amount)
+
+		if err != nil {
+			return nil, fmt.Errorf("disposal of %v %v on %v: %v", event.Amount, event.Asset, event.Date.Format(time.RFC3339), err)
+		}
+
+		positions[event.Asset] = held
+
+		if event.Date.UTC().Year() != year {
+			continue
+		}
+
+		proceeds, err := event.Amount.MulChecked(event.Price)
+
+		if err != nil {
+			return nil, err
+		}
+
+		costBasis = costBasis.Add(event.Fee)
+		disposal := TaxDisposal{
+			Asset:     event.Asset,
+			Source:    event.Source,
+			Date:      event.Date,
+			Amount:    event.Amount,
+			Proceeds:  proceeds,
+			Fees:      event.Fee,
+			CostBasis: costBasis,
+			Gain:      proceeds.Sub(costBasis),
+		}
+
+		report.Disposals = append(report.Disposals, disposal)
+		report.Proceeds = report.Proceeds.Add(proceeds)
+		report.CostBasis = report.CostBasis.Add(costBasis)
+	}
+
+	report.Gain = report.Proceeds.Sub(report.CostBasis)
+
+	return report, nil
+}
+
+// addTaxPosition queues the position or, with the average cost, merges it with the amount held
+func addTaxPosition(positions []taxPosition, position taxPosition, method CostBasisMethod) []taxPosition {
+	if method == CostBasisAverage && len(positions) > 0 {
+		return []taxPosition{{positions[0].amount.Add(position.amount), positions[0].cost.Add(position.cost)}}
+	}
+
+	return append(positions, position)
+}
+
+// disposeTaxPositions removes the amount from the positions in order and returns the positions left and the cost removed
+func disposeTaxPositions(positions []taxPosition, amount Decimal) ([]taxPosition, Decimal, error) {
+	var cost Decimal
+	remaining := amount
+
+	for len(positions) > 0 && remaining.GreaterThan(Decimal{}) {
+		position := positions[0]
+
+		if remaining.LessThan(position.amount) {
+			partialCost, err := position.cost.MulDivChecked(remaining, position.amount)
+
+			if err != nil {
+				return nil, cost, err
+			}
+
+			positions[0] = taxPosition{position.amount.Sub(remaining), position.cost.Sub(partialCost)}
+
+			return positions, cost.Add(partialCost), nil
+		}
+
+		cost = cost.Add(position.cost)
+		remaining = remaining.Sub(position.amount)
+		positions = positions[1:]
+	}
+
+	if remaining.GreaterThan(Decimal{}) {
+		return nil, cost, ErrInsufficientAssets
+	}
+
+	return positions, cost, nil
+}
@@ -0,0 +1,86 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+func TestNewTaxReport(t *testing.T) {
+	date := func(year int, month time.Month) time.Time { return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC) }
+	event := func(asset, source string, date time.Time, amount, price, fee float64) domain.TaxEvent {
+		return domain.TaxEvent{asset, source, date, domain.NewDecimal(amount), domain.NewDecimal(price), domain.NewDecimal(fee)}
+	}
+
+	acquisitions := []domain.TaxEvent{
+		event("BTC", domain.TaxSourceDCA, date(2020, 1), 1, 100, 1),
+		event("BTC", domain.TaxSourceTrading, date(2020, 6), 1, 200, 1),
+		event("ETH", domain.TaxSourceTrading, date(2020, 6), 10, 5, 0),
+	}
+	disposals := []domain.TaxEvent{
+		event("BTC", domain.TaxSourceTrading, date(2020, 12), 0.5, 300, 0),
+		event("BTC", domain.TaxSourceTrading, date(2021, 2), 1, 400, 2),
+	}
+
+	cases := []struct {
+		method    domain.CostBasisMethod
+		costBasis string
+		gain      string
+	}{
+		// 0.5 from the first lot (50.5) and 0.5 from the second lot (100.5) plus the 2 of fees
+		{domain.CostBasisFIFO, "153", "247"},
+		// 1 at the average cost of 151 plus the 2 of fees
+		{domain.CostBasisAverage, "153", "247"},
+	}
+
+	for _, c := range cases {
+		t.Run(string(c.method)+" should return the disposals of the year", func(t *testing.T) {
+			got, err := domain.NewTaxReport(2021, c.method, acquisitions, disposals)
+
+			if err != nil {
+				t.Fatalf("Not expected NewTaxReport to return error: %v", err)
+			}
+
+			if len(got.Disposals) != 1 || got.Disposals[0].Fees.String() != "2" {
+				t.Fatalf("got disposals %+v want the 2021 disposal", got.Disposals)
+			}
+
+			if got.CostBasis.String() != c.costBasis || got.Gain.String() != c.gain {
+				t.Errorf("got cost basis %v and gain %v want %v and %v", got.CostBasis, got.Gain, c.costBasis, c.gain)
+			}
+		})
+	}
+
+	t.Run("should use the cost basis method on the disposals matched", func(t *testing.T) {
+		fifo, _ := domain.NewTaxReport(2020, domain.CostBasisFIFO, acquisitions, disposals)
+		average, _ := domain.NewTaxReport(2020, domain.CostBasisAverage, acquisitions, disposals)
+
+		if fifo.CostBasis.String() != "50.5" || average.CostBasis.String() != "75.5" {
+			t.Errorf("got fifo cost basis %v and average %v want 50.5 and 75.5", fifo.CostBasis, average.CostBasis)
+		}
+	})
+
+	t.Run("should split the cost of large holdings of low price assets", func(t *testing.T) {
+		acquisitions := []domain.TaxEvent{event("SHIB", domain.TaxSourceTrading, date(2021, 1), 2000000, 0.05, 0)}
+		disposals := []domain.TaxEvent{event("SHIB", domain.TaxSourceTrading, date(2021, 2), 1000000, 0.06, 0)}
+
+		got, err := domain.NewTaxReport(2021, domain.CostBasisFIFO, acquisitions, disposals)
+
+		if err != nil {
+			t.Fatalf("Not expected NewTaxReport to return error: %v", err)
+		}
+
+		if got.CostBasis.String() != "50000" || got.Gain.String() != "10000" {
+			t.Errorf("got cost basis %v and gain %v want 50000 and 10000", got.CostBasis, got.Gain)
+		}
+	})
+
+	t.Run("should return error when disposals exceed the acquisitions", func(t *testing.T) {
+		disposals := []domain.TaxEvent{event("ETH", domain.TaxSourceTrading, date(2021, 1), 11, 10, 0)}
+
+		if _, err := domain.NewTaxReport(2021, domain.CostBasisFIFO, acquisitions, disposals); err == nil {
+			t.Errorf("Expected NewTaxReport to return error")
+		}
+	})
+}
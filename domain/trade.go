@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInsufficientAssets is returned when the lots do not hold the amount to sell
+var ErrInsufficientAssets = errors.New("insufficient assets")
+
+// ErrLotsNotMergeable is returned when the lots to merge are not pending lots of the same account and asset
+var ErrLotsNotMergeable = errors.New("lots not mergeable")
+
+// LotMatching decides which lots are closed by a sell
+type LotMatching string
+
+const (
+	// LotFIFO sells the lots bought first
+	LotFIFO LotMatching = "fifo"
+	// LotLIFO sells the lots bought last
+	LotLIFO LotMatching = "lifo"
+	// LotHighestCost sells the lots with the highest buy price
+	LotHighestCost LotMatching = "highest-cost"
+	// LotSpecificID sells the lots chosen by ID in the order they are given
+	LotSpecificID LotMatching = "specific-id"
+)
+
+// LotSelection is the lot matching of a sell and the lots chosen when matching by ID
+type LotSelection struct {
+	Method   LotMatching
+	AssetIDs []primitive.ObjectID
+}
+
+// LotFill is the amount sold of a lot
+type LotFill struct {
+	Asset  Asset
+	Amount Decimal
+	// Fee is the part of the sell fee paid on the amount of the lot
+	Fee Decimal
+}
+
+// MatchLots returns the lots closed by selling the amount passed by argument.
+// The last lot is partially filled when it holds more than the amount left to sell.
+func MatchLots(lots []Asset, amount Decimal, selection LotSelection) ([]LotFill, error) {
+	candidates := make([]Asset, 0, len(lots))
+
+	for _, lot := range lots {
+		if !lot.Sold {
+			candidates = append(candidates, lot)
+		}
+	}
+
+	switch selection.Method {
+	case LotFIFO, "":
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].BuyTime.Before(candidates[j].BuyTime) })
+	case LotLIFO:
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].BuyTime.After(candidates[j].BuyTime) })
+	case LotHighestCost:
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].BuyPrice.GreaterThan(candidates[j].BuyPrice) })
+	case LotSpecificID:
+		candidates = selectLots(candidates, selection.AssetIDs)
+	default:
+		return nil, errors.New("unknown lot matching " + string(selection.Method))
+	}
+
+	fills := []LotFill{}
+	remaining := amount
+
+	for _, lot := range candidates {
+		if !remaining.GreaterThan(Decimal{}) {
+			break
+		}
+
+		fill := lot.Amount
+
+		if remaining.LessThan(fill) {
+			fill = remaining
+		}
+
+		fills = append(fills, LotFill{Asset: lot, Amount: fill})
+		remaining = remaining.Sub(fill)
+	}
+
+	if remaining.GreaterThan(Decimal{}) {
+		return nil, ErrInsufficientAssets
+	}
+
+	return fills, nil
+}
+
+func selectLots(lots []Asset, ids []primitive.ObjectID) []Asset {
+	selected := []Asset{}
+
+	for _, id := range ids {
+		for _, lot := range lots {
+			if lot.ID == id {
+				selected = append(selected, lot)
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+// MergeLots returns a lot with the amount and the buy fees of the lots passed by argument bought at their average price weighted by amount.
+// It is bought at the last buy time of the lots, so its holding period is not longer than the one of any lot merged.
+// It returns ErrLotsNotMergeable when there are less than two lots or when they are not pending lots of the same account and asset.
+func MergeLots(lots []Asset) (*Asset, error) {
+	if len(lots) < 2 {
+		return nil, ErrLotsNotMergeable
+	}
+
+	merged := &Asset{
+		ID:             primitive.NewObjectID(),
+		Symbol:         lots[0].Symbol,
+		AccountID:      lots[0].AccountID,
+		OptionsVersion: lots[0].OptionsVersion,
+	}
+
+	var cost Decimal
+
+	for _, lot := range lots {
+		if lot.Sold || lot.Symbol != merged.Symbol || lot.AccountID != merged.AccountID {
+			return nil, ErrLotsNotMergeable
+		}
+
+		value, err := lot.Amount.MulChecked(lot.BuyPrice)
+
+		if err != nil {
+			return nil, err
+		}
+
+		cost = cost.Add(value)
+		merged.Amount = merged.Amount.Add(lot.Amount)
+		merged.BuyFee = merged.BuyFee.Add(lot.BuyFee)
+
+		if lot.BuyTime.After(merged.BuyTime) {
+			merged.BuyTime = lot.BuyTime
+		}
+
+		if lot.OptionsVersion != merged.OptionsVersion {
+			merged.OptionsVersion = 0
+		}
+	}
+
+	price, err := cost.DivChecked(merged.Amount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	merged.BuyPrice = price
+
+	return merged, nil
+}
+
+// TradeLot is a lot closed by a trade
+type TradeLot struct {
+	AssetID  primitive.ObjectID `bson:"assetId" json:"assetId"`
+	Amount   Decimal            `bson:"amount" json:"amount"`
+	BuyPrice Decimal            `bson:"buyPrice" json:"buyPrice"`
+	BuyTime  time.Time          `bson:"buyTime" json:"buyTime"`
+	// CostBasis is the buy value of the amount sold plus its part of the buy fee
+	CostBasis Decimal `bson:"costBasis" json:"costBasis"`
+	// Fees are the part of the sell fee paid on the lot
+	Fees        Decimal `bson:"fees,omitempty" json:"fees,omitempty"`
+	RealizedPnL Decimal `bson:"realizedPnL" json:"realizedPnL"`
+}
+
+// Trade is a closing trade, a sell of one or more lots, and its realized profit or loss
+type Trade struct {
+	ID        primitive.ObjectID `bson:"_id" json:"_id"`
+	AccountID primitive.ObjectID `bson:"accountId" json:"accountId"`
+	Asset     string             `bson:"asset,omitempty" json:"asset,omitempty"`
+	Amount    Decimal            `bson:"amount" json:"amount"`
+	Price     Decimal            `bson:"price" json:"price"`
+	Proceeds  Decimal            `bson:"proceeds" json:"proceeds"`
+	// CostBasis is the buy value of the amount sold plus its part of the buy fees, as on the tax reports
+	CostBasis Decimal `bson:"costBasis" json:"costBasis"`
+	// RealizedPnL is the proceeds minus the cost basis and the fees
+	RealizedPnL Decimal `bson:"realizedPnL" json:"realizedPnL"`
+	// Fees are paid to the exchange on the sell, they are not subtracted from the proceeds
+	Fees        Decimal     `bson:"fees,omitempty" json:"fees,omitempty"`
+	LotMatching LotMatching `bson:"lotMatching" json:"lotMatching"`
+	Lots        []TradeLot  `bson:"lots" json:"lots"`
+	Date        time.Time   `bson:"date" json:"date"`
+}
+
+// NewTrade returns the trade that sells the lots filled at the price passed by argument.
+// The realized profit or loss of each lot is net of its part of the buy fee and of the sell fee of the fill.
+// It returns ErrDecimalOverflow when the value of a lot does not fit in a decimal.
+func NewTrade(accountID primitive.ObjectID, fills []LotFill, price Decimal, lotMatching LotMatching, date time.Time) (*Trade, error) {
+	var asset string
+
+	if len(fills) > 0 {
+		asset = fills[0].Asset.Symbol
+	}
+
+	trade := &Trade{
+		ID:          primitive.NewObjectID(),
+		AccountID:   accountID,
+		Asset:       asset,
+		Price:       price,
+		LotMatching: lotMatching,
+		Lots:        []TradeLot{},
+		Date:        date,
+	}
+
+	for _, fill := range fills {
+		costBasis, err := fill.Amount.MulChecked(fill.Asset.BuyPrice)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buyFee := fill.Asset.BuyFee
+
+		if !buyFee.IsZero() && fill.Amount.LessThan(fill.Asset.Amount) {
+			buyFee, err = buyFee.MulDivChecked(fill.Amount, fill.Asset.Amount)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		costBasis = costBasis.Add(buyFee)
+
+		proceeds, err := fill.Amount.MulChecked(price)
+
+		if err != nil {
+			return nil, err
+		}
+
+		trade.Lots = append(trade.Lots, TradeLot{
+			AssetID:     fill.Asset.ID,
+			Amount:      fill.Amount,
+			BuyPrice:    fill.Asset.BuyPrice,
+			BuyTime:     fill.Asset.BuyTime,
+			CostBasis:   costBasis,
+			Fees:        fill.Fee,
+			RealizedPnL: proceeds.Sub(costBasis).Sub(fill.Fee),
+		})
+
+		trade.Amount = trade.Amount.Add(fill.Amount)
+		trade.Proceeds = trade.Proceeds.Add(proceeds)
+		trade.CostBasis = trade.CostBasis.Add(costBasis)
+		trade.Fees = trade.Fees.Add(fill.Fee)
+	}
+
+	trade.RealizedPnL = trade.Proceeds.Sub(trade.CostBasis).Sub(trade.Fees)
+
+	return trade, nil
+}
+
+// TradesRepository stores and fetches closing trades
+type TradesRepository interface {
+	Create(trade *Trade) error
+	FindAll(accountID string, startDate, endDate time.Time) (*[]Trade, error)
+}
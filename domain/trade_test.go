@@ -0,0 +1,144 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMatchLots(t *testing.T) {
+	date := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	lots := []domain.Asset{
+		{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(0.2), BuyPrice: domain.NewDecimalFromInt(30000), BuyTime: date.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(0.1), BuyPrice: domain.NewDecimalFromInt(20000), BuyTime: date},
+		{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(0.3), BuyPrice: domain.NewDecimalFromInt(40000), BuyTime: date.Add(2 * time.Hour)},
+		{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(1), BuyPrice: domain.NewDecimalFromInt(10000), BuyTime: date, Sold: true},
+	}
+
+	cases := []struct {
+		name      string
+		selection domain.LotSelection
+		want      []domain.LotFill
+	}{
+		{"fifo", domain.LotSelection{Method: domain.LotFIFO}, []domain.LotFill{{Asset: lots[1], Amount: domain.NewDecimal(0.1)}, {Asset: lots[0], Amount: domain.NewDecimal(0.15)}}},
+		{"lifo", domain.LotSelection{Method: domain.LotLIFO}, []domain.LotFill{{Asset: lots[2], Amount: domain.NewDecimal(0.25)}}},
+		{"highest-cost", domain.LotSelection{Method: domain.LotHighestCost}, []domain.LotFill{{Asset: lots[2], Amount: domain.NewDecimal(0.25)}}},
+		{"specific-id", domain.LotSelection{Method: domain.LotSpecificID, AssetIDs: []primitive.ObjectID{lots[0].ID, lots[1].ID}}, []domain.LotFill{{Asset: lots[0], Amount: domain.NewDecimal(0.2)}, {Asset: lots[1], Amount: domain.NewDecimal(0.05)}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name+" should match the lots", func(t *testing.T) {
+			got, err := domain.MatchLots(lots, domain.NewDecimal(0.25), c.selection)
+
+			if err != nil {
+				t.Fatalf("Not expected MatchLots to return error: %v", err)
+			}
+
+			if len(got) != len(c.want) {
+				t.Fatalf("got %d fills want %d", len(got), len(c.want))
+			}
+
+			for i := range got {
+				if got[i].Asset.ID != c.want[i].Asset.ID || got[i].Amount != c.want[i].Amount {
+					t.Errorf("got fill %+v want %+v", got[i], c.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("should return insufficient assets when lots do not hold the amount", func(t *testing.T) {
+		selection := domain.LotSelection{Method: domain.LotSpecificID, AssetIDs: []primitive.ObjectID{lots[1].ID, lots[3].ID}}
+		_, err := domain.MatchLots(lots, domain.NewDecimal(0.25), selection)
+
+		if err != domain.ErrInsufficientAssets {
+			t.Errorf("got %v want %v", err, domain.ErrInsufficientAssets)
+		}
+	})
+}
+
+func TestNewTrade(t *testing.T) {
+	fills := []domain.LotFill{
+		{Asset: domain.Asset{ID: primitive.NewObjectID(), BuyPrice: domain.NewDecimalFromInt(20000)}, Amount: domain.NewDecimal(0.1)},
+		{Asset: domain.Asset{ID: primitive.NewObjectID(), BuyPrice: domain.NewDecimalFromInt(40000)}, Amount: domain.NewDecimal(0.05)},
+	}
+
+	got, err := domain.NewTrade(primitive.NewObjectID(), fills, domain.NewDecimalFromInt(30000), domain.LotFIFO, time.Now())
+
+	if err != nil {
+		t.Fatalf("Not expected NewTrade to return error: %v", err)
+	}
+
+	if got.Amount.String() != "0.15" || got.Proceeds.String() != "4500" || got.CostBasis.String() != "4000" || got.RealizedPnL.String() != "500" {
+		t.Errorf("got amount %v, proceeds %v, cost basis %v and pnl %v want 0.15, 4500, 4000 and 500", got.Amount, got.Proceeds, got.CostBasis, got.RealizedPnL)
+	}
+
+	if got.Lots[0].RealizedPnL.String() != "1000" || got.Lots[1].RealizedPnL.String() != "-500" {
+		t.Errorf("got lots pnl %v and %v want 1000 and -500", got.Lots[0].RealizedPnL, got.Lots[1].RealizedPnL)
+	}
+}
+
+func TestNewTradeFees(t *testing.T) {
+	t.Run("should subtract the buy fees of the amounts sold and the sell fees from the realized pnl", func(t *testing.T) {
+		fills := []domain.LotFill{
+			{Asset: domain.Asset{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(0.1), BuyPrice: domain.NewDecimalFromInt(20000), BuyFee: domain.NewDecimalFromInt(5)}, Amount: domain.NewDecimal(0.1), Fee: domain.NewDecimalFromInt(3)},
+			{Asset: domain.Asset{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(0.1), BuyPrice: domain.NewDecimalFromInt(40000), BuyFee: domain.NewDecimalFromInt(10)}, Amount: domain.NewDecimal(0.05), Fee: domain.NewDecimal(1.5)},
+		}
+
+		got, err := domain.NewTrade(primitive.NewObjectID(), fills, domain.NewDecimalFromInt(30000), domain.LotFIFO, time.Now())
+
+		if err != nil {
+			t.Fatalf("Not expected NewTrade to return error: %v", err)
+		}
+
+		if got.CostBasis.String() != "4010" || got.Fees.String() != "4.5" || got.RealizedPnL.String() != "485.5" {
+			t.Errorf("got cost basis %v, fees %v and pnl %v want 4010, 4.5 and 485.5", got.CostBasis, got.Fees, got.RealizedPnL)
+		}
+
+		if got.Lots[0].RealizedPnL.String() != "992" || got.Lots[1].RealizedPnL.String() != "-506.5" {
+			t.Errorf("got lots pnl %v and %v want 992 and -506.5", got.Lots[0].RealizedPnL, got.Lots[1].RealizedPnL)
+		}
+	})
+}
+
+func TestMergeLots(t *testing.T) {
+	date := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	accountID := primitive.NewObjectID()
+	lots := []domain.Asset{
+		{ID: primitive.NewObjectID(), Symbol: "BTC", AccountID: accountID, Amount: domain.NewDecimal(0.1), BuyPrice: domain.NewDecimalFromInt(20000), BuyFee: domain.NewDecimalFromInt(5), BuyTime: date},
+		{ID: primitive.NewObjectID(), Symbol: "BTC", AccountID: accountID, Amount: domain.NewDecimal(0.3), BuyPrice: domain.NewDecimalFromInt(40000), BuyFee: domain.NewDecimalFromInt(30), BuyTime: date.Add(time.Hour)},
+	}
+
+	t.Run("should merge the amounts and the fees at the average buy price", func(t *testing.T) {
+		got, err := domain.MergeLots(lots)
+
+		if err != nil {
+			t.Fatalf("Not expected MergeLots to return error: %v", err)
+		}
+
+		if got.Amount.String() != "0.4" || got.BuyPrice.String() != "35000" || got.BuyFee.String() != "35" || !got.BuyTime.Equal(date.Add(time.Hour)) {
+			t.Errorf("got lot %+v want 0.4 bought at 35000 with 35 of fees on the last buy time", got)
+		}
+
+		if got.Symbol != "BTC" || got.AccountID != accountID || got.ID == lots[0].ID || got.ID == lots[1].ID {
+			t.Errorf("got lot %+v want a new BTC lot of the account", got)
+		}
+	})
+
+	t.Run("should not merge lots of different assets or sold", func(t *testing.T) {
+		eth, sold := lots[1], lots[1]
+		eth.Symbol = "ETH"
+		sold.Sold = true
+
+		for _, other := range []domain.Asset{eth, sold} {
+			if _, err := domain.MergeLots([]domain.Asset{lots[0], other}); err != domain.ErrLotsNotMergeable {
+				t.Errorf("got %v want %v", err, domain.ErrLotsNotMergeable)
+			}
+		}
+
+		if _, err := domain.MergeLots(lots[:1]); err != domain.ErrLotsNotMergeable {
+			t.Errorf("got %v want %v", err, domain.ErrLotsNotMergeable)
+		}
+	})
+}
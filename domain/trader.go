@@ -2,8 +2,8 @@ package domain
 
 import "time"
 
-// Trader buys and sells assets
+// Trader buys and sells assets, it returns the client order id of the order placed
 type Trader interface {
-	Buy(amount, price float32, buyTime time.Time) error
-	Sell(asset *Asset, price float32, sellTime time.Time) error
+	Buy(amount, price Decimal, buyTime time.Time) (string, error)
+	Sell(amount, price Decimal, sellTime time.Time) (string, error)
 }
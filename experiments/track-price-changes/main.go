@@ -6,15 +6,15 @@ import (
 	"os"
 	"time"
 
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/indicators"
 
 	"github.com/fabiodmferreira/crypto-trading/collectors"
+	"github.com/fabiodmferreira/crypto-trading/csvschema"
 )
 
 func main() {
-	historyFile, err := collectors.GetCsv(fmt.Sprintf("./data-history/%v", btcdatahistory.TwentyTwentyH1))
+	historyFile, err := csvschema.Open("./data-history/btc/2020-h1.csv", nil)
 
 	if err != nil {
 		log.Fatal(err)
@@ -41,9 +41,10 @@ func main() {
 	var timesSameAccelerationDirection int
 
 	bitcoinHistoryCollector.Regist(func(ohlc *domain.OHLC) {
-		stats.AddPoint(float64(ohlc.Close))
+		price := ohlc.Close.Float32()
+		stats.AddPoint(float64(price))
 		if lastPrice > 0 {
-			change := ohlc.Close - lastPrice
+			change := price - lastPrice
 			changeOfChange := change - lastChange
 			if accelerationCurrentDirection && changeOfChange > 0 || !accelerationCurrentDirection && changeOfChange < 0 {
 				timesSameAccelerationDirection++
@@ -62,17 +63,21 @@ func main() {
 			f.WriteString(
 				fmt.Sprintf(
 					"%v,%.2f,%.2f,%.2f,%d,%d\n",
-					ohlc.Time.Format("2006-01-02T15:04:05"), ohlc.Close, change, changeOfChange, timesSameVelocityDirection, timesSameAccelerationDirection,
+					ohlc.Time.Format("2006-01-02T15:04:05"), price, change, changeOfChange, timesSameVelocityDirection, timesSameAccelerationDirection,
 				),
 			)
 
-			lastPrice = ohlc.Close
+			lastPrice = price
 			lastChange = change
 		} else {
-			f.WriteString(fmt.Sprintf("%v,%.2f,%d,%d\n", ohlc.Time, ohlc.Close, 0, 0))
-			lastPrice = ohlc.Close
+			f.WriteString(fmt.Sprintf("%v,%.2f,%d,%d\n", ohlc.Time, price, 0, 0))
+			lastPrice = price
 		}
 	})
 
-	bitcoinHistoryCollector.Start()
+	err = bitcoinHistoryCollector.Start()
+
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -9,5 +9,6 @@ require (
 	github.com/gorilla/mux v1.7.4
 	github.com/gorilla/websocket v1.4.2
 	github.com/joho/godotenv v1.3.0
+	go.etcd.io/bbolt v1.3.6
 	go.mongodb.org/mongo-driver v1.5.1
 )
@@ -89,7 +89,7 @@ func NewPriceIndicator(metricIndicator *MetricStatisticsIndicator) *PriceIndicat
 }
 
 func (pi *PriceIndicator) AddValue(ohlc *domain.OHLC) {
-	pi.AddMetricValue((ohlc.Close + ohlc.High + ohlc.Low + ohlc.Open) / 4)
+	pi.AddMetricValue(ohlc.Close.Add(ohlc.High).Add(ohlc.Low).Add(ohlc.Open).Float32() / 4)
 }
 
 type VolumeIndicator struct {
@@ -101,5 +101,5 @@ func NewVolumeIndicator(metricIndicator *MetricStatisticsIndicator) *VolumeIndic
 }
 
 func (vi *VolumeIndicator) AddValue(ohlc *domain.OHLC) {
-	vi.AddMetricValue(ohlc.Volume)
+	vi.AddMetricValue(ohlc.Volume.Float32())
 }
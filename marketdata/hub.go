@@ -0,0 +1,366 @@
+package marketdata
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/pricesquality"
+)
+
+// DefaultRestartDelay is the time the hub waits to restart a collector that stopped while it had subscriptions
+const DefaultRestartDelay = 10 * time.Second
+
+// CollectorFactory returns a collector of the prices of an asset in a quote currency of an exchange with candles of the interval in minutes
+type CollectorFactory func(exchange, quote, asset string, interval int) (domain.Collector, error)
+
+// Hub keeps one collector per exchange, quote currency, asset and interval shared by every subscription.
+// Each price received is stored once, with the price symbol of its asset and quote, and published to the subscriptions of its feed.
+// Collectors that stop while their feed has subscriptions, e.g. when the websocket connection is closed, are restarted.
+type Hub struct {
+	mu                  sync.Mutex
+	newCollector        CollectorFactory
+	assetsPricesService domain.AssetsPricesService
+	restartDelay        time.Duration
+	feeds               map[feedKey]*feed
+}
+
+type feedKey struct {
+	exchange string
+	quote    string
+	asset    string
+	interval int
+}
+
+// feed is a collector and the subscriptions to its prices, closed is closed when the last subscription is removed
+type feed struct {
+	collector     domain.Collector
+	subscriptions map[*Subscription]bool
+	stats         domain.MarketDataStats
+	closed        chan struct{}
+}
+
+// NewHub returns an instance of Hub that waits the restart delay before restarting a collector that stopped
+func NewHub(newCollector CollectorFactory, assetsPricesService domain.AssetsPricesService, restartDelay time.Duration) *Hub {
+	return &Hub{
+		newCollector:        newCollector,
+		assetsPricesService: assetsPricesService,
+		restartDelay:        restartDelay,
+		feeds:               map[feedKey]*feed{},
+	}
+}
+
+// Subscribe returns a collector of the prices of the assets in the quote currency of the exchange that starts receiving them when started
+func (h *Hub) Subscribe(exchange, quote string, assets []string, interval int) *Subscription {
+	return &Subscription{hub: h, exchange: exchange, quote: quote, assets: assets, interval: interval, done: make(chan struct{})}
+}
+
+// Stats returns the statistics of the subscriptions sorted by exchange, quote currency, asset and interval
+func (h *Hub) Stats() []domain.MarketDataStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stats := []domain.MarketDataStats{}
+
+	for _, feed := range h.feeds {
+		stats = append(stats, feed.stats)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Exchange != stats[j].Exchange {
+			return stats[i].Exchange < stats[j].Exchange
+		}
+
+		if stats[i].Quote != stats[j].Quote {
+			return stats[i].Quote < stats[j].Quote
+		}
+
+		if stats[i].Asset != stats[j].Asset {
+			return stats[i].Asset < stats[j].Asset
+		}
+
+		return stats[i].Interval < stats[j].Interval
+	})
+
+	return stats
+}
+
+// ScheduleStats saves the statistics of the subscriptions periodically until done is closed
+func (h *Hub) ScheduleStats(repository domain.MarketDataStatsRepository, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		stats := h.Stats()
+		now := time.Now()
+
+		for i := range stats {
+			stats[i].UpdatedAt = now
+		}
+
+		if err := repository.Save(stats); err != nil {
+			fmt.Printf("Not able to save market data stats: %v\n", err)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// attach adds the subscription to the feed of the exchange, quote currency, asset and interval, starting its collector when it is the first one.
+// Subscriptions stopped before they are attached are not added.
+func (h *Hub) attach(subscription *Subscription, asset string) error {
+	key := feedKey{subscription.exchange, subscription.quote, asset, subscription.interval}
+
+	if h.join(key, subscription) {
+		return nil
+	}
+
+	collector, err := h.newCollector(subscription.exchange, subscription.quote, asset, subscription.interval)
+
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// another subscription may have created the feed while the collector was created
+	if _, ok := h.feeds[key]; ok || subscription.stopped() {
+		h.joinLocked(key, subscription)
+		return nil
+	}
+
+	f := &feed{
+		collector:     collector,
+		subscriptions: map[*Subscription]bool{subscription: true},
+		stats:         domain.MarketDataStats{Exchange: subscription.exchange, Quote: subscription.quote, Asset: asset, Interval: subscription.interval, Subscribers: 1, StartedAt: time.Now(), Running: true},
+		closed:        make(chan struct{}),
+	}
+	h.feeds[key] = f
+
+	collector.Regist(func(ohlc *domain.OHLC) { h.publish(key, f, ohlc) })
+
+	go h.run(key, f, collector)
+
+	return nil
+}
+
+// join adds the subscription to the feed of the key when it exists, it returns false when there is no feed
+func (h *Hub) join(key feedKey, subscription *Subscription) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.joinLocked(key, subscription)
+}
+
+// joinLocked adds the subscription to the feed of the key when it exists and the subscription is not stopped, the hub must be locked.
+// It returns false when there is no feed.
+func (h *Hub) joinLocked(key feedKey, subscription *Subscription) bool {
+	f, ok := h.feeds[key]
+
+	if !ok {
+		return false
+	}
+
+	if !subscription.stopped() {
+		f.subscriptions[subscription] = true
+		f.stats.Subscribers = len(f.subscriptions)
+	}
+
+	return true
+}
+
+// run starts the collector of the feed and restarts it after the restart delay whenever it stops, until the feed is closed
+func (h *Hub) run(key feedKey, f *feed, collector domain.Collector) {
+	for {
+		if collector != nil {
+			if err := collector.Start(); err != nil {
+				fmt.Printf("%v prices collector of %v stopped due to next error: %v\n", key.asset, key.exchange, err)
+			}
+
+			h.mu.Lock()
+			f.stats.Running = false
+			h.mu.Unlock()
+		}
+
+		select {
+		case <-f.closed:
+			return
+		case <-time.After(h.restartDelay):
+		}
+
+		collector = h.restart(key, f)
+	}
+}
+
+// restart replaces the collector of the feed by a new one, it returns nil when the collector is not created or the feed is closed
+func (h *Hub) restart(key feedKey, f *feed) domain.Collector {
+	collector, err := h.newCollector(key.exchange, key.quote, key.asset, key.interval)
+
+	if err != nil {
+		fmt.Printf("Not able to restart %v prices collector of %v: %v\n", key.asset, key.exchange, err)
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	select {
+	case <-f.closed:
+		return nil
+	default:
+	}
+
+	f.collector = collector
+	f.stats.Running = true
+	f.stats.Restarts++
+
+	collector.Regist(func(ohlc *domain.OHLC) { h.publish(key, f, ohlc) })
+
+	return collector
+}
+
+// detach removes the subscription from the feed of the exchange, quote currency, asset and interval, stopping its collector when it was the last one
+func (h *Hub) detach(subscription *Subscription, asset string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := feedKey{subscription.exchange, subscription.quote, asset, subscription.interval}
+	f, ok := h.feeds[key]
+
+	if !ok || !f.subscriptions[subscription] {
+		return
+	}
+
+	delete(f.subscriptions, subscription)
+	f.stats.Subscribers = len(f.subscriptions)
+
+	if len(f.subscriptions) == 0 {
+		delete(h.feeds, key)
+		close(f.closed)
+		f.collector.Stop()
+	}
+}
+
+// publish stores the price and publishes it to the subscriptions of the feed
+func (h *Hub) publish(key feedKey, f *feed, ohlc *domain.OHLC) {
+	if ohlc.Asset == "" {
+		ohlc.Asset = key.asset
+	}
+
+	symbol := domain.PriceSymbol(key.asset, key.quote)
+
+	if err := h.assetsPricesService.Create(ohlc, symbol, pricesquality.FormatInterval(time.Duration(key.interval)*time.Minute)); err != nil {
+		fmt.Printf("Not able to store %v price: %v\n", symbol, err)
+	}
+
+	h.mu.Lock()
+	f.stats.Candles++
+	f.stats.LastCandle = ohlc.Time
+	subscriptions := make([]*Subscription, 0, len(f.subscriptions))
+
+	for subscription := range f.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+	h.mu.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.publish(ohlc)
+	}
+}
+
+// Subscription is a collector of the prices of a set of assets in a quote currency of an exchange received by a hub.
+// Prices of different assets are published one at a time.
+type Subscription struct {
+	mu          sync.Mutex
+	hub         *Hub
+	exchange    string
+	quote       string
+	assets      []string
+	interval    int
+	observables []domain.OnNewAssetPrice
+	indicators  *[]domain.Indicator
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+// Start receives the prices of the assets until the subscription is stopped
+func (s *Subscription) Start() error {
+	for i, asset := range s.assets {
+		if err := s.hub.attach(s, asset); err != nil {
+			for _, attached := range s.assets[:i] {
+				s.hub.detach(s, attached)
+			}
+
+			return err
+		}
+	}
+
+	<-s.done
+
+	return nil
+}
+
+// Stop stops receiving prices
+func (s *Subscription) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+
+		for _, asset := range s.assets {
+			s.hub.detach(s, asset)
+		}
+	})
+}
+
+// stopped returns true when the subscription was stopped
+func (s *Subscription) stopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Regist add function to be executed when a new price is received
+func (s *Subscription) Regist(observable domain.OnNewAssetPrice) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.observables = append(s.observables, observable)
+}
+
+// SetIndicators set indicators that listen for price changes
+func (s *Subscription) SetIndicators(indicators *[]domain.Indicator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.indicators = indicators
+}
+
+// GetTicker is not supported by subscriptions
+func (s *Subscription) GetTicker(tickerSymbol string) (domain.Decimal, error) {
+	return domain.Decimal{}, errors.New("market data subscriptions do not fetch tickers")
+}
+
+func (s *Subscription) publish(ohlc *domain.OHLC) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.indicators != nil {
+		for _, indicator := range *s.indicators {
+			indicator.AddValue(ohlc)
+		}
+	}
+
+	for _, observable := range s.observables {
+		observable(ohlc)
+	}
+}
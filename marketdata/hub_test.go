@@ -0,0 +1,293 @@
+package marketdata_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/marketdata"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+// collectorStub publishes the prices emitted until it is stopped or it fails
+type collectorStub struct {
+	mu          sync.Mutex
+	observables []domain.OnNewAssetPrice
+	stopped     chan struct{}
+	failed      chan error
+}
+
+func newCollectorStub() *collectorStub {
+	return &collectorStub{stopped: make(chan struct{}), failed: make(chan error, 1)}
+}
+
+func (c *collectorStub) Start() error {
+	select {
+	case <-c.stopped:
+		return nil
+	case err := <-c.failed:
+		return err
+	}
+}
+
+func (c *collectorStub) Stop() { close(c.stopped) }
+
+func (c *collectorStub) Regist(observable domain.OnNewAssetPrice) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observables = append(c.observables, observable)
+}
+
+func (c *collectorStub) SetIndicators(indicators *[]domain.Indicator) {}
+
+func (c *collectorStub) GetTicker(tickerSymbol string) (domain.Decimal, error) {
+	return domain.Decimal{}, nil
+}
+
+func (c *collectorStub) emit(ohlc *domain.OHLC) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, observable := range c.observables {
+		observable(ohlc)
+	}
+}
+
+// collectorsStub creates a collector stub per asset
+type collectorsStub struct {
+	mu         sync.Mutex
+	collectors map[string]*collectorStub
+}
+
+func (c *collectorsStub) factory(exchange, quote, asset string, interval int) (domain.Collector, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if asset == "XYZ" {
+		return nil, errors.New("XYZ does not have a valid kraken pair")
+	}
+
+	c.collectors[asset] = newCollectorStub()
+
+	return c.collectors[asset], nil
+}
+
+func (c *collectorsStub) get(asset string) *collectorStub {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.collectors[asset]
+}
+
+// waitSubscribers waits until the asset has the number of subscribers passed by argument
+func waitSubscribers(t *testing.T, hub *marketdata.Hub, asset string, subscribers int) {
+	for i := 0; i < 100; i++ {
+		for _, stats := range hub.Stats() {
+			if stats.Asset == asset && stats.Subscribers == subscribers {
+				return
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("%v does not have %d subscribers: %+v", asset, subscribers, hub.Stats())
+}
+
+func TestHub(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pricesService := mocks.NewMockAssetsPricesService(ctrl)
+	stubs := &collectorsStub{collectors: map[string]*collectorStub{}}
+	hub := marketdata.NewHub(stubs.factory, pricesService, time.Millisecond)
+
+	btc, btcAndEth := hub.Subscribe(domain.ExchangeKraken, domain.QuoteEUR, []string{"BTC"}, 1), hub.Subscribe(domain.ExchangeKraken, domain.QuoteEUR, []string{"BTC", "ETH"}, 1)
+	received := map[*marketdata.Subscription][]string{}
+	var mu sync.Mutex
+
+	for _, subscription := range []*marketdata.Subscription{btc, btcAndEth} {
+		subscription := subscription
+		subscription.Regist(func(ohlc *domain.OHLC) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[subscription] = append(received[subscription], ohlc.Asset)
+		})
+
+		go subscription.Start()
+	}
+
+	waitSubscribers(t, hub, "BTC", 2)
+	waitSubscribers(t, hub, "ETH", 1)
+
+	t.Run("should store each price once and publish it to every subscription of the asset", func(t *testing.T) {
+		ohlc := &domain.OHLC{Close: domain.NewDecimal(100), Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+		pricesService.EXPECT().Create(ohlc, "BTC", "1m").Return(nil).Times(1)
+
+		stubs.get("BTC").emit(ohlc)
+
+		if len(received[btc]) != 1 || len(received[btcAndEth]) != 1 || received[btc][0] != "BTC" {
+			t.Errorf("got %v and %v want one BTC price each", received[btc], received[btcAndEth])
+		}
+
+		stats := hub.Stats()
+
+		if len(stats) != 2 || stats[0].Exchange != domain.ExchangeKraken || stats[0].Asset != "BTC" || stats[0].Candles != 1 || !stats[0].LastCandle.Equal(ohlc.Time) || !stats[0].Running {
+			t.Errorf("got stats %+v want one BTC candle", stats)
+		}
+	})
+
+	t.Run("should stop the collector of an asset without subscriptions", func(t *testing.T) {
+		btcAndEth.Stop()
+
+		select {
+		case <-stubs.get("ETH").stopped:
+		default:
+			t.Errorf("Expected ETH collector to be stopped")
+		}
+
+		stats := hub.Stats()
+
+		if len(stats) != 1 || stats[0].Asset != "BTC" || stats[0].Subscribers != 1 {
+			t.Errorf("got stats %+v want BTC with one subscriber", stats)
+		}
+
+		btc.Stop()
+
+		if stats := hub.Stats(); len(stats) != 0 {
+			t.Errorf("got stats %+v want none", stats)
+		}
+	})
+
+	t.Run("should keep a collector per exchange of an asset", func(t *testing.T) {
+		kraken, binance := hub.Subscribe(domain.ExchangeKraken, domain.QuoteEUR, []string{"DOT"}, 1), hub.Subscribe(domain.ExchangeBinance, domain.QuoteEUR, []string{"DOT"}, 1)
+
+		go kraken.Start()
+		go binance.Start()
+
+		waitSubscribers(t, hub, "DOT", 1)
+
+		for i := 0; i < 100 && len(hub.Stats()) < 2; i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		stats := hub.Stats()
+
+		if len(stats) != 2 || stats[0].Exchange != domain.ExchangeBinance || stats[1].Exchange != domain.ExchangeKraken {
+			t.Errorf("got stats %+v want DOT of binance and kraken", stats)
+		}
+
+		kraken.Stop()
+		binance.Stop()
+	})
+
+	t.Run("should store the prices of other quote currencies by their pair", func(t *testing.T) {
+		subscription := hub.Subscribe(domain.ExchangeKraken, domain.QuoteUSD, []string{"ADA"}, 1)
+
+		go subscription.Start()
+
+		waitSubscribers(t, hub, "ADA", 1)
+
+		ohlc := &domain.OHLC{Close: domain.NewDecimal(1)}
+		pricesService.EXPECT().Create(ohlc, "ADA/USD", "1m").Return(nil).Times(1)
+
+		stubs.get("ADA").emit(ohlc)
+
+		if stats := hub.Stats(); len(stats) != 1 || stats[0].Quote != domain.QuoteUSD {
+			t.Errorf("got stats %+v want ADA of USD", stats)
+		}
+
+		subscription.Stop()
+	})
+
+	t.Run("should restart the collector of an asset that stops", func(t *testing.T) {
+		subscription := hub.Subscribe(domain.ExchangeKraken, domain.QuoteEUR, []string{"SOL"}, 1)
+		var received int
+		subscription.Regist(func(ohlc *domain.OHLC) {
+			mu.Lock()
+			defer mu.Unlock()
+			received++
+		})
+
+		go subscription.Start()
+
+		waitSubscribers(t, hub, "SOL", 1)
+
+		failed := stubs.get("SOL")
+		failed.failed <- errors.New("websocket connection closed")
+
+		for i := 0; i < 100 && (len(hub.Stats()) != 1 || hub.Stats()[0].Restarts != 1); i++ {
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if stubs.get("SOL") == failed {
+			t.Fatalf("Expected SOL collector to be replaced")
+		}
+
+		ohlc := &domain.OHLC{Close: domain.NewDecimal(20)}
+		pricesService.EXPECT().Create(ohlc, "SOL", "1m").Return(nil).Times(1)
+
+		stubs.get("SOL").emit(ohlc)
+
+		mu.Lock()
+		if received != 1 {
+			t.Errorf("got %d prices want 1", received)
+		}
+		mu.Unlock()
+
+		if stats := hub.Stats(); len(stats) != 1 || stats[0].Restarts != 1 || !stats[0].Running {
+			t.Errorf("got stats %+v want SOL running after one restart", stats)
+		}
+
+		subscription.Stop()
+
+		if stats := hub.Stats(); len(stats) != 0 {
+			t.Errorf("got stats %+v want none", stats)
+		}
+	})
+
+	t.Run("should not attach subscriptions stopped before they start", func(t *testing.T) {
+		subscription := hub.Subscribe(domain.ExchangeKraken, domain.QuoteEUR, []string{"LTC"}, 1)
+		subscription.Stop()
+
+		if err := subscription.Start(); err != nil {
+			t.Fatalf("Not expected Start to return error: %v", err)
+		}
+
+		if stats := hub.Stats(); len(stats) != 0 {
+			t.Errorf("got stats %+v want none", stats)
+		}
+	})
+
+	t.Run("should return error when a collector is not created", func(t *testing.T) {
+		subscription := hub.Subscribe(domain.ExchangeKraken, domain.QuoteEUR, []string{"ETH", "XYZ"}, 1)
+
+		if err := subscription.Start(); err == nil {
+			t.Errorf("Expected Start to return error")
+		}
+
+		if stats := hub.Stats(); len(stats) != 0 {
+			t.Errorf("got stats %+v want none", stats)
+		}
+	})
+}
+
+func TestStatsRepository(t *testing.T) {
+	repository := marketdata.NewStatsRepository(db.NewMemoryDatabase().Collection(db.MARKET_DATA_STATS_COLLECTION))
+
+	repository.Save([]domain.MarketDataStats{{Asset: "ETH", Interval: 1}, {Asset: "BTC", Interval: 1}})
+	repository.Save([]domain.MarketDataStats{{Asset: "BTC", Interval: 1, Candles: 2}})
+
+	got, err := repository.FindAll()
+
+	if err != nil {
+		t.Fatalf("Not expected FindAll to return error: %v", err)
+	}
+
+	if len(*got) != 1 || (*got)[0].Asset != "BTC" || (*got)[0].Candles != 2 {
+		t.Errorf("got %+v want the BTC stats saved last", got)
+	}
+}
@@ -0,0 +1,44 @@
+package marketdata
+
+import (
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// StatsRepository stores the statistics of the market data hub subscriptions
+type StatsRepository struct {
+	repo domain.Repository
+}
+
+// NewStatsRepository returns an instance of StatsRepository
+func NewStatsRepository(repo domain.Repository) *StatsRepository {
+	return &StatsRepository{repo}
+}
+
+// Save replaces the statistics stored
+func (r *StatsRepository) Save(stats []domain.MarketDataStats) error {
+	if err := r.repo.BulkDelete(bson.M{}); err != nil {
+		return err
+	}
+
+	for _, stat := range stats {
+		if err := r.repo.InsertOne(stat); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindAll returns the statistics sorted by asset and interval
+func (r *StatsRepository) FindAll() (*[]domain.MarketDataStats, error) {
+	results := []domain.MarketDataStats{}
+	opts := options.Find().SetSort(bson.D{{Key: "asset", Value: 1}, {Key: "interval", Value: 1}})
+
+	if err := r.repo.FindAll(&results, bson.M{}, opts); err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
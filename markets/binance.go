@@ -0,0 +1,75 @@
+package markets
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fabiodmferreira/crypto-trading/binance"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/symbols"
+)
+
+// binanceExchangeInfo is a type used to decode the symbols of the binance exchangeInfo endpoint
+type binanceExchangeInfo struct {
+	Symbols []struct {
+		Symbol     string `json:"symbol"`
+		Status     string `json:"status"`
+		BaseAsset  string `json:"baseAsset"`
+		QuoteAsset string `json:"quoteAsset"`
+		Filters    []struct {
+			FilterType string `json:"filterType"`
+			TickSize   string `json:"tickSize"`
+			StepSize   string `json:"stepSize"`
+			MinQty     string `json:"minQty"`
+		} `json:"filters"`
+	} `json:"symbols"`
+}
+
+// Binance returns a loader of the markets being traded of the binance exchangeInfo endpoint
+func Binance(client *binance.Client) Loader {
+	return func() ([]domain.Market, error) {
+		var info binanceExchangeInfo
+
+		if err := client.Query(http.MethodGet, "/api/v3/exchangeInfo", url.Values{}, &info); err != nil {
+			return nil, err
+		}
+
+		markets := []domain.Market{}
+
+		for _, pair := range info.Symbols {
+			if pair.Status != "TRADING" {
+				continue
+			}
+
+			market := domain.Market{
+				Exchange:      domain.ExchangeBinance,
+				Base:          symbols.Binance.Asset(pair.BaseAsset),
+				Quote:         symbols.Binance.Asset(pair.QuoteAsset),
+				Symbol:        pair.Symbol,
+				WebsocketName: strings.ToLower(pair.Symbol),
+			}
+
+			for _, filter := range pair.Filters {
+				switch filter.FilterType {
+				case "PRICE_FILTER":
+					market.PriceDecimals = decimalPlaces(filter.TickSize)
+				case "LOT_SIZE":
+					market.LotDecimals = decimalPlaces(filter.StepSize)
+
+					minQty, err := domain.ParseDecimal(filter.MinQty)
+
+					if err != nil {
+						return nil, err
+					}
+
+					market.MinOrderSize = minQty
+				}
+			}
+
+			markets = append(markets, market)
+		}
+
+		return markets, nil
+	}
+}
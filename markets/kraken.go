@@ -0,0 +1,91 @@
+package markets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/symbols"
+)
+
+// krakenPair is a type used to decode the pairs of the kraken AssetPairs endpoint
+type krakenPair struct {
+	Altname      string      `json:"altname"`
+	WSName       string      `json:"wsname"`
+	Base         string      `json:"base"`
+	Quote        string      `json:"quote"`
+	PairDecimals int         `json:"pair_decimals"`
+	LotDecimals  int         `json:"lot_decimals"`
+	OrderMin     interface{} `json:"ordermin"`
+}
+
+// Kraken returns a loader of the markets of the kraken AssetPairs endpoint
+func Kraken(krakenAPI *krakenapi.KrakenAPI) Loader {
+	return func() ([]domain.Market, error) {
+		result, err := krakenAPI.Query("AssetPairs", map[string]string{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		pairs, ok := result.(map[string]interface{})
+
+		if !ok {
+			return nil, fmt.Errorf("unexpected kraken asset pairs response %v", result)
+		}
+
+		markets := []domain.Market{}
+
+		for name, value := range pairs {
+			// dark pool pairs are not traded
+			if strings.HasSuffix(name, ".d") {
+				continue
+			}
+
+			pair, err := decodeKrakenPair(value)
+
+			if err != nil {
+				return nil, fmt.Errorf("decoding kraken pair %v: %v", name, err)
+			}
+
+			market := domain.Market{
+				Exchange:      domain.ExchangeKraken,
+				Base:          symbols.Kraken.Asset(pair.Base),
+				Quote:         symbols.Kraken.Asset(pair.Quote),
+				Symbol:        name,
+				WebsocketName: pair.WSName,
+				PriceDecimals: pair.PairDecimals,
+				LotDecimals:   pair.LotDecimals,
+			}
+
+			if pair.OrderMin != nil {
+				if market.MinOrderSize, err = domain.ParseDecimal(fmt.Sprint(pair.OrderMin)); err != nil {
+					return nil, fmt.Errorf("decoding kraken pair %v minimum order: %v", name, err)
+				}
+			}
+
+			markets = append(markets, market)
+		}
+
+		return markets, nil
+	}
+}
+
+// decodeKrakenPair decodes a pair of the generic response of the kraken API
+func decodeKrakenPair(value interface{}) (*krakenPair, error) {
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pair := &krakenPair{}
+
+	if err := json.Unmarshal(data, pair); err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
@@ -0,0 +1,118 @@
+package markets
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// DefaultCacheDuration is the time markets of an exchange are kept before being loaded again
+const DefaultCacheDuration = 24 * time.Hour
+
+// Loader returns every market of an exchange
+type Loader func() ([]domain.Market, error)
+
+// Service loads the markets of the exchanges and caches them
+type Service struct {
+	mu            sync.Mutex
+	loaders       map[string]Loader
+	cacheDuration time.Duration
+	cache         map[string]cachedMarkets
+}
+
+type cachedMarkets struct {
+	markets  []domain.Market
+	loadedAt time.Time
+}
+
+// NewService returns an instance of markets service with the loaders of the exchanges
+func NewService(loaders map[string]Loader, cacheDuration time.Duration) *Service {
+	return &Service{
+		loaders:       loaders,
+		cacheDuration: cacheDuration,
+		cache:         map[string]cachedMarkets{},
+	}
+}
+
+// Markets returns the markets of the exchange, kraken when the exchange is empty.
+// Markets cached are returned when loading them fails.
+func (s *Service) Markets(exchange string) ([]domain.Market, error) {
+	if exchange == "" {
+		exchange = domain.ExchangeKraken
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.cache[exchange]
+
+	if ok && time.Since(cached.loadedAt) < s.cacheDuration {
+		return cached.markets, nil
+	}
+
+	load, supported := s.loaders[exchange]
+
+	if !supported {
+		return nil, fmt.Errorf("exchange %v is not supported", exchange)
+	}
+
+	markets, err := load()
+
+	if err != nil {
+		if ok {
+			fmt.Printf("Not able to reload %v markets, using markets loaded at %v: %v\n", exchange, cached.loadedAt, err)
+			return cached.markets, nil
+		}
+
+		return nil, fmt.Errorf("loading %v markets: %v", exchange, err)
+	}
+
+	s.cache[exchange] = cachedMarkets{markets, time.Now()}
+
+	return markets, nil
+}
+
+// Market returns the market of the base asset and quote currency in the exchange
+func (s *Service) Market(exchange, base, quote string) (*domain.Market, error) {
+	markets, err := s.Markets(exchange)
+
+	if err != nil {
+		return nil, err
+	}
+
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+
+	for i := range markets {
+		if markets[i].Base == base && markets[i].Quote == quote {
+			market := markets[i]
+			return &market, nil
+		}
+	}
+
+	if exchange == "" {
+		exchange = domain.ExchangeKraken
+	}
+
+	return nil, fmt.Errorf("%v/%v is not traded on %v", base, quote, exchange)
+}
+
+// Static returns a loader of the markets passed by argument
+func Static(markets []domain.Market) Loader {
+	return func() ([]domain.Market, error) {
+		return markets, nil
+	}
+}
+
+// decimalPlaces returns the decimal places of a step, e.g. 2 for 0.01000000
+func decimalPlaces(step string) int {
+	i := strings.Index(step, ".")
+
+	if i < 0 {
+		return 0
+	}
+
+	return len(strings.TrimRight(step[i+1:], "0"))
+}
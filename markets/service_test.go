@@ -0,0 +1,154 @@
+package markets_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	krakenapi "github.com/beldur/kraken-go-api-client"
+	"github.com/fabiodmferreira/crypto-trading/binance/binancetest"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/markets"
+)
+
+func TestService(t *testing.T) {
+	btc := domain.Market{Exchange: domain.ExchangeKraken, Base: "BTC", Quote: "EUR", Symbol: "XXBTZEUR", WebsocketName: "XBT/EUR"}
+	loads := 0
+	var loadErr error
+
+	loader := func() ([]domain.Market, error) {
+		loads++
+
+		if loadErr != nil {
+			return nil, loadErr
+		}
+
+		return []domain.Market{btc}, nil
+	}
+
+	t.Run("should return the market of the base asset and quote currency", func(t *testing.T) {
+		service := markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: loader}, time.Hour)
+
+		got, err := service.Market("", "btc", "eur")
+
+		if err != nil {
+			t.Fatalf("Not expected Market to return error: %v", err)
+		}
+
+		if *got != btc {
+			t.Errorf("got %+v want %+v", got, btc)
+		}
+
+		if _, err := service.Market(domain.ExchangeKraken, "XYZ", "EUR"); err == nil {
+			t.Errorf("Expected Market to return error for pairs not traded")
+		}
+
+		if loads != 1 {
+			t.Errorf("got %d loads want markets to be loaded once", loads)
+		}
+	})
+
+	t.Run("should return error for exchanges not supported", func(t *testing.T) {
+		service := markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: loader}, time.Hour)
+
+		if _, err := service.Market(domain.ExchangeBinance, "BTC", "EUR"); err == nil {
+			t.Errorf("Expected Market to return error")
+		}
+	})
+
+	t.Run("should reload expired markets and keep them when loading fails", func(t *testing.T) {
+		loads = 0
+		service := markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: loader}, 0)
+
+		service.Markets(domain.ExchangeKraken)
+
+		loadErr = errors.New("kraken is not available")
+		got, err := service.Markets(domain.ExchangeKraken)
+
+		if err != nil {
+			t.Fatalf("Not expected Markets to return error: %v", err)
+		}
+
+		if loads != 2 || len(got) != 1 {
+			t.Errorf("got %d loads and markets %+v want 2 loads and the markets cached", loads, got)
+		}
+
+		if _, err := markets.NewService(map[string]markets.Loader{domain.ExchangeKraken: loader}, time.Hour).Markets(domain.ExchangeKraken); err == nil {
+			t.Errorf("Expected Markets to return error when markets were never loaded")
+		}
+	})
+}
+
+func TestKraken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"error":[],"result":{
+			"XXBTZEUR":{"altname":"XBTEUR","wsname":"XBT/EUR","base":"XXBT","quote":"ZEUR","pair_decimals":1,"lot_decimals":8,"ordermin":"0.0001"},
+			"XXBTZEUR.d":{"altname":"XBTEUR.d","base":"XXBT","quote":"ZEUR","pair_decimals":1,"lot_decimals":8},
+			"ADAEUR":{"altname":"ADAEUR","wsname":"ADA/EUR","base":"ADA","quote":"ZEUR","pair_decimals":6,"lot_decimals":8,"ordermin":"15"}
+		}}`)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	api := krakenapi.NewWithClient("key", "c2VjcmV0", &http.Client{Transport: rewriteHost{serverURL}})
+
+	got, err := markets.Kraken(api)()
+
+	if err != nil {
+		t.Fatalf("Not expected Kraken loader to return error: %v", err)
+	}
+
+	want := map[string]domain.Market{
+		"BTC": {Exchange: domain.ExchangeKraken, Base: "BTC", Quote: "EUR", Symbol: "XXBTZEUR", WebsocketName: "XBT/EUR", PriceDecimals: 1, LotDecimals: 8, MinOrderSize: domain.NewDecimal(0.0001)},
+		"ADA": {Exchange: domain.ExchangeKraken, Base: "ADA", Quote: "EUR", Symbol: "ADAEUR", WebsocketName: "ADA/EUR", PriceDecimals: 6, LotDecimals: 8, MinOrderSize: domain.NewDecimalFromInt(15)},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %+v want %+v", got, want)
+	}
+
+	for _, market := range got {
+		if market != want[market.Base] {
+			t.Errorf("got %+v want %+v", market, want[market.Base])
+		}
+	}
+}
+
+func TestBinance(t *testing.T) {
+	server := binancetest.NewServer("key", "secret")
+	defer server.Close()
+
+	server.ExchangeInfo = `{"symbols":[
+		{"symbol":"BTCEUR","status":"TRADING","baseAsset":"BTC","quoteAsset":"EUR","filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"0.01000000","tickSize":"0.01000000"},
+			{"filterType":"LOT_SIZE","minQty":"0.00001000","stepSize":"0.00001000"}
+		]},
+		{"symbol":"XYZEUR","status":"BREAK","baseAsset":"XYZ","quoteAsset":"EUR","filters":[]}
+	]}`
+
+	got, err := markets.Binance(server.Client())()
+
+	if err != nil {
+		t.Fatalf("Not expected Binance loader to return error: %v", err)
+	}
+
+	want := domain.Market{Exchange: domain.ExchangeBinance, Base: "BTC", Quote: "EUR", Symbol: "BTCEUR", WebsocketName: "btceur", PriceDecimals: 2, LotDecimals: 5, MinOrderSize: domain.NewDecimal(0.00001)}
+
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
+type rewriteHost struct {
+	url *url.URL
+}
+
+func (rh rewriteHost) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = rh.url.Scheme, rh.url.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
@@ -0,0 +1,433 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/assetsprices"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/pricesquality"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All are the migrations of this project. New migrations must be appended with the next version.
+var All = []domain.Migration{
+	{
+		Version:     1,
+		Description: "set the interval of assets prices stored without it and delete the duplicated ones",
+		Up:          deduplicateAssetsPrices,
+	},
+	{
+		Version:     2,
+		Description: "create assets prices unique index on asset, interval and date",
+		Up: func(repositories domain.RepositoryFactory) error {
+			return assetsprices.NewRepository(repositories(db.ASSETS_PRICES_COLLECTION)).EnsureIndexes()
+		},
+	},
+	{
+		Version:     3,
+		Description: "create assets prices index on asset and date",
+		Up:          createIndex(db.ASSETS_PRICES_COLLECTION, bson.D{{Key: "asset", Value: 1}, {Key: "date", Value: -1}}),
+	},
+	{
+		Version:     4,
+		Description: "create application execution states index on execution id and date",
+		Up:          createIndex(db.APPLICATION_EXECUTION_STATES_COLLECTION, bson.D{{Key: "executionId", Value: 1}, {Key: "date", Value: 1}}),
+	},
+	{
+		Version:     5,
+		Description: "create assets index on account id and sold",
+		Up:          createIndex(db.ASSETS_COLLECTION, bson.D{{Key: "accountID", Value: 1}, {Key: "sold", Value: 1}}),
+	},
+	{
+		Version:     6,
+		Description: "rename assets buytime and selltime fields to buyTime and sellTime",
+		Up:          renameFields(db.ASSETS_COLLECTION, [][2]string{{"buytime", "buyTime"}, {"selltime", "sellTime"}}),
+	},
+	{
+		Version:     7,
+		Description: "expire application execution states by expireAt",
+		Up:          createTTLIndex(db.APPLICATION_EXECUTION_STATES_COLLECTION, "expireAt"),
+	},
+	{
+		Version:     8,
+		Description: "create application execution states rollups index on execution id, resolution and date",
+		Up:          createIndex(db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION, bson.D{{Key: "executionId", Value: 1}, {Key: "resolution", Value: 1}, {Key: "date", Value: 1}}),
+	},
+	{
+		Version:     9,
+		Description: "expire application execution states rollups by expireAt",
+		Up:          createTTLIndex(db.APPLICATION_EXECUTION_STATES_ROLLUPS_COLLECTION, "expireAt"),
+	},
+	{
+		Version:     10,
+		Description: "create ledger index on account id and date",
+		Up:          createIndex(db.LEDGER_COLLECTION, bson.D{{Key: "accountId", Value: 1}, {Key: "date", Value: 1}}),
+	},
+	{
+		Version:     11,
+		Description: "create ledger opening entries with the amount of existing accounts",
+		Up:          openLedgers,
+	},
+	{
+		Version:     12,
+		Description: "convert money, prices and quantities to decimal128",
+		Up: convertToDecimal(map[string][]string{
+			db.ACCOUNTS_COLLECTION:   {"amount"},
+			db.ASSETS_COLLECTION:     {"amount", "buyPrice", "sellPrice"},
+			db.LEDGER_COLLECTION:     {"amount"},
+			db.DCA_ASSETS_COLLECTION: {"amount", "price", "fiatamount"},
+			db.BENCHMARKS_COLLECTION: {"output.finalamount", "output.assetsamountpending", "output.assetsvaluepending"},
+		}),
+	},
+	{
+		Version:     13,
+		Description: "create trades index on account id and date",
+		Up:          createIndex(db.TRADES_COLLECTION, bson.D{{Key: "accountId", Value: 1}, {Key: "date", Value: 1}}),
+	},
+	{
+		Version:     14,
+		Description: "create trades of the assets sold before trades were recorded",
+		Up:          recordSoldAssetsTrades,
+	},
+	{
+		Version:     15,
+		Description: "set the asset of the lots and trades of each application account",
+		Up:          setLotsSymbols,
+	},
+	{
+		Version:     16,
+		Description: "create shadow orders index on application id and date",
+		Up:          createIndex(db.SHADOW_ORDERS_COLLECTION, bson.D{{Key: "applicationId", Value: 1}, {Key: "createdAt", Value: 1}}),
+	},
+	{
+		Version:     17,
+		Description: "create options versions unique index on application id and version",
+		Up:          createUniqueIndex(db.OPTIONS_VERSIONS_COLLECTION, bson.D{{Key: "applicationId", Value: 1}, {Key: "version", Value: 1}}),
+	},
+	{
+		Version:     18,
+		Description: "convert assets prices and benchmarks last prices to decimal128",
+		Up: convertToDecimal(map[string][]string{
+			db.ASSETS_PRICES_COLLECTION: {"o", "c", "h", "l", "v"},
+			db.BENCHMARKS_COLLECTION:    {"output.lastprice"},
+		}),
+	},
+	{
+		Version:     19,
+		Description: "expire application execution states of applications stored without expireAt",
+		Up:          expireApplicationsStates(domain.DefaultStatesRetentionPolicy.RawRetention),
+	},
+}
+
+// createIndex returns a migration that creates an index on the collection passed by argument
+func createIndex(collection string, keys bson.D) func(domain.RepositoryFactory) error {
+	return func(repositories domain.RepositoryFactory) error {
+		return repositories(collection).CreateIndex(mongo.IndexModel{Keys: keys, Options: options.Index()})
+	}
+}
+
+// createUniqueIndex returns a migration that creates an unique index on the collection passed by argument
+func createUniqueIndex(collection string, keys bson.D) func(domain.RepositoryFactory) error {
+	return func(repositories domain.RepositoryFactory) error {
+		return repositories(collection).CreateIndex(mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(true)})
+	}
+}
+
+// createTTLIndex returns a migration that deletes documents of the collection when the date of the field passed by argument is reached
+func createTTLIndex(collection string, field string) func(domain.RepositoryFactory) error {
+	return func(repositories domain.RepositoryFactory) error {
+		return repositories(collection).CreateIndex(mongo.IndexModel{
+			Keys:    bson.D{{Key: field, Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		})
+	}
+}
+
+// renameFields returns a migration that renames fields of every document of the collection passed by argument.
+// Each field is a pair of the current name and the new name.
+func renameFields(collection string, fields [][2]string) func(domain.RepositoryFactory) error {
+	return func(repositories domain.RepositoryFactory) error {
+		repo := repositories(collection)
+
+		for _, field := range fields {
+			from, to := field[0], field[1]
+			err := repo.BulkUpdate(bson.M{from: bson.M{"$exists": true}}, bson.M{"$rename": bson.M{from: to}})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// storedAssetPrice has the fields of an asset price that identify it
+type storedAssetPrice struct {
+	ID       primitive.ObjectID `bson:"_id"`
+	Asset    string             `bson:"asset"`
+	Interval string             `bson:"interval"`
+	Date     time.Time          `bson:"date"`
+	EndDate  time.Time          `bson:"endDate"`
+}
+
+// deduplicateAssetsPrices sets the interval of the prices stored before it was recorded and deletes the prices
+// with the same asset, interval and date as an older one, so the unique index can be created.
+// Prices with an end date get the duration until it rounded to minutes, the others were fetched from Coindesk.
+func deduplicateAssetsPrices(repositories domain.RepositoryFactory) error {
+	repo := repositories(db.ASSETS_PRICES_COLLECTION)
+
+	var prices []storedAssetPrice
+	err := repo.FindAll(&prices, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+
+	for _, price := range prices {
+		if price.Interval == "" {
+			price.Interval = pricesquality.FormatInterval(price.EndDate.Sub(price.Date).Round(time.Minute))
+
+			if price.Interval == "" {
+				price.Interval = assetsprices.CoindeskInterval
+			}
+
+			if err := repo.UpdateOne(bson.M{"_id": price.ID}, bson.M{"$set": bson.M{"interval": price.Interval}}); err != nil {
+				return err
+			}
+		}
+
+		key := fmt.Sprintf("%v/%v/%v", price.Asset, price.Interval, price.Date.UnixNano())
+
+		if seen[key] {
+			if err := repo.DeleteByID(price.ID.Hex()); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// openLedgers adds an adjustment with the account amount to the ledger of accounts without entries
+func openLedgers(repositories domain.RepositoryFactory) error {
+	var existingAccounts []domain.Account
+	err := repositories(db.ACCOUNTS_COLLECTION).FindAll(&existingAccounts, bson.M{}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	ledgerRepository := accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION))
+
+	for _, account := range existingAccounts {
+		entries, err := ledgerRepository.FindAll(account.ID.Hex(), time.Time{}, time.Time{})
+
+		if err != nil {
+			return err
+		}
+
+		if len(*entries) > 0 {
+			continue
+		}
+
+		entry := domain.NewLedgerEntry(account.ID, domain.LedgerAdjustment, account.Amount, account.ID.Timestamp())
+		entry.Description = "opening balance"
+
+		if err := ledgerRepository.Create(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expireApplicationsStates returns a migration that sets the expiration of the states of applications stored before it was recorded
+// to their date plus the retention passed by argument, so they are rolled up and expire like the new ones.
+// Benchmarks states are not changed, they are kept with their benchmark.
+func expireApplicationsStates(retention time.Duration) func(domain.RepositoryFactory) error {
+	return func(repositories domain.RepositoryFactory) error {
+		var applications []domain.Application
+		err := repositories(db.APPLICATIONS_COLLECTION).FindAll(&applications, bson.M{}, nil)
+
+		if err != nil {
+			return err
+		}
+
+		repo := repositories(db.APPLICATION_EXECUTION_STATES_COLLECTION)
+
+		for _, application := range applications {
+			var states []domain.ApplicationExecutionState
+			err := repo.FindAll(&states, bson.M{"executionId": application.ID, "expireAt": bson.M{"$exists": false}}, nil)
+
+			if err != nil {
+				return err
+			}
+
+			for _, state := range states {
+				err := repo.UpdateOne(bson.M{"_id": state.ID}, bson.M{"$set": bson.M{"expireAt": state.Date.Add(retention)}})
+
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// recordSoldAssetsTrades creates a trade for each sold asset that is not part of a trade
+func recordSoldAssetsTrades(repositories domain.RepositoryFactory) error {
+	var soldAssets []domain.Asset
+	err := repositories(db.ASSETS_COLLECTION).FindAll(&soldAssets, bson.M{"sold": true}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	tradesRepo := repositories(db.TRADES_COLLECTION)
+
+	var existingTrades []domain.Trade
+	err = tradesRepo.FindAll(&existingTrades, bson.M{}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	traded := map[primitive.ObjectID]bool{}
+
+	for _, trade := range existingTrades {
+		for _, lot := range trade.Lots {
+			traded[lot.AssetID] = true
+		}
+	}
+
+	for _, asset := range soldAssets {
+		if traded[asset.ID] {
+			continue
+		}
+
+		fills := []domain.LotFill{{Asset: asset, Amount: asset.Amount}}
+		trade, err := domain.NewTrade(asset.AccountID, fills, asset.SellPrice, domain.LotSpecificID, asset.SellTime)
+
+		if err != nil {
+			return err
+		}
+
+		if err := tradesRepo.InsertOne(trade); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setLotsSymbols sets the asset of the application of the account on the lots and trades without asset,
+// they were bought when accounts held a single asset
+func setLotsSymbols(repositories domain.RepositoryFactory) error {
+	var applications []domain.Application
+	err := repositories(db.APPLICATIONS_COLLECTION).FindAll(&applications, bson.M{}, nil)
+
+	if err != nil {
+		return err
+	}
+
+	for _, application := range applications {
+		if application.Asset == "" {
+			continue
+		}
+
+		filter := bson.M{"accountID": application.AccountID, "symbol": bson.M{"$exists": false}}
+		err := repositories(db.ASSETS_COLLECTION).BulkUpdate(filter, bson.M{"$set": bson.M{"symbol": application.Asset}})
+
+		if err != nil {
+			return err
+		}
+
+		filter = bson.M{"accountId": application.AccountID, "asset": bson.M{"$exists": false}}
+		err = repositories(db.TRADES_COLLECTION).BulkUpdate(filter, bson.M{"$set": bson.M{"asset": application.Asset}})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertToDecimal returns a migration that rewrites the numeric fields of each collection as decimal128.
+// Fields are dotted paths and doubles keep their shortest float32 representation, since they were written from float32 fields.
+func convertToDecimal(collections map[string][]string) func(domain.RepositoryFactory) error {
+	return func(repositories domain.RepositoryFactory) error {
+		for collection, fields := range collections {
+			repo := repositories(collection)
+
+			var documents []bson.M
+			err := repo.FindAll(&documents, bson.M{}, nil)
+
+			if err != nil {
+				return err
+			}
+
+			for _, document := range documents {
+				set := bson.M{}
+
+				for _, field := range fields {
+					if value, ok := numberField(document, field); ok {
+						set[field] = value
+					}
+				}
+
+				if len(set) == 0 {
+					continue
+				}
+
+				if err := repo.UpdateOne(bson.M{"_id": document["_id"]}, bson.M{"$set": set}); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// numberField returns the decimal of a double or integer field of the document
+func numberField(document bson.M, field string) (domain.Decimal, bool) {
+	var value interface{} = document
+
+	for _, key := range strings.Split(field, ".") {
+		embedded, ok := value.(bson.M)
+
+		if !ok {
+			return domain.Decimal{}, false
+		}
+
+		value = embedded[key]
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return domain.DecimalFromDouble(v), true
+	case int32:
+		return domain.NewDecimalFromInt(int64(v)), true
+	case int64:
+		return domain.NewDecimalFromInt(v), true
+	default:
+		return domain.Decimal{}, false
+	}
+}
@@ -0,0 +1,123 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Runner applies the migrations that were not applied yet and records them in the migrations collection
+type Runner struct {
+	repo         domain.Repository
+	repositories domain.RepositoryFactory
+	migrations   []domain.Migration
+}
+
+// NewRunner returns an instance of migrations Runner
+func NewRunner(repo domain.Repository, repositories domain.RepositoryFactory, migrations []domain.Migration) *Runner {
+	sorted := make([]domain.Migration, len(migrations))
+	copy(sorted, migrations)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	return &Runner{repo, repositories, sorted}
+}
+
+// NewMongoRunner returns a Runner of every migration of this project for the database passed by argument
+func NewMongoRunner(database *mongo.Database) *Runner {
+	return NewDefaultRunner(db.NewRepositoryFactory(database))
+}
+
+// NewDefaultRunner returns a Runner of every migration of this project for the repositories passed by argument
+func NewDefaultRunner(repositories domain.RepositoryFactory) *Runner {
+	return NewRunner(repositories(db.MIGRATIONS_COLLECTION), repositories, All)
+}
+
+// Run applies the pending migrations by version order and returns the migrations applied
+func (r *Runner) Run() ([]domain.Migration, error) {
+	applied := []domain.Migration{}
+
+	err := r.repo.CreateIndex(mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	if err != nil {
+		return applied, err
+	}
+
+	statuses, err := r.Status()
+
+	if err != nil {
+		return applied, err
+	}
+
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+
+		migration := status.Migration
+
+		err := migration.Up(r.repositories)
+
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%v): %v", migration.Version, migration.Description, err)
+		}
+
+		err = r.repo.InsertOne(domain.MigrationRecord{
+			ID:          primitive.NewObjectID(),
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+		})
+
+		// other process applied the same migration meanwhile
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+
+		if err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// Status returns every migration and whether it was applied
+func (r *Runner) Status() ([]domain.MigrationStatus, error) {
+	var records []domain.MigrationRecord
+
+	err := r.repo.FindAll(&records, bson.M{}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[int]time.Time{}
+
+	for _, record := range records {
+		appliedAt[record.Version] = record.AppliedAt
+	}
+
+	statuses := []domain.MigrationStatus{}
+
+	for _, migration := range r.migrations {
+		date, applied := appliedAt[migration.Version]
+		statuses = append(statuses, domain.MigrationStatus{Migration: migration, Applied: applied, AppliedAt: date})
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,274 @@
+package migrations_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/migrations"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRunnerRun(t *testing.T) {
+	t.Run("should apply migrations by version order and record them", func(t *testing.T) {
+		repo := &mocks.RepositorySpy{}
+		calls := []int{}
+
+		runner := migrations.NewRunner(repo, newRepositoryFactory().factory, []domain.Migration{
+			{Version: 2, Up: func(domain.RepositoryFactory) error { calls = append(calls, 2); return nil }},
+			{Version: 1, Up: func(domain.RepositoryFactory) error { calls = append(calls, 1); return nil }},
+		})
+
+		applied, err := runner.Run()
+
+		if err != nil {
+			t.Fatalf("Not expected Run to return error: %v", err)
+		}
+
+		if !reflect.DeepEqual(calls, []int{1, 2}) {
+			t.Errorf("got %v want [1 2]", calls)
+		}
+
+		if len(applied) != 2 || len(repo.InsertOneCalls) != 2 {
+			t.Errorf("Expected 2 migrations to be applied and recorded, got %d applied and %d records", len(applied), len(repo.InsertOneCalls))
+		}
+
+		if len(repo.CreateIndexCalls) != 1 || !*repo.CreateIndexCalls[0].Options.Unique {
+			t.Errorf("Expected an unique index on version to be created")
+		}
+	})
+
+	t.Run("should stop on the first migration failing", func(t *testing.T) {
+		repo := &mocks.RepositorySpy{}
+		called := false
+
+		runner := migrations.NewRunner(repo, newRepositoryFactory().factory, []domain.Migration{
+			{Version: 1, Up: func(domain.RepositoryFactory) error { return errors.New("failed") }},
+			{Version: 2, Up: func(domain.RepositoryFactory) error { called = true; return nil }},
+		})
+
+		_, err := runner.Run()
+
+		if err == nil {
+			t.Errorf("Expected Run to return error")
+		}
+
+		if called || len(repo.InsertOneCalls) != 0 {
+			t.Errorf("Not expected migrations after the failing one to be applied")
+		}
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	t.Run("should have unique and sequential versions", func(t *testing.T) {
+		for i, migration := range migrations.All {
+			if migration.Version != i+1 {
+				t.Errorf("got version %d want %d", migration.Version, i+1)
+			}
+		}
+	})
+
+	t.Run("should rename assets time fields", func(t *testing.T) {
+		repositories := newRepositoryFactory()
+
+		migration := migrations.All[5]
+
+		err := migration.Up(repositories.factory)
+
+		if err != nil {
+			t.Fatalf("Not expected migration to return error: %v", err)
+		}
+
+		got := repositories.spies[db.ASSETS_COLLECTION].BulkUpdateCalls
+		want := [][]interface{}{
+			{bson.M{"buytime": bson.M{"$exists": true}}, bson.M{"$rename": bson.M{"buytime": "buyTime"}}},
+			{bson.M{"selltime": bson.M{"$exists": true}}, bson.M{"$rename": bson.M{"selltime": "sellTime"}}},
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("should open the ledger of existing accounts once", func(t *testing.T) {
+		repositories := db.NewMemoryDatabase().Collection
+		account, _ := accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION)).Create("kraken", domain.NewDecimalFromInt(150))
+
+		migration := migrations.All[10]
+
+		for i := 0; i < 2; i++ {
+			if err := migration.Up(repositories); err != nil {
+				t.Fatalf("Not expected migration to return error: %v", err)
+			}
+		}
+
+		ledgerRepository := accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION))
+		entries, _ := ledgerRepository.FindAll(account.ID.Hex(), time.Time{}, time.Time{})
+		balance, _ := ledgerRepository.GetBalance(account.ID.Hex())
+
+		if len(*entries) != 1 || balance != domain.NewDecimalFromInt(150) {
+			t.Errorf("got %d entries and balance %v want 1 entry and balance 150", len(*entries), balance)
+		}
+	})
+}
+
+func TestConvertToDecimalMigration(t *testing.T) {
+	repositories := db.NewMemoryDatabase().Collection
+	assetsRepo := repositories(db.ASSETS_COLLECTION)
+	assetsRepo.InsertOne(bson.M{"amount": float64(float32(0.1)), "buyPrice": 9000.5, "sellPrice": int32(0)})
+	benchmarksRepo := repositories(db.BENCHMARKS_COLLECTION)
+	benchmarksRepo.InsertOne(bson.M{"output": bson.M{"finalamount": float64(float32(2010.3))}})
+
+	if err := migrations.All[11].Up(repositories); err != nil {
+		t.Fatalf("Not expected migration to return error: %v", err)
+	}
+
+	var assets []domain.Asset
+	assetsRepo.FindAll(&assets, bson.M{}, nil)
+
+	if assets[0].Amount != domain.NewDecimal(0.1) || assets[0].BuyPrice != domain.NewDecimal(9000.5) {
+		t.Errorf("got amount %v and buy price %v want 0.1 and 9000.5", assets[0].Amount, assets[0].BuyPrice)
+	}
+
+	var benchmarks []bson.M
+	benchmarksRepo.FindAll(&benchmarks, bson.M{}, nil)
+
+	want, _ := primitive.ParseDecimal128("2010.3")
+
+	if got := benchmarks[0]["output"].(bson.M)["finalamount"]; got != want {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestRecordSoldAssetsTradesMigration(t *testing.T) {
+	repositories := db.NewMemoryDatabase().Collection
+	accountID := primitive.NewObjectID()
+	assetsRepo := repositories(db.ASSETS_COLLECTION)
+	assetsRepo.InsertOne(domain.Asset{ID: primitive.NewObjectID(), AccountID: accountID, Amount: domain.NewDecimal(0.5), BuyPrice: domain.NewDecimalFromInt(100), SellPrice: domain.NewDecimalFromInt(120), Sold: true})
+	assetsRepo.InsertOne(domain.Asset{ID: primitive.NewObjectID(), AccountID: accountID, Amount: domain.NewDecimal(0.5), BuyPrice: domain.NewDecimalFromInt(100)})
+
+	for i := 0; i < 2; i++ {
+		if err := migrations.All[13].Up(repositories); err != nil {
+			t.Fatalf("Not expected migration to return error: %v", err)
+		}
+	}
+
+	var trades []domain.Trade
+	repositories(db.TRADES_COLLECTION).FindAll(&trades, bson.M{}, nil)
+
+	if len(trades) != 1 || trades[0].RealizedPnL.String() != "10" {
+		t.Errorf("got trades %+v want one trade with pnl 10", trades)
+	}
+}
+
+func TestDeduplicateAssetsPricesMigration(t *testing.T) {
+	repositories := db.NewMemoryDatabase().Collection
+	pricesRepo := repositories(db.ASSETS_PRICES_COLLECTION)
+	date := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	pricesRepo.InsertOne(bson.M{"_id": primitive.NewObjectID(), "asset": "BTC", "date": date, "c": 1})
+	pricesRepo.InsertOne(bson.M{"_id": primitive.NewObjectID(), "asset": "BTC", "date": date, "c": 2})
+	pricesRepo.InsertOne(bson.M{"_id": primitive.NewObjectID(), "asset": "BTC", "date": date, "endDate": date.Add(time.Minute - time.Second), "c": 3})
+	pricesRepo.InsertOne(bson.M{"_id": primitive.NewObjectID(), "asset": "BTC", "date": date, "interval": "1m", "c": 4})
+
+	for _, migration := range migrations.All[:2] {
+		if err := migration.Up(repositories); err != nil {
+			t.Fatalf("Not expected migration %d to return error: %v", migration.Version, err)
+		}
+	}
+
+	var prices []domain.AssetPrice
+	pricesRepo.FindAll(&prices, bson.M{}, nil)
+
+	if len(prices) != 2 {
+		t.Fatalf("got %d prices want 2", len(prices))
+	}
+
+	if prices[0].Interval != "1h" || prices[0].Close.String() != "1" || prices[1].Interval != "1m" || prices[1].Close.String() != "3" {
+		t.Errorf("got prices %+v want the first 1h and 1m prices", prices)
+	}
+}
+
+func TestSetLotsSymbolsMigration(t *testing.T) {
+	repositories := db.NewMemoryDatabase().Collection
+	accountID := primitive.NewObjectID()
+	repositories(db.APPLICATIONS_COLLECTION).InsertOne(domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", AccountID: accountID})
+	assetsRepo := repositories(db.ASSETS_COLLECTION)
+	assetsRepo.InsertOne(domain.Asset{ID: primitive.NewObjectID(), AccountID: accountID, Amount: domain.NewDecimal(0.5)})
+	assetsRepo.InsertOne(domain.Asset{ID: primitive.NewObjectID(), Symbol: "ETH", AccountID: accountID, Amount: domain.NewDecimal(2)})
+	repositories(db.TRADES_COLLECTION).InsertOne(domain.Trade{ID: primitive.NewObjectID(), AccountID: accountID})
+
+	if err := migrations.All[14].Up(repositories); err != nil {
+		t.Fatalf("Not expected migration to return error: %v", err)
+	}
+
+	var lots []domain.Asset
+	assetsRepo.FindAll(&lots, bson.M{}, nil)
+
+	if len(lots) != 2 || lots[0].Symbol != "BTC" || lots[1].Symbol != "ETH" {
+		t.Errorf("got lots %+v want BTC and ETH lots", lots)
+	}
+
+	var trades []domain.Trade
+	repositories(db.TRADES_COLLECTION).FindAll(&trades, bson.M{}, nil)
+
+	if len(trades) != 1 || trades[0].Asset != "BTC" {
+		t.Errorf("got trades %+v want one BTC trade", trades)
+	}
+}
+
+func TestExpireApplicationsStatesMigration(t *testing.T) {
+	repositories := db.NewMemoryDatabase().Collection
+	applicationID, benchmarkID := primitive.NewObjectID(), primitive.NewObjectID()
+	repositories(db.APPLICATIONS_COLLECTION).InsertOne(domain.Application{ID: applicationID, Asset: "BTC"})
+
+	date := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	expireAt := date.Add(time.Hour)
+	statesRepo := repositories(db.APPLICATION_EXECUTION_STATES_COLLECTION)
+	statesRepo.InsertOne(domain.ApplicationExecutionState{ID: primitive.NewObjectID(), ExecutionID: applicationID, Date: date})
+	statesRepo.InsertOne(domain.ApplicationExecutionState{ID: primitive.NewObjectID(), ExecutionID: applicationID, Date: date, ExpireAt: &expireAt})
+	statesRepo.InsertOne(domain.ApplicationExecutionState{ID: primitive.NewObjectID(), ExecutionID: benchmarkID, Date: date})
+
+	if err := migrations.All[18].Up(repositories); err != nil {
+		t.Fatalf("Not expected migration to return error: %v", err)
+	}
+
+	var states []domain.ApplicationExecutionState
+	statesRepo.FindAll(&states, bson.M{}, nil)
+
+	retained := date.Add(domain.DefaultStatesRetentionPolicy.RawRetention)
+	want := []*time.Time{&retained, &expireAt, nil}
+
+	if len(states) != len(want) {
+		t.Fatalf("got %d states want %d", len(states), len(want))
+	}
+
+	for i, state := range states {
+		if (state.ExpireAt == nil) != (want[i] == nil) || state.ExpireAt != nil && !state.ExpireAt.Equal(*want[i]) {
+			t.Errorf("got state %d expiring at %v want %v", i, state.ExpireAt, want[i])
+		}
+	}
+}
+
+// repositoryFactory returns a spy per collection
+type repositoryFactory struct {
+	spies map[string]*mocks.RepositorySpy
+}
+
+func newRepositoryFactory() *repositoryFactory {
+	return &repositoryFactory{map[string]*mocks.RepositorySpy{}}
+}
+
+func (r *repositoryFactory) factory(collection string) domain.Repository {
+	if _, ok := r.spies[collection]; !ok {
+		r.spies[collection] = &mocks.RepositorySpy{}
+	}
+
+	return r.spies[collection]
+}
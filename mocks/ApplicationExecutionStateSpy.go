@@ -10,11 +10,12 @@ import (
 )
 
 type ApplicationExecutionStatesRepositorySpy struct {
-	CreateCalls     [][]interface{}
-	AggregateCalls  []interface{}
-	BulkCreateCalls []interface{}
-	BulkDeleteCalls []string
-	FindLastCalls   []interface{}
+	CreateCalls        [][]interface{}
+	AggregateCalls     []interface{}
+	BulkCreateCalls    []interface{}
+	BulkDeleteCalls    []string
+	FindLastCalls      []interface{}
+	DeleteExpiredCalls []time.Time
 }
 
 func (a *ApplicationExecutionStatesRepositorySpy) Create(date time.Time, executionID primitive.ObjectID, state interface{}) error {
@@ -37,6 +38,11 @@ func (a *ApplicationExecutionStatesRepositorySpy) BulkDeleteByExecutionID(id str
 	return nil
 }
 
+func (a *ApplicationExecutionStatesRepositorySpy) DeleteExpired(date time.Time) error {
+	a.DeleteExpiredCalls = append(a.DeleteExpiredCalls, date)
+	return nil
+}
+
 func (a *ApplicationExecutionStatesRepositorySpy) FindLast(filter interface{}) (*domain.ApplicationExecutionState, error) {
 	a.FindLastCalls = append(a.FindLastCalls, filter)
 	return &domain.ApplicationExecutionState{}, nil
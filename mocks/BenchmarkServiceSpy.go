@@ -48,9 +48,9 @@ func (s *BenchmarkServiceSpy) HandleBenchmark(benchmark *domain.Benchmark) error
 	return nil
 }
 
-func (s *BenchmarkServiceSpy) GetDataSources() map[string]map[string]string {
+func (s *BenchmarkServiceSpy) GetDataSources() (map[string]map[string]string, error) {
 	s.GetDataSourcesCalls++
-	return map[string]map[string]string{}
+	return map[string]map[string]string{}, nil
 }
 
 func (s *BenchmarkServiceSpy) AggregateApplicationState(pipeline mongo.Pipeline) (*[]bson.M, error) {
@@ -7,16 +7,17 @@ import (
 )
 
 type RepositorySpy struct {
-	FindAllCalls    [][]interface{}
-	AggregateCalls  [][]interface{}
-	FindOneCalls    [][]interface{}
-	InsertOneCalls  []interface{}
-	UpdateOneCalls  [][]interface{}
-	DeleteByIDCalls []string
-	BulkUpsertCalls []interface{}
-	BulkCreateCalls []interface{}
-	BulkDeleteCalls []interface{}
-	BulkUpdateCalls [][]interface{}
+	FindAllCalls     [][]interface{}
+	AggregateCalls   [][]interface{}
+	FindOneCalls     [][]interface{}
+	InsertOneCalls   []interface{}
+	UpdateOneCalls   [][]interface{}
+	DeleteByIDCalls  []string
+	BulkUpsertCalls  []interface{}
+	BulkCreateCalls  []interface{}
+	BulkDeleteCalls  []interface{}
+	BulkUpdateCalls  [][]interface{}
+	CreateIndexCalls []mongo.IndexModel
 }
 
 func (r *RepositorySpy) FindAll(documents interface{}, query interface{}, opts *options.FindOptions) error {
@@ -44,14 +45,19 @@ func (r *RepositorySpy) UpdateOne(query interface{}, update interface{}) error {
 	return nil
 }
 
+func (r *RepositorySpy) UpdateOneMatched(query interface{}, update interface{}) (bool, error) {
+	r.UpdateOneCalls = append(r.UpdateOneCalls, []interface{}{query, update})
+	return true, nil
+}
+
 func (r *RepositorySpy) DeleteByID(id string) error {
 	r.DeleteByIDCalls = append(r.DeleteByIDCalls, id)
 	return nil
 }
 
-func (r *RepositorySpy) BulkUpsert(documents []bson.M) error {
-	r.BulkUpsertCalls = append(r.BulkUpsertCalls, documents)
-	return nil
+func (r *RepositorySpy) BulkUpsert(documents []bson.M, keys []string) (int, error) {
+	r.BulkUpsertCalls = append(r.BulkUpsertCalls, []interface{}{documents, keys})
+	return len(documents), nil
 }
 
 func (r *RepositorySpy) BulkCreate(documents *[]bson.M) error {
@@ -68,3 +74,8 @@ func (r *RepositorySpy) BulkUpdate(filter bson.M, update bson.M) error {
 	r.BulkUpdateCalls = append(r.BulkUpdateCalls, []interface{}{filter, update})
 	return nil
 }
+
+func (r *RepositorySpy) CreateIndex(index mongo.IndexModel) error {
+	r.CreateIndexCalls = append(r.CreateIndexCalls, index)
+	return nil
+}
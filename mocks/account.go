@@ -7,6 +7,7 @@ package mocks
 import (
 	domain "github.com/fabiodmferreira/crypto-trading/domain"
 	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 	reflect "reflect"
 	time "time"
 )
@@ -65,7 +66,7 @@ func (mr *MockAccountsRepositoryMockRecorder) FindByBroker(broker interface{}) *
 }
 
 // Create mocks base method
-func (m *MockAccountsRepository) Create(broker string, amount float32) (*domain.Account, error) {
+func (m *MockAccountsRepository) Create(broker string, amount domain.Decimal) (*domain.Account, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", broker, amount)
 	ret0, _ := ret[0].(*domain.Account)
@@ -80,7 +81,7 @@ func (mr *MockAccountsRepositoryMockRecorder) Create(broker, amount interface{})
 }
 
 // Withdraw mocks base method
-func (m *MockAccountsRepository) Withdraw(id string, amount float32) error {
+func (m *MockAccountsRepository) Withdraw(id string, amount domain.Decimal) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Withdraw", id, amount)
 	ret0, _ := ret[0].(error)
@@ -94,7 +95,7 @@ func (mr *MockAccountsRepositoryMockRecorder) Withdraw(id, amount interface{}) *
 }
 
 // Deposit mocks base method
-func (m *MockAccountsRepository) Deposit(id string, amount float32) error {
+func (m *MockAccountsRepository) Deposit(id string, amount domain.Decimal) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Deposit", id, amount)
 	ret0, _ := ret[0].(error)
@@ -131,10 +132,10 @@ func (m *MockAccountServiceReader) EXPECT() *MockAccountServiceReaderMockRecorde
 }
 
 // GetAmount mocks base method
-func (m *MockAccountServiceReader) GetAmount() (float32, error) {
+func (m *MockAccountServiceReader) GetAmount() (domain.Decimal, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAmount")
-	ret0, _ := ret[0].(float32)
+	ret0, _ := ret[0].(domain.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -176,10 +177,10 @@ func (mr *MockAccountServiceReaderMockRecorder) FindAllAssets() *gomock.Call {
 }
 
 // GetBalance mocks base method
-func (m *MockAccountServiceReader) GetBalance(startDate, endDate time.Time) (float32, error) {
+func (m *MockAccountServiceReader) GetBalance(startDate, endDate time.Time) (domain.Decimal, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBalance", startDate, endDate)
-	ret0, _ := ret[0].(float32)
+	ret0, _ := ret[0].(domain.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -229,10 +230,10 @@ func (m *MockAccountService) EXPECT() *MockAccountServiceMockRecorder {
 }
 
 // GetAmount mocks base method
-func (m *MockAccountService) GetAmount() (float32, error) {
+func (m *MockAccountService) GetAmount() (domain.Decimal, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAmount")
-	ret0, _ := ret[0].(float32)
+	ret0, _ := ret[0].(domain.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -274,10 +275,10 @@ func (mr *MockAccountServiceMockRecorder) FindAllAssets() *gomock.Call {
 }
 
 // GetBalance mocks base method
-func (m *MockAccountService) GetBalance(startDate, endDate time.Time) (float32, error) {
+func (m *MockAccountService) GetBalance(startDate, endDate time.Time) (domain.Decimal, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBalance", startDate, endDate)
-	ret0, _ := ret[0].(float32)
+	ret0, _ := ret[0].(domain.Decimal)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -304,7 +305,7 @@ func (mr *MockAccountServiceMockRecorder) CheckAssetWithCloserPriceExists(price,
 }
 
 // Withdraw mocks base method
-func (m *MockAccountService) Withdraw(amount float32) error {
+func (m *MockAccountService) Withdraw(amount domain.Decimal) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Withdraw", amount)
 	ret0, _ := ret[0].(error)
@@ -318,7 +319,7 @@ func (mr *MockAccountServiceMockRecorder) Withdraw(amount interface{}) *gomock.C
 }
 
 // Deposit mocks base method
-func (m *MockAccountService) Deposit(amount float32) error {
+func (m *MockAccountService) Deposit(amount domain.Decimal) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Deposit", amount)
 	ret0, _ := ret[0].(error)
@@ -332,7 +333,7 @@ func (mr *MockAccountServiceMockRecorder) Deposit(amount interface{}) *gomock.Ca
 }
 
 // CreateAsset mocks base method
-func (m *MockAccountService) CreateAsset(amount, price float32, time time.Time) (*domain.Asset, error) {
+func (m *MockAccountService) CreateAsset(amount, price domain.Decimal, time time.Time) (*domain.Asset, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateAsset", amount, price, time)
 	ret0, _ := ret[0].(*domain.Asset)
@@ -347,7 +348,7 @@ func (mr *MockAccountServiceMockRecorder) CreateAsset(amount, price, time interf
 }
 
 // SellAsset mocks base method
-func (m *MockAccountService) SellAsset(assetID string, price float32, time time.Time) error {
+func (m *MockAccountService) SellAsset(assetID string, price domain.Decimal, time time.Time) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "SellAsset", assetID, price, time)
 	ret0, _ := ret[0].(error)
@@ -359,3 +360,90 @@ func (mr *MockAccountServiceMockRecorder) SellAsset(assetID, price, time interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellAsset", reflect.TypeOf((*MockAccountService)(nil).SellAsset), assetID, price, time)
 }
+
+// Buy mocks base method
+func (m *MockAccountService) Buy(amount, price domain.Decimal, time time.Time, orderID string) (*domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Buy", amount, price, time, orderID)
+	ret0, _ := ret[0].(*domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Buy indicates an expected call of Buy
+func (mr *MockAccountServiceMockRecorder) Buy(amount, price, time, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Buy", reflect.TypeOf((*MockAccountService)(nil).Buy), amount, price, time, orderID)
+}
+
+// Sell mocks base method
+func (m *MockAccountService) Sell(asset *domain.Asset, price domain.Decimal, time time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sell", asset, price, time)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sell indicates an expected call of Sell
+func (mr *MockAccountServiceMockRecorder) Sell(asset, price, time interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sell", reflect.TypeOf((*MockAccountService)(nil).Sell), asset, price, time)
+}
+
+// SellAmount mocks base method
+func (m *MockAccountService) SellAmount(amount, price domain.Decimal, selection domain.LotSelection, time time.Time, orderID string) (*domain.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SellAmount", amount, price, selection, time, orderID)
+	ret0, _ := ret[0].(*domain.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SellAmount indicates an expected call of SellAmount
+func (mr *MockAccountServiceMockRecorder) SellAmount(amount, price, selection, time, orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellAmount", reflect.TypeOf((*MockAccountService)(nil).SellAmount), amount, price, selection, time, orderID)
+}
+
+// MergeLots mocks base method
+func (m *MockAccountService) MergeLots(ids []primitive.ObjectID) (*domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeLots", ids)
+	ret0, _ := ret[0].(*domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeLots indicates an expected call of MergeLots
+func (mr *MockAccountServiceMockRecorder) MergeLots(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeLots", reflect.TypeOf((*MockAccountService)(nil).MergeLots), ids)
+}
+
+// Adjust mocks base method
+func (m *MockAccountService) Adjust(amount domain.Decimal, description string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", amount, description)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Adjust indicates an expected call of Adjust
+func (mr *MockAccountServiceMockRecorder) Adjust(amount, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockAccountService)(nil).Adjust), amount, description)
+}
+
+// Fee mocks base method
+func (m *MockAccountService) Fee(value domain.Decimal) domain.Decimal {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fee", value)
+	ret0, _ := ret[0].(domain.Decimal)
+	return ret0
+}
+
+// Fee indicates an expected call of Fee
+func (mr *MockAccountServiceMockRecorder) Fee(value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fee", reflect.TypeOf((*MockAccountService)(nil).Fee), value)
+}
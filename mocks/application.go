@@ -66,6 +66,20 @@ func (mr *MockApplicationRepositoryMockRecorder) Create(asset, options, acountID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockApplicationRepository)(nil).Create), asset, options, acountID)
 }
 
+// UpdateOptions mocks base method
+func (m *MockApplicationRepository) UpdateOptions(id string, options domain.ApplicationOptions, updatedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOptions", id, options, updatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOptions indicates an expected call of UpdateOptions
+func (mr *MockApplicationRepositoryMockRecorder) UpdateOptions(id, options, updatedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOptions", reflect.TypeOf((*MockApplicationRepository)(nil).UpdateOptions), id, options, updatedBy)
+}
+
 // FindAll mocks base method
 func (m *MockApplicationRepository) FindAll() (*[]domain.Application, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,266 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/asset.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockAssetsRepositoryReader is a mock of AssetsRepositoryReader interface
+type MockAssetsRepositoryReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetsRepositoryReaderMockRecorder
+}
+
+// MockAssetsRepositoryReaderMockRecorder is the mock recorder for MockAssetsRepositoryReader
+type MockAssetsRepositoryReaderMockRecorder struct {
+	mock *MockAssetsRepositoryReader
+}
+
+// NewMockAssetsRepositoryReader creates a new mock instance
+func NewMockAssetsRepositoryReader(ctrl *gomock.Controller) *MockAssetsRepositoryReader {
+	mock := &MockAssetsRepositoryReader{ctrl: ctrl}
+	mock.recorder = &MockAssetsRepositoryReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetsRepositoryReader) EXPECT() *MockAssetsRepositoryReaderMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method
+func (m *MockAssetsRepositoryReader) FindAll(accountID string) (*[]domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", accountID)
+	ret0, _ := ret[0].(*[]domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockAssetsRepositoryReaderMockRecorder) FindAll(accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockAssetsRepositoryReader)(nil).FindAll), accountID)
+}
+
+// FindPendingAssets mocks base method
+func (m *MockAssetsRepositoryReader) FindPendingAssets(accountID string) (*[]domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingAssets", accountID)
+	ret0, _ := ret[0].(*[]domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingAssets indicates an expected call of FindPendingAssets
+func (mr *MockAssetsRepositoryReaderMockRecorder) FindPendingAssets(accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingAssets", reflect.TypeOf((*MockAssetsRepositoryReader)(nil).FindPendingAssets), accountID)
+}
+
+// FindCheaperAssetPrice mocks base method
+func (m *MockAssetsRepositoryReader) FindCheaperAssetPrice(accountID string) (domain.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCheaperAssetPrice", accountID)
+	ret0, _ := ret[0].(domain.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCheaperAssetPrice indicates an expected call of FindCheaperAssetPrice
+func (mr *MockAssetsRepositoryReaderMockRecorder) FindCheaperAssetPrice(accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCheaperAssetPrice", reflect.TypeOf((*MockAssetsRepositoryReader)(nil).FindCheaperAssetPrice), accountID)
+}
+
+// CheckAssetWithCloserPriceExists mocks base method
+func (m *MockAssetsRepositoryReader) CheckAssetWithCloserPriceExists(accountID string, price, limit float32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAssetWithCloserPriceExists", accountID, price, limit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAssetWithCloserPriceExists indicates an expected call of CheckAssetWithCloserPriceExists
+func (mr *MockAssetsRepositoryReaderMockRecorder) CheckAssetWithCloserPriceExists(accountID, price, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAssetWithCloserPriceExists", reflect.TypeOf((*MockAssetsRepositoryReader)(nil).CheckAssetWithCloserPriceExists), accountID, price, limit)
+}
+
+// GetBalance mocks base method
+func (m *MockAssetsRepositoryReader) GetBalance(accountID string, startDate, endDate time.Time) (domain.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", accountID, startDate, endDate)
+	ret0, _ := ret[0].(domain.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance
+func (mr *MockAssetsRepositoryReaderMockRecorder) GetBalance(accountID, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockAssetsRepositoryReader)(nil).GetBalance), accountID, startDate, endDate)
+}
+
+// MockAssetsRepository is a mock of AssetsRepository interface
+type MockAssetsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAssetsRepositoryMockRecorder
+}
+
+// MockAssetsRepositoryMockRecorder is the mock recorder for MockAssetsRepository
+type MockAssetsRepositoryMockRecorder struct {
+	mock *MockAssetsRepository
+}
+
+// NewMockAssetsRepository creates a new mock instance
+func NewMockAssetsRepository(ctrl *gomock.Controller) *MockAssetsRepository {
+	mock := &MockAssetsRepository{ctrl: ctrl}
+	mock.recorder = &MockAssetsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAssetsRepository) EXPECT() *MockAssetsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindAll mocks base method
+func (m *MockAssetsRepository) FindAll(accountID string) (*[]domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", accountID)
+	ret0, _ := ret[0].(*[]domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockAssetsRepositoryMockRecorder) FindAll(accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockAssetsRepository)(nil).FindAll), accountID)
+}
+
+// FindPendingAssets mocks base method
+func (m *MockAssetsRepository) FindPendingAssets(accountID string) (*[]domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPendingAssets", accountID)
+	ret0, _ := ret[0].(*[]domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPendingAssets indicates an expected call of FindPendingAssets
+func (mr *MockAssetsRepositoryMockRecorder) FindPendingAssets(accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPendingAssets", reflect.TypeOf((*MockAssetsRepository)(nil).FindPendingAssets), accountID)
+}
+
+// FindCheaperAssetPrice mocks base method
+func (m *MockAssetsRepository) FindCheaperAssetPrice(accountID string) (domain.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCheaperAssetPrice", accountID)
+	ret0, _ := ret[0].(domain.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCheaperAssetPrice indicates an expected call of FindCheaperAssetPrice
+func (mr *MockAssetsRepositoryMockRecorder) FindCheaperAssetPrice(accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCheaperAssetPrice", reflect.TypeOf((*MockAssetsRepository)(nil).FindCheaperAssetPrice), accountID)
+}
+
+// CheckAssetWithCloserPriceExists mocks base method
+func (m *MockAssetsRepository) CheckAssetWithCloserPriceExists(accountID string, price, limit float32) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckAssetWithCloserPriceExists", accountID, price, limit)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckAssetWithCloserPriceExists indicates an expected call of CheckAssetWithCloserPriceExists
+func (mr *MockAssetsRepositoryMockRecorder) CheckAssetWithCloserPriceExists(accountID, price, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckAssetWithCloserPriceExists", reflect.TypeOf((*MockAssetsRepository)(nil).CheckAssetWithCloserPriceExists), accountID, price, limit)
+}
+
+// GetBalance mocks base method
+func (m *MockAssetsRepository) GetBalance(accountID string, startDate, endDate time.Time) (domain.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", accountID, startDate, endDate)
+	ret0, _ := ret[0].(domain.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance
+func (mr *MockAssetsRepositoryMockRecorder) GetBalance(accountID, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockAssetsRepository)(nil).GetBalance), accountID, startDate, endDate)
+}
+
+// Sell mocks base method
+func (m *MockAssetsRepository) Sell(id string, price, fee domain.Decimal, sellTime time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sell", id, price, fee, sellTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sell indicates an expected call of Sell
+func (mr *MockAssetsRepositoryMockRecorder) Sell(id, price, fee, sellTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sell", reflect.TypeOf((*MockAssetsRepository)(nil).Sell), id, price, fee, sellTime)
+}
+
+// Split mocks base method
+func (m *MockAssetsRepository) Split(asset *domain.Asset, amount domain.Decimal) (*domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Split", asset, amount)
+	ret0, _ := ret[0].(*domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Split indicates an expected call of Split
+func (mr *MockAssetsRepositoryMockRecorder) Split(asset, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Split", reflect.TypeOf((*MockAssetsRepository)(nil).Split), asset, amount)
+}
+
+// Merge mocks base method
+func (m *MockAssetsRepository) Merge(lots []domain.Asset) (*domain.Asset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", lots)
+	ret0, _ := ret[0].(*domain.Asset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Merge indicates an expected call of Merge
+func (mr *MockAssetsRepositoryMockRecorder) Merge(lots interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockAssetsRepository)(nil).Merge), lots)
+}
+
+// Create mocks base method
+func (m *MockAssetsRepository) Create(asset *domain.Asset) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", asset)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockAssetsRepositoryMockRecorder) Create(asset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAssetsRepository)(nil).Create), asset)
+}
@@ -37,17 +37,17 @@ func (m *MockAssetPriceRepository) EXPECT() *MockAssetPriceRepositoryMockRecorde
 }
 
 // Create mocks base method
-func (m *MockAssetPriceRepository) Create(ohlc *domain.OHLC, asset string) error {
+func (m *MockAssetPriceRepository) Create(ohlc *domain.OHLC, asset, interval string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ohlc, asset)
+	ret := m.ctrl.Call(m, "Create", ohlc, asset, interval)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create
-func (mr *MockAssetPriceRepositoryMockRecorder) Create(ohlc, asset interface{}) *gomock.Call {
+func (mr *MockAssetPriceRepositoryMockRecorder) Create(ohlc, asset, interval interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAssetPriceRepository)(nil).Create), ohlc, asset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAssetPriceRepository)(nil).Create), ohlc, asset, interval)
 }
 
 // FindAll mocks base method
@@ -81,32 +81,62 @@ func (mr *MockAssetPriceRepositoryMockRecorder) Aggregate(pipeline interface{})
 }
 
 // GetLastAssetsPrices mocks base method
-func (m *MockAssetPriceRepository) GetLastAssetsPrices(asset string, limit int) (*[]domain.AssetPrice, error) {
+func (m *MockAssetPriceRepository) GetLastAssetsPrices(asset, interval string, limit int) (*[]domain.AssetPrice, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLastAssetsPrices", asset, limit)
+	ret := m.ctrl.Call(m, "GetLastAssetsPrices", asset, interval, limit)
 	ret0, _ := ret[0].(*[]domain.AssetPrice)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLastAssetsPrices indicates an expected call of GetLastAssetsPrices
-func (mr *MockAssetPriceRepositoryMockRecorder) GetLastAssetsPrices(asset, limit interface{}) *gomock.Call {
+func (mr *MockAssetPriceRepositoryMockRecorder) GetLastAssetsPrices(asset, interval, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAssetsPrices", reflect.TypeOf((*MockAssetPriceRepository)(nil).GetLastAssetsPrices), asset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAssetsPrices", reflect.TypeOf((*MockAssetPriceRepository)(nil).GetLastAssetsPrices), asset, interval, limit)
 }
 
-// BulkCreate mocks base method
-func (m *MockAssetPriceRepository) BulkCreate(documents *[]bson.M) error {
+// GetLastAssetPrice mocks base method
+func (m *MockAssetPriceRepository) GetLastAssetPrice(asset, interval string) (*domain.AssetPrice, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BulkCreate", documents)
+	ret := m.ctrl.Call(m, "GetLastAssetPrice", asset, interval)
+	ret0, _ := ret[0].(*domain.AssetPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastAssetPrice indicates an expected call of GetLastAssetPrice
+func (mr *MockAssetPriceRepositoryMockRecorder) GetLastAssetPrice(asset, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAssetPrice", reflect.TypeOf((*MockAssetPriceRepository)(nil).GetLastAssetPrice), asset, interval)
+}
+
+// BulkUpsert mocks base method
+func (m *MockAssetPriceRepository) BulkUpsert(documents *[]bson.M) (*domain.IngestionResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpsert", documents)
+	ret0, _ := ret[0].(*domain.IngestionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpsert indicates an expected call of BulkUpsert
+func (mr *MockAssetPriceRepositoryMockRecorder) BulkUpsert(documents interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpsert", reflect.TypeOf((*MockAssetPriceRepository)(nil).BulkUpsert), documents)
+}
+
+// EnsureIndexes mocks base method
+func (m *MockAssetPriceRepository) EnsureIndexes() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes")
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// BulkCreate indicates an expected call of BulkCreate
-func (mr *MockAssetPriceRepositoryMockRecorder) BulkCreate(documents interface{}) *gomock.Call {
+// EnsureIndexes indicates an expected call of EnsureIndexes
+func (mr *MockAssetPriceRepositoryMockRecorder) EnsureIndexes() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreate", reflect.TypeOf((*MockAssetPriceRepository)(nil).BulkCreate), documents)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockAssetPriceRepository)(nil).EnsureIndexes))
 }
 
 // MockAssetsPricesService is a mock of AssetsPricesService interface
@@ -133,40 +163,41 @@ func (m *MockAssetsPricesService) EXPECT() *MockAssetsPricesServiceMockRecorder
 }
 
 // GetLastAssetsPrices mocks base method
-func (m *MockAssetsPricesService) GetLastAssetsPrices(asset string, limit int) (*[]domain.AssetPrice, error) {
+func (m *MockAssetsPricesService) GetLastAssetsPrices(asset, interval string, limit int) (*[]domain.AssetPrice, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLastAssetsPrices", asset, limit)
+	ret := m.ctrl.Call(m, "GetLastAssetsPrices", asset, interval, limit)
 	ret0, _ := ret[0].(*[]domain.AssetPrice)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLastAssetsPrices indicates an expected call of GetLastAssetsPrices
-func (mr *MockAssetsPricesServiceMockRecorder) GetLastAssetsPrices(asset, limit interface{}) *gomock.Call {
+func (mr *MockAssetsPricesServiceMockRecorder) GetLastAssetsPrices(asset, interval, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAssetsPrices", reflect.TypeOf((*MockAssetsPricesService)(nil).GetLastAssetsPrices), asset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAssetsPrices", reflect.TypeOf((*MockAssetsPricesService)(nil).GetLastAssetsPrices), asset, interval, limit)
 }
 
 // Create mocks base method
-func (m *MockAssetsPricesService) Create(ohlc *domain.OHLC, asset string) error {
+func (m *MockAssetsPricesService) Create(ohlc *domain.OHLC, asset, interval string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ohlc, asset)
+	ret := m.ctrl.Call(m, "Create", ohlc, asset, interval)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Create indicates an expected call of Create
-func (mr *MockAssetsPricesServiceMockRecorder) Create(ohlc, asset interface{}) *gomock.Call {
+func (mr *MockAssetsPricesServiceMockRecorder) Create(ohlc, asset, interval interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAssetsPricesService)(nil).Create), ohlc, asset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAssetsPricesService)(nil).Create), ohlc, asset, interval)
 }
 
 // FetchAndStoreAssetPrices mocks base method
-func (m *MockAssetsPricesService) FetchAndStoreAssetPrices(asset string, endDate time.Time) error {
+func (m *MockAssetsPricesService) FetchAndStoreAssetPrices(asset string, endDate time.Time) (*domain.IngestionResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FetchAndStoreAssetPrices", asset, endDate)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*domain.IngestionResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FetchAndStoreAssetPrices indicates an expected call of FetchAndStoreAssetPrices
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/broker.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBroker is a mock of Broker interface
+type MockBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrokerMockRecorder
+}
+
+// MockBrokerMockRecorder is the mock recorder for MockBroker
+type MockBrokerMockRecorder struct {
+	mock *MockBroker
+}
+
+// NewMockBroker creates a new mock instance
+func NewMockBroker(ctrl *gomock.Controller) *MockBroker {
+	mock := &MockBroker{ctrl: ctrl}
+	mock.recorder = &MockBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBroker) EXPECT() *MockBrokerMockRecorder {
+	return m.recorder
+}
+
+// AddBuyOrder mocks base method
+func (m *MockBroker) AddBuyOrder(amount, price domain.Decimal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBuyOrder", amount, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBuyOrder indicates an expected call of AddBuyOrder
+func (mr *MockBrokerMockRecorder) AddBuyOrder(amount, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBuyOrder", reflect.TypeOf((*MockBroker)(nil).AddBuyOrder), amount, price)
+}
+
+// AddSellOrder mocks base method
+func (m *MockBroker) AddSellOrder(amount, price domain.Decimal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSellOrder", amount, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSellOrder indicates an expected call of AddSellOrder
+func (mr *MockBrokerMockRecorder) AddSellOrder(amount, price interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSellOrder", reflect.TypeOf((*MockBroker)(nil).AddSellOrder), amount, price)
+}
+
+// PlaceOrder mocks base method
+func (m *MockBroker) PlaceOrder(order domain.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceOrder", order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PlaceOrder indicates an expected call of PlaceOrder
+func (mr *MockBrokerMockRecorder) PlaceOrder(order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockBroker)(nil).PlaceOrder), order)
+}
+
+// SetTicker mocks base method
+func (m *MockBroker) SetTicker(ticker string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetTicker", ticker)
+}
+
+// SetTicker indicates an expected call of SetTicker
+func (mr *MockBrokerMockRecorder) SetTicker(ticker interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTicker", reflect.TypeOf((*MockBroker)(nil).SetTicker), ticker)
+}
+
+// SetQuote mocks base method
+func (m *MockBroker) SetQuote(quote string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetQuote", quote)
+}
+
+// SetQuote indicates an expected call of SetQuote
+func (mr *MockBrokerMockRecorder) SetQuote(quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQuote", reflect.TypeOf((*MockBroker)(nil).SetQuote), quote)
+}
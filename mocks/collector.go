@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/collector.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockCollector is a mock of Collector interface
+type MockCollector struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectorMockRecorder
+}
+
+// MockCollectorMockRecorder is the mock recorder for MockCollector
+type MockCollectorMockRecorder struct {
+	mock *MockCollector
+}
+
+// NewMockCollector creates a new mock instance
+func NewMockCollector(ctrl *gomock.Controller) *MockCollector {
+	mock := &MockCollector{ctrl: ctrl}
+	mock.recorder = &MockCollectorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCollector) EXPECT() *MockCollectorMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method
+func (m *MockCollector) Start() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start
+func (mr *MockCollectorMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockCollector)(nil).Start))
+}
+
+// Stop mocks base method
+func (m *MockCollector) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop
+func (mr *MockCollectorMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockCollector)(nil).Stop))
+}
+
+// Regist mocks base method
+func (m *MockCollector) Regist(observable domain.OnNewAssetPrice) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Regist", observable)
+}
+
+// Regist indicates an expected call of Regist
+func (mr *MockCollectorMockRecorder) Regist(observable interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Regist", reflect.TypeOf((*MockCollector)(nil).Regist), observable)
+}
+
+// SetIndicators mocks base method
+func (m *MockCollector) SetIndicators(indicators *[]domain.Indicator) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetIndicators", indicators)
+}
+
+// SetIndicators indicates an expected call of SetIndicators
+func (mr *MockCollectorMockRecorder) SetIndicators(indicators interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIndicators", reflect.TypeOf((*MockCollector)(nil).SetIndicators), indicators)
+}
+
+// GetTicker mocks base method
+func (m *MockCollector) GetTicker(tickerSymbol string) (domain.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicker", tickerSymbol)
+	ret0, _ := ret[0].(domain.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicker indicates an expected call of GetTicker
+func (mr *MockCollectorMockRecorder) GetTicker(tickerSymbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicker", reflect.TypeOf((*MockCollector)(nil).GetTicker), tickerSymbol)
+}
+
+// MockOHLCReader is a mock of OHLCReader interface
+type MockOHLCReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockOHLCReaderMockRecorder
+}
+
+// MockOHLCReaderMockRecorder is the mock recorder for MockOHLCReader
+type MockOHLCReaderMockRecorder struct {
+	mock *MockOHLCReader
+}
+
+// NewMockOHLCReader creates a new mock instance
+func NewMockOHLCReader(ctrl *gomock.Controller) *MockOHLCReader {
+	mock := &MockOHLCReader{ctrl: ctrl}
+	mock.recorder = &MockOHLCReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOHLCReader) EXPECT() *MockOHLCReaderMockRecorder {
+	return m.recorder
+}
+
+// Read mocks base method
+func (m *MockOHLCReader) Read() (*domain.OHLC, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read")
+	ret0, _ := ret[0].(*domain.OHLC)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read
+func (mr *MockOHLCReaderMockRecorder) Read() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockOHLCReader)(nil).Read))
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/decisionMaker.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockDecisionMaker is a mock of DecisionMaker interface
+type MockDecisionMaker struct {
+	ctrl     *gomock.Controller
+	recorder *MockDecisionMakerMockRecorder
+}
+
+// MockDecisionMakerMockRecorder is the mock recorder for MockDecisionMaker
+type MockDecisionMakerMockRecorder struct {
+	mock *MockDecisionMaker
+}
+
+// NewMockDecisionMaker creates a new mock instance
+func NewMockDecisionMaker(ctrl *gomock.Controller) *MockDecisionMaker {
+	mock := &MockDecisionMaker{ctrl: ctrl}
+	mock.recorder = &MockDecisionMakerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDecisionMaker) EXPECT() *MockDecisionMakerMockRecorder {
+	return m.recorder
+}
+
+// ShouldBuy mocks base method
+func (m *MockDecisionMaker) ShouldBuy() (bool, float32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldBuy")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(float32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ShouldBuy indicates an expected call of ShouldBuy
+func (mr *MockDecisionMakerMockRecorder) ShouldBuy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldBuy", reflect.TypeOf((*MockDecisionMaker)(nil).ShouldBuy))
+}
+
+// ShouldSell mocks base method
+func (m *MockDecisionMaker) ShouldSell() (bool, float32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShouldSell")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(float32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ShouldSell indicates an expected call of ShouldSell
+func (mr *MockDecisionMakerMockRecorder) ShouldSell() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShouldSell", reflect.TypeOf((*MockDecisionMaker)(nil).ShouldSell))
+}
+
+// MockStrategy is a mock of Strategy interface
+type MockStrategy struct {
+	ctrl     *gomock.Controller
+	recorder *MockStrategyMockRecorder
+}
+
+// MockStrategyMockRecorder is the mock recorder for MockStrategy
+type MockStrategyMockRecorder struct {
+	mock *MockStrategy
+}
+
+// NewMockStrategy creates a new mock instance
+func NewMockStrategy(ctrl *gomock.Controller) *MockStrategy {
+	mock := &MockStrategy{ctrl: ctrl}
+	mock.recorder = &MockStrategyMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockStrategy) EXPECT() *MockStrategyMockRecorder {
+	return m.recorder
+}
+
+// Execute mocks base method
+func (m *MockStrategy) Execute() (bool, float32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(float32)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Execute indicates an expected call of Execute
+func (mr *MockStrategyMockRecorder) Execute() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockStrategy)(nil).Execute))
+}
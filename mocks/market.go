@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/market.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockMarketsService is a mock of MarketsService interface
+type MockMarketsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarketsServiceMockRecorder
+}
+
+// MockMarketsServiceMockRecorder is the mock recorder for MockMarketsService
+type MockMarketsServiceMockRecorder struct {
+	mock *MockMarketsService
+}
+
+// NewMockMarketsService creates a new mock instance
+func NewMockMarketsService(ctrl *gomock.Controller) *MockMarketsService {
+	mock := &MockMarketsService{ctrl: ctrl}
+	mock.recorder = &MockMarketsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMarketsService) EXPECT() *MockMarketsServiceMockRecorder {
+	return m.recorder
+}
+
+// Market mocks base method
+func (m *MockMarketsService) Market(exchange, base, quote string) (*domain.Market, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Market", exchange, base, quote)
+	ret0, _ := ret[0].(*domain.Market)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Market indicates an expected call of Market
+func (mr *MockMarketsServiceMockRecorder) Market(exchange, base, quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Market", reflect.TypeOf((*MockMarketsService)(nil).Market), exchange, base, quote)
+}
+
+// Markets mocks base method
+func (m *MockMarketsService) Markets(exchange string) ([]domain.Market, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Markets", exchange)
+	ret0, _ := ret[0].([]domain.Market)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Markets indicates an expected call of Markets
+func (mr *MockMarketsServiceMockRecorder) Markets(exchange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Markets", reflect.TypeOf((*MockMarketsService)(nil).Markets), exchange)
+}
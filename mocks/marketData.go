@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/marketData.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockMarketDataStatsRepository is a mock of MarketDataStatsRepository interface
+type MockMarketDataStatsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarketDataStatsRepositoryMockRecorder
+}
+
+// MockMarketDataStatsRepositoryMockRecorder is the mock recorder for MockMarketDataStatsRepository
+type MockMarketDataStatsRepositoryMockRecorder struct {
+	mock *MockMarketDataStatsRepository
+}
+
+// NewMockMarketDataStatsRepository creates a new mock instance
+func NewMockMarketDataStatsRepository(ctrl *gomock.Controller) *MockMarketDataStatsRepository {
+	mock := &MockMarketDataStatsRepository{ctrl: ctrl}
+	mock.recorder = &MockMarketDataStatsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMarketDataStatsRepository) EXPECT() *MockMarketDataStatsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method
+func (m *MockMarketDataStatsRepository) Save(stats []domain.MarketDataStats) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", stats)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockMarketDataStatsRepositoryMockRecorder) Save(stats interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockMarketDataStatsRepository)(nil).Save), stats)
+}
+
+// FindAll mocks base method
+func (m *MockMarketDataStatsRepository) FindAll() (*[]domain.MarketDataStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll")
+	ret0, _ := ret[0].(*[]domain.MarketDataStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockMarketDataStatsRepositoryMockRecorder) FindAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockMarketDataStatsRepository)(nil).FindAll))
+}
@@ -13,6 +13,57 @@ import (
 	time "time"
 )
 
+// MockNotificationChannel is a mock of NotificationChannel interface
+type MockNotificationChannel struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationChannelMockRecorder
+}
+
+// MockNotificationChannelMockRecorder is the mock recorder for MockNotificationChannel
+type MockNotificationChannelMockRecorder struct {
+	mock *MockNotificationChannel
+}
+
+// NewMockNotificationChannel creates a new mock instance
+func NewMockNotificationChannel(ctrl *gomock.Controller) *MockNotificationChannel {
+	mock := &MockNotificationChannel{ctrl: ctrl}
+	mock.recorder = &MockNotificationChannelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockNotificationChannel) EXPECT() *MockNotificationChannelMockRecorder {
+	return m.recorder
+}
+
+// Receiver mocks base method
+func (m *MockNotificationChannel) Receiver() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Receiver")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Receiver indicates an expected call of Receiver
+func (mr *MockNotificationChannelMockRecorder) Receiver() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Receiver", reflect.TypeOf((*MockNotificationChannel)(nil).Receiver))
+}
+
+// Send mocks base method
+func (m *MockNotificationChannel) Send(notification *domain.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send
+func (mr *MockNotificationChannelMockRecorder) Send(notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockNotificationChannel)(nil).Send), notification)
+}
+
 // MockNotificationsService is a mock of NotificationsService interface
 type MockNotificationsService struct {
 	ctrl     *gomock.Controller
@@ -65,6 +116,20 @@ func (mr *MockNotificationsServiceMockRecorder) CreateEmailNotification(subject,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailNotification", reflect.TypeOf((*MockNotificationsService)(nil).CreateEmailNotification), subject, message, notificationType)
 }
 
+// Notify mocks base method
+func (m *MockNotificationsService) Notify(subject, message, notificationType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", subject, message, notificationType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify
+func (mr *MockNotificationsServiceMockRecorder) Notify(subject, message, notificationType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotificationsService)(nil).Notify), subject, message, notificationType)
+}
+
 // ShouldSendNotification mocks base method
 func (m *MockNotificationsService) ShouldSendNotification() bool {
 	m.ctrl.T.Helper()
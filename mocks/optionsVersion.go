@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/optionsVersion.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockOptionsVersionsRepository is a mock of OptionsVersionsRepository interface
+type MockOptionsVersionsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOptionsVersionsRepositoryMockRecorder
+}
+
+// MockOptionsVersionsRepositoryMockRecorder is the mock recorder for MockOptionsVersionsRepository
+type MockOptionsVersionsRepositoryMockRecorder struct {
+	mock *MockOptionsVersionsRepository
+}
+
+// NewMockOptionsVersionsRepository creates a new mock instance
+func NewMockOptionsVersionsRepository(ctrl *gomock.Controller) *MockOptionsVersionsRepository {
+	mock := &MockOptionsVersionsRepository{ctrl: ctrl}
+	mock.recorder = &MockOptionsVersionsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOptionsVersionsRepository) EXPECT() *MockOptionsVersionsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockOptionsVersionsRepository) Create(version *domain.OptionsVersion) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockOptionsVersionsRepositoryMockRecorder) Create(version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOptionsVersionsRepository)(nil).Create), version)
+}
+
+// FindLast mocks base method
+func (m *MockOptionsVersionsRepository) FindLast(applicationID string) (*domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLast", applicationID)
+	ret0, _ := ret[0].(*domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLast indicates an expected call of FindLast
+func (mr *MockOptionsVersionsRepositoryMockRecorder) FindLast(applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLast", reflect.TypeOf((*MockOptionsVersionsRepository)(nil).FindLast), applicationID)
+}
+
+// FindByVersion mocks base method
+func (m *MockOptionsVersionsRepository) FindByVersion(applicationID string, version int) (*domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByVersion", applicationID, version)
+	ret0, _ := ret[0].(*domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByVersion indicates an expected call of FindByVersion
+func (mr *MockOptionsVersionsRepositoryMockRecorder) FindByVersion(applicationID, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByVersion", reflect.TypeOf((*MockOptionsVersionsRepository)(nil).FindByVersion), applicationID, version)
+}
+
+// FindAll mocks base method
+func (m *MockOptionsVersionsRepository) FindAll(applicationID string) (*[]domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", applicationID)
+	ret0, _ := ret[0].(*[]domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockOptionsVersionsRepositoryMockRecorder) FindAll(applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockOptionsVersionsRepository)(nil).FindAll), applicationID)
+}
+
+// MockOptionsVersionsService is a mock of OptionsVersionsService interface
+type MockOptionsVersionsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOptionsVersionsServiceMockRecorder
+}
+
+// MockOptionsVersionsServiceMockRecorder is the mock recorder for MockOptionsVersionsService
+type MockOptionsVersionsServiceMockRecorder struct {
+	mock *MockOptionsVersionsService
+}
+
+// NewMockOptionsVersionsService creates a new mock instance
+func NewMockOptionsVersionsService(ctrl *gomock.Controller) *MockOptionsVersionsService {
+	mock := &MockOptionsVersionsService{ctrl: ctrl}
+	mock.recorder = &MockOptionsVersionsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOptionsVersionsService) EXPECT() *MockOptionsVersionsServiceMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method
+func (m *MockOptionsVersionsService) Record(application *domain.Application) (*domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", application)
+	ret0, _ := ret[0].(*domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record
+func (mr *MockOptionsVersionsServiceMockRecorder) Record(application interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockOptionsVersionsService)(nil).Record), application)
+}
+
+// FindAll mocks base method
+func (m *MockOptionsVersionsService) FindAll(applicationID string) (*[]domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", applicationID)
+	ret0, _ := ret[0].(*[]domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockOptionsVersionsServiceMockRecorder) FindAll(applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockOptionsVersionsService)(nil).FindAll), applicationID)
+}
+
+// Update mocks base method
+func (m *MockOptionsVersionsService) Update(applicationID string, options domain.ApplicationOptions, author string) (*domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", applicationID, options, author)
+	ret0, _ := ret[0].(*domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockOptionsVersionsServiceMockRecorder) Update(applicationID, options, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOptionsVersionsService)(nil).Update), applicationID, options, author)
+}
+
+// Rollback mocks base method
+func (m *MockOptionsVersionsService) Rollback(applicationID string, version int, author string) (*domain.OptionsVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", applicationID, version, author)
+	ret0, _ := ret[0].(*domain.OptionsVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rollback indicates an expected call of Rollback
+func (mr *MockOptionsVersionsServiceMockRecorder) Rollback(applicationID, version, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockOptionsVersionsService)(nil).Rollback), applicationID, version, author)
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/portfolio.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockPortfolioService is a mock of PortfolioService interface
+type MockPortfolioService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortfolioServiceMockRecorder
+}
+
+// MockPortfolioServiceMockRecorder is the mock recorder for MockPortfolioService
+type MockPortfolioServiceMockRecorder struct {
+	mock *MockPortfolioService
+}
+
+// NewMockPortfolioService creates a new mock instance
+func NewMockPortfolioService(ctrl *gomock.Controller) *MockPortfolioService {
+	mock := &MockPortfolioService{ctrl: ctrl}
+	mock.recorder = &MockPortfolioServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPortfolioService) EXPECT() *MockPortfolioServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPortfolio mocks base method
+func (m *MockPortfolioService) GetPortfolio(accountID, quote string) (*domain.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPortfolio", accountID, quote)
+	ret0, _ := ret[0].(*domain.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPortfolio indicates an expected call of GetPortfolio
+func (mr *MockPortfolioServiceMockRecorder) GetPortfolio(accountID, quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortfolio", reflect.TypeOf((*MockPortfolioService)(nil).GetPortfolio), accountID, quote)
+}
+
+// GetEquity mocks base method
+func (m *MockPortfolioService) GetEquity(accountID, quote string, startDate, endDate time.Time) ([]domain.EquityPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEquity", accountID, quote, startDate, endDate)
+	ret0, _ := ret[0].([]domain.EquityPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEquity indicates an expected call of GetEquity
+func (mr *MockPortfolioServiceMockRecorder) GetEquity(accountID, quote, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEquity", reflect.TypeOf((*MockPortfolioService)(nil).GetEquity), accountID, quote, startDate, endDate)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/reconciliation.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockBrokerAccount is a mock of BrokerAccount interface
+type MockBrokerAccount struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrokerAccountMockRecorder
+}
+
+// MockBrokerAccountMockRecorder is the mock recorder for MockBrokerAccount
+type MockBrokerAccountMockRecorder struct {
+	mock *MockBrokerAccount
+}
+
+// NewMockBrokerAccount creates a new mock instance
+func NewMockBrokerAccount(ctrl *gomock.Controller) *MockBrokerAccount {
+	mock := &MockBrokerAccount{ctrl: ctrl}
+	mock.recorder = &MockBrokerAccountMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBrokerAccount) EXPECT() *MockBrokerAccountMockRecorder {
+	return m.recorder
+}
+
+// GetBalances mocks base method
+func (m *MockBrokerAccount) GetBalances() (map[string]domain.Decimal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalances")
+	ret0, _ := ret[0].(map[string]domain.Decimal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalances indicates an expected call of GetBalances
+func (mr *MockBrokerAccountMockRecorder) GetBalances() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalances", reflect.TypeOf((*MockBrokerAccount)(nil).GetBalances))
+}
+
+// GetOpenOrders mocks base method
+func (m *MockBrokerAccount) GetOpenOrders() ([]domain.OpenOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenOrders")
+	ret0, _ := ret[0].([]domain.OpenOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenOrders indicates an expected call of GetOpenOrders
+func (mr *MockBrokerAccountMockRecorder) GetOpenOrders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenOrders", reflect.TypeOf((*MockBrokerAccount)(nil).GetOpenOrders))
+}
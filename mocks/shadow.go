@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/shadow.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+	time "time"
+)
+
+// MockShadowOrdersRepository is a mock of ShadowOrdersRepository interface
+type MockShadowOrdersRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShadowOrdersRepositoryMockRecorder
+}
+
+// MockShadowOrdersRepositoryMockRecorder is the mock recorder for MockShadowOrdersRepository
+type MockShadowOrdersRepositoryMockRecorder struct {
+	mock *MockShadowOrdersRepository
+}
+
+// NewMockShadowOrdersRepository creates a new mock instance
+func NewMockShadowOrdersRepository(ctrl *gomock.Controller) *MockShadowOrdersRepository {
+	mock := &MockShadowOrdersRepository{ctrl: ctrl}
+	mock.recorder = &MockShadowOrdersRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockShadowOrdersRepository) EXPECT() *MockShadowOrdersRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockShadowOrdersRepository) Create(order *domain.ShadowOrder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockShadowOrdersRepositoryMockRecorder) Create(order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockShadowOrdersRepository)(nil).Create), order)
+}
+
+// Fill mocks base method
+func (m *MockShadowOrdersRepository) Fill(id primitive.ObjectID, price domain.Decimal, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fill", id, price, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fill indicates an expected call of Fill
+func (mr *MockShadowOrdersRepositoryMockRecorder) Fill(id, price, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fill", reflect.TypeOf((*MockShadowOrdersRepository)(nil).Fill), id, price, date)
+}
+
+// FindAll mocks base method
+func (m *MockShadowOrdersRepository) FindAll(applicationID string) (*[]domain.ShadowOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", applicationID)
+	ret0, _ := ret[0].(*[]domain.ShadowOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockShadowOrdersRepositoryMockRecorder) FindAll(applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockShadowOrdersRepository)(nil).FindAll), applicationID)
+}
+
+// MockShadowService is a mock of ShadowService interface
+type MockShadowService struct {
+	ctrl     *gomock.Controller
+	recorder *MockShadowServiceMockRecorder
+}
+
+// MockShadowServiceMockRecorder is the mock recorder for MockShadowService
+type MockShadowServiceMockRecorder struct {
+	mock *MockShadowService
+}
+
+// NewMockShadowService creates a new mock instance
+func NewMockShadowService(ctrl *gomock.Controller) *MockShadowService {
+	mock := &MockShadowService{ctrl: ctrl}
+	mock.recorder = &MockShadowServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockShadowService) EXPECT() *MockShadowServiceMockRecorder {
+	return m.recorder
+}
+
+// Compare mocks base method
+func (m *MockShadowService) Compare(applicationID string) (*domain.ShadowComparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compare", applicationID)
+	ret0, _ := ret[0].(*domain.ShadowComparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compare indicates an expected call of Compare
+func (mr *MockShadowServiceMockRecorder) Compare(applicationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compare", reflect.TypeOf((*MockShadowService)(nil).Compare), applicationID)
+}
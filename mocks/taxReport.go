@@ -0,0 +1,49 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/taxReport.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockTaxReportService is a mock of TaxReportService interface
+type MockTaxReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaxReportServiceMockRecorder
+}
+
+// MockTaxReportServiceMockRecorder is the mock recorder for MockTaxReportService
+type MockTaxReportServiceMockRecorder struct {
+	mock *MockTaxReportService
+}
+
+// NewMockTaxReportService creates a new mock instance
+func NewMockTaxReportService(ctrl *gomock.Controller) *MockTaxReportService {
+	mock := &MockTaxReportService{ctrl: ctrl}
+	mock.recorder = &MockTaxReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTaxReportService) EXPECT() *MockTaxReportServiceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method
+func (m *MockTaxReportService) Generate(year int, method domain.CostBasisMethod, quote string) (*domain.TaxReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", year, method, quote)
+	ret0, _ := ret[0].(*domain.TaxReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate
+func (mr *MockTaxReportServiceMockRecorder) Generate(year, method, quote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockTaxReportService)(nil).Generate), year, method, quote)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/trade.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockTradesRepository is a mock of TradesRepository interface
+type MockTradesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTradesRepositoryMockRecorder
+}
+
+// MockTradesRepositoryMockRecorder is the mock recorder for MockTradesRepository
+type MockTradesRepositoryMockRecorder struct {
+	mock *MockTradesRepository
+}
+
+// NewMockTradesRepository creates a new mock instance
+func NewMockTradesRepository(ctrl *gomock.Controller) *MockTradesRepository {
+	mock := &MockTradesRepository{ctrl: ctrl}
+	mock.recorder = &MockTradesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTradesRepository) EXPECT() *MockTradesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockTradesRepository) Create(trade *domain.Trade) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", trade)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockTradesRepositoryMockRecorder) Create(trade interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTradesRepository)(nil).Create), trade)
+}
+
+// FindAll mocks base method
+func (m *MockTradesRepository) FindAll(accountID string, startDate, endDate time.Time) (*[]domain.Trade, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", accountID, startDate, endDate)
+	ret0, _ := ret[0].(*[]domain.Trade)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll
+func (mr *MockTradesRepositoryMockRecorder) FindAll(accountID, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTradesRepository)(nil).FindAll), accountID, startDate, endDate)
+}
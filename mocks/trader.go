@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/trader.go
+
+// Package mock_domain is a generated GoMock package.
+package mocks
+
+import (
+	domain "github.com/fabiodmferreira/crypto-trading/domain"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockTrader is a mock of Trader interface
+type MockTrader struct {
+	ctrl     *gomock.Controller
+	recorder *MockTraderMockRecorder
+}
+
+// MockTraderMockRecorder is the mock recorder for MockTrader
+type MockTraderMockRecorder struct {
+	mock *MockTrader
+}
+
+// NewMockTrader creates a new mock instance
+func NewMockTrader(ctrl *gomock.Controller) *MockTrader {
+	mock := &MockTrader{ctrl: ctrl}
+	mock.recorder = &MockTraderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTrader) EXPECT() *MockTraderMockRecorder {
+	return m.recorder
+}
+
+// Buy mocks base method
+func (m *MockTrader) Buy(amount, price domain.Decimal, buyTime time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Buy", amount, price, buyTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Buy indicates an expected call of Buy
+func (mr *MockTraderMockRecorder) Buy(amount, price, buyTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Buy", reflect.TypeOf((*MockTrader)(nil).Buy), amount, price, buyTime)
+}
+
+// Sell mocks base method
+func (m *MockTrader) Sell(amount, price domain.Decimal, sellTime time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sell", amount, price, sellTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sell indicates an expected call of Sell
+func (mr *MockTraderMockRecorder) Sell(amount, price, sellTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sell", reflect.TypeOf((*MockTrader)(nil).Sell), amount, price, sellTime)
+}
@@ -0,0 +1,111 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// httpTimeout is the time a channel waits for a webhook or bot API response
+const httpTimeout = 10 * time.Second
+
+var (
+	styleTags  = regexp.MustCompile(`(?is)<(style|script|head)[^>]*>.*?</(style|script|head)>`)
+	lineTags   = regexp.MustCompile(`(?i)<(br|/p|/div|/tr|/h[1-6]|/li)[^>]*>`)
+	tags       = regexp.MustCompile(`<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n\s*\n\s*`)
+)
+
+// NewChannels returns the channels configured by the options, the email channel is always returned
+func NewChannels(options domain.NotificationOptions, sendMail domain.SendMail) map[string]domain.NotificationChannel {
+	client := &http.Client{Timeout: httpTimeout}
+	channels := map[string]domain.NotificationChannel{
+		domain.NotificationEmail: NewEmailChannel(options, sendMail),
+	}
+
+	if options.Webhook != nil {
+		channels[domain.NotificationWebhook] = NewWebhookChannel(*options.Webhook, client)
+	}
+
+	if options.Slack != nil {
+		channels[domain.NotificationSlack] = NewSlackChannel(*options.Slack, client)
+	}
+
+	if options.Telegram != nil {
+		channels[domain.NotificationTelegram] = NewTelegramChannel(*options.Telegram, client)
+	}
+
+	return channels
+}
+
+// PlainText returns the text of an HTML message with one line per paragraph, row or line break
+func PlainText(message string) string {
+	text := styleTags.ReplaceAllString(message, "")
+	text = lineTags.ReplaceAllString(text, "\n")
+	text = html.UnescapeString(tags.ReplaceAllString(text, ""))
+
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n"))
+}
+
+// postJSON posts the body as JSON with the headers and returns error when the response status is not 2xx
+func postJSON(client *http.Client, endpoint string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := client.Do(req)
+
+	// errors of requests have the URL, which has the token of telegram bots
+	if urlError, ok := err.(*url.Error); ok {
+		return fmt.Errorf("posting to %v: %w", req.URL.Host, urlError.Err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		response, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("%v responded %v: %v", req.URL.Host, res.Status, strings.TrimSpace(string(response)))
+	}
+
+	return nil
+}
+
+// marshal returns the JSON of a body without escaping HTML characters
+func marshal(body interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(body); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,178 @@
+package notifications_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/notifications"
+)
+
+// standIn is a local HTTP server recording the requests of a channel
+type standIn struct {
+	*httptest.Server
+	status   int
+	response string
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newStandIn() *standIn {
+	s := &standIn{status: http.StatusOK, response: "ok"}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+
+		w.WriteHeader(s.status)
+		w.Write([]byte(s.response))
+	}))
+
+	return s
+}
+
+func (s *standIn) lastBody(t *testing.T) map[string]interface{} {
+	var body map[string]interface{}
+
+	if len(s.bodies) == 0 {
+		t.Fatalf("Expected the stand-in to receive a request")
+	}
+
+	if err := json.Unmarshal(s.bodies[len(s.bodies)-1], &body); err != nil {
+		t.Fatalf("Not expected the request body to be invalid JSON: %v", err)
+	}
+
+	return body
+}
+
+var report = &domain.Notification{Title: "Report", Message: "<html><head><style>p {}</style></head><body><p>Balance &amp; assets</p><br><b>100</b> EUR</body></html>", NotificationType: "eventlogs"}
+
+func TestEmailChannel(t *testing.T) {
+	var addr string
+	sendMail := func(a string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		addr = a
+		return nil
+	}
+
+	t.Run("should send by gmail when the options do not have a SMTP server", func(t *testing.T) {
+		channel := notifications.NewEmailChannel(domain.NotificationOptions{Receiver: "to@mail.com"}, sendMail)
+
+		if err := channel.Send(report); err != nil {
+			t.Fatalf("Not expected Send to return error: %v", err)
+		}
+
+		if addr != "smtp.gmail.com:587" {
+			t.Errorf("got %v want smtp.gmail.com:587", addr)
+		}
+	})
+
+	t.Run("should send by the SMTP server of the options", func(t *testing.T) {
+		channel := notifications.NewEmailChannel(domain.NotificationOptions{Receiver: "to@mail.com", SMTP: domain.SMTPOptions{Host: "mail.example.com", Port: 2525}}, sendMail)
+		channel.Send(report)
+
+		if addr != "mail.example.com:2525" {
+			t.Errorf("got %v want mail.example.com:2525", addr)
+		}
+	})
+}
+
+func TestWebhookChannel(t *testing.T) {
+	server := newStandIn()
+	defer server.Close()
+
+	t.Run("should post the notification signed with the secret", func(t *testing.T) {
+		channel := notifications.NewWebhookChannel(domain.WebhookOptions{URL: server.URL + "/hooks", Secret: "secret"}, http.DefaultClient)
+
+		if err := channel.Send(report); err != nil {
+			t.Fatalf("Not expected Send to return error: %v", err)
+		}
+
+		signature := server.requests[0].Header.Get(notifications.SignatureHeader)
+
+		if !notifications.VerifySignature("secret", server.bodies[0], signature) {
+			t.Errorf("got signature %v want the HMAC of the body", signature)
+		}
+
+		if body := server.lastBody(t); body["title"] != "Report" || body["notificationType"] != "eventlogs" {
+			t.Errorf("got %v want the notification", body)
+		}
+	})
+
+	t.Run("should return error when the webhook does not respond 2xx", func(t *testing.T) {
+		server.status = http.StatusInternalServerError
+		defer func() { server.status = http.StatusOK }()
+
+		channel := notifications.NewWebhookChannel(domain.WebhookOptions{URL: server.URL}, http.DefaultClient)
+
+		if err := channel.Send(report); err == nil {
+			t.Errorf("Expected Send to return error")
+		}
+
+		if signature := server.requests[len(server.requests)-1].Header.Get(notifications.SignatureHeader); signature != "" {
+			t.Errorf("got signature %v want none without a secret", signature)
+		}
+	})
+}
+
+func TestSlackChannel(t *testing.T) {
+	server := newStandIn()
+	defer server.Close()
+
+	channel := notifications.NewSlackChannel(domain.SlackOptions{WebhookURL: server.URL + "/services/T0/B0/X"}, http.DefaultClient)
+
+	if err := channel.Send(report); err != nil {
+		t.Fatalf("Not expected Send to return error: %v", err)
+	}
+
+	want := "*Report*\nBalance & assets\n100 EUR"
+
+	if got := server.lastBody(t)["text"]; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestTelegramChannel(t *testing.T) {
+	server := newStandIn()
+	defer server.Close()
+
+	channel := notifications.NewTelegramChannel(domain.TelegramOptions{BotToken: "123:token", ChatID: "-42", APIURL: server.URL}, http.DefaultClient)
+
+	t.Run("should send the message to the chat", func(t *testing.T) {
+		server.response = `{"ok":true}`
+
+		if err := channel.Send(report); err != nil {
+			t.Fatalf("Not expected Send to return error: %v", err)
+		}
+
+		if path := server.requests[0].URL.Path; path != "/bot123:token/sendMessage" {
+			t.Errorf("got path %v want /bot123:token/sendMessage", path)
+		}
+
+		if body := server.lastBody(t); body["chat_id"] != "-42" || body["text"] != "Report\n\nBalance & assets\n100 EUR" {
+			t.Errorf("got %v want the plain text of the report", body)
+		}
+	})
+
+	t.Run("should truncate messages longer than telegram messages", func(t *testing.T) {
+		channel.Send(&domain.Notification{Title: "Long", Message: strings.Repeat("a", 5000)})
+
+		if text := server.lastBody(t)["text"].(string); len([]rune(text)) != 4096 {
+			t.Errorf("got %d characters want 4096", len([]rune(text)))
+		}
+	})
+
+	t.Run("should return the error of telegram without the bot token", func(t *testing.T) {
+		server.status, server.response = http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+
+		err := channel.Send(report)
+
+		if err == nil || !strings.Contains(err.Error(), "chat not found") || strings.Contains(err.Error(), "token") {
+			t.Errorf("got %v want the telegram error without the token", err)
+		}
+	})
+}
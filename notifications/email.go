@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// EmailChannel sends notifications by email to the receiver of the options
+type EmailChannel struct {
+	options  domain.NotificationOptions
+	sendMail domain.SendMail
+}
+
+// NewEmailChannel returns an email channel sending with gmail on port 587 unless the options set another SMTP server.
+// The send mail function is used with starttls, servers with implicit TLS are dialed by the channel.
+func NewEmailChannel(options domain.NotificationOptions, sendMail domain.SendMail) *EmailChannel {
+	return &EmailChannel{options, sendMail}
+}
+
+// Receiver returns the email address of the receiver
+func (c *EmailChannel) Receiver() string {
+	return c.options.Receiver
+}
+
+// Send sends the notification message as the HTML body of the email
+func (c *EmailChannel) Send(notification *domain.Notification) error {
+	from := c.options.Sender
+	to := c.options.Receiver
+	host, port := c.server()
+
+	msg := "From: " + from + "\n" +
+		"To: " + to + "\n" +
+		"Subject: " + notification.Title + "\n" +
+		"MIME-Version: 1.0;\n" +
+		"Content-Type: text/html;\n\n" +
+
+		notification.Message
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	auth := smtp.PlainAuth("", from, c.options.SenderPassword, host)
+
+	if c.options.SMTP.TLS == domain.SMTPImplicitTLS {
+		return sendMailTLS(addr, host, auth, from, []string{to}, []byte(msg))
+	}
+
+	return c.sendMail(addr, auth, from, []string{to}, []byte(msg))
+}
+
+// server returns the host and port of the SMTP server
+func (c *EmailChannel) server() (string, int) {
+	host, port := c.options.SMTP.Host, c.options.SMTP.Port
+
+	if host == "" {
+		host = "smtp.gmail.com"
+	}
+
+	if port == 0 && c.options.SMTP.TLS == domain.SMTPImplicitTLS {
+		port = 465
+	} else if port == 0 {
+		port = 587
+	}
+
+	return host, port
+}
+
+// sendMailTLS sends an email through a SMTP server with implicit TLS
+func sendMailTLS(addr, host string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: host})
+
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+
+	if err != nil {
+		return err
+	}
+
+	defer client.Close()
+
+	if err := client.Auth(auth); err != nil {
+		return err
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+
+	for _, receiver := range to {
+		if err := client.Rcpt(receiver); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(msg); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -26,8 +26,8 @@ type PendingAssetsEmailFormat struct {
 }
 
 func GenerateEventlogReportEmail(
-	amount float32,
-	balance float32,
+	amount domain.Decimal,
+	balance domain.Decimal,
 	startDate time.Time,
 	endDate time.Time,
 	eventsLog *[]domain.EventLog,
@@ -44,7 +44,7 @@ func GenerateEventlogReportEmail(
 	pendingAssetsFormatted := []PendingAssetsEmailFormat{}
 
 	for _, asset := range *pendingAssets {
-		pendingAssetsFormatted = append(pendingAssetsFormatted, PendingAssetsEmailFormat{asset.BuyTime.Format("02-Jan-2006 15:04"), fmt.Sprintf("%.2fBTC", asset.Amount), fmt.Sprintf("%.2f€", asset.BuyPrice), asset.ID.Hex()})
+		pendingAssetsFormatted = append(pendingAssetsFormatted, PendingAssetsEmailFormat{asset.BuyTime.Format("02-Jan-2006 15:04"), fmt.Sprintf("%vBTC", asset.Amount), fmt.Sprintf("%v€", asset.BuyPrice.StringFixed(2)), asset.ID.Hex()})
 
 	}
 
@@ -73,9 +73,9 @@ func GenerateEventlogReportEmail(
 		EventsLog          []EventsLogEmailFormat
 		AssetsPending      []PendingAssetsEmailFormat
 	}{
-		AccountAmount:      fmt.Sprintf("%.2f€", amount),
+		AccountAmount:      fmt.Sprintf("%v€", amount.StringFixed(2)),
 		TotalAssetsPending: fmt.Sprintf("%d", len(*pendingAssets)),
-		Balance:            fmt.Sprintf("%.2f€", balance),
+		Balance:            fmt.Sprintf("%v€", balance.StringFixed(2)),
 		StartDate:          startDate.Format("02-Jan-2006 15:04"),
 		EndDate:            endDate.Format("02-Jan-2006 15:04"),
 		EventsLog:          eventLogFormatted,
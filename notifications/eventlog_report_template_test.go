@@ -16,8 +16,8 @@ func TestGenerateEventlogReportEmail(t *testing.T) {
 
 		date := time.Date(2009, 11, 17, 20, 34, 58, 651387237, time.UTC)
 		got, err := GenerateEventlogReportEmail(
-			5000,
-			-20,
+			domain.NewDecimalFromInt(5000),
+			domain.NewDecimalFromInt(-20),
 			date,
 			date,
 			&[]domain.EventLog{
@@ -32,8 +32,8 @@ func TestGenerateEventlogReportEmail(t *testing.T) {
 			&[]domain.Asset{
 				{
 					ID:       id2,
-					Amount:   0.01,
-					BuyPrice: 9000,
+					Amount:   domain.NewDecimal(0.01),
+					BuyPrice: domain.NewDecimalFromInt(9000),
 					BuyTime:  date,
 				},
 			},
@@ -1,7 +1,8 @@
 package notifications
 
 import (
-	"net/smtp"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
@@ -12,7 +13,7 @@ import (
 type Service struct {
 	notificationsRepository domain.NotificationsRepository
 	options                 domain.NotificationOptions
-	sendMail                domain.SendMail
+	channels                map[string]domain.NotificationChannel
 	appID                   primitive.ObjectID
 }
 
@@ -22,28 +23,12 @@ func NewService(
 	sendMail domain.SendMail,
 	appID primitive.ObjectID,
 ) *Service {
-	return &Service{notificationsRepository, options, sendMail, appID}
+	return &Service{notificationsRepository, options, NewChannels(options, sendMail), appID}
 }
 
 // SendEmail setup an email options and sends it
 func (n *Service) SendEmail(subject, body string) error {
-	from := n.options.Sender
-	pass := n.options.SenderPassword
-	to := n.options.Receiver
-
-	msg := "From: " + from + "\n" +
-		"To: " + to + "\n" +
-		"Subject: " + subject + "\n" +
-		"MIME-Version: 1.0;\n" +
-		"Content-Type: text/html;\n\n" +
-
-		body
-
-	err := n.sendMail("smtp.gmail.com:587",
-		smtp.PlainAuth("", from, pass, "smtp.gmail.com"),
-		from, []string{to}, []byte(msg))
-
-	return err
+	return n.channels[domain.NotificationEmail].Send(&domain.Notification{Title: subject, Message: body})
 }
 
 // FindLastEventLogsNotificationDate returns last notification date
@@ -53,14 +38,43 @@ func (n *Service) FindLastEventLogsNotificationDate() (time.Time, error) {
 
 // CreateEmailNotification stores notification in repository and send email to Receiver
 func (n *Service) CreateEmailNotification(subject, message, notificationType string) error {
+	return n.send(domain.NotificationEmail, subject, message, notificationType)
+}
+
+// Notify stores and sends a notification by each channel routed by the notification type.
+// A channel failing does not stop the others, their errors are returned together.
+func (n *Service) Notify(subject, message, notificationType string) error {
+	errors := []string{}
+
+	for _, channel := range n.options.GetChannels(notificationType) {
+		if err := n.send(channel, subject, message, notificationType); err != nil {
+			errors = append(errors, fmt.Sprintf("%v: %v", channel, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("Not able to send %v notification by %v", notificationType, strings.Join(errors, ", "))
+	}
+
+	return nil
+}
+
+// send stores a notification of the channel, sends it and marks it as sent
+func (n *Service) send(channelName, subject, message, notificationType string) error {
+	channel, ok := n.channels[channelName]
+
+	if !ok {
+		return fmt.Errorf("notification channel %v is not configured", channelName)
+	}
+
 	notification := &domain.Notification{
 		ID:                  primitive.NewObjectID(),
-		To:                  n.options.Receiver,
+		To:                  channel.Receiver(),
 		Title:               subject,
 		Message:             message,
 		CreatedAt:           time.Now(),
 		NotificationType:    notificationType,
-		NotificationChannel: "email",
+		NotificationChannel: channelName,
 		ApplicationID:       n.appID,
 	}
 
@@ -70,18 +84,13 @@ func (n *Service) CreateEmailNotification(subject, message, notificationType str
 		return err
 	}
 
-	err = n.SendEmail(subject, message)
-	if err != nil {
-		return err
-	}
-
-	err = n.notificationsRepository.Sent(notification.ID)
+	err = channel.Send(notification)
 
 	if err != nil {
 		return err
 	}
 
-	return err
+	return n.notificationsRepository.Sent(notification.ID)
 }
 
 // ShouldSendNotification verifies wheter last notification was sent more than 12 hours ago
@@ -1,7 +1,9 @@
 package notifications_test
 
 import (
+	"net/http"
 	"net/smtp"
+	"strings"
 	"testing"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
@@ -34,6 +36,76 @@ func TestCreateEmailNotification(t *testing.T) {
 	}
 }
 
+func TestNotify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	slack, webhook := newStandIn(), newStandIn()
+	defer slack.Close()
+	defer webhook.Close()
+
+	var emails int
+	sendMail := func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		emails++
+		return nil
+	}
+
+	repository := mocks.NewMockNotificationsRepository(ctrl)
+	options := domain.NotificationOptions{
+		Receiver: "a",
+		Slack:    &domain.SlackOptions{WebhookURL: slack.URL},
+		Webhook:  &domain.WebhookOptions{URL: webhook.URL},
+		Routes:   map[string][]string{"eventlogs": {domain.NotificationSlack, domain.NotificationWebhook}},
+	}
+	service := notifications.NewService(repository, options, sendMail, primitive.NewObjectID())
+
+	t.Run("should send by the channels routed by the notification type", func(t *testing.T) {
+		var channels []string
+
+		repository.EXPECT().Create(gomock.Any()).Times(2).Do(func(notification *domain.Notification) {
+			channels = append(channels, notification.NotificationChannel)
+		})
+		repository.EXPECT().Sent(gomock.Any()).Times(2)
+
+		if err := service.Notify("subject", "message", "eventlogs"); err != nil {
+			t.Fatalf("Not expected Notify to return error: %v", err)
+		}
+
+		if len(slack.requests) != 1 || len(webhook.requests) != 1 || emails != 0 {
+			t.Errorf("got %d slack, %d webhook and %d email notifications want slack and webhook", len(slack.requests), len(webhook.requests), emails)
+		}
+
+		if len(channels) != 2 || channels[0] != domain.NotificationSlack || channels[1] != domain.NotificationWebhook {
+			t.Errorf("got notifications of %v want slack and webhook", channels)
+		}
+	})
+
+	t.Run("should send by email the types without a route", func(t *testing.T) {
+		repository.EXPECT().Create(gomock.Any())
+		repository.EXPECT().Sent(gomock.Any())
+
+		service.Notify("subject", "message", "dca")
+
+		if emails != 1 {
+			t.Errorf("got %d emails want 1", emails)
+		}
+	})
+
+	t.Run("should send by the other channels when a channel fails", func(t *testing.T) {
+		slack.status = http.StatusNotFound
+		repository.EXPECT().Create(gomock.Any()).Times(2)
+		repository.EXPECT().Sent(gomock.Any()).Times(1)
+
+		if err := service.Notify("subject", "message", "eventlogs"); err == nil || !strings.Contains(err.Error(), domain.NotificationSlack) {
+			t.Errorf("got %v want the error of slack", err)
+		}
+
+		if len(webhook.requests) != 2 {
+			t.Errorf("got %d webhook notifications want 2", len(webhook.requests))
+		}
+	})
+}
+
 func TestFindLastNotificationDate(t *testing.T) {
 	service, repository, _ := setupNotificationsService(t)
 
@@ -0,0 +1,34 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// SlackChannel posts notifications to a Slack incoming webhook
+type SlackChannel struct {
+	options domain.SlackOptions
+	client  *http.Client
+}
+
+// NewSlackChannel returns an instance of SlackChannel
+func NewSlackChannel(options domain.SlackOptions, client *http.Client) *SlackChannel {
+	return &SlackChannel{options, client}
+}
+
+// Receiver returns the incoming webhook URL
+func (c *SlackChannel) Receiver() string {
+	return c.options.WebhookURL
+}
+
+// Send posts the title in bold followed by the plain text of the message
+func (c *SlackChannel) Send(notification *domain.Notification) error {
+	body, err := marshal(map[string]string{"text": "*" + notification.Title + "*\n" + PlainText(notification.Message)})
+
+	if err != nil {
+		return err
+	}
+
+	return postJSON(c.client, c.options.WebhookURL, body, nil)
+}
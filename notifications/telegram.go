@@ -0,0 +1,56 @@
+package notifications
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// telegramMaxLength is the maximum number of characters of a telegram message
+const telegramMaxLength = 4096
+
+// TelegramChannel sends notifications to a chat with a telegram bot
+type TelegramChannel struct {
+	options domain.TelegramOptions
+	client  *http.Client
+}
+
+// NewTelegramChannel returns an instance of TelegramChannel
+func NewTelegramChannel(options domain.TelegramOptions, client *http.Client) *TelegramChannel {
+	return &TelegramChannel{options, client}
+}
+
+// Receiver returns the chat id
+func (c *TelegramChannel) Receiver() string {
+	return c.options.ChatID
+}
+
+// Send sends the title followed by the plain text of the message, truncated to the length of telegram messages
+func (c *TelegramChannel) Send(notification *domain.Notification) error {
+	text := []rune(notification.Title + "\n\n" + PlainText(notification.Message))
+
+	if len(text) > telegramMaxLength {
+		text = append(text[:telegramMaxLength-1], '…')
+	}
+
+	body, err := marshal(map[string]interface{}{
+		"chat_id":                  c.options.ChatID,
+		"text":                     string(text),
+		"disable_web_page_preview": true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return postJSON(c.client, c.apiURL()+"/bot"+c.options.BotToken+"/sendMessage", body, nil)
+}
+
+func (c *TelegramChannel) apiURL() string {
+	if c.options.APIURL == "" {
+		return "https://api.telegram.org"
+	}
+
+	return strings.TrimSuffix(c.options.APIURL, "/")
+}
@@ -0,0 +1,59 @@
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// SignatureHeader is the header with the HMAC-SHA256 of the webhook body, e.g. sha256=5d2c...
+const SignatureHeader = "X-Crypto-Trading-Signature"
+
+// WebhookChannel posts notifications as JSON to an URL
+type WebhookChannel struct {
+	options domain.WebhookOptions
+	client  *http.Client
+}
+
+// NewWebhookChannel returns an instance of WebhookChannel
+func NewWebhookChannel(options domain.WebhookOptions, client *http.Client) *WebhookChannel {
+	return &WebhookChannel{options, client}
+}
+
+// Receiver returns the webhook URL
+func (c *WebhookChannel) Receiver() string {
+	return c.options.URL
+}
+
+// Send posts the notification signed with the secret of the webhook when it is set
+func (c *WebhookChannel) Send(notification *domain.Notification) error {
+	body, err := marshal(notification)
+
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+
+	if c.options.Secret != "" {
+		headers[SignatureHeader] = Sign(c.options.Secret, body)
+	}
+
+	return postJSON(c.client, c.options.URL, body, headers)
+}
+
+// Sign returns the signature of a webhook body with a secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature returns true when the signature is the signature of the body with the secret
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
@@ -0,0 +1,308 @@
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Service values the accounts of the applications with the last assets prices stored
+type Service struct {
+	applicationsRepository domain.ApplicationRepository
+	assetsRepository       domain.AssetsRepositoryReader
+	ledgerRepository       domain.LedgerRepository
+	tradesRepository       domain.TradesRepository
+	pricesRepository       domain.AssetPriceRepository
+}
+
+// NewService returns an instance of Service
+func NewService(
+	applicationsRepository domain.ApplicationRepository,
+	assetsRepository domain.AssetsRepositoryReader,
+	ledgerRepository domain.LedgerRepository,
+	tradesRepository domain.TradesRepository,
+	pricesRepository domain.AssetPriceRepository,
+) *Service {
+	return &Service{applicationsRepository, assetsRepository, ledgerRepository, tradesRepository, pricesRepository}
+}
+
+// GetPortfolio returns the cash and the holdings by asset of an account, or of every account of the quote currency when the id is empty
+func (s *Service) GetPortfolio(accountID, quote string) (*domain.Portfolio, error) {
+	accounts, quote, err := s.findAccounts(accountID, quote)
+
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &domain.Portfolio{Date: time.Now(), Quote: quote, Holdings: []domain.Holding{}}
+	lots := map[string][]domain.Asset{}
+	realizedPnL := map[string]domain.Decimal{}
+	assets := []string{}
+
+	for _, account := range accounts {
+		cash, err := s.ledgerRepository.GetBalance(account.id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		portfolio.Cash = portfolio.Cash.Add(cash)
+
+		pending, err := s.assetsRepository.FindPendingAssets(account.id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		trades, err := s.tradesRepository.FindAll(account.id, time.Time{}, time.Time{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, asset := range account.assets {
+			if _, ok := lots[asset]; !ok {
+				assets = append(assets, asset)
+				lots[asset] = []domain.Asset{}
+			}
+		}
+
+		for _, lot := range *pending {
+			asset := lot.GetSymbol(account.asset)
+
+			if _, ok := lots[asset]; !ok {
+				assets = append(assets, asset)
+			}
+
+			lots[asset] = append(lots[asset], lot)
+		}
+
+		for _, trade := range *trades {
+			asset := trade.Asset
+
+			if asset == "" {
+				asset = account.asset
+			}
+
+			if _, ok := lots[asset]; !ok {
+				assets = append(assets, asset)
+				lots[asset] = []domain.Asset{}
+			}
+
+			realizedPnL[asset] = realizedPnL[asset].Add(trade.RealizedPnL)
+		}
+	}
+
+	portfolio.Equity = portfolio.Cash
+
+	for _, asset := range assets {
+		price, err := s.lastPrice(domain.PriceSymbol(asset, quote))
+
+		if err != nil {
+			return nil, err
+		}
+
+		portfolio.AddHolding(domain.NewHolding(asset, lots[asset], price, realizedPnL[asset]))
+	}
+
+	return portfolio, nil
+}
+
+// GetEquity returns the value of an account, or of every account of the quote currency when the id is empty, at the end of each day between two dates
+func (s *Service) GetEquity(accountID, quote string, startDate, endDate time.Time) ([]domain.EquityPoint, error) {
+	accounts, quote, err := s.findAccounts(accountID, quote)
+
+	if err != nil {
+		return nil, err
+	}
+
+	startDate = startDate.UTC().Truncate(24 * time.Hour)
+	days := []time.Time{}
+
+	for day := startDate; !day.After(endDate); day = day.Add(24 * time.Hour) {
+		days = append(days, day)
+	}
+
+	points := make([]domain.EquityPoint, len(days))
+
+	for i, day := range days {
+		points[i].Date = day
+	}
+
+	if len(days) == 0 {
+		return points, nil
+	}
+
+	lastDayEnd := days[len(days)-1].Add(24 * time.Hour)
+	prices := map[string][]dailyPrice{}
+
+	for _, account := range accounts {
+		entries, err := s.ledgerRepository.FindAll(account.id, time.Time{}, lastDayEnd)
+
+		if err != nil {
+			return nil, err
+		}
+
+		lots, err := s.assetsRepository.FindAll(account.id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lot := range *lots {
+			asset := lot.GetSymbol(account.asset)
+
+			if _, ok := prices[asset]; !ok {
+				prices[asset], err = s.dailyPrices(domain.PriceSymbol(asset, quote), lastDayEnd)
+
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		for i, day := range days {
+			dayEnd := day.Add(24 * time.Hour)
+
+			for _, entry := range *entries {
+				if entry.Date.Before(dayEnd) {
+					points[i].Cash = points[i].Cash.Add(entry.CashAmount())
+				}
+			}
+
+			for _, lot := range *lots {
+				if lot.BuyTime.Before(dayEnd) && (!lot.Sold || !lot.SellTime.Before(dayEnd)) {
+					price := priceAt(prices[lot.GetSymbol(account.asset)], dayEnd)
+					points[i].MarketValue = points[i].MarketValue.Add(lot.Amount.Mul(price))
+				}
+			}
+		}
+	}
+
+	for i := range points {
+		points[i].Equity = points[i].Cash.Add(points[i].MarketValue)
+	}
+
+	return points, nil
+}
+
+// account is an account, the assets traded on it and the asset of the lots without symbol
+type account struct {
+	id     string
+	asset  string
+	assets []string
+}
+
+// findAccounts returns the accounts used by applications and their quote currency.
+// Accounts are filtered by id when it is not empty, otherwise by the quote currency of their live applications, EUR when it is empty.
+func (s *Service) findAccounts(accountID, quote string) ([]account, string, error) {
+	applications, err := s.applicationsRepository.FindAll()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if quote == "" {
+		quote = domain.DefaultQuote
+	}
+
+	accounts := []account{}
+	found := map[string]bool{}
+
+	for _, application := range *applications {
+		id := application.AccountID.Hex()
+
+		if found[id] || (accountID != "" && id != accountID) || (accountID == "" && (application.GetQuote() != quote || application.IsSimulated())) {
+			continue
+		}
+
+		if accountID != "" {
+			quote = application.GetQuote()
+		}
+
+		found[id] = true
+		assets := []string{}
+
+		for _, allocation := range application.GetAssets() {
+			assets = append(assets, allocation.Asset)
+		}
+
+		accounts = append(accounts, account{id, application.Asset, assets})
+	}
+
+	if accountID != "" && len(accounts) == 0 {
+		return nil, "", fmt.Errorf("account %v is not used by an application", accountID)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].id < accounts[j].id })
+
+	return accounts, quote, nil
+}
+
+// lastPrice returns the close of the last price stored of the asset in any interval, zero when there are no prices
+func (s *Service) lastPrice(asset string) (domain.Decimal, error) {
+	prices, err := s.pricesRepository.GetLastAssetsPrices(asset, "", 1)
+
+	if err != nil || len(*prices) == 0 {
+		return domain.Decimal{}, err
+	}
+
+	return (*prices)[0].Close, nil
+}
+
+// dailyPrice is the last close of a day
+type dailyPrice struct {
+	Date  time.Time      `bson:"date"`
+	Price domain.Decimal `bson:"price"`
+}
+
+// dailyPrices returns the last close of each day with prices before the date passed by argument sorted by date
+func (s *Service) dailyPrices(asset string, endDate time.Time) ([]dailyPrice, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"asset": asset, "date": bson.M{"$lt": endDate}}}},
+		{{Key: "$sort", Value: bson.M{"date": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"year": bson.M{"$year": "$date"}, "month": bson.M{"$month": "$date"}, "day": bson.M{"$dayOfMonth": "$date"}},
+			"date":  bson.M{"$last": "$date"},
+			"price": bson.M{"$last": "$c"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"date": 1}}},
+	}
+
+	results, err := s.pricesRepository.Aggregate(pipeline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]dailyPrice, len(*results))
+
+	for i, result := range *results {
+		data, err := bson.Marshal(result)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bson.Unmarshal(data, &prices[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return prices, nil
+}
+
+// priceAt returns the last price before the date passed by argument, zero when there is none
+func priceAt(prices []dailyPrice, date time.Time) domain.Decimal {
+	i := sort.Search(len(prices), func(i int) bool { return !prices[i].Date.Before(date) })
+
+	if i == 0 {
+		return domain.Decimal{}
+	}
+
+	return prices[i-1].Price
+}
@@ -0,0 +1,201 @@
+package portfolio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/assetsprices"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/portfolio"
+	"github.com/fabiodmferreira/crypto-trading/trades"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPortfolioService(t *testing.T) {
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := db.NewMemoryStorage()
+	repositories := storage.Repositories
+
+	applicationsRepository := app.NewRepository(repositories(db.APPLICATIONS_COLLECTION))
+	assetsRepository := assets.NewRepository(repositories(db.ASSETS_COLLECTION))
+	ledgerRepository := accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION))
+	pricesRepository := assetsprices.NewRepository(repositories(db.ASSETS_PRICES_COLLECTION))
+
+	newAccount := func(asset string, amount int64) *accounts.AccountService {
+		account, _ := accounts.OpenAccount(storage.UnitOfWork, "kraken", domain.NewDecimalFromInt(amount), day)
+		applicationsRepository.Create(asset, domain.ApplicationOptions{}, account.ID)
+		service, _ := accounts.NewAccountService(account.ID.Hex(), accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION)), assetsRepository, ledgerRepository, storage.UnitOfWork)
+
+		return service
+	}
+
+	btc := newAccount("BTC", 1000)
+	btc.Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(100), day.Add(time.Hour), "")
+	btc.Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(200), day.Add(24*time.Hour+time.Hour), "")
+	btc.SellAmount(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(300), domain.LotSelection{Method: domain.LotFIFO}, day.Add(48*time.Hour+time.Hour), "")
+
+	eth := newAccount("ETH", 500)
+	eth.Buy(domain.NewDecimalFromInt(10), domain.NewDecimalFromInt(20), day.Add(time.Hour), "")
+
+	for i, close := range []int64{150, 250, 400} {
+		pricesRepository.Create(&domain.OHLC{Time: day.Add(time.Duration(i*24+12) * time.Hour), EndTime: day.Add(time.Duration(i*24+13) * time.Hour), Close: domain.NewDecimalFromInt(close)}, "BTC", "1h")
+	}
+
+	pricesRepository.Create(&domain.OHLC{Time: day.Add(12 * time.Hour), EndTime: day.Add(13 * time.Hour), Close: domain.NewDecimal(25)}, "ETH", "1h")
+
+	usdAccount, _ := accounts.OpenAccount(storage.UnitOfWork, "kraken", domain.NewDecimalFromInt(800), day)
+	repositories(db.APPLICATIONS_COLLECTION).InsertOne(domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", Quote: domain.QuoteUSD, AccountID: usdAccount.ID})
+	usd, _ := accounts.NewAccountService(usdAccount.ID.Hex(), accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION)), assetsRepository, ledgerRepository, storage.UnitOfWork)
+	usd.Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(120), day.Add(time.Hour), "")
+
+	pricesRepository.Create(&domain.OHLC{Time: day.Add(12 * time.Hour), EndTime: day.Add(13 * time.Hour), Close: domain.NewDecimal(500)}, domain.PriceSymbol("BTC", domain.QuoteUSD), "1h")
+
+	// simulated accounts are not valued with the live accounts
+	shadowAccount, _ := accounts.OpenAccount(storage.UnitOfWork, "kraken", domain.NewDecimalFromInt(300), day)
+	repositories(db.APPLICATIONS_COLLECTION).InsertOne(domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", Mode: domain.ModeShadow, AccountID: shadowAccount.ID})
+
+	service := portfolio.NewService(applicationsRepository, assetsRepository, ledgerRepository, trades.NewRepository(repositories(db.TRADES_COLLECTION)), pricesRepository)
+
+	t.Run("should value the holdings of an account at the last price", func(t *testing.T) {
+		got, err := service.GetPortfolio(btc.ID, "")
+
+		if err != nil {
+			t.Fatalf("Not expected GetPortfolio to return error: %v", err)
+		}
+
+		want := domain.Holding{
+			Asset:         "BTC",
+			Amount:        domain.NewDecimalFromInt(3),
+			AverageCost:   domain.NewDecimal(166.66666667),
+			CostBasis:     domain.NewDecimalFromInt(500),
+			Price:         domain.NewDecimalFromInt(400),
+			MarketValue:   domain.NewDecimalFromInt(1200),
+			UnrealizedPnL: domain.NewDecimalFromInt(700),
+			RealizedPnL:   domain.NewDecimalFromInt(200),
+		}
+
+		if len(got.Holdings) != 1 || got.Holdings[0] != want {
+			t.Errorf("got %+v want %+v", got.Holdings, want)
+		}
+
+		if got.Cash.String() != "700" || got.Equity.String() != "1900" {
+			t.Errorf("got cash %v and equity %v want 700 and 1900", got.Cash, got.Equity)
+		}
+	})
+
+	t.Run("should value every account", func(t *testing.T) {
+		got, _ := service.GetPortfolio("", "")
+
+		if len(got.Holdings) != 2 || got.Cash.String() != "1000" || got.Equity.String() != "2450" {
+			t.Errorf("got %d holdings, cash %v and equity %v want 2, 1000 and 2450", len(got.Holdings), got.Cash, got.Equity)
+		}
+	})
+
+	t.Run("should value the accounts of the quote currency at the prices of the quote", func(t *testing.T) {
+		got, err := service.GetPortfolio("", domain.QuoteUSD)
+
+		if err != nil {
+			t.Fatalf("Not expected GetPortfolio to return error: %v", err)
+		}
+
+		if got.Quote != domain.QuoteUSD || len(got.Holdings) != 1 || got.Cash.String() != "680" || got.Equity.String() != "1180" {
+			t.Errorf("got %v portfolio with %d holdings, cash %v and equity %v want USD, 1, 680 and 1180", got.Quote, len(got.Holdings), got.Cash, got.Equity)
+		}
+
+		byAccount, _ := service.GetPortfolio(usd.ID, "")
+
+		if byAccount.Quote != domain.QuoteUSD || byAccount.Equity.String() != "1180" {
+			t.Errorf("got %v portfolio with equity %v want USD and 1180", byAccount.Quote, byAccount.Equity)
+		}
+	})
+
+	t.Run("should return the equity at the end of each day", func(t *testing.T) {
+		got, err := service.GetEquity(btc.ID, "", day, day.Add(3*24*time.Hour))
+
+		if err != nil {
+			t.Fatalf("Not expected GetEquity to return error: %v", err)
+		}
+
+		// the last day has no prices so the last close is used
+		want := []string{"1100", "1400", "1900", "1900"}
+
+		if len(got) != len(want) {
+			t.Fatalf("got %d points want %d", len(got), len(want))
+		}
+
+		for i := range want {
+			if got[i].Equity.String() != want[i] || !got[i].Date.Equal(day.Add(time.Duration(i*24)*time.Hour)) {
+				t.Errorf("got %v equity %v want %v", got[i].Date, got[i].Equity, want[i])
+			}
+		}
+	})
+
+	t.Run("should return error for accounts without application", func(t *testing.T) {
+		if _, err := service.GetPortfolio("5f0c3b6c6c4a3f1e2c0f1a2b", ""); err == nil {
+			t.Errorf("Expected GetPortfolio to return error")
+		}
+	})
+}
+
+func TestPortfolioServiceMultiAsset(t *testing.T) {
+	day := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage := db.NewMemoryStorage()
+	repositories := storage.Repositories
+
+	assetsRepository := assets.NewRepository(repositories(db.ASSETS_COLLECTION))
+	ledgerRepository := accounts.NewLedgerRepository(repositories(db.LEDGER_COLLECTION))
+	pricesRepository := assetsprices.NewRepository(repositories(db.ASSETS_PRICES_COLLECTION))
+
+	account, _ := accounts.OpenAccount(storage.UnitOfWork, "kraken", domain.NewDecimalFromInt(1000), day)
+	repositories(db.APPLICATIONS_COLLECTION).InsertOne(domain.Application{
+		ID:        primitive.NewObjectID(),
+		Asset:     "BTC",
+		Assets:    []domain.AssetAllocation{{Asset: "BTC"}, {Asset: "ETH"}, {Asset: "ADA"}},
+		AccountID: account.ID,
+	})
+	service, _ := accounts.NewAccountService(account.ID.Hex(), accounts.NewRepository(repositories(db.ACCOUNTS_COLLECTION)), assetsRepository, ledgerRepository, storage.UnitOfWork)
+
+	service.ForAsset("BTC").Buy(domain.NewDecimalFromInt(2), domain.NewDecimalFromInt(100), day.Add(time.Hour), "")
+	service.ForAsset("ETH").Buy(domain.NewDecimalFromInt(10), domain.NewDecimalFromInt(20), day.Add(time.Hour), "")
+
+	pricesRepository.Create(&domain.OHLC{Time: day.Add(12 * time.Hour), EndTime: day.Add(13 * time.Hour), Close: domain.NewDecimal(150)}, "BTC", "1h")
+	pricesRepository.Create(&domain.OHLC{Time: day.Add(12 * time.Hour), EndTime: day.Add(13 * time.Hour), Close: domain.NewDecimal(25)}, "ETH", "1h")
+
+	portfolioService := portfolio.NewService(app.NewRepository(repositories(db.APPLICATIONS_COLLECTION)), assetsRepository, ledgerRepository, trades.NewRepository(repositories(db.TRADES_COLLECTION)), pricesRepository)
+
+	t.Run("should value the holdings of each asset of the account", func(t *testing.T) {
+		got, err := portfolioService.GetPortfolio(account.ID.Hex(), "")
+
+		if err != nil {
+			t.Fatalf("Not expected GetPortfolio to return error: %v", err)
+		}
+
+		want := map[string]string{"BTC": "300", "ETH": "250", "ADA": "0"}
+
+		if len(got.Holdings) != len(want) {
+			t.Fatalf("got %+v want holdings of %v", got.Holdings, want)
+		}
+
+		for _, holding := range got.Holdings {
+			if holding.MarketValue.String() != want[holding.Asset] {
+				t.Errorf("got %v market value %v want %v", holding.Asset, holding.MarketValue, want[holding.Asset])
+			}
+		}
+
+		if got.Cash.String() != "600" || got.Equity.String() != "1150" {
+			t.Errorf("got cash %v and equity %v want 600 and 1150", got.Cash, got.Equity)
+		}
+	})
+
+	t.Run("should value each lot at the price of its asset", func(t *testing.T) {
+		got, _ := portfolioService.GetEquity(account.ID.Hex(), "", day, day)
+
+		if len(got) != 1 || got[0].Equity.String() != "1150" {
+			t.Errorf("got %+v want equity 1150", got)
+		}
+	})
+}
@@ -0,0 +1,344 @@
+package pricesquality
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DefaultZScoreThreshold is the z-score used to find outliers when none is configured
+const DefaultZScoreThreshold = 4
+
+// Check scans a prices series and reports gaps, duplicates, out of order dates, invalid prices and outliers
+func Check(prices []domain.OHLC, options domain.PricesQualityOptions) *domain.PricesQualityReport {
+	report := &domain.PricesQualityReport{
+		Count:         len(prices),
+		Gaps:          []domain.PriceGap{},
+		Duplicates:    []domain.PriceIssue{},
+		OutOfOrder:    []domain.PriceIssue{},
+		InvalidPrices: []domain.PriceIssue{},
+		Outliers:      []domain.PriceOutlier{},
+	}
+
+	if len(prices) == 0 {
+		return report
+	}
+
+	seen := map[int64]bool{}
+
+	for i, price := range prices {
+		if i > 0 && price.Time.Before(prices[i-1].Time) {
+			report.OutOfOrder = append(report.OutOfOrder, newIssue(i, price))
+		}
+
+		if seen[price.Time.UnixNano()] {
+			report.Duplicates = append(report.Duplicates, newIssue(i, price))
+		}
+		seen[price.Time.UnixNano()] = true
+
+		if !isValid(price) {
+			report.InvalidPrices = append(report.InvalidPrices, newIssue(i, price))
+		}
+	}
+
+	indexes := sortedUniqueIndexes(prices)
+
+	dates := make([]time.Time, len(indexes))
+	for i, index := range indexes {
+		dates[i] = prices[index].Time
+	}
+
+	report.StartDate = dates[0]
+	report.EndDate = dates[len(dates)-1]
+
+	interval := options.Interval
+	if interval <= 0 {
+		interval = DetectInterval(dates)
+	}
+
+	report.Interval = FormatInterval(interval)
+
+	if interval > 0 {
+		for i := 1; i < len(dates); i++ {
+			if missing := countMissing(dates[i-1], dates[i], interval); missing > 0 {
+				report.Gaps = append(report.Gaps, domain.PriceGap{
+					Start:   dates[i-1].Add(interval),
+					End:     dates[i-1].Add(time.Duration(missing) * interval),
+					Missing: missing,
+				})
+			}
+		}
+	}
+
+	threshold := options.ZScoreThreshold
+	if threshold <= 0 {
+		threshold = DefaultZScoreThreshold
+	}
+
+	report.Outliers = findOutliers(prices, indexes, threshold)
+
+	return report
+}
+
+// Repair returns the prices sorted by date without duplicates nor invalid prices.
+// Gaps are filled when the method is forward-fill or interpolate.
+func Repair(prices []domain.OHLC, interval time.Duration, method string) ([]domain.OHLC, error) {
+	if method != domain.RepairDedupe && method != domain.RepairForwardFill && method != domain.RepairInterpolate {
+		return nil, fmt.Errorf("unknown repair method %q", method)
+	}
+
+	repaired := []domain.OHLC{}
+
+	for _, index := range sortedUniqueIndexes(prices) {
+		if isValid(prices[index]) {
+			repaired = append(repaired, prices[index])
+		}
+	}
+
+	if method == domain.RepairDedupe || len(repaired) < 2 {
+		return repaired, nil
+	}
+
+	if interval <= 0 {
+		dates := make([]time.Time, len(repaired))
+		for i, price := range repaired {
+			dates[i] = price.Time
+		}
+
+		interval = DetectInterval(dates)
+	}
+
+	if interval <= 0 {
+		return repaired, nil
+	}
+
+	filled := []domain.OHLC{repaired[0]}
+
+	for i := 1; i < len(repaired); i++ {
+		previous, next := repaired[i-1], repaired[i]
+		missing := countMissing(previous.Time, next.Time, interval)
+
+		for step := 1; step <= missing; step++ {
+			value := previous.Close
+
+			if method == domain.RepairInterpolate {
+				change, err := next.Close.Sub(previous.Close).MulDivChecked(domain.NewDecimalFromInt(int64(step)), domain.NewDecimalFromInt(int64(missing+1)))
+
+				if err != nil {
+					return nil, err
+				}
+
+				value = previous.Close.Add(change)
+			}
+
+			date := previous.Time.Add(time.Duration(step) * interval)
+
+			filled = append(filled, domain.OHLC{
+				Time:    date,
+				EndTime: date.Add(previous.EndTime.Sub(previous.Time)),
+				Open:    value,
+				High:    value,
+				Low:     value,
+				Close:   value,
+			})
+		}
+
+		filled = append(filled, next)
+	}
+
+	return filled, nil
+}
+
+// DetectInterval returns the most frequent duration between consecutive dates rounded to the minute
+func DetectInterval(dates []time.Time) time.Duration {
+	frequencies := map[time.Duration]int{}
+	var interval time.Duration
+
+	for i := 1; i < len(dates); i++ {
+		delta := dates[i].Sub(dates[i-1])
+
+		if delta <= 0 {
+			continue
+		}
+
+		if delta >= time.Minute {
+			delta = delta.Round(time.Minute)
+		}
+
+		frequencies[delta]++
+
+		if frequencies[delta] > frequencies[interval] {
+			interval = delta
+		}
+	}
+
+	return interval
+}
+
+// FormatInterval returns a compact representation of an interval like 1m, 1h or 1d
+func FormatInterval(interval time.Duration) string {
+	switch {
+	case interval <= 0:
+		return ""
+	case interval%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", interval/(24*time.Hour))
+	case interval%time.Hour == 0:
+		return fmt.Sprintf("%dh", interval/time.Hour)
+	case interval%time.Minute == 0:
+		return fmt.Sprintf("%dm", interval/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", interval/time.Second)
+	}
+}
+
+// ParseInterval converts intervals formatted by FormatInterval to duration
+func ParseInterval(interval string) (time.Duration, error) {
+	if strings.HasSuffix(interval, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(interval, "d"))
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q", interval)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	return time.ParseDuration(interval)
+}
+
+// newIssue returns the issue of the price at the index passed by argument
+func newIssue(index int, price domain.OHLC) domain.PriceIssue {
+	return domain.PriceIssue{Index: index, Date: price.Time, Close: price.Close}
+}
+
+// isValid returns false when any of the price values is zero or negative
+func isValid(price domain.OHLC) bool {
+	var zero domain.Decimal
+
+	return price.Open.GreaterThan(zero) && price.High.GreaterThan(zero) && price.Low.GreaterThan(zero) && price.Close.GreaterThan(zero)
+}
+
+// sortedUniqueIndexes returns the indexes of the prices sorted by date keeping the first price of each date
+func sortedUniqueIndexes(prices []domain.OHLC) []int {
+	indexes := make([]int, len(prices))
+	for i := range prices {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return prices[indexes[i]].Time.Before(prices[indexes[j]].Time)
+	})
+
+	unique := []int{}
+
+	for _, index := range indexes {
+		if len(unique) > 0 && prices[unique[len(unique)-1]].Time.Equal(prices[index].Time) {
+			continue
+		}
+
+		unique = append(unique, index)
+	}
+
+	return unique
+}
+
+// countMissing returns the number of intervals missing between two consecutive dates
+func countMissing(previous, next time.Time, interval time.Duration) int {
+	return int(math.Round(float64(next.Sub(previous))/float64(interval))) - 1
+}
+
+// findOutliers returns the valid prices whose change from the previous valid price has a z-score above the threshold
+func findOutliers(prices []domain.OHLC, indexes []int, threshold float64) []domain.PriceOutlier {
+	outliers := []domain.PriceOutlier{}
+
+	valid := []int{}
+	for _, index := range indexes {
+		if isValid(prices[index]) {
+			valid = append(valid, index)
+		}
+	}
+
+	if len(valid) < 3 {
+		return outliers
+	}
+
+	changes := make([]float64, len(valid)-1)
+	mean := 0.0
+
+	for i := 1; i < len(valid); i++ {
+		changes[i-1] = prices[valid[i]].Close.Float64()/prices[valid[i-1]].Close.Float64() - 1
+		mean += changes[i-1]
+	}
+
+	mean /= float64(len(changes))
+
+	variance := 0.0
+	for _, change := range changes {
+		variance += (change - mean) * (change - mean)
+	}
+
+	deviation := math.Sqrt(variance / float64(len(changes)))
+
+	if deviation == 0 {
+		return outliers
+	}
+
+	for i, change := range changes {
+		zScore := (change - mean) / deviation
+
+		if math.Abs(zScore) > threshold {
+			index := valid[i+1]
+			outliers = append(outliers, domain.PriceOutlier{PriceIssue: newIssue(index, prices[index]), ZScore: zScore})
+		}
+	}
+
+	return outliers
+}
+
+// ReadAll returns every price of the reader
+func ReadAll(reader domain.OHLCReader) ([]domain.OHLC, error) {
+	prices := []domain.OHLC{}
+
+	for {
+		price, err := reader.Read()
+
+		if err == io.EOF {
+			return prices, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		prices = append(prices, *price)
+	}
+}
+
+// FindAssetPrices returns the prices of an asset stored in the repository with an interval, like 1m or 1h, between two dates sorted by date.
+// The database has no order of its own, so the prices found are never reported out of order.
+func FindAssetPrices(repo domain.AssetPriceRepository, asset string, interval string, startDate, endDate time.Time) ([]domain.OHLC, error) {
+	assetsPrices, err := repo.FindAll(bson.M{"asset": asset, "interval": interval, "date": bson.M{"$gte": startDate, "$lte": endDate}})
+
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]domain.OHLC, len(*assetsPrices))
+
+	for i, assetPrice := range *assetsPrices {
+		prices[i] = assetPrice.OHLC()
+	}
+
+	sort.SliceStable(prices, func(i, j int) bool {
+		return prices[i].Time.Before(prices[j].Time)
+	})
+
+	return prices, nil
+}
@@ -0,0 +1,133 @@
+package pricesquality_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/pricesquality"
+)
+
+var startDate = time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// newPrices returns prices with closes passed by argument and dates spaced by the offsets in hours
+func newPrices(hours []int, closes []float64) []domain.OHLC {
+	prices := make([]domain.OHLC, len(hours))
+
+	for i, hour := range hours {
+		date := startDate.Add(time.Duration(hour) * time.Hour)
+		price := domain.NewDecimal(closes[i])
+		prices[i] = domain.OHLC{Time: date, EndTime: date, Open: price, High: price, Low: price, Close: price}
+	}
+
+	return prices
+}
+
+func TestCheck(t *testing.T) {
+	t.Run("should report no problems on a clean series", func(t *testing.T) {
+		report := pricesquality.Check(newPrices([]int{0, 1, 2, 3}, []float64{10, 11, 10, 11}), domain.PricesQualityOptions{})
+
+		if !report.OK() {
+			t.Errorf("Expected report to be ok, got %+v", report)
+		}
+
+		if report.Interval != "1h" || report.Count != 4 {
+			t.Errorf("got interval %v count %d want interval 1h count 4", report.Interval, report.Count)
+		}
+	})
+
+	t.Run("should report gaps, duplicates, out of order and invalid prices", func(t *testing.T) {
+		prices := newPrices([]int{0, 1, 1, 5, 4, 6}, []float64{10, 11, 11, 10, 0, 11})
+
+		report := pricesquality.Check(prices, domain.PricesQualityOptions{Interval: time.Hour})
+
+		wantGaps := []domain.PriceGap{{Start: startDate.Add(2 * time.Hour), End: startDate.Add(3 * time.Hour), Missing: 2}}
+
+		if !reflect.DeepEqual(report.Gaps, wantGaps) {
+			t.Errorf("gaps: got %+v want %+v", report.Gaps, wantGaps)
+		}
+
+		if len(report.Duplicates) != 1 || report.Duplicates[0].Index != 2 {
+			t.Errorf("duplicates: got %+v want index 2", report.Duplicates)
+		}
+
+		if len(report.OutOfOrder) != 1 || report.OutOfOrder[0].Index != 4 {
+			t.Errorf("out of order: got %+v want index 4", report.OutOfOrder)
+		}
+
+		if len(report.InvalidPrices) != 1 || report.InvalidPrices[0].Index != 4 {
+			t.Errorf("invalid prices: got %+v want index 4", report.InvalidPrices)
+		}
+	})
+
+	t.Run("should report spikes as outliers", func(t *testing.T) {
+		hours := []int{}
+		closes := []float64{}
+
+		for i := 0; i < 50; i++ {
+			hours = append(hours, i)
+			closes = append(closes, 100+float64(i%2))
+		}
+
+		closes[25] = 1000
+
+		report := pricesquality.Check(newPrices(hours, closes), domain.PricesQualityOptions{})
+
+		if len(report.Outliers) == 0 || report.Outliers[0].Index != 25 {
+			t.Errorf("Expected price 25 to be an outlier, got %+v", report.Outliers)
+		}
+	})
+}
+
+func TestRepair(t *testing.T) {
+	prices := newPrices([]int{0, 2, 1, 1, 5}, []float64{10, 12, 11, 11, 15})
+
+	t.Run("should sort and remove duplicates", func(t *testing.T) {
+		got, err := pricesquality.Repair(prices, time.Hour, domain.RepairDedupe)
+
+		if err != nil {
+			t.Fatalf("Not expected Repair to return error: %v", err)
+		}
+
+		want := newPrices([]int{0, 1, 2, 5}, []float64{10, 11, 12, 15})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should fill gaps with the last price", func(t *testing.T) {
+		got, err := pricesquality.Repair(prices, time.Hour, domain.RepairForwardFill)
+
+		if err != nil {
+			t.Fatalf("Not expected Repair to return error: %v", err)
+		}
+
+		want := newPrices([]int{0, 1, 2, 3, 4, 5}, []float64{10, 11, 12, 12, 12, 15})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should fill gaps with interpolated prices", func(t *testing.T) {
+		got, err := pricesquality.Repair(prices, time.Hour, domain.RepairInterpolate)
+
+		if err != nil {
+			t.Fatalf("Not expected Repair to return error: %v", err)
+		}
+
+		want := newPrices([]int{0, 1, 2, 3, 4, 5}, []float64{10, 11, 12, 13, 14, 15})
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("should return error on unknown methods", func(t *testing.T) {
+		if _, err := pricesquality.Repair(prices, time.Hour, "average"); err == nil {
+			t.Errorf("Expected Repair to return error")
+		}
+	})
+}
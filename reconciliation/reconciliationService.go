@@ -0,0 +1,175 @@
+package reconciliation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// AdjustmentDescription is the description of the ledger entries created by reconciliations
+const AdjustmentDescription = "reconciliation with exchange"
+
+// Options are the reconciliation options
+type Options struct {
+	// CashTolerance and AssetTolerance are the differences ignored, e.g. rounding of exchange fees
+	CashTolerance  domain.Decimal
+	AssetTolerance domain.Decimal
+	// AutoAdjust corrects the account amount with a ledger adjustment when cash does not match
+	AutoAdjust bool
+}
+
+// DefaultOptions tolerates a cent of cash and a satoshi of asset differences
+var DefaultOptions = Options{
+	CashTolerance:  domain.NewDecimal(0.01),
+	AssetTolerance: domain.NewDecimal(0.00000001),
+}
+
+// Book is the bookkeeping of an account and the assets traded with it
+type Book struct {
+	AccountService domain.AccountService
+	// Assets are the assets reconciled, lots without symbol are of the first asset
+	Assets   []string
+	Currency string
+}
+
+// defaultAsset returns the asset of the lots without symbol
+func (b Book) defaultAsset() string {
+	if len(b.Assets) == 0 {
+		return ""
+	}
+
+	return b.Assets[0]
+}
+
+// Books returns the books of the accounts that use the exchange account, they are loaded on each reconciliation as applications change
+type Books func() ([]Book, error)
+
+// Service compares the exchange account balances with the bookkeeping of the accounts that use it
+type Service struct {
+	brokerAccount domain.BrokerAccount
+	books         Books
+	eventLogs     domain.EventsLog
+	currency      string
+	options       Options
+}
+
+// NewService returns an instance of Service
+func NewService(brokerAccount domain.BrokerAccount, books Books, eventLogs domain.EventsLog, currency string, options Options) *Service {
+	return &Service{brokerAccount, books, eventLogs, currency, options}
+}
+
+// Reconcile compares the exchange balances and open orders of every asset of the books with the sum of the accounts amounts and pending lots.
+// Discrepancies are registered as event logs and cash discrepancies are adjusted when AutoAdjust is set and only one account uses the exchange account.
+func (s *Service) Reconcile(date time.Time) (*domain.Reconciliation, error) {
+	balances, err := s.brokerAccount.GetBalances()
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching exchange balances: %v", err)
+	}
+
+	orders, err := s.brokerAccount.GetOpenOrders()
+
+	if err != nil {
+		return nil, fmt.Errorf("fetching exchange open orders: %v", err)
+	}
+
+	books, err := s.books()
+
+	if err != nil {
+		return nil, fmt.Errorf("loading accounts of the exchange account: %v", err)
+	}
+
+	assets := []string{}
+	bookAssets := map[string]domain.Decimal{}
+	var bookCash domain.Decimal
+
+	for _, book := range books {
+		for _, asset := range book.Assets {
+			if _, ok := bookAssets[asset]; !ok {
+				assets = append(assets, asset)
+				bookAssets[asset] = domain.Decimal{}
+			}
+		}
+
+		// accounts of other quotes hold cash the exchange has in another balance
+		if book.Currency == s.currency {
+			amount, err := book.AccountService.GetAmount()
+
+			if err != nil {
+				return nil, err
+			}
+
+			bookCash = bookCash.Add(amount)
+		}
+
+		pendingAssets, err := book.AccountService.FindPendingAssets()
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lot := range *pendingAssets {
+			symbol := lot.GetSymbol(book.defaultAsset())
+			bookAssets[symbol] = bookAssets[symbol].Add(lot.Amount)
+		}
+	}
+
+	result := domain.NewReconciliation(assets, s.currency, balances, orders, bookCash, bookAssets)
+	result.Date = date
+
+	for _, asset := range result.AssetDiscrepancies(s.options.AssetTolerance) {
+		s.log(fmt.Sprintf("%v on exchange is %v but the accounts hold %v", asset.Asset, asset.Exchange, asset.Book))
+	}
+
+	if !result.HasCashDiscrepancy(s.options.CashTolerance) {
+		return result, nil
+	}
+
+	s.log(fmt.Sprintf("%v on exchange is %v but the accounts have %v", result.Currency, result.ExchangeCash, result.BookCash))
+
+	if !s.options.AutoAdjust {
+		return result, nil
+	}
+
+	// the difference of a shared exchange account can not be assigned to one of its accounts
+	if len(books) != 1 {
+		s.log(fmt.Sprintf("Account amount not adjusted, the exchange account is used by %d accounts", len(books)))
+		return result, nil
+	}
+
+	err = books[0].AccountService.Adjust(result.CashDifference, AdjustmentDescription)
+
+	if err != nil {
+		return result, fmt.Errorf("adjusting account amount: %v", err)
+	}
+
+	result.Adjusted = true
+	s.log(fmt.Sprintf("Account amount adjusted by %v", result.CashDifference))
+
+	return result, nil
+}
+
+// Schedule reconciles the account periodically until done is closed
+func (s *Service) Schedule(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Reconcile(time.Now()); err != nil {
+			fmt.Printf("Not able to reconcile %v account: %v\n", s.currency, err)
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) log(message string) {
+	if err := s.eventLogs.Create("reconciliation", message); err != nil {
+		fmt.Printf("Not able to log reconciliation: %v\n", err)
+	}
+}
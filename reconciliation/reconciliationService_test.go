@@ -0,0 +1,166 @@
+package reconciliation_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/eventlogs"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/reconciliation"
+	"github.com/golang/mock/gomock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReconcile(t *testing.T) {
+	setup := func(t *testing.T, eur float64, options reconciliation.Options) (*reconciliation.Service, *accounts.AccountServiceInMemory, *eventlogs.EventLogsRepository) {
+		ctrl := gomock.NewController(t)
+		brokerAccount := mocks.NewMockBrokerAccount(ctrl)
+		brokerAccount.EXPECT().GetBalances().Return(map[string]domain.Decimal{
+			"EUR": domain.NewDecimal(eur),
+			"BTC": domain.NewDecimal(0.02),
+		}, nil)
+		brokerAccount.EXPECT().GetOpenOrders().Return([]domain.OpenOrder{
+			{ID: "O1", Asset: "BTC", Type: "buy", Volume: domain.NewDecimal(0.01), Price: domain.NewDecimalFromInt(30000)},
+		}, nil)
+
+		accountService := accounts.NewAccountServiceInMemory(domain.NewDecimalFromInt(1000), assets.NewAssetsRepositoryInMemory())
+		accountService.CreateAsset(domain.NewDecimal(0.02), domain.NewDecimalFromInt(25000), time.Now())
+		accountService.CreateAsset(domain.NewDecimal(0.01), domain.NewDecimalFromInt(30000), time.Now())
+
+		eventLogs := eventlogs.NewEventLogsRepository(db.NewMemoryRepository(), primitive.NewObjectID())
+
+		accountBooks := books(reconciliation.Book{AccountService: accountService, Assets: []string{"BTC"}, Currency: "EUR"})
+
+		return reconciliation.NewService(brokerAccount, accountBooks, eventLogs, "EUR", options), accountService, eventLogs
+	}
+
+	t.Run("should not log when balances match", func(t *testing.T) {
+		service, _, eventLogs := setup(t, 1300, reconciliation.DefaultOptions)
+
+		got, err := service.Reconcile(time.Now())
+
+		if err != nil {
+			t.Fatalf("Not expected Reconcile to return error: %v", err)
+		}
+
+		if len(got.Assets) != 1 || got.Assets[0].Book.String() != "0.03" || got.Assets[0].Exchange.String() != "0.03" {
+			t.Errorf("got assets %+v want book and exchange BTC 0.03", got.Assets)
+		}
+
+		if logs, _ := eventLogs.FindAll(bson.M{}); len(*logs) != 0 {
+			t.Errorf("got %d event logs want 0", len(*logs))
+		}
+	})
+
+	t.Run("should log cash discrepancies without adjusting the account", func(t *testing.T) {
+		service, accountService, eventLogs := setup(t, 1250, reconciliation.DefaultOptions)
+
+		got, _ := service.Reconcile(time.Now())
+
+		if got.Adjusted || accountService.Amount.String() != "1000" {
+			t.Errorf("Not expected account to be adjusted, amount is %v", accountService.Amount)
+		}
+
+		logs, _ := eventLogs.FindAll(bson.M{})
+
+		if len(*logs) != 1 || (*logs)[0].EventName != "reconciliation" {
+			t.Errorf("got %+v want a reconciliation event log", logs)
+		}
+	})
+
+	t.Run("should adjust the account amount when auto adjust is set", func(t *testing.T) {
+		options := reconciliation.DefaultOptions
+		options.AutoAdjust = true
+		service, accountService, _ := setup(t, 1250, options)
+
+		got, _ := service.Reconcile(time.Now())
+
+		if !got.Adjusted || accountService.Amount.String() != "950" {
+			t.Errorf("got amount %v want 950", accountService.Amount)
+		}
+	})
+
+	t.Run("should reconcile every asset of the book with the cash reserved by their open orders", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		brokerAccount := mocks.NewMockBrokerAccount(ctrl)
+		brokerAccount.EXPECT().GetBalances().Return(map[string]domain.Decimal{
+			"EUR": domain.NewDecimalFromInt(1300),
+			"BTC": domain.NewDecimal(0.02),
+			"ETH": domain.NewDecimalFromInt(1),
+		}, nil)
+		brokerAccount.EXPECT().GetOpenOrders().Return([]domain.OpenOrder{
+			{ID: "O1", Asset: "BTC", Type: "buy", Volume: domain.NewDecimal(0.01), Price: domain.NewDecimalFromInt(30000)},
+			{ID: "O2", Asset: "ETH", Type: "buy", Volume: domain.NewDecimalFromInt(1), Price: domain.NewDecimalFromInt(200)},
+		}, nil)
+
+		assetsRepository := assets.NewAssetsRepositoryInMemory()
+		accountService := accounts.NewAccountServiceInMemory(domain.NewDecimalFromInt(800), assetsRepository)
+		assetsRepository.Create(&domain.Asset{ID: primitive.NewObjectID(), Amount: domain.NewDecimal(0.03)})
+		assetsRepository.Create(&domain.Asset{ID: primitive.NewObjectID(), Symbol: "ETH", Amount: domain.NewDecimalFromInt(2)})
+
+		options := reconciliation.DefaultOptions
+		options.AutoAdjust = true
+		accountBooks := books(reconciliation.Book{AccountService: accountService, Assets: []string{"BTC", "ETH"}, Currency: "EUR"})
+		service := reconciliation.NewService(brokerAccount, accountBooks, eventlogs.NewEventLogsRepository(db.NewMemoryRepository(), primitive.NewObjectID()), "EUR", options)
+
+		got, err := service.Reconcile(time.Now())
+
+		if err != nil {
+			t.Fatalf("Not expected Reconcile to return error: %v", err)
+		}
+
+		if got.Adjusted || got.HasCashDiscrepancy(options.CashTolerance) || len(got.AssetDiscrepancies(options.AssetTolerance)) != 0 {
+			t.Errorf("Not expected discrepancies %+v", got)
+		}
+	})
+
+	t.Run("should compare the exchange with every account sharing it without adjusting them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		brokerAccount := mocks.NewMockBrokerAccount(ctrl)
+		brokerAccount.EXPECT().GetBalances().Return(map[string]domain.Decimal{
+			"EUR": domain.NewDecimalFromInt(1400),
+			"BTC": domain.NewDecimal(0.03),
+		}, nil).Times(2)
+		brokerAccount.EXPECT().GetOpenOrders().Return([]domain.OpenOrder{}, nil).Times(2)
+
+		first := accounts.NewAccountServiceInMemory(domain.NewDecimalFromInt(1000), assets.NewAssetsRepositoryInMemory())
+		first.CreateAsset(domain.NewDecimal(0.02), domain.NewDecimalFromInt(25000), time.Now())
+		second := accounts.NewAccountServiceInMemory(domain.NewDecimalFromInt(400), assets.NewAssetsRepositoryInMemory())
+		second.CreateAsset(domain.NewDecimal(0.01), domain.NewDecimalFromInt(30000), time.Now())
+
+		options := reconciliation.DefaultOptions
+		options.AutoAdjust = true
+		eventLogs := eventlogs.NewEventLogsRepository(db.NewMemoryRepository(), primitive.NewObjectID())
+		shared := books(
+			reconciliation.Book{AccountService: first, Assets: []string{"BTC"}, Currency: "EUR"},
+			reconciliation.Book{AccountService: second, Assets: []string{"BTC"}, Currency: "EUR"},
+		)
+		service := reconciliation.NewService(brokerAccount, shared, eventLogs, "EUR", options)
+
+		if got, _ := service.Reconcile(time.Now()); got.HasCashDiscrepancy(options.CashTolerance) || len(got.AssetDiscrepancies(options.AssetTolerance)) != 0 {
+			t.Errorf("Not expected discrepancies %+v", got)
+		}
+
+		first.Deposit(domain.NewDecimalFromInt(50))
+		got, _ := service.Reconcile(time.Now())
+
+		if got.Adjusted || first.Amount.String() != "1050" || second.Amount.String() != "400" {
+			t.Errorf("got amounts %v and %v want the accounts not adjusted", first.Amount, second.Amount)
+		}
+
+		if logs, _ := eventLogs.FindAll(bson.M{}); len(*logs) != 2 {
+			t.Errorf("got %d event logs want the discrepancy and the adjustment refused", len(*logs))
+		}
+	})
+}
+
+func books(books ...reconciliation.Book) reconciliation.Books {
+	return func() ([]reconciliation.Book, error) {
+		return books, nil
+	}
+}
@@ -0,0 +1,143 @@
+package reports
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// TaxReportService generates the capital gains tax reports of the trading applications and dca purchases
+type TaxReportService struct {
+	applicationsRepository domain.ApplicationRepository
+	assetsRepository       domain.AssetsRepositoryReader
+	dcaAssetsRepository    domain.DCAAssetsRepository
+}
+
+// NewTaxReportService returns an instance of TaxReportService
+func NewTaxReportService(applicationsRepository domain.ApplicationRepository, assetsRepository domain.AssetsRepositoryReader, dcaAssetsRepository domain.DCAAssetsRepository) *TaxReportService {
+	return &TaxReportService{applicationsRepository, assetsRepository, dcaAssetsRepository}
+}
+
+// Generate returns the realized gains of the year in the quote currency using the cost basis method passed by argument.
+// The assets of an account are the asset of the application that uses it, and only the accounts and the dca purchases
+// of the quote currency are included, EUR when it is empty.
+func (s *TaxReportService) Generate(year int, method domain.CostBasisMethod, quote string) (*domain.TaxReport, error) {
+	if quote == "" {
+		quote = domain.DefaultQuote
+	}
+
+	acquisitions, disposals, err := s.findTradingEvents(quote)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dcaAssets, err := s.dcaAssetsRepository.FindAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range *dcaAssets {
+		if asset.GetQuote() != quote {
+			continue
+		}
+
+		acquisitions = append(acquisitions, domain.TaxEvent{
+			Asset:  strings.ToUpper(asset.Coin),
+			Source: domain.TaxSourceDCA,
+			Date:   asset.CreatedAt,
+			Amount: asset.Amount,
+			Price:  asset.Price,
+			Fee:    asset.Fee,
+		})
+	}
+
+	report, err := domain.NewTaxReport(year, method, acquisitions, disposals)
+
+	if err != nil {
+		return nil, err
+	}
+
+	report.Quote = quote
+
+	return report, nil
+}
+
+// findTradingEvents returns the buys and sells of the lots of every account of live applications of the quote currency
+func (s *TaxReportService) findTradingEvents(quote string) ([]domain.TaxEvent, []domain.TaxEvent, error) {
+	applications, err := s.applicationsRepository.FindAll()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acquisitions, disposals := []domain.TaxEvent{}, []domain.TaxEvent{}
+	accounts := map[string]bool{}
+
+	for _, application := range *applications {
+		accountID := application.AccountID.Hex()
+
+		if accounts[accountID] || application.GetQuote() != quote || application.IsSimulated() {
+			continue
+		}
+
+		accounts[accountID] = true
+		lots, err := s.assetsRepository.FindAll(accountID)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, lot := range *lots {
+			symbol := lot.GetSymbol(application.Asset)
+			acquisitions = append(acquisitions, domain.TaxEvent{
+				Asset:  symbol,
+				Source: domain.TaxSourceTrading,
+				Date:   lot.BuyTime,
+				Amount: lot.Amount,
+				Price:  lot.BuyPrice,
+				Fee:    lot.BuyFee,
+			})
+
+			if lot.Sold {
+				disposals = append(disposals, domain.TaxEvent{
+					Asset:  symbol,
+					Source: domain.TaxSourceTrading,
+					Date:   lot.SellTime,
+					Amount: lot.Amount,
+					Price:  lot.SellPrice,
+					Fee:    lot.SellFee,
+				})
+			}
+		}
+	}
+
+	return acquisitions, disposals, nil
+}
+
+// WriteTaxReportCSV writes a line per disposal of the report
+func WriteTaxReportCSV(w io.Writer, report *domain.TaxReport) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"date", "asset", "source", "amount", "proceeds", "fees", "costBasis", "gain"})
+
+	for _, disposal := range report.Disposals {
+		writer.Write([]string{
+			disposal.Date.UTC().Format(time.RFC3339),
+			disposal.Asset,
+			disposal.Source,
+			disposal.Amount.String(),
+			disposal.Proceeds.String(),
+			disposal.Fees.String(),
+			disposal.CostBasis.String(),
+			disposal.Gain.String(),
+		})
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
@@ -0,0 +1,168 @@
+package reports_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/assets"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/dca"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/reports"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaxReportService(t *testing.T) {
+	date := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	accountID := primitive.NewObjectID()
+
+	dollarAccountID := primitive.NewObjectID()
+
+	applicationsCollection := db.NewMemoryRepository()
+	applicationsRepository := app.NewRepository(applicationsCollection)
+	applicationsRepository.Create("BTC", domain.ApplicationOptions{}, accountID)
+	applicationsCollection.InsertOne(domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", Quote: domain.QuoteUSD, AccountID: dollarAccountID})
+
+	assetsRepository := assets.NewRepository(db.NewMemoryRepository())
+	assetsRepository.Create(&domain.Asset{
+		ID:        primitive.NewObjectID(),
+		AccountID: accountID,
+		Amount:    domain.NewDecimal(0.5),
+		BuyPrice:  domain.NewDecimalFromInt(40000),
+		BuyTime:   date,
+		SellPrice: domain.NewDecimalFromInt(50000),
+		SellTime:  date.Add(24 * time.Hour),
+		SellFee:   domain.NewDecimal(12.5),
+		Sold:      true,
+	})
+	assetsRepository.Create(&domain.Asset{
+		ID:        primitive.NewObjectID(),
+		AccountID: dollarAccountID,
+		Amount:    domain.NewDecimal(1),
+		BuyPrice:  domain.NewDecimalFromInt(45000),
+		BuyTime:   date,
+		SellPrice: domain.NewDecimalFromInt(46000),
+		SellTime:  date.Add(48 * time.Hour),
+		Sold:      true,
+	})
+
+	dcaAssetsRepository := dca.NewAssetsRepository(db.NewMemoryRepository())
+	dcaAssetsRepository.Save(&domain.DCAAsset{Coin: "btc", Amount: domain.NewDecimal(0.1), Price: domain.NewDecimalFromInt(20000), Fee: domain.NewDecimalFromInt(5), CreatedAt: date.Add(-time.Hour)})
+	dcaAssetsRepository.Save(&domain.DCAAsset{Coin: "btc", Amount: domain.NewDecimal(0.1), Price: domain.NewDecimalFromInt(30000), CreatedAt: date.Add(-time.Hour), Quote: domain.QuoteUSD})
+
+	service := reports.NewTaxReportService(applicationsRepository, assetsRepository, dcaAssetsRepository)
+
+	t.Run("should match the trading disposals with the dca purchases", func(t *testing.T) {
+		got, err := service.Generate(2021, domain.CostBasisFIFO, "")
+
+		if err != nil {
+			t.Fatalf("Not expected Generate to return error: %v", err)
+		}
+
+		// 0.1 bought on dca for 2005 and 0.4 bought by the application for 16000 plus the sell fee
+		if got.CostBasis.String() != "18017.5" || got.Gain.String() != "6982.5" {
+			t.Errorf("got cost basis %v and gain %v want 18017.5 and 6982.5", got.CostBasis, got.Gain)
+		}
+
+		var buffer bytes.Buffer
+		reports.WriteTaxReportCSV(&buffer, got)
+
+		want := "date,asset,source,amount,proceeds,fees,costBasis,gain\n2021-03-02T00:00:00Z,BTC,trading,0.5,25000,12.5,18017.5,6982.5\n"
+
+		if buffer.String() != want {
+			t.Errorf("got %q want %q", buffer.String(), want)
+		}
+	})
+
+	t.Run("should only include the trades and purchases of the quote currency", func(t *testing.T) {
+		got, err := service.Generate(2021, domain.CostBasisFIFO, domain.QuoteUSD)
+
+		if err != nil {
+			t.Fatalf("Not expected Generate to return error: %v", err)
+		}
+
+		// 0.1 bought on dca for 3000 and 0.9 bought by the application for 40500
+		if got.Quote != domain.QuoteUSD || len(got.Disposals) != 1 || got.CostBasis.String() != "43500" || got.Gain.String() != "2500" {
+			t.Errorf("got %+v want one dollar disposal with 2500 gain", got)
+		}
+	})
+
+	t.Run("should return an empty report of years without disposals", func(t *testing.T) {
+		got, _ := service.Generate(2020, domain.CostBasisAverage, domain.QuoteEUR)
+
+		if len(got.Disposals) != 0 || !got.Gain.IsZero() {
+			t.Errorf("got %+v want an empty report", got)
+		}
+	})
+}
+
+func TestTaxReportServiceFees(t *testing.T) {
+	date := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	storage := db.NewMemoryStorage()
+	account, _ := accounts.OpenAccount(storage.UnitOfWork, domain.ExchangeKraken, domain.NewDecimalFromInt(100000), date)
+
+	applicationsRepository := app.NewRepository(db.NewMemoryRepository())
+	applicationsRepository.Create("BTC", domain.ApplicationOptions{}, account.ID)
+
+	assetsRepository := assets.NewRepository(storage.Repositories(db.ASSETS_COLLECTION))
+	accountService, err := accounts.NewAccountService(
+		account.ID.Hex(),
+		accounts.NewRepository(storage.Repositories(db.ACCOUNTS_COLLECTION)),
+		assetsRepository,
+		accounts.NewLedgerRepository(storage.Repositories(db.LEDGER_COLLECTION)),
+		storage.UnitOfWork,
+	)
+
+	if err != nil {
+		t.Fatalf("Not expected NewAccountService to return error: %v", err)
+	}
+
+	accountService = accountService.WithFeeRate(domain.ExchangeFeeRate(domain.ExchangeKraken))
+
+	// the fees are 0.26% of 40000 and of 25000
+	if _, err := accountService.Buy(domain.NewDecimalFromInt(1), domain.NewDecimalFromInt(40000), date, ""); err != nil {
+		t.Fatalf("Not expected Buy to return error: %v", err)
+	}
+
+	if _, err := accountService.SellAmount(domain.NewDecimal(0.5), domain.NewDecimalFromInt(50000), domain.LotSelection{Method: domain.LotFIFO}, date.Add(24*time.Hour), ""); err != nil {
+		t.Fatalf("Not expected SellAmount to return error: %v", err)
+	}
+
+	t.Run("should withdraw the fees from the account", func(t *testing.T) {
+		got, _ := accountService.GetAmount()
+
+		if got.String() != "84831" {
+			t.Errorf("got %v want 84831", got)
+		}
+
+		entries, _ := accountService.FindLedgerEntries(date, date.Add(48*time.Hour))
+		var fees domain.Decimal
+
+		for _, entry := range *entries {
+			if entry.Type == domain.LedgerFee {
+				fees = fees.Add(entry.Amount)
+			}
+		}
+
+		if fees.String() != "169" {
+			t.Errorf("got ledger fees %v want 169", fees)
+		}
+	})
+
+	t.Run("should include the fees of the lots sold", func(t *testing.T) {
+		service := reports.NewTaxReportService(applicationsRepository, assetsRepository, dca.NewAssetsRepository(db.NewMemoryRepository()))
+		got, err := service.Generate(2021, domain.CostBasisFIFO, "")
+
+		if err != nil {
+			t.Fatalf("Not expected Generate to return error: %v", err)
+		}
+
+		// half of the buy fee is moved to the lot sold, 20000 + 52 + 65
+		if len(got.Disposals) != 1 || got.Disposals[0].Fees.String() != "65" || got.CostBasis.String() != "20117" || got.Gain.String() != "4883" {
+			t.Errorf("got %+v want a disposal with 65 of fees, 20117 of cost basis and 4883 of gain", got)
+		}
+	})
+}
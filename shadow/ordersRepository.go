@@ -0,0 +1,48 @@
+package shadow
+
+import (
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OrdersRepository stores the orders of shadow applications
+type OrdersRepository struct {
+	repo domain.Repository
+}
+
+// NewOrdersRepository returns an instance of OrdersRepository
+func NewOrdersRepository(repo domain.Repository) *OrdersRepository {
+	return &OrdersRepository{repo}
+}
+
+// Create inserts a new order
+func (r *OrdersRepository) Create(order *domain.ShadowOrder) error {
+	return r.repo.InsertOne(order)
+}
+
+// Fill sets the simulated fill of an open order
+func (r *OrdersRepository) Fill(id primitive.ObjectID, price domain.Decimal, date time.Time) error {
+	return r.repo.UpdateOne(bson.M{"_id": id}, bson.M{"$set": bson.M{"status": domain.ShadowOrderFilled, "fillPrice": price, "filledAt": date}})
+}
+
+// FindAll returns the orders of an application sorted by date
+func (r *OrdersRepository) FindAll(applicationID string) (*[]domain.ShadowOrder, error) {
+	applicationOID, err := primitive.ObjectIDFromHex(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	results := []domain.ShadowOrder{}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	if err := r.repo.FindAll(&results, bson.M{"applicationId": applicationOID}, opts); err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
@@ -0,0 +1,68 @@
+package shadow
+
+import (
+	"fmt"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// Service compares shadow applications with the live applications they shadow
+type Service struct {
+	applicationsRepository domain.ApplicationRepository
+	ordersRepository       domain.ShadowOrdersRepository
+	portfolioService       domain.PortfolioService
+}
+
+// NewService returns an instance of Service
+func NewService(applicationsRepository domain.ApplicationRepository, ordersRepository domain.ShadowOrdersRepository, portfolioService domain.PortfolioService) *Service {
+	return &Service{applicationsRepository, ordersRepository, portfolioService}
+}
+
+// Compare returns the portfolios of a shadow application and of its live application with the orders of the shadow application
+func (s *Service) Compare(applicationID string) (*domain.ShadowComparison, error) {
+	shadowApplication, err := s.applicationsRepository.FindByID(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if shadowApplication.GetMode() != domain.ModeShadow || shadowApplication.ShadowOf.IsZero() {
+		return nil, fmt.Errorf("application %v does not shadow a live application", applicationID)
+	}
+
+	liveApplication, err := s.applicationsRepository.FindByID(shadowApplication.ShadowOf.Hex())
+
+	if err != nil {
+		return nil, err
+	}
+
+	shadowPerformance, err := s.performance(shadowApplication)
+
+	if err != nil {
+		return nil, err
+	}
+
+	livePerformance, err := s.performance(liveApplication)
+
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.ordersRepository.FindAll(applicationID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ShadowComparison{Shadow: *shadowPerformance, Live: *livePerformance, Orders: *orders}, nil
+}
+
+func (s *Service) performance(application *domain.Application) (*domain.ApplicationPerformance, error) {
+	portfolio, err := s.portfolioService.GetPortfolio(application.AccountID.Hex(), application.GetQuote())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.ApplicationPerformance{ApplicationID: application.ID, Mode: application.GetMode(), Portfolio: portfolio}, nil
+}
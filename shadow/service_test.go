@@ -0,0 +1,60 @@
+package shadow_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/app"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/shadow"
+	"github.com/golang/mock/gomock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	applicationsRepo := db.NewMemoryRepository()
+	live := domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", AccountID: primitive.NewObjectID()}
+	shadowApplication := domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", Mode: domain.ModeShadow, ShadowOf: live.ID, AccountID: primitive.NewObjectID()}
+	paper := domain.Application{ID: primitive.NewObjectID(), Asset: "BTC", Mode: domain.ModePaper, AccountID: primitive.NewObjectID()}
+
+	for _, application := range []domain.Application{live, shadowApplication, paper} {
+		applicationsRepo.InsertOne(application)
+	}
+
+	ordersRepository := shadow.NewOrdersRepository(db.NewMemoryRepository())
+	ordersRepository.Create(&domain.ShadowOrder{ID: primitive.NewObjectID(), ApplicationID: shadowApplication.ID, Asset: "BTC", Status: domain.ShadowOrderOpen, CreatedAt: time.Now()})
+	ordersRepository.Create(&domain.ShadowOrder{ID: primitive.NewObjectID(), ApplicationID: paper.ID, Asset: "BTC", Status: domain.ShadowOrderOpen, CreatedAt: time.Now()})
+
+	portfolioService := mocks.NewMockPortfolioService(ctrl)
+	service := shadow.NewService(app.NewRepository(applicationsRepo), ordersRepository, portfolioService)
+
+	t.Run("should compare the portfolios of the shadow and the live applications", func(t *testing.T) {
+		portfolioService.EXPECT().GetPortfolio(shadowApplication.AccountID.Hex(), domain.QuoteEUR).Return(&domain.Portfolio{Equity: domain.NewDecimalFromInt(1100)}, nil)
+		portfolioService.EXPECT().GetPortfolio(live.AccountID.Hex(), domain.QuoteEUR).Return(&domain.Portfolio{Equity: domain.NewDecimalFromInt(1050)}, nil)
+
+		got, err := service.Compare(shadowApplication.ID.Hex())
+
+		if err != nil {
+			t.Fatalf("Not expected Compare to return error: %v", err)
+		}
+
+		if got.Shadow.Mode != domain.ModeShadow || got.Shadow.Portfolio.Equity.String() != "1100" || got.Live.ApplicationID != live.ID || got.Live.Portfolio.Equity.String() != "1050" {
+			t.Errorf("got %+v want shadow equity 1100 and live equity 1050", got)
+		}
+
+		if len(got.Orders) != 1 || got.Orders[0].ApplicationID != shadowApplication.ID {
+			t.Errorf("got %+v want the order of the shadow application", got.Orders)
+		}
+	})
+
+	t.Run("should return error for applications that do not shadow a live application", func(t *testing.T) {
+		if _, err := service.Compare(paper.ID.Hex()); err == nil {
+			t.Errorf("Expected Compare to return error")
+		}
+	})
+}
@@ -0,0 +1,11 @@
+package symbols
+
+// Binance maps the symbols of binance, which uses the asset symbols
+var Binance = binanceMapper{}
+
+type binanceMapper struct{}
+
+// Asset returns the binance asset code
+func (binanceMapper) Asset(code string) string {
+	return code
+}
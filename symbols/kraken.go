@@ -0,0 +1,27 @@
+package symbols
+
+// krakenCodes are the kraken asset codes that do not match the asset symbol after removing their X or Z prefix
+var krakenCodes = map[string]string{
+	"XXBT": "BTC",
+	"XBT":  "BTC",
+	"XXDG": "DOGE",
+	"XDG":  "DOGE",
+}
+
+// Kraken maps the symbols of kraken
+var Kraken = krakenMapper{}
+
+type krakenMapper struct{}
+
+// Asset returns the symbol of a kraken asset code, e.g. BTC for XXBT and EUR for ZEUR
+func (krakenMapper) Asset(code string) string {
+	if symbol, ok := krakenCodes[code]; ok {
+		return symbol
+	}
+
+	if len(code) == 4 && (code[0] == 'X' || code[0] == 'Z') {
+		return code[1:]
+	}
+
+	return code
+}
@@ -0,0 +1,19 @@
+package symbols
+
+import (
+	"fmt"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// ForExchange returns the symbol mapper of the exchange, kraken when the exchange is empty
+func ForExchange(exchange string) (domain.SymbolMapper, error) {
+	switch exchange {
+	case domain.ExchangeKraken, "":
+		return Kraken, nil
+	case domain.ExchangeBinance:
+		return Binance, nil
+	default:
+		return nil, fmt.Errorf("exchange %v is not supported", exchange)
+	}
+}
@@ -0,0 +1,39 @@
+package symbols_test
+
+import (
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/symbols"
+)
+
+func TestSymbolMappers(t *testing.T) {
+	cases := []struct {
+		exchange string
+		code     string
+		want     string
+	}{
+		{domain.ExchangeKraken, "XXBT", "BTC"},
+		{domain.ExchangeKraken, "XBT", "BTC"},
+		{domain.ExchangeKraken, "ZEUR", "EUR"},
+		{domain.ExchangeKraken, "XETH", "ETH"},
+		{domain.ExchangeKraken, "ADA", "ADA"},
+		{domain.ExchangeBinance, "BTC", "BTC"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.exchange+" should map the asset code "+c.code, func(t *testing.T) {
+			mapper, _ := symbols.ForExchange(c.exchange)
+
+			if got := mapper.Asset(c.code); got != c.want {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+
+	t.Run("should return error for exchanges not supported", func(t *testing.T) {
+		if _, err := symbols.ForExchange("ftx"); err == nil {
+			t.Errorf("Expected ForExchange to return error")
+		}
+	})
+}
@@ -3,27 +3,65 @@ package trader
 import (
 	"time"
 
+	brokers "github.com/fabiodmferreira/crypto-trading/broker"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 )
 
 // Trader execute operations to buy and sell assets
 type Trader struct {
 	broker domain.Broker
+	asset  string
 }
 
 // NewTrader returns a Trader instance
 func NewTrader(broker domain.Broker) *Trader {
 	return &Trader{
-		broker,
+		broker: broker,
 	}
 }
 
-// Sell requests broker to sell an asset
-func (t *Trader) Sell(asset *domain.Asset, price float32, sellTime time.Time) error {
-	return t.broker.AddSellOrder(asset.Amount, price)
+// NewAssetTrader returns a Trader instance that sets the broker ticker to the asset before each order,
+// so that traders of different assets can share a broker
+func NewAssetTrader(broker domain.Broker, asset string) *Trader {
+	return &Trader{broker, asset}
 }
 
-// Buy requests broker to buy an asset
-func (t *Trader) Buy(amount, price float32, buyTime time.Time) error {
-	return t.broker.AddBuyOrder(amount, price)
+// Sell requests broker to sell an amount of the asset and returns the client order id of the order
+func (t *Trader) Sell(amount, price domain.Decimal, sellTime time.Time) (string, error) {
+	return t.place(domain.NewLimitOrder(domain.OrderSell, amount, price))
+}
+
+// Buy requests broker to buy an asset and returns the client order id of the order
+func (t *Trader) Buy(amount, price domain.Decimal, buyTime time.Time) (string, error) {
+	return t.place(domain.NewLimitOrder(domain.OrderBuy, amount, price))
+}
+
+// place places the order with a new client order id, so the records of the order can be linked to it
+func (t *Trader) place(order domain.Order) (string, error) {
+	id, err := brokers.NewClientOrderID()
+
+	if err != nil {
+		return "", err
+	}
+
+	order.ClientOrderID = id
+	t.setTicker()
+
+	if err := t.broker.PlaceOrder(order); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// PlaceOrder requests broker to place an order of the asset, e.g. a stop-loss of the amount bought
+func (t *Trader) PlaceOrder(order domain.Order) error {
+	t.setTicker()
+	return t.broker.PlaceOrder(order)
+}
+
+func (t *Trader) setTicker() {
+	if t.asset != "" {
+		t.broker.SetTicker(t.asset)
+	}
 }
@@ -0,0 +1,60 @@
+package trades
+
+import (
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository stores and gets the closing trades of accounts
+type Repository struct {
+	repo domain.Repository
+}
+
+// NewRepository returns an instance of Repository
+func NewRepository(repo domain.Repository) *Repository {
+	return &Repository{repo}
+}
+
+// Create inserts a new trade
+func (r *Repository) Create(trade *domain.Trade) error {
+	return r.repo.InsertOne(trade)
+}
+
+// FindAll returns the trades of an account sorted by date.
+// Zero dates do not limit the trades returned.
+func (r *Repository) FindAll(accountID string, startDate, endDate time.Time) (*[]domain.Trade, error) {
+	accountOID, err := primitive.ObjectIDFromHex(accountID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"accountId": accountOID}
+	date := bson.M{}
+
+	if !startDate.IsZero() {
+		date["$gte"] = startDate
+	}
+
+	if !endDate.IsZero() {
+		date["$lte"] = endDate
+	}
+
+	if len(date) > 0 {
+		filter["date"] = date
+	}
+
+	results := []domain.Trade{}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	err = r.repo.FindAll(&results, filter, opts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &results, nil
+}
@@ -1,23 +1,29 @@
 package webserver
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/assets"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/gorilla/mux"
 )
 
+// ledgerDateLayout is the layout of the ledger startDate and endDate parameters
+const ledgerDateLayout = "2006-01-02T15:04:05"
+
 // AccountsController has the accounts routes handlers
 type AccountsController struct {
 	repo       domain.AccountsRepository
 	assetsRepo domain.AssetsRepository
+	ledgerRepo domain.LedgerRepository
 }
 
-func NewAccountsController(repo domain.AccountsRepository, assetsRepo domain.AssetsRepository) *AccountsController {
-	return &AccountsController{repo, assetsRepo}
+func NewAccountsController(repo domain.AccountsRepository, assetsRepo domain.AssetsRepository, ledgerRepo domain.LedgerRepository) *AccountsController {
+	return &AccountsController{repo, assetsRepo, ledgerRepo}
 }
 
 func (a *AccountsController) GetAccountHandler(w http.ResponseWriter, r *http.Request) {
@@ -61,3 +67,71 @@ func (a *AccountsController) GetAccountAssetsGroupedByStateHandler(w http.Respon
 
 	json.NewEncoder(w).Encode(assets.GroupAssetsByState(docs))
 }
+
+// GetAccountLedgerHandler returns the ledger entries of an account between the optional startDate and endDate.
+// Entries are exported as CSV when the format parameter is csv.
+func (a *AccountsController) GetAccountLedgerHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	queryVars := r.URL.Query()
+
+	var dates [2]time.Time
+
+	for i, name := range []string{"startDate", "endDate"} {
+		value := queryVars.Get(name)
+
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(ledgerDateLayout, value)
+
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "%v must have the format %v", name, ledgerDateLayout)
+			return
+		}
+
+		dates[i] = date
+	}
+
+	entries, err := a.ledgerRepo.FindAll(vars["id"], dates[0], dates[1])
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	if queryVars.Get("format") != "csv" {
+		json.NewEncoder(w).Encode(entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=ledger-%v.csv", vars["id"]))
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "date", "type", "debit", "credit", "amount", "assetId", "orderId", "description"})
+
+	for _, entry := range *entries {
+		assetID := ""
+
+		if !entry.AssetID.IsZero() {
+			assetID = entry.AssetID.Hex()
+		}
+
+		writer.Write([]string{
+			entry.ID.Hex(),
+			entry.Date.UTC().Format(time.RFC3339),
+			string(entry.Type),
+			entry.Debit,
+			entry.Credit,
+			entry.Amount.String(),
+			assetID,
+			entry.OrderID,
+			entry.Description,
+		})
+	}
+
+	writer.Flush()
+}
@@ -0,0 +1,74 @@
+package webserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/accounts"
+	"github.com/fabiodmferreira/crypto-trading/db"
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAccountsControllerGetAccountLedger(t *testing.T) {
+	accountID := primitive.NewObjectID()
+	ledgerRepository := accounts.NewLedgerRepository(db.NewMemoryRepository())
+	startDate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	deposit := domain.NewLedgerEntry(accountID, domain.LedgerDeposit, domain.NewDecimalFromInt(100), startDate)
+	buy := domain.NewLedgerEntry(accountID, domain.LedgerBuy, domain.NewDecimal(40.5), startDate.Add(24*time.Hour))
+	buy.AssetID = primitive.NewObjectID()
+	ledgerRepository.Create(deposit)
+	ledgerRepository.Create(buy)
+
+	controller := webserver.NewAccountsController(nil, nil, ledgerRepository)
+
+	request := func(query string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "/api/accounts/"+accountID.Hex()+"/ledger"+query, nil)
+		return mux.SetURLVars(req, map[string]string{"id": accountID.Hex()})
+	}
+
+	t.Run("should return entries between dates", func(t *testing.T) {
+		rr := NewHttpResponse(controller.GetAccountLedgerHandler, request("?startDate=2021-01-02T00:00:00"))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+
+		var got []domain.LedgerEntry
+		json.NewDecoder(rr.Body).Decode(&got)
+
+		if len(got) != 1 || got[0].ID != buy.ID {
+			t.Errorf("got %+v want the buy entry", got)
+		}
+	})
+
+	t.Run("should export entries as csv", func(t *testing.T) {
+		rr := NewHttpResponse(controller.GetAccountLedgerHandler, request("?format=csv"))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+
+		want := strings.Join([]string{
+			"id,date,type,debit,credit,amount,assetId,orderId,description",
+			fmt.Sprintf("%v,2021-01-01T00:00:00Z,deposit,cash,external,100,,,", deposit.ID.Hex()),
+			fmt.Sprintf("%v,2021-01-02T00:00:00Z,buy,assets,cash,40.5,%v,,", buy.ID.Hex(), buy.AssetID.Hex()),
+			"",
+		}, "\n")
+
+		AssertRequestResponse(t, rr, want)
+
+		if got := rr.Header().Get("Content-Type"); got != "text/csv" {
+			t.Errorf("got content type %v want text/csv", got)
+		}
+	})
+
+	t.Run("should return 400 if dates are not valid", func(t *testing.T) {
+		rr := NewHttpResponse(controller.GetAccountLedgerHandler, request("?endDate=yesterday"))
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/pricesquality"
 	"github.com/fabiodmferreira/crypto-trading/utils"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson"
@@ -82,5 +84,90 @@ func (a *AssetsPricesController) GetAssetPrices(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// prices are stored as decimal128, which is encoded as a string on json
+	for _, assetPrice := range *assetsPrices {
+		if price, ok := assetPrice["price"].(primitive.Decimal128); ok {
+			assetPrice["price"], err = domain.ParseDecimal(price.String())
+
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, err)
+				return
+			}
+		}
+	}
+
 	json.NewEncoder(w).Encode(*assetsPrices)
 }
+
+// AssetPricesQualityResponse is the body of the asset prices quality response
+type AssetPricesQualityResponse struct {
+	Report   *domain.PricesQualityReport `json:"report"`
+	Repaired []domain.OHLC               `json:"repaired,omitempty"`
+}
+
+// GetAssetPricesQuality returns the quality report of the asset prices stored with an interval between a start date and an end date.
+// Prices repaired are returned too when a repair method is passed, the repair is a dry run and the prices stored are not changed.
+// Prices stored are sorted by date, so they are never reported out of order.
+func (a *AssetsPricesController) GetAssetPricesQuality(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	queryVars := r.URL.Query()
+
+	asset := strings.ToUpper(vars["asset"])
+
+	startDate, err := time.Parse("2006-01-02T15:04:05", queryVars.Get("startDate"))
+
+	if err != nil {
+		http.Error(w, "startDate parameter is invalid", http.StatusBadRequest)
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02T15:04:05", queryVars.Get("endDate"))
+
+	if err != nil {
+		http.Error(w, "endDate parameter is invalid", http.StatusBadRequest)
+		return
+	}
+
+	options := domain.PricesQualityOptions{}
+
+	// prices of several intervals are stored for the same asset, only the ones of the interval are checked
+	options.Interval, err = pricesquality.ParseInterval(queryVars.Get("interval"))
+
+	if err != nil || options.Interval <= 0 {
+		http.Error(w, "interval parameter is invalid", http.StatusBadRequest)
+		return
+	}
+
+	if zScore := queryVars.Get("zScore"); zScore != "" {
+		options.ZScoreThreshold, err = strconv.ParseFloat(zScore, 64)
+
+		if err != nil {
+			http.Error(w, "zScore parameter is invalid", http.StatusBadRequest)
+			return
+		}
+	}
+
+	prices, err := pricesquality.FindAssetPrices(a.repo, asset, pricesquality.FormatInterval(options.Interval), startDate, endDate)
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	response := AssetPricesQualityResponse{Report: pricesquality.Check(prices, options)}
+
+	if method := queryVars.Get("repair"); method != "" {
+		response.Repaired, err = pricesquality.Repair(prices, options.Interval, method)
+
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -1,13 +1,19 @@
 package webserver_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/mocks"
 	"github.com/fabiodmferreira/crypto-trading/webserver"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -30,7 +36,8 @@ func TestGetAssetPrices(t *testing.T) {
 	t.Run("should return 200 if start date and end date are passed as parameters", func(t *testing.T) {
 		assetspricesController, assetspricesRepository := NewAssetsPricesController(t)
 
-		assetspricesRepository.EXPECT().Aggregate(gomock.Any()).Return(&[]primitive.M{}, nil).Times(1)
+		price, _ := primitive.ParseDecimal128("9800.39605022")
+		assetspricesRepository.EXPECT().Aggregate(gomock.Any()).Return(&[]primitive.M{{"price": price}}, nil).Times(1)
 
 		params := url.Values{"startDate": {"2006-01-02T15:04:05"}, "endDate": {"2006-01-02T15:04:05"}}
 
@@ -43,6 +50,70 @@ func TestGetAssetPrices(t *testing.T) {
 		rr := NewHttpResponse(NewGetAssetsPricesHandler(assetspricesController), req)
 
 		AssertResponseStatusCode(t, rr, http.StatusOK)
+
+		if got := strings.TrimSpace(rr.Body.String()); got != `[{"price":9800.39605022}]` {
+			t.Errorf("got %v want the price as a number", got)
+		}
+	})
+}
+
+func TestGetAssetPricesQuality(t *testing.T) {
+	t.Run("should return 400 if dates are invalid", func(t *testing.T) {
+		assetspricesController, _ := NewAssetsPricesController(t)
+
+		req, _ := http.NewRequest("GET", "/api/assets/btc/quality?startDate=yesterday", nil)
+
+		rr := NewHttpResponse(http.HandlerFunc(assetspricesController.GetAssetPricesQuality), req)
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("should return 400 if the interval is not passed", func(t *testing.T) {
+		assetspricesController, _ := NewAssetsPricesController(t)
+
+		params := url.Values{"startDate": {"2020-03-01T00:00:00"}, "endDate": {"2020-03-02T00:00:00"}}
+		req, _ := http.NewRequest("GET", "/api/assets/btc/quality?"+params.Encode(), nil)
+
+		rr := NewHttpResponse(http.HandlerFunc(assetspricesController.GetAssetPricesQuality), req)
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+
+	t.Run("should return the quality report of the prices stored", func(t *testing.T) {
+		assetspricesController, assetspricesRepository := NewAssetsPricesController(t)
+
+		date := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+		assetsPrices := []domain.AssetPrice{
+			{Date: date, Open: domain.NewDecimal(10), High: domain.NewDecimal(10), Low: domain.NewDecimal(10), Close: domain.NewDecimal(10)},
+			{Date: date.Add(time.Hour), Open: domain.NewDecimal(11), High: domain.NewDecimal(11), Low: domain.NewDecimal(11), Close: domain.NewDecimal(11)},
+			{Date: date.Add(3 * time.Hour), Open: domain.NewDecimal(12), High: domain.NewDecimal(12), Low: domain.NewDecimal(12), Close: domain.NewDecimal(12)},
+		}
+
+		filter := bson.M{"asset": "BTC", "interval": "1h", "date": bson.M{"$gte": date, "$lte": date.AddDate(0, 0, 1)}}
+		assetspricesRepository.EXPECT().FindAll(filter).Return(&assetsPrices, nil).Times(1)
+
+		params := url.Values{"startDate": {"2020-03-01T00:00:00"}, "endDate": {"2020-03-02T00:00:00"}, "interval": {"60m"}, "repair": {"interpolate"}}
+
+		req, _ := http.NewRequest("GET", "/api/assets/btc/quality?"+params.Encode(), nil)
+
+		rr := NewHttpResponse(http.HandlerFunc(assetspricesController.GetAssetPricesQuality), mux.SetURLVars(req, map[string]string{"asset": "btc"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+
+		var got webserver.AssetPricesQualityResponse
+		err := json.NewDecoder(rr.Body).Decode(&got)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got.Report.Gaps) != 1 || got.Report.Gaps[0].Missing != 1 {
+			t.Errorf("Expected one gap with one price missing, got %+v", got.Report.Gaps)
+		}
+
+		if len(got.Repaired) != 4 || got.Repaired[2].Close.String() != "11.5" {
+			t.Errorf("Expected gap to be interpolated, got %+v", got.Repaired)
+		}
 	})
 }
 
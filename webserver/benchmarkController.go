@@ -24,8 +24,16 @@ func NewBenchmarkController(benchmark domain.BenchmarkService, application domai
 
 // GetBenchmarkDataSources returns list of all available data sources
 func (b *BenchmarkController) GetBenchmarkDataSourcesHandler(w http.ResponseWriter, r *http.Request) {
+	dataSources, err := b.benchmark.GetDataSources()
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, err)
+		return
+	}
+
 	w.Header().Set("content-type", "application/json")
-	json.NewEncoder(w).Encode(b.benchmark.GetDataSources())
+	json.NewEncoder(w).Encode(dataSources)
 }
 
 // BenchmarkHandler handles benchmark routes
@@ -9,7 +9,6 @@ import (
 	"testing"
 
 	"github.com/fabiodmferreira/crypto-trading/benchmark"
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/mocks"
 	"github.com/fabiodmferreira/crypto-trading/webserver"
@@ -63,7 +62,7 @@ func TestBenchmarkControllerCreateBenchmark(t *testing.T) {
 		StatisticsOptions:    domain.StatisticsOptions{NumberOfPointsHold: 200},
 		CollectorOptions:     domain.CollectorOptions{PriceVariationDetection: 0.01, DataSource: nil},
 		AccountInitialAmount: 2000,
-		DataSourceFilePath:   btcdatahistory.March2020,
+		DataSourceFilePath:   "btc/2020-mar.csv",
 	}
 
 	body, _ := json.Marshal(input)
@@ -0,0 +1,87 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// maxDatasetUploadSize is the maximum size in bytes of the dataset file kept in memory while uploading
+const maxDatasetUploadSize = 32 << 20
+
+// DatasetsController has the datasets routes handlers
+type DatasetsController struct {
+	datasets domain.DatasetsService
+}
+
+// NewDatasetsController returns an instance of DatasetsController
+func NewDatasetsController(datasets domain.DatasetsService) *DatasetsController {
+	return &DatasetsController{datasets}
+}
+
+// DatasetsHandler handles datasets routes
+func (d *DatasetsController) DatasetsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		d.UploadDataset(w, r)
+	case http.MethodGet:
+		d.GetDatasets(w, r)
+	}
+}
+
+// GetDatasets returns every dataset registered
+func (d *DatasetsController) GetDatasets(w http.ResponseWriter, r *http.Request) {
+	datasets, err := d.datasets.FindAll()
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(datasets)
+}
+
+// UploadDataset handles multipart requests with asset, name and file fields and registers the file as a dataset
+func (d *DatasetsController) UploadDataset(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseMultipartForm(maxDatasetUploadSize)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	defer file.Close()
+
+	asset := strings.ToLower(r.FormValue("asset"))
+
+	dataset := &domain.Dataset{
+		Asset:    asset,
+		Name:     r.FormValue("name"),
+		FilePath: path.Join(asset, filepath.Base(header.Filename)),
+	}
+
+	err = d.datasets.Import(dataset, file)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dataset)
+}
@@ -0,0 +1,92 @@
+package webserver_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/datasets"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+)
+
+func TestDatasetsControllerUploadDataset(t *testing.T) {
+	t.Run("should register the dataset uploaded", func(t *testing.T) {
+		datasetsController, repository, rootDir := NewDatasetsController(t)
+		defer os.RemoveAll(rootDir)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("asset", "BTC")
+		writer.WriteField("name", "March 2020")
+		part, _ := writer.CreateFormFile("file", "2020-mar.csv")
+		part.Write([]byte("Date,Price\n1583020800000,8531.11\n1583024400000,8548.63"))
+		writer.Close()
+
+		req, err := http.NewRequest(http.MethodPost, "/api/datasets", body)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		rr := NewHttpResponse(http.HandlerFunc(datasetsController.DatasetsHandler), req)
+
+		AssertResponseStatusCode(t, rr, http.StatusCreated)
+
+		if len(repository.Datasets) != 1 || repository.Datasets[0].FilePath != "btc/2020-mar.csv" {
+			t.Errorf("Expected dataset btc/2020-mar.csv to be registered, got %+v", repository.Datasets)
+		}
+	})
+
+	t.Run("should return 400 if file is not a prices history", func(t *testing.T) {
+		datasetsController, repository, rootDir := NewDatasetsController(t)
+		defer os.RemoveAll(rootDir)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		writer.WriteField("asset", "BTC")
+		writer.WriteField("name", "March 2020")
+		part, _ := writer.CreateFormFile("file", "2020-mar.csv")
+		part.Write([]byte("Date,Price\n"))
+		writer.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/datasets", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		rr := NewHttpResponse(http.HandlerFunc(datasetsController.DatasetsHandler), req)
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+
+		if len(repository.Datasets) != 0 {
+			t.Errorf("Not expected dataset to be registered")
+		}
+	})
+}
+
+func TestDatasetsControllerGetDatasets(t *testing.T) {
+	datasetsController, _, rootDir := NewDatasetsController(t)
+	defer os.RemoveAll(rootDir)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/datasets", nil)
+
+	rr := NewHttpResponse(http.HandlerFunc(datasetsController.DatasetsHandler), req)
+
+	AssertResponseStatusCode(t, rr, http.StatusOK)
+	AssertRequestResponse(t, rr, "[]\n")
+}
+
+func NewDatasetsController(t *testing.T) (*webserver.DatasetsController, *datasets.RepositoryInMemory, string) {
+	rootDir, err := ioutil.TempDir("", "datasets")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repository := datasets.NewRepositoryInMemory()
+
+	return webserver.NewDatasetsController(datasets.NewService(repository, rootDir)), repository, rootDir
+}
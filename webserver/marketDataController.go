@@ -0,0 +1,32 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+)
+
+// MarketDataController has the market data routes handlers
+type MarketDataController struct {
+	statsRepository domain.MarketDataStatsRepository
+}
+
+// NewMarketDataController returns an instance of MarketDataController
+func NewMarketDataController(statsRepository domain.MarketDataStatsRepository) *MarketDataController {
+	return &MarketDataController{statsRepository}
+}
+
+// GetSubscriptionsHandler returns the statistics of the subscriptions of the market data hub
+func (c *MarketDataController) GetSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.statsRepository.FindAll()
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(stats)
+}
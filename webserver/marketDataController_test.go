@@ -0,0 +1,39 @@
+package webserver_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+	"github.com/golang/mock/gomock"
+)
+
+func TestMarketDataController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := mocks.NewMockMarketDataStatsRepository(ctrl)
+	controller := webserver.NewMarketDataController(repository)
+
+	t.Run("should return the subscriptions stats", func(t *testing.T) {
+		repository.EXPECT().FindAll().Return(&[]domain.MarketDataStats{{Exchange: domain.ExchangeKraken, Quote: domain.QuoteEUR, Asset: "BTC", Interval: 1, Subscribers: 2, Candles: 3, Running: true}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/market-data/subscriptions", nil)
+		rr := NewHttpResponse(controller.GetSubscriptionsHandler, req)
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, `[{"exchange":"kraken","quote":"EUR","asset":"BTC","interval":1,"subscribers":2,"candles":3,"lastCandle":"0001-01-01T00:00:00Z","startedAt":"0001-01-01T00:00:00Z","running":true,"restarts":0,"updatedAt":"0001-01-01T00:00:00Z"}]`+"\n")
+	})
+
+	t.Run("should return 500 when stats are not available", func(t *testing.T) {
+		repository.EXPECT().FindAll().Return(nil, errors.New("db down"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/market-data/subscriptions", nil)
+		rr := NewHttpResponse(controller.GetSubscriptionsHandler, req)
+
+		AssertResponseStatusCode(t, rr, http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,105 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/gorilla/mux"
+)
+
+// OptionsController has the applications options routes handlers
+type OptionsController struct {
+	service domain.OptionsVersionsService
+}
+
+// NewOptionsController returns an instance of OptionsController
+func NewOptionsController(service domain.OptionsVersionsService) *OptionsController {
+	return &OptionsController{service}
+}
+
+// UpdateOptionsInput is the body of options updates, the author is stored in the version of the options
+type UpdateOptionsInput struct {
+	Options domain.ApplicationOptions `json:"options"`
+	Author  string                    `json:"author"`
+}
+
+// RollbackInput is the optional body of rollbacks
+type RollbackInput struct {
+	Author string `json:"author"`
+}
+
+// UpdateOptionsHandler sets the options of the application id and returns their version
+func (c *OptionsController) UpdateOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input UpdateOptionsInput
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	version, err := c.service.Update(mux.Vars(r)["id"], input.Options, input.Author)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(version)
+}
+
+// GetVersionsHandler returns the options versions of the application id
+func (c *OptionsController) GetVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	versions, err := c.service.FindAll(mux.Vars(r)["id"])
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(versions)
+}
+
+// RollbackHandler sets the options of a version on the application id and returns the new version
+func (c *OptionsController) RollbackHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	vars := mux.Vars(r)
+	version, err := strconv.Atoi(vars["version"])
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var input RollbackInput
+
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	optionsVersion, err := c.service.Rollback(vars["id"], version, input.Author)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(optionsVersion)
+}
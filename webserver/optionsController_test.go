@@ -0,0 +1,59 @@
+package webserver_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestOptionsController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockOptionsVersionsService(ctrl)
+	controller := webserver.NewOptionsController(service)
+
+	t.Run("should update the options with the author", func(t *testing.T) {
+		service.EXPECT().Update("1", domain.ApplicationOptions{LotMatching: domain.LotFIFO}, "alice").Return(&domain.OptionsVersion{Version: 2, Author: "alice"}, nil)
+
+		req, _ := http.NewRequest(http.MethodPut, "/api/applications/1/options", strings.NewReader(`{"options":{"lotMatching":"fifo"},"author":"alice"}`))
+		rr := NewHttpResponse(controller.UpdateOptionsHandler, mux.SetURLVars(req, map[string]string{"id": "1"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+	})
+
+	t.Run("should return the versions of the options", func(t *testing.T) {
+		service.EXPECT().FindAll("1").Return(&[]domain.OptionsVersion{}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/applications/1/options/versions", nil)
+		rr := NewHttpResponse(controller.GetVersionsHandler, mux.SetURLVars(req, map[string]string{"id": "1"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, "[]\n")
+	})
+
+	t.Run("should rollback to a version without a body", func(t *testing.T) {
+		service.EXPECT().Rollback("1", 2, "").Return(&domain.OptionsVersion{Version: 3}, nil)
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/applications/1/options/versions/2/rollback", nil)
+		rr := NewHttpResponse(controller.RollbackHandler, mux.SetURLVars(req, map[string]string{"id": "1", "version": "2"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+	})
+
+	t.Run("should return 400 for versions that do not exist", func(t *testing.T) {
+		service.EXPECT().Rollback("1", 9, "bob").Return(nil, errors.New("Not able to find version 9 of application 1"))
+
+		req, _ := http.NewRequest(http.MethodPost, "/api/applications/1/options/versions/9/rollback", strings.NewReader(`{"author":"bob"}`))
+		rr := NewHttpResponse(controller.RollbackHandler, mux.SetURLVars(req, map[string]string{"id": "1", "version": "9"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,75 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/gorilla/mux"
+)
+
+// defaultEquityDays is the number of days of the equity series when the startDate parameter is not set
+const defaultEquityDays = 30
+
+// PortfolioController has the portfolio routes handlers
+type PortfolioController struct {
+	service domain.PortfolioService
+}
+
+// NewPortfolioController returns an instance of PortfolioController
+func NewPortfolioController(service domain.PortfolioService) *PortfolioController {
+	return &PortfolioController{service}
+}
+
+// GetPortfolioHandler returns the holdings valued at the last prices of the account id, or of every account without id
+func (c *PortfolioController) GetPortfolioHandler(w http.ResponseWriter, r *http.Request) {
+	portfolio, err := c.service.GetPortfolio(mux.Vars(r)["id"], r.URL.Query().Get("quote"))
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(portfolio)
+}
+
+// GetEquityHandler returns the daily equity of the account id, or of every account without id, between the optional startDate and endDate.
+// By default it returns the last 30 days.
+func (c *PortfolioController) GetEquityHandler(w http.ResponseWriter, r *http.Request) {
+	queryVars := r.URL.Query()
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -defaultEquityDays)
+	dates := []*time.Time{&startDate, &endDate}
+
+	for i, name := range []string{"startDate", "endDate"} {
+		value := queryVars.Get(name)
+
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(ledgerDateLayout, value)
+
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "%v must have the format %v", name, ledgerDateLayout)
+			return
+		}
+
+		*dates[i] = date
+	}
+
+	points, err := c.service.GetEquity(mux.Vars(r)["id"], r.URL.Query().Get("quote"), startDate, endDate)
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(points)
+}
@@ -0,0 +1,50 @@
+package webserver_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestPortfolioController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockPortfolioService(ctrl)
+	controller := webserver.NewPortfolioController(service)
+
+	t.Run("should return the portfolio of the account", func(t *testing.T) {
+		service.EXPECT().GetPortfolio("1", "").Return(&domain.Portfolio{Cash: domain.NewDecimalFromInt(10), Holdings: []domain.Holding{}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/accounts/1/portfolio", nil)
+		rr := NewHttpResponse(controller.GetPortfolioHandler, mux.SetURLVars(req, map[string]string{"id": "1"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, `{"date":"0001-01-01T00:00:00Z","quote":"","cash":10,"holdings":[],"marketValue":0,"unrealizedPnL":0,"realizedPnL":0,"equity":0}`+"\n")
+	})
+
+	t.Run("should return the equity of every account between dates", func(t *testing.T) {
+		startDate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+		service.EXPECT().GetEquity("", "", startDate, endDate).Return([]domain.EquityPoint{}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/portfolio/equity?startDate=2021-01-01T00:00:00&endDate=2021-01-02T00:00:00", nil)
+		rr := NewHttpResponse(controller.GetEquityHandler, req)
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, "[]\n")
+	})
+
+	t.Run("should return 400 if dates are not valid", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/portfolio/equity?startDate=today", nil)
+		rr := NewHttpResponse(controller.GetEquityHandler, req)
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+}
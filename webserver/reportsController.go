@@ -0,0 +1,59 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/reports"
+)
+
+// ReportsController has the reports routes handlers
+type ReportsController struct {
+	taxReportService domain.TaxReportService
+}
+
+// NewReportsController returns an instance of ReportsController
+func NewReportsController(taxReportService domain.TaxReportService) *ReportsController {
+	return &ReportsController{taxReportService}
+}
+
+// GetTaxReportHandler returns the capital gains of the year parameter using the method parameter, fifo by default.
+// The report is exported as CSV when the format parameter is csv.
+func (c *ReportsController) GetTaxReportHandler(w http.ResponseWriter, r *http.Request) {
+	queryVars := r.URL.Query()
+
+	year, err := strconv.Atoi(queryVars.Get("year"))
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "year must be a number")
+		return
+	}
+
+	method := domain.CostBasisMethod(queryVars.Get("method"))
+
+	if method == "" {
+		method = domain.CostBasisFIFO
+	}
+
+	report, err := c.taxReportService.Generate(year, method, queryVars.Get("quote"))
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	if queryVars.Get("format") != "csv" {
+		json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=tax-report-%d.csv", year))
+
+	reports.WriteTaxReportCSV(w, report)
+}
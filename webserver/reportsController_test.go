@@ -0,0 +1,48 @@
+package webserver_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+	"github.com/golang/mock/gomock"
+)
+
+func TestReportsControllerGetTaxReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockTaxReportService(ctrl)
+	controller := webserver.NewReportsController(service)
+
+	request := func(query string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "/api/reports/tax"+query, nil)
+		return req
+	}
+
+	t.Run("should return the report of the year with fifo by default", func(t *testing.T) {
+		service.EXPECT().Generate(2021, domain.CostBasisFIFO, "").Return(&domain.TaxReport{Year: 2021, Method: domain.CostBasisFIFO, Quote: domain.QuoteEUR, Disposals: []domain.TaxDisposal{}}, nil)
+
+		rr := NewHttpResponse(controller.GetTaxReportHandler, request("?year=2021"))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, `{"year":2021,"method":"fifo","quote":"EUR","disposals":[],"proceeds":0,"costBasis":0,"gain":0}`+"\n")
+	})
+
+	t.Run("should export the report as csv", func(t *testing.T) {
+		service.EXPECT().Generate(2021, domain.CostBasisAverage, "USD").Return(&domain.TaxReport{}, nil)
+
+		rr := NewHttpResponse(controller.GetTaxReportHandler, request("?year=2021&method=average&quote=USD&format=csv"))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, "date,asset,source,amount,proceeds,fees,costBasis,gain\n")
+	})
+
+	t.Run("should return 400 if year is not valid", func(t *testing.T) {
+		rr := NewHttpResponse(controller.GetTaxReportHandler, request("?year=last"))
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+}
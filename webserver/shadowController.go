@@ -0,0 +1,33 @@
+package webserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/gorilla/mux"
+)
+
+// ShadowController has the shadow applications routes handlers
+type ShadowController struct {
+	service domain.ShadowService
+}
+
+// NewShadowController returns an instance of ShadowController
+func NewShadowController(service domain.ShadowService) *ShadowController {
+	return &ShadowController{service}
+}
+
+// GetComparisonHandler returns the portfolios of the shadow application id and of the live application it shadows with the shadow orders
+func (c *ShadowController) GetComparisonHandler(w http.ResponseWriter, r *http.Request) {
+	comparison, err := c.service.Compare(mux.Vars(r)["id"])
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(comparison)
+}
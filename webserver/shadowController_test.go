@@ -0,0 +1,40 @@
+package webserver_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fabiodmferreira/crypto-trading/domain"
+	"github.com/fabiodmferreira/crypto-trading/mocks"
+	"github.com/fabiodmferreira/crypto-trading/webserver"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+)
+
+func TestShadowController(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := mocks.NewMockShadowService(ctrl)
+	controller := webserver.NewShadowController(service)
+
+	t.Run("should return the comparison of the shadow application", func(t *testing.T) {
+		service.EXPECT().Compare("1").Return(&domain.ShadowComparison{Shadow: domain.ApplicationPerformance{Mode: domain.ModeShadow}, Live: domain.ApplicationPerformance{Mode: domain.ModeLive}, Orders: []domain.ShadowOrder{}}, nil)
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/applications/1/shadow", nil)
+		rr := NewHttpResponse(controller.GetComparisonHandler, mux.SetURLVars(req, map[string]string{"id": "1"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusOK)
+		AssertRequestResponse(t, rr, `{"shadow":{"applicationId":"000000000000000000000000","mode":"shadow","portfolio":null},"live":{"applicationId":"000000000000000000000000","mode":"live","portfolio":null},"orders":[]}`+"\n")
+	})
+
+	t.Run("should return 400 for applications that do not shadow a live application", func(t *testing.T) {
+		service.EXPECT().Compare("2").Return(nil, errors.New("application 2 does not shadow a live application"))
+
+		req, _ := http.NewRequest(http.MethodGet, "/api/applications/2/shadow", nil)
+		rr := NewHttpResponse(controller.GetComparisonHandler, mux.SetURLVars(req, map[string]string{"id": "2"}))
+
+		AssertResponseStatusCode(t, rr, http.StatusBadRequest)
+	})
+}
@@ -20,7 +20,14 @@ func NewCryptoTradingServer(
 	assetsPrice domain.AssetPriceRepository,
 	accounts domain.AccountsRepository,
 	assets domain.AssetsRepository,
+	ledger domain.LedgerRepository,
 	appService domain.ApplicationService,
+	datasets domain.DatasetsService,
+	taxReport domain.TaxReportService,
+	portfolio domain.PortfolioService,
+	marketDataStats domain.MarketDataStatsRepository,
+	shadow domain.ShadowService,
+	optionsVersions domain.OptionsVersionsService,
 ) (*CryptoTradingServer, error) {
 	server := new(CryptoTradingServer)
 
@@ -32,13 +39,30 @@ func NewCryptoTradingServer(
 	router.HandleFunc("/api/benchmark/{id}", benchmarkController.ResourceHandler)
 	router.HandleFunc("/api/benchmark/{id}/state", benchmarkController.GetBenchmarkExecutionStateHandler)
 
+	datasetsController := NewDatasetsController(datasets)
+	router.HandleFunc("/api/datasets", datasetsController.DatasetsHandler)
+
 	assetsPricesController := NewAssetsPricesController(assetsPrice)
 	router.Handle("/api/assets/{asset}/prices", http.HandlerFunc(assetsPricesController.GetAssetPrices))
+	router.HandleFunc("/api/assets/{asset}/quality", assetsPricesController.GetAssetPricesQuality)
 
-	accountsController := NewAccountsController(accounts, assets)
+	accountsController := NewAccountsController(accounts, assets, ledger)
 	router.HandleFunc("/api/accounts/{id}", accountsController.GetAccountHandler)
 	router.HandleFunc("/api/accounts/{id}/assets", accountsController.GetAccountAssetsHandler)
 	router.HandleFunc("/api/accounts/{id}/buys-and-sells", accountsController.GetAccountAssetsGroupedByStateHandler)
+	router.HandleFunc("/api/accounts/{id}/ledger", accountsController.GetAccountLedgerHandler)
+
+	portfolioController := NewPortfolioController(portfolio)
+	router.HandleFunc("/api/portfolio", portfolioController.GetPortfolioHandler)
+	router.HandleFunc("/api/portfolio/equity", portfolioController.GetEquityHandler)
+	router.HandleFunc("/api/accounts/{id}/portfolio", portfolioController.GetPortfolioHandler)
+	router.HandleFunc("/api/accounts/{id}/equity", portfolioController.GetEquityHandler)
+
+	reportsController := NewReportsController(taxReport)
+	router.HandleFunc("/api/reports/tax", reportsController.GetTaxReportHandler)
+
+	marketDataController := NewMarketDataController(marketDataStats)
+	router.HandleFunc("/api/market-data/subscriptions", marketDataController.GetSubscriptionsHandler)
 
 	applicationsController := NewApplicationsController(appService)
 	router.HandleFunc("/api/applications", applicationsController.GetApplicationsHandler)
@@ -47,6 +71,14 @@ func NewCryptoTradingServer(
 	router.HandleFunc("/api/applications/{id}", applicationsController.ApplicationItemHandler)
 	router.HandleFunc("/api/applications/{id}/state", applicationsController.GetApplicationStateHandler)
 
+	shadowController := NewShadowController(shadow)
+	router.HandleFunc("/api/applications/{id}/shadow", shadowController.GetComparisonHandler)
+
+	optionsController := NewOptionsController(optionsVersions)
+	router.HandleFunc("/api/applications/{id}/options", optionsController.UpdateOptionsHandler)
+	router.HandleFunc("/api/applications/{id}/options/versions", optionsController.GetVersionsHandler)
+	router.HandleFunc("/api/applications/{id}/options/versions/{version}/rollback", optionsController.RollbackHandler)
+
 	router.Handle("/", http.HandlerFunc(server.versionHandler))
 
 	server.Handler = router
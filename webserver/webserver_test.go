@@ -6,13 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"path"
 	"testing"
 
 	"github.com/fabiodmferreira/crypto-trading/application-execution-states"
 	"github.com/fabiodmferreira/crypto-trading/assets"
 	"github.com/fabiodmferreira/crypto-trading/assetsprices"
 	"github.com/fabiodmferreira/crypto-trading/benchmark"
-	btcdatahistory "github.com/fabiodmferreira/crypto-trading/data-history/btc"
+	"github.com/fabiodmferreira/crypto-trading/datasets"
 	"github.com/fabiodmferreira/crypto-trading/domain"
 	"github.com/fabiodmferreira/crypto-trading/mocks"
 	"github.com/fabiodmferreira/crypto-trading/webserver"
@@ -24,7 +25,7 @@ func TestGetBenchmarkDataSources(t *testing.T) {
 
 	AssertResponseStatusCode(t, res, http.StatusOK)
 
-	AssertRequestResponse(t, res, fmt.Sprintf("%v\n", `{"ada":{"2019":"ada/2019.csv","2020 H1":"ada/2020-h1.csv"},"btc":{"2019":"btc/2019.csv","2019-2020 H1":"btc/2019-2020-h1.csv","2020 H1":"btc/2020-h1.csv"},"btc-cash":{"2019":"btc-cash/2019.csv","2020 H1":"btc-cash/2020-h1.csv"},"eos":{"2019":"eos/2019.csv","2020 H1":"eos/2020-h1.csv"},"etc":{"2019":"etc/2019.csv","2020 H1":"etc/2020-h1.csv"},"eth":{"2019":"eth/2019.csv","2020 H1":"eth/2020-h1.csv"},"ltc":{"2019":"ltc/2019.csv","2020 H1":"ltc/2020-h1.csv"},"monero":{"2019":"monero/2019.csv","2020 H1":"monero/2020-h1.csv"},"stellar":{"2019":"stellar/2019.csv","2020 H1":"stellar/2020-h1.csv"},"xrp":{"2019":"xrp/2019.csv","2020 H1":"xrp/2020-h1.csv"}}`))
+	AssertRequestResponse(t, res, fmt.Sprintf("%v\n", `{"ada":{"2019":"ada/2019.csv","2019-2020 H1":"ada/2019-2020-h1.csv","2020 H1":"ada/2020-h1.csv"},"btc":{"2019":"btc/2019.csv","2019-2020 H1":"btc/2019-2020-h1.csv","2020 H1":"btc/2020-h1.csv","March 2020":"btc/2020-mar.csv"},"btc-cash":{"2019":"btc-cash/2019.csv","2020 H1":"btc-cash/2020-h1.csv"},"eos":{"2019":"eos/2019.csv","2020 H1":"eos/2020-h1.csv"},"etc":{"2019":"etc/2019.csv","2020 H1":"etc/2020-h1.csv"},"eth":{"2019":"eth/2019.csv","2019-2020 H1":"eth/2019-2020-h1.csv","2020 H1":"eth/2020-h1.csv"},"ltc":{"2019":"ltc/2019.csv","2020 H1":"ltc/2020-h1.csv"},"monero":{"2019":"monero/2019.csv","2020 H1":"monero/2020-h1.csv"},"stellar":{"2019":"stellar/2019.csv","2020 H1":"stellar/2020-h1.csv"},"xrp":{"2019":"xrp/2019.csv","2020 H1":"xrp/2020-h1.csv"}}`))
 }
 
 func TestGetBenchmarkList(t *testing.T) {
@@ -49,7 +50,7 @@ func TestCreateBenchmarkResource(t *testing.T) {
 		StatisticsOptions:    domain.StatisticsOptions{NumberOfPointsHold: 200},
 		CollectorOptions:     domain.CollectorOptions{PriceVariationDetection: 0.01, DataSource: nil},
 		AccountInitialAmount: 2000,
-		DataSourceFilePath:   btcdatahistory.March2020,
+		DataSourceFilePath:   "btc/2020-mar.csv",
 	}
 
 	body, _ := json.Marshal(input)
@@ -71,8 +72,10 @@ func MakeRequest(t *testing.T, method string, url string, body *bytes.Reader) *h
 	accountsRepo := mocks.NewMockAccountsRepository(ctrl)
 	appService := mocks.NewMockApplicationService(ctrl)
 	assetsRepo := &assets.AssetsRepositoryInMemory{}
-	benchmarkService := benchmark.NewService(repo, assetsPricesRepo, applicationExecutionsStatesRepo)
-	server, _ := webserver.NewCryptoTradingServer(benchmarkService, assetsPricesRepo, accountsRepo, assetsRepo, appService)
+	datasetsRootDir := path.Join("..", datasets.DefaultRootDir)
+	datasetsService := datasets.NewService(datasets.NewManifestRepository(path.Join(datasetsRootDir, datasets.ManifestFileName)), datasetsRootDir)
+	benchmarkService := benchmark.NewService(repo, assetsPricesRepo, applicationExecutionsStatesRepo, datasetsService)
+	server, _ := webserver.NewCryptoTradingServer(benchmarkService, assetsPricesRepo, accountsRepo, assetsRepo, mocks.NewMockLedgerRepository(ctrl), appService, datasetsService, mocks.NewMockTaxReportService(ctrl), mocks.NewMockPortfolioService(ctrl), mocks.NewMockMarketDataStatsRepository(ctrl), mocks.NewMockShadowService(ctrl), mocks.NewMockOptionsVersionsService(ctrl))
 
 	var req *http.Request
 